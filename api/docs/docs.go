@@ -0,0 +1,14 @@
+// Pacote com a documentação da API embutida no binário.
+package docs
+
+import _ "embed"
+
+// Documento OpenAPI 3 da API, servido em /openapi.json
+//
+//go:embed openapi.json
+var OpenAPI []byte
+
+// Página do Swagger UI, servida em /docs
+//
+//go:embed index.html
+var Index []byte
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"context"
+	"errors"
+
+	"github.com/samluiz/delivery-service/api/http/utils"
+	"github.com/samluiz/delivery-service/internal/auth"
+	"github.com/samluiz/delivery-service/internal/delivery"
+)
+
+var (
+	ErrInvalidID     = errors.New("id must be a positive integer")
+	ErrInvalidCursor = errors.New("cursor is invalid")
+	ErrInvalidFirst  = errors.New("first must not be negative")
+	ErrQueryTooDeep  = errors.New("query exceeds the maximum depth")
+	ErrQueryTooLarge = errors.New("query exceeds the maximum complexity")
+)
+
+// Códigos retornados em extensions.code, equivalentes aos status da API HTTP.
+const (
+	CodeBadUserInput    = "BAD_USER_INPUT"
+	CodeUnauthenticated = "UNAUTHENTICATED"
+	CodeForbidden       = "FORBIDDEN"
+	CodeNotFound        = "NOT_FOUND"
+	CodeQueryTooComplex = "QUERY_TOO_COMPLEX"
+	CodeInternal        = "INTERNAL_SERVER_ERROR"
+)
+
+// Erro retornado pelos resolvers, com o código e a falha de cada campo nas extensions da resposta.
+type Error struct {
+	Code   string
+	Err    error
+	Fields []utils.FieldError
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Implementa gqlerrors.ExtendedError, utilizado pelo graphql-go para preencher as extensions do erro.
+func (e *Error) Extensions() map[string]interface{} {
+	extensions := map[string]interface{}{"code": e.Code}
+	if len(e.Fields) > 0 {
+		extensions["fields"] = e.Fields
+	}
+	return extensions
+}
+
+// Função responsável por converter os erros do service e da autorização para os códigos da API GraphQL.
+func toError(err error) error {
+	switch {
+	case errors.Is(err, delivery.ErrDeliveryNotFound):
+		return &Error{Code: CodeNotFound, Err: err}
+	case errors.Is(err, auth.ErrForbidden):
+		return &Error{Code: CodeForbidden, Err: err}
+	case errors.Is(err, auth.ErrUnauthenticated):
+		return &Error{Code: CodeUnauthenticated, Err: err}
+	}
+
+	return &Error{Code: CodeInternal, Err: err}
+}
+
+// Função responsável por validar o input com as regras da API HTTP.
+// As mensagens dos campos seguem o idioma da requisição.
+func (s *Server) validate(ctx context.Context, input any) error {
+	errs, err := s.validator.Validate(input)
+	if err != nil {
+		return &Error{Code: CodeBadUserInput, Err: err}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+
+	fields := s.validator.NewFieldErrors(languageFromContext(ctx), errs)
+	return &Error{Code: CodeBadUserInput, Err: errors.New(fields[0].Campo + ": " + fields[0].Mensagem), Fields: fields}
+}
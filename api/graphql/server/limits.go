@@ -0,0 +1,138 @@
+package server
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// Limites aplicados às consultas antes da execução, para que uma única requisição não sobrecarregue o banco.
+// A profundidade conta os níveis de campos aninhados e a complexidade estima a quantidade de campos resolvidos:
+// cada campo custa 1 e os campos paginados multiplicam o custo dos seus subcampos pelo first solicitado.
+// Valores zerados desativam o limite correspondente.
+type Limits struct {
+	MaxDepth      int
+	MaxComplexity int
+}
+
+// Análise de uma operação, com os fragmentos e variáveis da requisição.
+type analysis struct {
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]interface{}
+	defaults  map[string]ast.Value
+	visiting  map[string]bool
+}
+
+// Função responsável por calcular a profundidade e a complexidade da operação.
+func analyze(document *ast.Document, operation *ast.OperationDefinition, variables map[string]interface{}) (int, int) {
+	a := &analysis{
+		fragments: map[string]*ast.FragmentDefinition{},
+		variables: variables,
+		defaults:  map[string]ast.Value{},
+		visiting:  map[string]bool{},
+	}
+
+	for _, definition := range document.Definitions {
+		if fragment, ok := definition.(*ast.FragmentDefinition); ok {
+			a.fragments[fragment.Name.Value] = fragment
+		}
+	}
+
+	for _, definition := range operation.VariableDefinitions {
+		if definition.DefaultValue != nil {
+			a.defaults[definition.Variable.Name.Value] = definition.DefaultValue
+		}
+	}
+
+	return a.selectionSet(operation.SelectionSet)
+}
+
+// Função responsável por calcular a profundidade e a complexidade de um conjunto de campos.
+// Os fragmentos são expandidos no nível em que aparecem.
+func (a *analysis) selectionSet(set *ast.SelectionSet) (int, int) {
+	if set == nil {
+		return 0, 0
+	}
+
+	depth, complexity := 0, 0
+
+	for _, selection := range set.Selections {
+		var d, c int
+
+		switch selection := selection.(type) {
+		case *ast.Field:
+			d, c = a.field(selection)
+		case *ast.InlineFragment:
+			d, c = a.selectionSet(selection.SelectionSet)
+		case *ast.FragmentSpread:
+			fragment, ok := a.fragments[selection.Name.Value]
+			// Fragmentos cíclicos são rejeitados na validação, a verificação apenas evita a recursão infinita
+			if !ok || a.visiting[fragment.Name.Value] {
+				continue
+			}
+			a.visiting[fragment.Name.Value] = true
+			d, c = a.selectionSet(fragment.SelectionSet)
+			a.visiting[fragment.Name.Value] = false
+		}
+
+		depth = max(depth, d)
+		complexity += c
+	}
+
+	return depth, complexity
+}
+
+func (a *analysis) field(field *ast.Field) (int, int) {
+	depth, complexity := a.selectionSet(field.SelectionSet)
+
+	if first, ok := a.first(field); ok {
+		complexity *= first
+	}
+
+	return depth + 1, complexity + 1
+}
+
+// Função responsável por obter o tamanho da página solicitado em um campo paginado.
+// Campos paginados sem first utilizam o tamanho padrão, e valores acima do máximo são limitados como na execução.
+func (a *analysis) first(field *ast.Field) (int, bool) {
+	if field.Name.Value != "deliveries" {
+		return 0, false
+	}
+
+	for _, argument := range field.Arguments {
+		if argument.Name.Value == "first" {
+			if first, ok := a.intValue(argument.Value); ok && first >= 0 {
+				return min(first, MaxPageSize), true
+			}
+		}
+	}
+
+	return DefaultPageSize, true
+}
+
+func (a *analysis) intValue(value ast.Value) (int, bool) {
+	switch value := value.(type) {
+	case *ast.IntValue:
+		n, err := strconv.Atoi(value.Value)
+		return n, err == nil
+	case *ast.Variable:
+		name := value.Name.Value
+
+		switch v := a.variables[name].(type) {
+		case float64:
+			return int(v), true
+		case int:
+			return v, true
+		case json.Number:
+			n, err := strconv.Atoi(v.String())
+			return n, err == nil
+		}
+
+		if defaultValue, ok := a.defaults[name]; ok {
+			return a.intValue(defaultValue)
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,133 @@
+package server
+
+import (
+	"context"
+	"sync"
+
+	"github.com/samluiz/delivery-service/internal/delivery"
+)
+
+// Loader que agrupa em uma única busca as chaves solicitadas pelos resolvers durante a execução da consulta.
+// O Load registra a chave e devolve um thunk; o graphql-go só resolve os thunks depois de percorrer todos os
+// campos do mesmo nível, então a primeira resolução busca de uma vez todas as chaves pendentes.
+// Os resultados ficam em cache até o fim da requisição, evitando buscar a mesma chave duas vezes.
+type Loader[K comparable, V any] struct {
+	fetch func(ctx context.Context, keys []K) (map[K]V, error)
+
+	mu      sync.Mutex
+	pending []K
+	results map[K]loaderResult[V]
+}
+
+type loaderResult[V any] struct {
+	value V
+	found bool
+	err   error
+}
+
+// Função responsável por instanciar o loader com a função que busca um lote de chaves.
+// Chaves ausentes no mapa retornado são tratadas como inexistentes.
+func NewLoader[K comparable, V any](fetch func(ctx context.Context, keys []K) (map[K]V, error)) *Loader[K, V] {
+	return &Loader[K, V]{fetch: fetch, results: map[K]loaderResult[V]{}}
+}
+
+// Função responsável por registrar a chave no próximo lote e retornar a função que obtém o seu valor.
+func (l *Loader[K, V]) Load(ctx context.Context, key K) func() (V, bool, error) {
+	l.mu.Lock()
+	if _, ok := l.results[key]; !ok && !l.isPending(key) {
+		l.pending = append(l.pending, key)
+	}
+	l.mu.Unlock()
+
+	return func() (V, bool, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		if _, ok := l.results[key]; !ok {
+			l.dispatch(ctx)
+		}
+
+		result := l.results[key]
+		return result.value, result.found, result.err
+	}
+}
+
+// Função responsável por adicionar ao cache um valor obtido por outra busca, como a listagem.
+func (l *Loader[K, V]) Prime(key K, value V) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.results[key]; !ok {
+		l.results[key] = loaderResult[V]{value: value, found: true}
+	}
+}
+
+// Função responsável por buscar todas as chaves pendentes em uma única chamada.
+// Chaves adicionadas ao cache pelo Prime depois de registradas não são buscadas novamente.
+// Em caso de erro, todas as chaves do lote recebem o mesmo erro.
+func (l *Loader[K, V]) dispatch(ctx context.Context) {
+	keys := make([]K, 0, len(l.pending))
+	for _, key := range l.pending {
+		if _, ok := l.results[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	l.pending = nil
+
+	if len(keys) == 0 {
+		return
+	}
+
+	values, err := l.fetch(ctx, keys)
+
+	for _, key := range keys {
+		value, found := values[key]
+		l.results[key] = loaderResult[V]{value: value, found: found, err: err}
+	}
+}
+
+func (l *Loader[K, V]) isPending(key K) bool {
+	for _, pending := range l.pending {
+		if pending == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Loaders de uma requisição. São criados a cada execução para que o cache não seja compartilhado entre usuários.
+type loaders struct {
+	deliveries *Loader[int, *delivery.DeliveryResponse]
+}
+
+func newLoaders(service delivery.IDeliveryService) *loaders {
+	return &loaders{
+		deliveries: NewLoader(func(ctx context.Context, ids []int) (map[int]*delivery.DeliveryResponse, error) {
+			deliveries, err := service.GetDeliveriesByIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+
+			values := make(map[int]*delivery.DeliveryResponse, len(deliveries))
+			for _, d := range deliveries {
+				values[d.ID] = d
+			}
+			return values, nil
+		}),
+	}
+}
+
+type contextKey int
+
+const (
+	loadersKey contextKey = iota
+	languageKey
+)
+
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey, l)
+}
+
+func loadersFromContext(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey).(*loaders)
+}
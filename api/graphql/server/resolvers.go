@@ -0,0 +1,232 @@
+package server
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/samluiz/delivery-service/internal/auth"
+	"github.com/samluiz/delivery-service/internal/delivery"
+)
+
+const (
+	DefaultPageSize = 20  // Tamanho da página quando first não é informado
+	MaxPageSize     = 100 // Tamanho máximo da página
+)
+
+// Prefixo dos cursores, que codificam a posição da entrega na listagem.
+const cursorPrefix = "offset:"
+
+func (s *Server) resolveDelivery(p graphql.ResolveParams) (interface{}, error) {
+	if err := auth.Authorize(p.Context, s.policies.Read); err != nil {
+		return nil, toError(err)
+	}
+
+	id, err := deliveryID(p.Args["id"])
+	if err != nil {
+		return nil, err
+	}
+
+	// A busca é adiada para que os IDs de todos os campos delivery da consulta sejam buscados juntos
+	load := loadersFromContext(p.Context).deliveries.Load(p.Context, id)
+
+	return func() (interface{}, error) {
+		response, found, err := load()
+		if err != nil {
+			return nil, toError(err)
+		}
+		if !found {
+			return nil, nil
+		}
+		return response.ToDeliveryResponseV2(), nil
+	}, nil
+}
+
+// Lista as entregas no formato de connection do Relay.
+// Os cursores são opacos para o cliente e representam a posição da entrega na listagem com os mesmos filtros e ordenação.
+func (s *Server) resolveDeliveries(p graphql.ResolveParams) (interface{}, error) {
+	if err := auth.Authorize(p.Context, s.policies.Read); err != nil {
+		return nil, toError(err)
+	}
+
+	first := DefaultPageSize
+	if value, ok := p.Args["first"].(int); ok {
+		if value < 0 {
+			return nil, &Error{Code: CodeBadUserInput, Err: ErrInvalidFirst}
+		}
+		first = min(value, MaxPageSize)
+	}
+
+	offset := 0
+	if cursor, ok := p.Args["after"].(string); ok {
+		position, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		offset = position + 1
+	}
+
+	filter := &delivery.DeliveryFilter{
+		Limite:       first + 1, // Buscando uma entrega a mais para saber se existe a próxima página
+		Deslocamento: offset,
+	}
+
+	if args, ok := p.Args["filter"].(map[string]interface{}); ok {
+		filter.Cidade, _ = args["city"].(string)
+		filter.Motorista, _ = args["driver"].(string)
+	}
+
+	if args, ok := p.Args["sort"].(map[string]interface{}); ok {
+		filter.Ordenacao.Campo, _ = args["field"].(delivery.SortField)
+		filter.Ordenacao.Crescente = args["direction"] == "ASC"
+	}
+
+	// Sem resultados a buscar, a listagem não é consultada
+	var deliveries []*delivery.DeliveryResponse
+	if first > 0 {
+		var err error
+		deliveries, err = s.deliveryService.GetDeliveries(p.Context, filter)
+		if err != nil {
+			return nil, toError(err)
+		}
+	}
+
+	hasNextPage := len(deliveries) > first
+	if hasNextPage {
+		deliveries = deliveries[:first]
+	}
+
+	loader := loadersFromContext(p.Context).deliveries
+	edges := make([]map[string]interface{}, 0, len(deliveries))
+
+	for i, d := range deliveries {
+		// As entregas listadas ficam no cache do loader, evitando buscá-las novamente na mesma consulta
+		loader.Prime(d.ID, d)
+		edges = append(edges, map[string]interface{}{
+			"cursor": encodeCursor(offset + i),
+			"node":   d.ToDeliveryResponseV2(),
+		})
+	}
+
+	pageInfo := map[string]interface{}{
+		"hasNextPage":     hasNextPage,
+		"hasPreviousPage": offset > 0,
+	}
+	if len(edges) > 0 {
+		pageInfo["startCursor"] = edges[0]["cursor"]
+		pageInfo["endCursor"] = edges[len(edges)-1]["cursor"]
+	}
+
+	return map[string]interface{}{"edges": edges, "pageInfo": pageInfo}, nil
+}
+
+func (s *Server) resolveCreateDelivery(p graphql.ResolveParams) (interface{}, error) {
+	if err := auth.Authorize(p.Context, s.policies.Create); err != nil {
+		return nil, toError(err)
+	}
+
+	var request delivery.CreateDeliveryRequestV2
+	if err := decodeInput(p.Args["input"], &request); err != nil {
+		return nil, err
+	}
+
+	if err := s.validate(p.Context, &request); err != nil {
+		return nil, err
+	}
+
+	response, err := s.deliveryService.CreateDelivery(p.Context, request.ToCreateDeliveryRequest())
+	if err != nil {
+		return nil, toError(err)
+	}
+
+	return response.ToDeliveryResponseV2(), nil
+}
+
+func (s *Server) resolveUpdateDelivery(p graphql.ResolveParams) (interface{}, error) {
+	if err := auth.Authorize(p.Context, s.policies.Write); err != nil {
+		return nil, toError(err)
+	}
+
+	id, err := deliveryID(p.Args["id"])
+	if err != nil {
+		return nil, err
+	}
+
+	var request delivery.UpdateDeliveryRequestV2
+	if err := decodeInput(p.Args["input"], &request); err != nil {
+		return nil, err
+	}
+
+	if err := s.validate(p.Context, &request); err != nil {
+		return nil, err
+	}
+
+	response, err := s.deliveryService.UpdateDelivery(p.Context, request.ToUpdateDeliveryRequest(), id)
+	if err != nil {
+		return nil, toError(err)
+	}
+
+	return response.ToDeliveryResponseV2(), nil
+}
+
+func (s *Server) resolveDeleteDelivery(p graphql.ResolveParams) (interface{}, error) {
+	if err := auth.Authorize(p.Context, s.policies.Write); err != nil {
+		return nil, toError(err)
+	}
+
+	id, err := deliveryID(p.Args["id"])
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.deliveryService.DeleteDelivery(p.Context, id); err != nil {
+		return nil, toError(err)
+	}
+
+	return id, nil
+}
+
+// Função responsável por converter o argumento do tipo ID, recebido como string, para o ID da entrega.
+func deliveryID(value interface{}) (int, error) {
+	raw, _ := value.(string)
+
+	id, err := strconv.Atoi(raw)
+	if err != nil || id <= 0 {
+		return 0, &Error{Code: CodeBadUserInput, Err: ErrInvalidID}
+	}
+
+	return id, nil
+}
+
+// Função responsável por converter o input da mutation para o request da v2, que possui os mesmos campos.
+func decodeInput(input interface{}, dst interface{}) error {
+	data, err := json.Marshal(input)
+	if err == nil {
+		err = json.Unmarshal(data, dst)
+	}
+	if err != nil {
+		return &Error{Code: CodeBadUserInput, Err: err}
+	}
+	return nil
+}
+
+func encodeCursor(position int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + strconv.Itoa(position)))
+}
+
+// Função responsável por obter a posição da entrega representada pelo cursor.
+func decodeCursor(cursor string) (int, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, &Error{Code: CodeBadUserInput, Err: ErrInvalidCursor}
+	}
+
+	position, err := strconv.Atoi(strings.TrimPrefix(string(decoded), cursorPrefix))
+	if err != nil || position < 0 || !strings.HasPrefix(string(decoded), cursorPrefix) {
+		return 0, &Error{Code: CodeBadUserInput, Err: ErrInvalidCursor}
+	}
+
+	return position, nil
+}
@@ -0,0 +1,219 @@
+package server
+
+import (
+	"github.com/graphql-go/graphql"
+	"github.com/samluiz/delivery-service/internal/delivery"
+)
+
+// Tipos do schema GraphQL. Os campos seguem os nomes da v2 da API HTTP, em camelCase.
+// Os objetos de saída são resolvidos a partir do delivery.DeliveryResponseV2 pelo resolver padrão do graphql-go,
+// que associa cada campo ao campo da struct com o mesmo nome.
+
+var addressType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Address",
+	Description: "Endereço da entrega.",
+	Fields: graphql.Fields{
+		"line":         &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"street":       &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"number":       &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"complement":   &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"neighborhood": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"city":         &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"state":        &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"country":      &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+	},
+})
+
+var coordinatesType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Coordinates",
+	Description: "Coordenadas da entrega.",
+	Fields: graphql.Fields{
+		"latitude":  &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+		"longitude": &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+	},
+})
+
+var deliveryType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Delivery",
+	Description: "Entrega ativa.",
+	Fields: graphql.Fields{
+		"id":          &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+		"customer":    &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"weight":      &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+		"address":     &graphql.Field{Type: graphql.NewNonNull(addressType)},
+		"coordinates": &graphql.Field{Type: graphql.NewNonNull(coordinatesType)},
+		"driver":      &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"createdAt":   &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+		"updatedAt":   &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+	},
+})
+
+// Tipos da paginação no formato de connections do Relay
+
+var pageInfoType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "PageInfo",
+	Fields: graphql.Fields{
+		"hasNextPage":     &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+		"hasPreviousPage": &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+		"startCursor":     &graphql.Field{Type: graphql.String},
+		"endCursor":       &graphql.Field{Type: graphql.String},
+	},
+})
+
+var deliveryEdgeType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "DeliveryEdge",
+	Fields: graphql.Fields{
+		"cursor": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"node":   &graphql.Field{Type: graphql.NewNonNull(deliveryType)},
+	},
+})
+
+var deliveryConnectionType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "DeliveryConnection",
+	Fields: graphql.Fields{
+		"edges":    &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(deliveryEdgeType)))},
+		"pageInfo": &graphql.Field{Type: graphql.NewNonNull(pageInfoType)},
+	},
+})
+
+// Tipos dos argumentos da listagem
+
+var deliveryFilterInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "DeliveryFilter",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"city":   &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"driver": &graphql.InputObjectFieldConfig{Type: graphql.String, Description: "Ignorado para motoristas, que só enxergam as próprias entregas."},
+	},
+})
+
+var deliverySortFieldEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name: "DeliverySortField",
+	Values: graphql.EnumValueConfigMap{
+		"ID":         &graphql.EnumValueConfig{Value: delivery.SortByID},
+		"CUSTOMER":   &graphql.EnumValueConfig{Value: delivery.SortByCliente},
+		"WEIGHT":     &graphql.EnumValueConfig{Value: delivery.SortByPeso},
+		"CITY":       &graphql.EnumValueConfig{Value: delivery.SortByCidade},
+		"CREATED_AT": &graphql.EnumValueConfig{Value: delivery.SortByDataInclusao},
+		"UPDATED_AT": &graphql.EnumValueConfig{Value: delivery.SortByDataAlteracao},
+	},
+})
+
+var sortDirectionEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name: "SortDirection",
+	Values: graphql.EnumValueConfigMap{
+		"ASC":  &graphql.EnumValueConfig{Value: "ASC"},
+		"DESC": &graphql.EnumValueConfig{Value: "DESC"},
+	},
+})
+
+var deliverySortInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "DeliverySort",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"field":     &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(deliverySortFieldEnum)},
+		"direction": &graphql.InputObjectFieldConfig{Type: sortDirectionEnum, DefaultValue: "DESC"},
+	},
+})
+
+// Tipos dos inputs das mutations, com os mesmos campos dos requests da v2
+
+var addressInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "AddressInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"line":         &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		"street":       &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		"number":       &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		"complement":   &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		"neighborhood": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		"city":         &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		"state":        &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		"country":      &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+	},
+})
+
+var coordinatesInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "CoordinatesInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"latitude":  &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.Float)},
+		"longitude": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.Float)},
+	},
+})
+
+var createDeliveryInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "CreateDeliveryInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"customer":    &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		"weight":      &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.Float)},
+		"address":     &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(addressInput)},
+		"coordinates": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(coordinatesInput)},
+		"driver":      &graphql.InputObjectFieldConfig{Type: graphql.String},
+	},
+})
+
+var updateDeliveryInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "UpdateDeliveryInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"weight":      &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.Float)},
+		"address":     &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(addressInput)},
+		"coordinates": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(coordinatesInput)},
+		"driver":      &graphql.InputObjectFieldConfig{Type: graphql.String},
+	},
+})
+
+// Função responsável por montar o schema com as queries e mutations resolvidas pelo servidor.
+func newSchema(s *Server) (graphql.Schema, error) {
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"delivery": &graphql.Field{
+				Type:        deliveryType,
+				Description: "Busca uma entrega ativa pelo ID. Retorna null quando a entrega não existe.",
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: s.resolveDelivery,
+			},
+			"deliveries": &graphql.Field{
+				Type:        graphql.NewNonNull(deliveryConnectionType),
+				Description: "Lista as entregas ativas, da mais recente para a mais antiga quando a ordenação não é informada.",
+				Args: graphql.FieldConfigArgument{
+					"filter": &graphql.ArgumentConfig{Type: deliveryFilterInput},
+					"sort":   &graphql.ArgumentConfig{Type: deliverySortInput},
+					"first":  &graphql.ArgumentConfig{Type: graphql.Int, Description: "Tamanho da página, limitado a 100."},
+					"after":  &graphql.ArgumentConfig{Type: graphql.String, Description: "Cursor da última entrega da página anterior."},
+				},
+				Resolve: s.resolveDeliveries,
+			},
+		},
+	})
+
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createDelivery": &graphql.Field{
+				Type: graphql.NewNonNull(deliveryType),
+				Args: graphql.FieldConfigArgument{
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(createDeliveryInput)},
+				},
+				Resolve: s.resolveCreateDelivery,
+			},
+			"updateDelivery": &graphql.Field{
+				Type: graphql.NewNonNull(deliveryType),
+				Args: graphql.FieldConfigArgument{
+					"id":    &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(updateDeliveryInput)},
+				},
+				Resolve: s.resolveUpdateDelivery,
+			},
+			"deleteDelivery": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.ID),
+				Description: "Move a entrega para a lixeira e retorna o seu ID.",
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: s.resolveDeleteDelivery,
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query, Mutation: mutation})
+}
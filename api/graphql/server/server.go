@@ -0,0 +1,147 @@
+package server
+
+import (
+	"context"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/location"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	"github.com/samluiz/delivery-service/api/http/utils"
+	"github.com/samluiz/delivery-service/internal/auth"
+	"github.com/samluiz/delivery-service/internal/delivery"
+)
+
+// Políticas de acesso das operações da API GraphQL, as mesmas das rotas HTTP equivalentes.
+// Como todas as operações chegam pela mesma rota, cada campo raiz verifica a sua política ao ser resolvido.
+type Policies struct {
+	Read   auth.Policy // Queries delivery e deliveries
+	Create auth.Policy // Mutation createDelivery
+	Write  auth.Policy // Mutations updateDelivery e deleteDelivery
+}
+
+// Requisição GraphQL no formato do GraphQL over HTTP.
+type Request struct {
+	Query         string                 `json:"query" validate:"required"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Struct responsável por validar e executar as operações GraphQL sobre o IDeliveryService.
+type Server struct {
+	deliveryService delivery.IDeliveryService
+	validator       *utils.XValidator
+	policies        Policies
+	limits          Limits
+	schema          graphql.Schema
+}
+
+// Função responsável por instanciar o servidor GraphQL com o schema de entregas.
+// O validator deve ter as regras de domínio das entregas registradas. Retorna erro quando o schema é inválido.
+func NewServer(deliveryService delivery.IDeliveryService, validator *utils.XValidator, policies Policies, limits Limits) (*Server, error) {
+	s := &Server{deliveryService: deliveryService, validator: validator, policies: policies, limits: limits}
+
+	schema, err := newSchema(s)
+	if err != nil {
+		return nil, err
+	}
+	s.schema = schema
+
+	return s, nil
+}
+
+// Função responsável por executar a requisição, com as mensagens de validação no idioma informado.
+// Retorna também se a operação chegou a ser executada: requisições com sintaxe inválida, que não seguem o schema
+// ou que excedem os limites de profundidade e complexidade são rejeitadas antes da execução, sem data.
+func (s *Server) Execute(ctx context.Context, request Request, lang utils.Language) (*graphql.Result, bool) {
+	document, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{Body: []byte(request.Query), Name: "GraphQL request"}),
+	})
+	if err != nil {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}, false
+	}
+
+	if validation := graphql.ValidateDocument(&s.schema, document, nil); !validation.IsValid {
+		return &graphql.Result{Errors: validation.Errors}, false
+	}
+
+	// Operações ambíguas ou inexistentes são rejeitadas pelo executor
+	if operation := selectOperation(document, request.OperationName); operation != nil {
+		if err := s.checkLimits(document, operation, request.Variables); err != nil {
+			return &graphql.Result{Errors: []gqlerrors.FormattedError{{
+				Message:    err.Error(),
+				Locations:  []location.SourceLocation{},
+				Extensions: err.Extensions(),
+			}}}, false
+		}
+	}
+
+	ctx = withLoaders(ctx, newLoaders(s.deliveryService))
+	ctx = context.WithValue(ctx, languageKey, lang)
+
+	return graphql.Execute(graphql.ExecuteParams{
+		Schema:        s.schema,
+		AST:           document,
+		OperationName: request.OperationName,
+		Args:          request.Variables,
+		Context:       ctx,
+	}), true
+}
+
+// Função responsável por verificar se a operação que será executada é uma query, sem validá-la contra o schema.
+// Requisições com sintaxe inválida ou operação ambígua retornam false.
+func IsQuery(request Request) bool {
+	document, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{Body: []byte(request.Query), Name: "GraphQL request"}),
+	})
+	if err != nil {
+		return false
+	}
+
+	operation := selectOperation(document, request.OperationName)
+	return operation != nil && operation.Operation == ast.OperationTypeQuery
+}
+
+// Função responsável por verificar se a operação respeita os limites de profundidade e complexidade.
+func (s *Server) checkLimits(document *ast.Document, operation *ast.OperationDefinition, variables map[string]interface{}) *Error {
+	depth, complexity := analyze(document, operation, variables)
+
+	if s.limits.MaxDepth > 0 && depth > s.limits.MaxDepth {
+		return &Error{Code: CodeQueryTooComplex, Err: ErrQueryTooDeep}
+	}
+	if s.limits.MaxComplexity > 0 && complexity > s.limits.MaxComplexity {
+		return &Error{Code: CodeQueryTooComplex, Err: ErrQueryTooLarge}
+	}
+
+	return nil
+}
+
+// Função responsável por buscar a operação que será executada: a com o nome informado ou a única do documento.
+func selectOperation(document *ast.Document, name string) *ast.OperationDefinition {
+	var selected *ast.OperationDefinition
+
+	for _, definition := range document.Definitions {
+		operation, ok := definition.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+
+		if name == "" {
+			if selected != nil {
+				return nil
+			}
+			selected = operation
+		} else if operation.Name != nil && operation.Name.Value == name {
+			return operation
+		}
+	}
+
+	return selected
+}
+
+func languageFromContext(ctx context.Context) utils.Language {
+	lang, _ := ctx.Value(languageKey).(utils.Language)
+	return lang
+}
@@ -0,0 +1,431 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/samluiz/delivery-service/api/http/utils"
+	"github.com/samluiz/delivery-service/internal/apikey"
+	"github.com/samluiz/delivery-service/internal/auth"
+	"github.com/samluiz/delivery-service/internal/delivery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Função responsável por criar o validator com as regras de domínio das entregas, assim como na inicialização do serviço.
+func newValidator(t *testing.T) *utils.XValidator {
+	t.Helper()
+
+	validator, err := utils.NewXValidator(delivery.DefaultValidationConfig)
+	require.NoError(t, err)
+
+	return validator
+}
+
+// Mock do service de entregas, com apenas os métodos utilizados pela API GraphQL
+
+type MockDeliveryService struct {
+	delivery.IDeliveryService
+	CreateDeliveryFn     func(ctx context.Context, req *delivery.CreateDeliveryRequest) (*delivery.DeliveryResponse, error)
+	GetDeliveriesFn      func(ctx context.Context, filter *delivery.DeliveryFilter) ([]*delivery.DeliveryResponse, error)
+	GetDeliveriesByIDsFn func(ctx context.Context, ids []int) ([]*delivery.DeliveryResponse, error)
+	UpdateDeliveryFn     func(ctx context.Context, req *delivery.UpdateDeliveryRequest, id int) (*delivery.DeliveryResponse, error)
+	DeleteDeliveryFn     func(ctx context.Context, id int) (*delivery.DeliveryResponse, error)
+}
+
+func (m MockDeliveryService) CreateDelivery(ctx context.Context, req *delivery.CreateDeliveryRequest) (*delivery.DeliveryResponse, error) {
+	return m.CreateDeliveryFn(ctx, req)
+}
+
+func (m MockDeliveryService) GetDeliveries(ctx context.Context, filter *delivery.DeliveryFilter) ([]*delivery.DeliveryResponse, error) {
+	return m.GetDeliveriesFn(ctx, filter)
+}
+
+func (m MockDeliveryService) GetDeliveriesByIDs(ctx context.Context, ids []int) ([]*delivery.DeliveryResponse, error) {
+	return m.GetDeliveriesByIDsFn(ctx, ids)
+}
+
+func (m MockDeliveryService) UpdateDelivery(ctx context.Context, req *delivery.UpdateDeliveryRequest, id int) (*delivery.DeliveryResponse, error) {
+	return m.UpdateDeliveryFn(ctx, req, id)
+}
+
+func (m MockDeliveryService) DeleteDelivery(ctx context.Context, id int) (*delivery.DeliveryResponse, error) {
+	return m.DeleteDeliveryFn(ctx, id)
+}
+
+func testDelivery(id int) *delivery.DeliveryResponse {
+	return &delivery.DeliveryResponse{
+		ID: id, Cliente: "Cliente A", Peso: 10.5, Endereco: "Rua A, 123", Logradouro: "Rua A", Numero: "123",
+		Bairro: "Bairro A", Complemento: "Apto 1", Cidade: "Recife", Estado: "PE", Pais: "BR",
+		Latitude: -8.05, Longitude: -34.9, Motorista: "driver-1",
+		DataInclusao: time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC), DataAlteracao: time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func newTestServer(t *testing.T, service delivery.IDeliveryService, limits Limits) *Server {
+	t.Helper()
+
+	policies := Policies{
+		Read:   auth.Policy{Scope: apikey.ScopeRead, Roles: []auth.Role{auth.RoleAdmin}},
+		Create: auth.Policy{Scope: apikey.ScopeWrite, Roles: []auth.Role{auth.RoleAdmin}},
+		Write:  auth.Policy{Scope: apikey.ScopeWrite, Roles: []auth.Role{auth.RoleAdmin}},
+	}
+
+	s, err := NewServer(service, newValidator(t), policies, limits)
+	require.NoError(t, err)
+
+	return s
+}
+
+// Função responsável por criar o contexto autenticado com uma chave de API com os escopos informados.
+func withScopes(scopes ...apikey.Scope) context.Context {
+	ctx := apikey.WithAPIKey(context.Background(), &apikey.APIKey{ID: 1, Escopos: scopes})
+	return auth.WithPrincipal(ctx, &auth.Principal{Type: auth.PrincipalAPIKey, Subject: "apikey:1"})
+}
+
+// Função responsável por executar a requisição e converter o resultado para JSON, como na resposta HTTP.
+func execute(t *testing.T, s *Server, ctx context.Context, query string, variables map[string]interface{}) (map[string]interface{}, bool) {
+	t.Helper()
+
+	result, executed := s.Execute(ctx, Request{Query: query, Variables: variables}, utils.LanguageEnglish)
+
+	data, err := json.Marshal(result)
+	require.NoError(t, err)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &response))
+
+	return response, executed
+}
+
+func errorCode(response map[string]interface{}) string {
+	errors, _ := response["errors"].([]interface{})
+	if len(errors) == 0 {
+		return ""
+	}
+	extensions, _ := errors[0].(map[string]interface{})["extensions"].(map[string]interface{})
+	code, _ := extensions["code"].(string)
+	return code
+}
+
+func TestServer_DeliveryBatchesLookups(t *testing.T) {
+	var calls [][]int
+	s := newTestServer(t, MockDeliveryService{
+		GetDeliveriesByIDsFn: func(ctx context.Context, ids []int) ([]*delivery.DeliveryResponse, error) {
+			calls = append(calls, ids)
+			return []*delivery.DeliveryResponse{testDelivery(1), testDelivery(2)}, nil
+		},
+	}, Limits{})
+
+	response, executed := execute(t, s, withScopes(apikey.ScopeRead), `{
+		a: delivery(id: "1") { id customer address { city } coordinates { latitude } createdAt }
+		b: delivery(id: "2") { id }
+		c: delivery(id: "3") { id }
+		d: delivery(id: "1") { weight }
+	}`, nil)
+
+	assert.True(t, executed)
+	assert.Nil(t, response["errors"])
+
+	// Todos os IDs da consulta são buscados em uma única chamada, sem repetir o ID 1
+	require.Len(t, calls, 1)
+	assert.ElementsMatch(t, []int{1, 2, 3}, calls[0])
+
+	data := response["data"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{
+		"id":          "1",
+		"customer":    "Cliente A",
+		"address":     map[string]interface{}{"city": "Recife"},
+		"coordinates": map[string]interface{}{"latitude": -8.05},
+		"createdAt":   "2024-05-10T12:00:00Z",
+	}, data["a"])
+	assert.Equal(t, map[string]interface{}{"id": "2"}, data["b"])
+	assert.Nil(t, data["c"])
+	assert.Equal(t, map[string]interface{}{"weight": 10.5}, data["d"])
+}
+
+func TestServer_DeliveriesConnection(t *testing.T) {
+	var filters []*delivery.DeliveryFilter
+	var lookups int
+	s := newTestServer(t, MockDeliveryService{
+		GetDeliveriesFn: func(ctx context.Context, filter *delivery.DeliveryFilter) ([]*delivery.DeliveryResponse, error) {
+			filters = append(filters, filter)
+			return []*delivery.DeliveryResponse{testDelivery(5), testDelivery(4), testDelivery(3)}, nil
+		},
+		GetDeliveriesByIDsFn: func(ctx context.Context, ids []int) ([]*delivery.DeliveryResponse, error) {
+			lookups++
+			return nil, nil
+		},
+	}, Limits{})
+
+	query := `query($after: String) {
+		deliveries(filter: {city: "Recife"}, sort: {field: WEIGHT, direction: ASC}, first: 2, after: $after) {
+			edges { cursor node { id } }
+			pageInfo { hasNextPage hasPreviousPage startCursor endCursor }
+		}
+		delivery(id: "5") { id }
+	}`
+
+	response, _ := execute(t, s, withScopes(apikey.ScopeRead), query, nil)
+	require.Nil(t, response["errors"])
+
+	connection := response["data"].(map[string]interface{})["deliveries"].(map[string]interface{})
+	edges := connection["edges"].([]interface{})
+	pageInfo := connection["pageInfo"].(map[string]interface{})
+
+	require.Len(t, edges, 2)
+	assert.Equal(t, "5", edges[0].(map[string]interface{})["node"].(map[string]interface{})["id"])
+	assert.Equal(t, true, pageInfo["hasNextPage"])
+	assert.Equal(t, false, pageInfo["hasPreviousPage"])
+	assert.Equal(t, edges[1].(map[string]interface{})["cursor"], pageInfo["endCursor"])
+
+	assert.Equal(t, &delivery.DeliveryFilter{
+		Cidade:    "Recife",
+		Limite:    3,
+		Ordenacao: delivery.DeliverySort{Campo: delivery.SortByPeso, Crescente: true},
+	}, filters[0])
+
+	// A entrega listada é reaproveitada pelo campo delivery da mesma consulta
+	assert.Equal(t, map[string]interface{}{"id": "5"}, response["data"].(map[string]interface{})["delivery"])
+	assert.Zero(t, lookups)
+
+	// A próxima página começa após o cursor da última entrega
+	response, _ = execute(t, s, withScopes(apikey.ScopeRead), query, map[string]interface{}{"after": pageInfo["endCursor"]})
+	require.Nil(t, response["errors"])
+
+	assert.Equal(t, 2, filters[1].Deslocamento)
+	pageInfo = response["data"].(map[string]interface{})["deliveries"].(map[string]interface{})["pageInfo"].(map[string]interface{})
+	assert.Equal(t, true, pageInfo["hasPreviousPage"])
+
+	// Cursores inválidos
+	response, _ = execute(t, s, withScopes(apikey.ScopeRead), query, map[string]interface{}{"after": "invalid"})
+	assert.Equal(t, CodeBadUserInput, errorCode(response))
+}
+
+const createMutation = `mutation($input: CreateDeliveryInput!) {
+	createDelivery(input: $input) { id customer address { state } }
+}`
+
+func createInput(state string) map[string]interface{} {
+	return map[string]interface{}{"input": map[string]interface{}{
+		"customer": "Cliente A",
+		"weight":   10.5,
+		"address": map[string]interface{}{
+			"line": "Rua A, 123", "street": "Rua A", "number": "123", "complement": "Apto 1",
+			"neighborhood": "Bairro A", "city": "Recife", "state": state, "country": "BR",
+		},
+		"coordinates": map[string]interface{}{"latitude": 0, "longitude": -34.9},
+	}}
+}
+
+func TestServer_CreateDelivery(t *testing.T) {
+	var received *delivery.CreateDeliveryRequest
+	s := newTestServer(t, MockDeliveryService{
+		CreateDeliveryFn: func(ctx context.Context, req *delivery.CreateDeliveryRequest) (*delivery.DeliveryResponse, error) {
+			received = req
+			return testDelivery(1), nil
+		},
+	}, Limits{})
+
+	response, _ := execute(t, s, withScopes(apikey.ScopeWrite), createMutation, createInput("PE"))
+
+	require.Nil(t, response["errors"])
+	assert.Equal(t, map[string]interface{}{"id": "1", "customer": "Cliente A", "address": map[string]interface{}{"state": "PE"}},
+		response["data"].(map[string]interface{})["createDelivery"])
+	assert.Equal(t, "Rua A", received.Logradouro)
+	assert.Equal(t, -34.9, *received.Longitude)
+}
+
+func TestServer_CreateDeliveryValidation(t *testing.T) {
+	s := newTestServer(t, MockDeliveryService{}, Limits{})
+
+	response, executed := execute(t, s, withScopes(apikey.ScopeWrite), createMutation, createInput("Pernambuco"))
+
+	assert.True(t, executed)
+	assert.Equal(t, CodeBadUserInput, errorCode(response))
+
+	extensions := response["errors"].([]interface{})[0].(map[string]interface{})["extensions"].(map[string]interface{})
+	assert.Equal(t, []interface{}{map[string]interface{}{
+		"campo":    "address.state",
+		"regra":    "uf",
+		"mensagem": "state must be a Brazilian state code (e.g. SP)",
+	}}, extensions["fields"])
+}
+
+func TestServer_UpdateAndDeleteDelivery(t *testing.T) {
+	var updatedID, deletedID int
+	s := newTestServer(t, MockDeliveryService{
+		UpdateDeliveryFn: func(ctx context.Context, req *delivery.UpdateDeliveryRequest, id int) (*delivery.DeliveryResponse, error) {
+			updatedID = id
+			return testDelivery(id), nil
+		},
+		DeleteDeliveryFn: func(ctx context.Context, id int) (*delivery.DeliveryResponse, error) {
+			deletedID = id
+			if id == 9 {
+				return nil, delivery.ErrDeliveryNotFound
+			}
+			return testDelivery(id), nil
+		},
+	}, Limits{})
+
+	input := createInput("PE")["input"].(map[string]interface{})
+	delete(input, "customer")
+
+	response, _ := execute(t, s, withScopes(apikey.ScopeWrite), `mutation($input: UpdateDeliveryInput!) {
+		updateDelivery(id: "7", input: $input) { id }
+	}`, map[string]interface{}{"input": input})
+	require.Nil(t, response["errors"])
+	assert.Equal(t, 7, updatedID)
+
+	response, _ = execute(t, s, withScopes(apikey.ScopeWrite), `mutation { deleteDelivery(id: "7") }`, nil)
+	require.Nil(t, response["errors"])
+	assert.Equal(t, "7", response["data"].(map[string]interface{})["deleteDelivery"])
+	assert.Equal(t, 7, deletedID)
+
+	response, _ = execute(t, s, withScopes(apikey.ScopeWrite), `mutation { deleteDelivery(id: "9") }`, nil)
+	assert.Equal(t, CodeNotFound, errorCode(response))
+
+	response, _ = execute(t, s, withScopes(apikey.ScopeWrite), `mutation { deleteDelivery(id: "abc") }`, nil)
+	assert.Equal(t, CodeBadUserInput, errorCode(response))
+}
+
+func TestServer_Authorization(t *testing.T) {
+	s := newTestServer(t, MockDeliveryService{}, Limits{})
+
+	tests := []struct {
+		name         string
+		ctx          context.Context
+		query        string
+		expectedCode string
+	}{
+		{"unauthenticated query", context.Background(), `{ delivery(id: "1") { id } }`, CodeUnauthenticated},
+		{"read key deleting", withScopes(apikey.ScopeRead), `mutation { deleteDelivery(id: "1") }`, CodeForbidden},
+		{"write key without read", withScopes(apikey.ScopeWrite), `{ deliveries { edges { cursor } } }`, CodeForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, executed := execute(t, s, tt.ctx, tt.query, nil)
+
+			assert.True(t, executed)
+			assert.Equal(t, tt.expectedCode, errorCode(response))
+		})
+	}
+}
+
+func TestServer_Limits(t *testing.T) {
+	s := newTestServer(t, MockDeliveryService{
+		GetDeliveriesFn: func(ctx context.Context, filter *delivery.DeliveryFilter) ([]*delivery.DeliveryResponse, error) {
+			return nil, nil
+		},
+	}, Limits{MaxDepth: 4, MaxComplexity: 200})
+
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]interface{}
+		executed  bool
+	}{
+		{
+			name:     "within limits",
+			query:    `{ deliveries(first: 10) { edges { node { id customer weight } } } }`,
+			executed: true,
+		},
+		{
+			name:  "too deep",
+			query: `{ deliveries { edges { node { address { city } } } } }`,
+		},
+		{
+			name:  "too complex",
+			query: `{ deliveries(first: 100) { edges { node { id customer weight } } } }`,
+		},
+		{
+			name:      "too complex through variables",
+			query:     `query($first: Int) { deliveries(first: $first) { edges { node { id customer weight } } } }`,
+			variables: map[string]interface{}{"first": float64(100)},
+		},
+		{
+			name:  "too complex through variable defaults",
+			query: `query($first: Int = 100) { deliveries(first: $first) { edges { node { id customer weight } } } }`,
+		},
+		{
+			name:  "too complex through fragments",
+			query: `{ a: deliveries(first: 30) { ...page } b: deliveries(first: 30) { ...page } } fragment page on DeliveryConnection { edges { node { id customer } } }`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, executed := execute(t, s, withScopes(apikey.ScopeRead), tt.query, tt.variables)
+
+			assert.Equal(t, tt.executed, executed)
+			if !tt.executed {
+				assert.Equal(t, CodeQueryTooComplex, errorCode(response))
+				assert.Nil(t, response["data"])
+			}
+		})
+	}
+}
+
+func TestServer_InvalidQuery(t *testing.T) {
+	s := newTestServer(t, MockDeliveryService{}, Limits{})
+
+	for _, query := range []string{`{ delivery(id: "1") {`, `{ delivery(id: "1") { unknown } }`} {
+		result, executed := s.Execute(withScopes(apikey.ScopeRead), Request{Query: query}, utils.LanguageEnglish)
+
+		assert.False(t, executed)
+		assert.NotEmpty(t, result.Errors)
+	}
+}
+
+func TestIsQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		request  Request
+		expected bool
+	}{
+		{"query", Request{Query: `{ delivery(id: "1") { id } }`}, true},
+		{"named query", Request{Query: `query Get { delivery(id: "1") { id } }`}, true},
+		{"mutation", Request{Query: `mutation { deleteDelivery(id: "1") }`}, false},
+		{"selected mutation", Request{Query: `query Get { delivery(id: "1") { id } } mutation Delete { deleteDelivery(id: "1") }`, OperationName: "Delete"}, false},
+		{"selected query", Request{Query: `query Get { delivery(id: "1") { id } } mutation Delete { deleteDelivery(id: "1") }`, OperationName: "Get"}, true},
+		{"ambiguous operation", Request{Query: `query Get { delivery(id: "1") { id } } mutation Delete { deleteDelivery(id: "1") }`}, false},
+		{"syntax error", Request{Query: `{ delivery(id: "1") {`}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, IsQuery(tt.request))
+		})
+	}
+}
+
+func TestLoader(t *testing.T) {
+	var batches [][]string
+	loader := NewLoader(func(ctx context.Context, keys []string) (map[string]int, error) {
+		batches = append(batches, keys)
+		return map[string]int{"a": 1, "b": 2}, nil
+	})
+
+	ctx := context.Background()
+	a, b, c := loader.Load(ctx, "a"), loader.Load(ctx, "b"), loader.Load(ctx, "c")
+
+	value, found, err := a()
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, 1, value)
+
+	value, found, _ = b()
+	assert.True(t, found)
+	assert.Equal(t, 2, value)
+
+	_, found, _ = c()
+	assert.False(t, found)
+
+	// Chaves em cache não são buscadas novamente
+	value, _, _ = loader.Load(ctx, "a")()
+	assert.Equal(t, 1, value)
+	assert.Equal(t, [][]string{{"a", "b", "c"}}, batches)
+}
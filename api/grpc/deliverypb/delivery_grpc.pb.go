@@ -0,0 +1,337 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: delivery.proto
+
+package deliverypb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	DeliveryService_CreateDelivery_FullMethodName  = "/delivery.v1.DeliveryService/CreateDelivery"
+	DeliveryService_GetDelivery_FullMethodName     = "/delivery.v1.DeliveryService/GetDelivery"
+	DeliveryService_ListDeliveries_FullMethodName  = "/delivery.v1.DeliveryService/ListDeliveries"
+	DeliveryService_UpdateDelivery_FullMethodName  = "/delivery.v1.DeliveryService/UpdateDelivery"
+	DeliveryService_DeleteDelivery_FullMethodName  = "/delivery.v1.DeliveryService/DeleteDelivery"
+	DeliveryService_WatchDeliveries_FullMethodName = "/delivery.v1.DeliveryService/WatchDeliveries"
+)
+
+// DeliveryServiceClient is the client API for DeliveryService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type DeliveryServiceClient interface {
+	// Cria uma entrega.
+	CreateDelivery(ctx context.Context, in *CreateDeliveryRequest, opts ...grpc.CallOption) (*Delivery, error)
+	// Busca uma entrega ativa pelo ID.
+	GetDelivery(ctx context.Context, in *GetDeliveryRequest, opts ...grpc.CallOption) (*Delivery, error)
+	// Lista as entregas ativas que satisfazem os filtros, da mais recente para a mais antiga.
+	ListDeliveries(ctx context.Context, in *ListDeliveriesRequest, opts ...grpc.CallOption) (*ListDeliveriesResponse, error)
+	// Atualiza uma entrega ativa.
+	UpdateDelivery(ctx context.Context, in *UpdateDeliveryRequest, opts ...grpc.CallOption) (*Delivery, error)
+	// Move uma entrega para a lixeira.
+	DeleteDelivery(ctx context.Context, in *DeleteDeliveryRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Acompanha as mudanças de status das entregas que satisfazem os filtros.
+	// Apenas as mudanças ocorridas após a inscrição são enviadas.
+	WatchDeliveries(ctx context.Context, in *WatchDeliveriesRequest, opts ...grpc.CallOption) (DeliveryService_WatchDeliveriesClient, error)
+}
+
+type deliveryServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDeliveryServiceClient(cc grpc.ClientConnInterface) DeliveryServiceClient {
+	return &deliveryServiceClient{cc}
+}
+
+func (c *deliveryServiceClient) CreateDelivery(ctx context.Context, in *CreateDeliveryRequest, opts ...grpc.CallOption) (*Delivery, error) {
+	out := new(Delivery)
+	err := c.cc.Invoke(ctx, DeliveryService_CreateDelivery_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deliveryServiceClient) GetDelivery(ctx context.Context, in *GetDeliveryRequest, opts ...grpc.CallOption) (*Delivery, error) {
+	out := new(Delivery)
+	err := c.cc.Invoke(ctx, DeliveryService_GetDelivery_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deliveryServiceClient) ListDeliveries(ctx context.Context, in *ListDeliveriesRequest, opts ...grpc.CallOption) (*ListDeliveriesResponse, error) {
+	out := new(ListDeliveriesResponse)
+	err := c.cc.Invoke(ctx, DeliveryService_ListDeliveries_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deliveryServiceClient) UpdateDelivery(ctx context.Context, in *UpdateDeliveryRequest, opts ...grpc.CallOption) (*Delivery, error) {
+	out := new(Delivery)
+	err := c.cc.Invoke(ctx, DeliveryService_UpdateDelivery_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deliveryServiceClient) DeleteDelivery(ctx context.Context, in *DeleteDeliveryRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, DeliveryService_DeleteDelivery_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deliveryServiceClient) WatchDeliveries(ctx context.Context, in *WatchDeliveriesRequest, opts ...grpc.CallOption) (DeliveryService_WatchDeliveriesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &DeliveryService_ServiceDesc.Streams[0], DeliveryService_WatchDeliveries_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &deliveryServiceWatchDeliveriesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DeliveryService_WatchDeliveriesClient interface {
+	Recv() (*DeliveryEvent, error)
+	grpc.ClientStream
+}
+
+type deliveryServiceWatchDeliveriesClient struct {
+	grpc.ClientStream
+}
+
+func (x *deliveryServiceWatchDeliveriesClient) Recv() (*DeliveryEvent, error) {
+	m := new(DeliveryEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// DeliveryServiceServer is the server API for DeliveryService service.
+// All implementations must embed UnimplementedDeliveryServiceServer
+// for forward compatibility
+type DeliveryServiceServer interface {
+	// Cria uma entrega.
+	CreateDelivery(context.Context, *CreateDeliveryRequest) (*Delivery, error)
+	// Busca uma entrega ativa pelo ID.
+	GetDelivery(context.Context, *GetDeliveryRequest) (*Delivery, error)
+	// Lista as entregas ativas que satisfazem os filtros, da mais recente para a mais antiga.
+	ListDeliveries(context.Context, *ListDeliveriesRequest) (*ListDeliveriesResponse, error)
+	// Atualiza uma entrega ativa.
+	UpdateDelivery(context.Context, *UpdateDeliveryRequest) (*Delivery, error)
+	// Move uma entrega para a lixeira.
+	DeleteDelivery(context.Context, *DeleteDeliveryRequest) (*emptypb.Empty, error)
+	// Acompanha as mudanças de status das entregas que satisfazem os filtros.
+	// Apenas as mudanças ocorridas após a inscrição são enviadas.
+	WatchDeliveries(*WatchDeliveriesRequest, DeliveryService_WatchDeliveriesServer) error
+	mustEmbedUnimplementedDeliveryServiceServer()
+}
+
+// UnimplementedDeliveryServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedDeliveryServiceServer struct {
+}
+
+func (UnimplementedDeliveryServiceServer) CreateDelivery(context.Context, *CreateDeliveryRequest) (*Delivery, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateDelivery not implemented")
+}
+func (UnimplementedDeliveryServiceServer) GetDelivery(context.Context, *GetDeliveryRequest) (*Delivery, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDelivery not implemented")
+}
+func (UnimplementedDeliveryServiceServer) ListDeliveries(context.Context, *ListDeliveriesRequest) (*ListDeliveriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDeliveries not implemented")
+}
+func (UnimplementedDeliveryServiceServer) UpdateDelivery(context.Context, *UpdateDeliveryRequest) (*Delivery, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateDelivery not implemented")
+}
+func (UnimplementedDeliveryServiceServer) DeleteDelivery(context.Context, *DeleteDeliveryRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteDelivery not implemented")
+}
+func (UnimplementedDeliveryServiceServer) WatchDeliveries(*WatchDeliveriesRequest, DeliveryService_WatchDeliveriesServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchDeliveries not implemented")
+}
+func (UnimplementedDeliveryServiceServer) mustEmbedUnimplementedDeliveryServiceServer() {}
+
+// UnsafeDeliveryServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DeliveryServiceServer will
+// result in compilation errors.
+type UnsafeDeliveryServiceServer interface {
+	mustEmbedUnimplementedDeliveryServiceServer()
+}
+
+func RegisterDeliveryServiceServer(s grpc.ServiceRegistrar, srv DeliveryServiceServer) {
+	s.RegisterService(&DeliveryService_ServiceDesc, srv)
+}
+
+func _DeliveryService_CreateDelivery_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateDeliveryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeliveryServiceServer).CreateDelivery(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeliveryService_CreateDelivery_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeliveryServiceServer).CreateDelivery(ctx, req.(*CreateDeliveryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeliveryService_GetDelivery_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDeliveryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeliveryServiceServer).GetDelivery(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeliveryService_GetDelivery_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeliveryServiceServer).GetDelivery(ctx, req.(*GetDeliveryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeliveryService_ListDeliveries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDeliveriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeliveryServiceServer).ListDeliveries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeliveryService_ListDeliveries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeliveryServiceServer).ListDeliveries(ctx, req.(*ListDeliveriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeliveryService_UpdateDelivery_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateDeliveryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeliveryServiceServer).UpdateDelivery(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeliveryService_UpdateDelivery_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeliveryServiceServer).UpdateDelivery(ctx, req.(*UpdateDeliveryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeliveryService_DeleteDelivery_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteDeliveryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeliveryServiceServer).DeleteDelivery(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeliveryService_DeleteDelivery_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeliveryServiceServer).DeleteDelivery(ctx, req.(*DeleteDeliveryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeliveryService_WatchDeliveries_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchDeliveriesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DeliveryServiceServer).WatchDeliveries(m, &deliveryServiceWatchDeliveriesServer{stream})
+}
+
+type DeliveryService_WatchDeliveriesServer interface {
+	Send(*DeliveryEvent) error
+	grpc.ServerStream
+}
+
+type deliveryServiceWatchDeliveriesServer struct {
+	grpc.ServerStream
+}
+
+func (x *deliveryServiceWatchDeliveriesServer) Send(m *DeliveryEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// DeliveryService_ServiceDesc is the grpc.ServiceDesc for DeliveryService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var DeliveryService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "delivery.v1.DeliveryService",
+	HandlerType: (*DeliveryServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateDelivery",
+			Handler:    _DeliveryService_CreateDelivery_Handler,
+		},
+		{
+			MethodName: "GetDelivery",
+			Handler:    _DeliveryService_GetDelivery_Handler,
+		},
+		{
+			MethodName: "ListDeliveries",
+			Handler:    _DeliveryService_ListDeliveries_Handler,
+		},
+		{
+			MethodName: "UpdateDelivery",
+			Handler:    _DeliveryService_UpdateDelivery_Handler,
+		},
+		{
+			MethodName: "DeleteDelivery",
+			Handler:    _DeliveryService_DeleteDelivery_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchDeliveries",
+			Handler:       _DeliveryService_WatchDeliveries_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "delivery.proto",
+}
@@ -0,0 +1,5 @@
+// Código gerado a partir de api/grpc/proto/delivery.proto.
+// Para regenerar, é necessário o protoc com os plugins protoc-gen-go e protoc-gen-go-grpc instalados.
+package deliverypb
+
+//go:generate protoc --proto_path=../proto --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative delivery.proto
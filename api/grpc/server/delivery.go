@@ -0,0 +1,197 @@
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"strconv"
+
+	"github.com/samluiz/delivery-service/api/grpc/deliverypb"
+	"github.com/samluiz/delivery-service/api/http/utils"
+	"github.com/samluiz/delivery-service/internal/delivery"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const (
+	DefaultPageSize = 50  // Tamanho da página quando page_size não é informado
+	MaxPageSize     = 500 // Tamanho máximo da página
+)
+
+// Struct que implementa a API gRPC de entregas sobre o IDeliveryService.
+type DeliveryServer struct {
+	deliverypb.UnimplementedDeliveryServiceServer
+	deliveryService delivery.IDeliveryService
+	events          *delivery.EventBroker
+	validator       *utils.XValidator
+}
+
+// Função responsável por instanciar a API gRPC de entregas.
+// O broker deve ser o mesmo utilizado pelo service (delivery.WithEvents) para que as escritas sejam acompanhadas,
+// e o validator deve ter as regras de domínio das entregas registradas.
+func NewDeliveryServer(deliveryService delivery.IDeliveryService, events *delivery.EventBroker, validator *utils.XValidator) *DeliveryServer {
+	return &DeliveryServer{deliveryService: deliveryService, events: events, validator: validator}
+}
+
+func (s *DeliveryServer) CreateDelivery(ctx context.Context, request *deliverypb.CreateDeliveryRequest) (*deliverypb.Delivery, error) {
+	createRequest := toCreateDeliveryRequestV2(request)
+
+	if err := s.validate(ctx, createRequest); err != nil {
+		return nil, err
+	}
+
+	response, err := s.deliveryService.CreateDelivery(ctx, createRequest.ToCreateDeliveryRequest())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return toDeliveryProto(response), nil
+}
+
+func (s *DeliveryServer) GetDelivery(ctx context.Context, request *deliverypb.GetDeliveryRequest) (*deliverypb.Delivery, error) {
+	id, err := deliveryID(request.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.deliveryService.GetDelivery(ctx, id)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return toDeliveryProto(response), nil
+}
+
+// Lista as entregas com paginação por cursor: o token da próxima página é o ID da última entrega retornada.
+func (s *DeliveryServer) ListDeliveries(ctx context.Context, request *deliverypb.ListDeliveriesRequest) (*deliverypb.ListDeliveriesResponse, error) {
+	pageSize := int(request.GetPageSize())
+	switch {
+	case pageSize < 0:
+		return nil, status.Error(codes.InvalidArgument, ErrInvalidPageSize.Error())
+	case pageSize == 0:
+		pageSize = DefaultPageSize
+	case pageSize > MaxPageSize:
+		pageSize = MaxPageSize
+	}
+
+	after, err := decodePageToken(request.GetPageToken())
+	if err != nil {
+		return nil, err
+	}
+
+	// Buscando uma entrega a mais para saber se existe a próxima página
+	deliveries, err := s.deliveryService.GetDeliveries(ctx, &delivery.DeliveryFilter{
+		Cidade:    request.GetCity(),
+		Motorista: request.GetDriver(),
+		AposID:    after,
+		Limite:    pageSize + 1,
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	response := &deliverypb.ListDeliveriesResponse{}
+
+	if len(deliveries) > pageSize {
+		deliveries = deliveries[:pageSize]
+		response.NextPageToken = encodePageToken(deliveries[pageSize-1].ID)
+	}
+
+	response.Deliveries = make([]*deliverypb.Delivery, 0, len(deliveries))
+	for _, d := range deliveries {
+		response.Deliveries = append(response.Deliveries, toDeliveryProto(d))
+	}
+
+	return response, nil
+}
+
+func (s *DeliveryServer) UpdateDelivery(ctx context.Context, request *deliverypb.UpdateDeliveryRequest) (*deliverypb.Delivery, error) {
+	id, err := deliveryID(request.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	updateRequest := toUpdateDeliveryRequestV2(request)
+
+	if err := s.validate(ctx, updateRequest); err != nil {
+		return nil, err
+	}
+
+	response, err := s.deliveryService.UpdateDelivery(ctx, updateRequest.ToUpdateDeliveryRequest(), id)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return toDeliveryProto(response), nil
+}
+
+func (s *DeliveryServer) DeleteDelivery(ctx context.Context, request *deliverypb.DeleteDeliveryRequest) (*emptypb.Empty, error) {
+	id, err := deliveryID(request.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.deliveryService.DeleteDelivery(ctx, id); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// Envia as mudanças das entregas até o cliente encerrar a chamada.
+// Clientes que não consomem os eventos a tempo têm a chamada encerrada com RESOURCE_EXHAUSTED e devem se inscrever novamente.
+func (s *DeliveryServer) WatchDeliveries(request *deliverypb.WatchDeliveriesRequest, stream deliverypb.DeliveryService_WatchDeliveriesServer) error {
+	if request.GetId() < 0 {
+		return status.Error(codes.InvalidArgument, ErrInvalidID.Error())
+	}
+
+	subscription := s.events.Subscribe(stream.Context(), delivery.EventFilter{
+		ID:        int(request.GetId()),
+		Cidade:    request.GetCity(),
+		Motorista: request.GetDriver(),
+	})
+
+	// Enviando os headers para indicar ao cliente que a inscrição foi registrada
+	if err := stream.SendHeader(nil); err != nil {
+		return err
+	}
+
+	for event := range subscription.Events() {
+		if err := stream.Send(toDeliveryEventProto(event)); err != nil {
+			return err
+		}
+	}
+
+	return toStatus(subscription.Err())
+}
+
+// Função responsável por validar o ID recebido, como o parâmetro {id} da API HTTP.
+func deliveryID(id int64) (int, error) {
+	if id <= 0 {
+		return 0, status.Error(codes.InvalidArgument, ErrInvalidID.Error())
+	}
+	return int(id), nil
+}
+
+func encodePageToken(id int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(id)))
+}
+
+// Função responsável por obter o ID da última entrega da página anterior, zero para a primeira página.
+func decodePageToken(token string) (int, error) {
+	if token == "" {
+		return 0, nil
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, status.Error(codes.InvalidArgument, ErrInvalidPageToken.Error())
+	}
+
+	id, err := strconv.Atoi(string(decoded))
+	if err != nil || id <= 0 {
+		return 0, status.Error(codes.InvalidArgument, ErrInvalidPageToken.Error())
+	}
+
+	return id, nil
+}
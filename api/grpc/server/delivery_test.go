@@ -0,0 +1,529 @@
+package server
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/samluiz/delivery-service/api/grpc/deliverypb"
+	"github.com/samluiz/delivery-service/api/http/middleware"
+	"github.com/samluiz/delivery-service/api/http/utils"
+	"github.com/samluiz/delivery-service/internal/apikey"
+	"github.com/samluiz/delivery-service/internal/auth"
+	"github.com/samluiz/delivery-service/internal/concurrency"
+	"github.com/samluiz/delivery-service/internal/delivery"
+	"github.com/samluiz/delivery-service/internal/ratelimit"
+	"github.com/samluiz/delivery-service/internal/requestmeta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// Função responsável por criar o validator com as regras de domínio das entregas, assim como na inicialização do serviço.
+func newValidator(t *testing.T) *utils.XValidator {
+	t.Helper()
+
+	validator, err := utils.NewXValidator(delivery.DefaultValidationConfig)
+	require.NoError(t, err)
+
+	return validator
+}
+
+// Mock do service de entregas, com apenas os métodos utilizados pela API gRPC
+
+type MockDeliveryService struct {
+	delivery.IDeliveryService
+	CreateDeliveryFn func(ctx context.Context, req *delivery.CreateDeliveryRequest) (*delivery.DeliveryResponse, error)
+	GetDeliveryFn    func(ctx context.Context, id int) (*delivery.DeliveryResponse, error)
+	GetDeliveriesFn  func(ctx context.Context, filter *delivery.DeliveryFilter) ([]*delivery.DeliveryResponse, error)
+	UpdateDeliveryFn func(ctx context.Context, req *delivery.UpdateDeliveryRequest, id int) (*delivery.DeliveryResponse, error)
+	DeleteDeliveryFn func(ctx context.Context, id int) (*delivery.DeliveryResponse, error)
+}
+
+func (m MockDeliveryService) CreateDelivery(ctx context.Context, req *delivery.CreateDeliveryRequest) (*delivery.DeliveryResponse, error) {
+	return m.CreateDeliveryFn(ctx, req)
+}
+
+func (m MockDeliveryService) GetDelivery(ctx context.Context, id int) (*delivery.DeliveryResponse, error) {
+	return m.GetDeliveryFn(ctx, id)
+}
+
+func (m MockDeliveryService) GetDeliveries(ctx context.Context, filter *delivery.DeliveryFilter) ([]*delivery.DeliveryResponse, error) {
+	return m.GetDeliveriesFn(ctx, filter)
+}
+
+func (m MockDeliveryService) UpdateDelivery(ctx context.Context, req *delivery.UpdateDeliveryRequest, id int) (*delivery.DeliveryResponse, error) {
+	return m.UpdateDeliveryFn(ctx, req, id)
+}
+
+func (m MockDeliveryService) DeleteDelivery(ctx context.Context, id int) (*delivery.DeliveryResponse, error) {
+	return m.DeleteDeliveryFn(ctx, id)
+}
+
+// Mock do service de chaves de API
+
+type MockAPIKeyService struct {
+	apikey.IAPIKeyService
+}
+
+func (m MockAPIKeyService) Authenticate(ctx context.Context, rawKey string) (*apikey.APIKey, error) {
+	switch rawKey {
+	case "dsk_read":
+		return &apikey.APIKey{ID: 1, Escopos: []apikey.Scope{apikey.ScopeRead}}, nil
+	case "dsk_write":
+		return &apikey.APIKey{ID: 2, Escopos: []apikey.Scope{apikey.ScopeRead, apikey.ScopeWrite}}, nil
+	}
+	return nil, apikey.ErrAPIKeyInvalid
+}
+
+var testResponse = &delivery.DeliveryResponse{
+	ID: 1, Cliente: "Cliente A", Peso: 10.5, Endereco: "Rua A, 123", Logradouro: "Rua A", Numero: "123",
+	Bairro: "Bairro A", Complemento: "Apto 1", Cidade: "Recife", Estado: "PE", Pais: "BR",
+	Latitude: -8.05, Longitude: -34.9, Motorista: "driver-1",
+	DataInclusao: time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC), DataAlteracao: time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC),
+}
+
+// Função responsável por iniciar o servidor em memória (bufconn) e retornar o cliente com a chave de API informada.
+func newTestClient(t *testing.T, service delivery.IDeliveryService, events *delivery.EventBroker) deliverypb.DeliveryServiceClient {
+	t.Helper()
+	return newTestClientWithLimits(t, service, events, Limits{})
+}
+
+// Função responsável por iniciar o servidor em memória com os limites informados.
+func newTestClientWithLimits(t *testing.T, service delivery.IDeliveryService, events *delivery.EventBroker, limits Limits) deliverypb.DeliveryServiceClient {
+	t.Helper()
+
+	policies := Policies{
+		Read:   auth.Policy{Scope: apikey.ScopeRead, Roles: []auth.Role{auth.RoleAdmin}},
+		Create: auth.Policy{Scope: apikey.ScopeWrite, Roles: []auth.Role{auth.RoleAdmin}},
+		Write:  auth.Policy{Scope: apikey.ScopeWrite, Roles: []auth.Role{auth.RoleAdmin}},
+	}
+	srv := NewServer(middleware.NewAuthenticator(MockAPIKeyService{}, nil), policies, limits, NewDeliveryServer(service, events, newValidator(t)))
+
+	listener := bufconn.Listen(1024 * 1024)
+	go srv.Serve(listener)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return deliverypb.NewDeliveryServiceClient(conn)
+}
+
+func withAPIKey(key string, pairs ...string) context.Context {
+	return metadata.NewOutgoingContext(context.Background(), metadata.Pairs(append([]string{"x-api-key", key}, pairs...)...))
+}
+
+func validCreateRequest() *deliverypb.CreateDeliveryRequest {
+	return &deliverypb.CreateDeliveryRequest{
+		Customer: "Cliente A",
+		Weight:   10.5,
+		Address: &deliverypb.Address{
+			Line: "Rua A, 123", Street: "Rua A", Number: "123", Complement: "Apto 1",
+			Neighborhood: "Bairro A", City: "Recife", State: "PE", Country: "BR",
+		},
+		Coordinates: &deliverypb.Coordinates{Latitude: 0, Longitude: -34.9},
+	}
+}
+
+func TestDeliveryServer_CreateDelivery(t *testing.T) {
+	var received *delivery.CreateDeliveryRequest
+	var metadata requestmeta.Metadata
+	client := newTestClient(t, MockDeliveryService{
+		CreateDeliveryFn: func(ctx context.Context, req *delivery.CreateDeliveryRequest) (*delivery.DeliveryResponse, error) {
+			received = req
+			metadata, _ = requestmeta.FromContext(ctx)
+			return testResponse, nil
+		},
+	}, nil)
+
+	response, err := client.CreateDelivery(withAPIKey("dsk_write", "x-request-id", "req-1"), validCreateRequest())
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), response.GetId())
+	assert.Equal(t, "Recife", response.GetAddress().GetCity())
+	assert.Equal(t, -8.05, response.GetCoordinates().GetLatitude())
+	assert.True(t, response.GetCreatedAt().AsTime().Equal(testResponse.DataInclusao))
+	assert.Nil(t, response.GetDeletedAt())
+
+	// O request é convertido para o do service, com as coordenadas informadas (inclusive zero)
+	assert.Equal(t, "Cliente A", received.Cliente)
+	assert.Equal(t, "PE", received.Estado)
+	assert.Equal(t, 0.0, *received.Latitude)
+	assert.Equal(t, -34.9, *received.Longitude)
+
+	assert.Equal(t, "req-1", metadata.RequestID)
+	assert.NotEmpty(t, metadata.ClientIP)
+}
+
+func TestDeliveryServer_CreateDelivery_Validation(t *testing.T) {
+	client := newTestClient(t, MockDeliveryService{}, nil)
+
+	request := validCreateRequest()
+	request.Weight = 0
+	request.Address.State = "XX"
+	request.Coordinates.Latitude = 91
+
+	_, err := client.CreateDelivery(withAPIKey("dsk_write", "accept-language", "en"), request)
+
+	st := status.Convert(err)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	require.Len(t, st.Details(), 1)
+
+	badRequest, ok := st.Details()[0].(*errdetails.BadRequest)
+	require.True(t, ok)
+
+	fields := map[string]string{}
+	for _, violation := range badRequest.GetFieldViolations() {
+		fields[violation.GetField()] = violation.GetDescription()
+	}
+	assert.Equal(t, map[string]string{
+		"weight":               "weight is a required field",
+		"address.state":        "state must be a Brazilian state code (e.g. SP)",
+		"coordinates.latitude": "latitude must contain valid latitude coordinates",
+	}, fields)
+}
+
+func TestDeliveryServer_GetDelivery(t *testing.T) {
+	client := newTestClient(t, MockDeliveryService{
+		GetDeliveryFn: func(ctx context.Context, id int) (*delivery.DeliveryResponse, error) {
+			if id != 1 {
+				return nil, delivery.ErrDeliveryNotFound
+			}
+			return testResponse, nil
+		},
+	}, nil)
+
+	response, err := client.GetDelivery(withAPIKey("dsk_read"), &deliverypb.GetDeliveryRequest{Id: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "Cliente A", response.GetCustomer())
+
+	_, err = client.GetDelivery(withAPIKey("dsk_read"), &deliverypb.GetDeliveryRequest{Id: 2})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.GetDelivery(withAPIKey("dsk_read"), &deliverypb.GetDeliveryRequest{Id: 0})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestDeliveryServer_ListDeliveries(t *testing.T) {
+	var filters []delivery.DeliveryFilter
+	client := newTestClient(t, MockDeliveryService{
+		GetDeliveriesFn: func(ctx context.Context, filter *delivery.DeliveryFilter) ([]*delivery.DeliveryResponse, error) {
+			filters = append(filters, *filter)
+
+			// Entregas de ID 5 a 1, da mais recente para a mais antiga
+			deliveries := make([]*delivery.DeliveryResponse, 0)
+			for id := 5; id > 0 && len(deliveries) < filter.Limite; id-- {
+				if filter.AposID == 0 || id < filter.AposID {
+					d := *testResponse
+					d.ID = id
+					deliveries = append(deliveries, &d)
+				}
+			}
+			return deliveries, nil
+		},
+	}, nil)
+
+	ids := func(response *deliverypb.ListDeliveriesResponse) []int64 {
+		result := make([]int64, 0)
+		for _, d := range response.GetDeliveries() {
+			result = append(result, d.GetId())
+		}
+		return result
+	}
+
+	first, err := client.ListDeliveries(withAPIKey("dsk_read"), &deliverypb.ListDeliveriesRequest{City: "Recife", Driver: "driver-1", PageSize: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{5, 4}, ids(first))
+	assert.NotEmpty(t, first.GetNextPageToken())
+
+	second, err := client.ListDeliveries(withAPIKey("dsk_read"), &deliverypb.ListDeliveriesRequest{PageSize: 2, PageToken: first.GetNextPageToken()})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{3, 2}, ids(second))
+
+	last, err := client.ListDeliveries(withAPIKey("dsk_read"), &deliverypb.ListDeliveriesRequest{PageSize: 2, PageToken: second.GetNextPageToken()})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1}, ids(last))
+	assert.Empty(t, last.GetNextPageToken())
+
+	assert.Equal(t, delivery.DeliveryFilter{Cidade: "Recife", Motorista: "driver-1", Limite: 3}, filters[0])
+	assert.Equal(t, 4, filters[1].AposID)
+
+	// Sem page_size é utilizado o tamanho padrão
+	_, err = client.ListDeliveries(withAPIKey("dsk_read"), &deliverypb.ListDeliveriesRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, DefaultPageSize+1, filters[3].Limite)
+
+	_, err = client.ListDeliveries(withAPIKey("dsk_read"), &deliverypb.ListDeliveriesRequest{PageToken: "invalid"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.ListDeliveries(withAPIKey("dsk_read"), &deliverypb.ListDeliveriesRequest{PageSize: -1})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestDeliveryServer_UpdateDelivery(t *testing.T) {
+	client := newTestClient(t, MockDeliveryService{
+		UpdateDeliveryFn: func(ctx context.Context, req *delivery.UpdateDeliveryRequest, id int) (*delivery.DeliveryResponse, error) {
+			if id != 1 {
+				return nil, delivery.ErrDeliveryNotFound
+			}
+			return testResponse, nil
+		},
+	}, nil)
+
+	create := validCreateRequest()
+	request := &deliverypb.UpdateDeliveryRequest{Id: 1, Weight: 2, Address: create.Address, Coordinates: create.Coordinates}
+
+	response, err := client.UpdateDelivery(withAPIKey("dsk_write"), request)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), response.GetId())
+
+	request.Id = 2
+	_, err = client.UpdateDelivery(withAPIKey("dsk_write"), request)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	request.Address = nil
+	_, err = client.UpdateDelivery(withAPIKey("dsk_write"), request)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestDeliveryServer_DeleteDelivery(t *testing.T) {
+	client := newTestClient(t, MockDeliveryService{
+		DeleteDeliveryFn: func(ctx context.Context, id int) (*delivery.DeliveryResponse, error) {
+			if id != 1 {
+				return nil, delivery.ErrDeliveryNotFound
+			}
+			return testResponse, nil
+		},
+	}, nil)
+
+	_, err := client.DeleteDelivery(withAPIKey("dsk_write"), &deliverypb.DeleteDeliveryRequest{Id: 1})
+	assert.NoError(t, err)
+
+	_, err = client.DeleteDelivery(withAPIKey("dsk_write"), &deliverypb.DeleteDeliveryRequest{Id: 2})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestDeliveryServer_Authentication(t *testing.T) {
+	client := newTestClient(t, MockDeliveryService{
+		GetDeliveryFn: func(ctx context.Context, id int) (*delivery.DeliveryResponse, error) {
+			return testResponse, nil
+		},
+	}, nil)
+
+	tests := []struct {
+		name         string
+		ctx          context.Context
+		call         func(ctx context.Context) error
+		expectedCode codes.Code
+	}{
+		{
+			name: "missing credentials",
+			ctx:  context.Background(),
+			call: func(ctx context.Context) error {
+				_, err := client.GetDelivery(ctx, &deliverypb.GetDeliveryRequest{Id: 1})
+				return err
+			},
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name: "invalid api key",
+			ctx:  withAPIKey("dsk_unknown"),
+			call: func(ctx context.Context) error {
+				_, err := client.GetDelivery(ctx, &deliverypb.GetDeliveryRequest{Id: 1})
+				return err
+			},
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name: "api key in authorization",
+			ctx:  metadata.NewOutgoingContext(context.Background(), metadata.Pairs("authorization", "Bearer dsk_read")),
+			call: func(ctx context.Context) error {
+				_, err := client.GetDelivery(ctx, &deliverypb.GetDeliveryRequest{Id: 1})
+				return err
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name: "jwt not configured",
+			ctx:  metadata.NewOutgoingContext(context.Background(), metadata.Pairs("authorization", "Bearer eyJ.token")),
+			call: func(ctx context.Context) error {
+				_, err := client.GetDelivery(ctx, &deliverypb.GetDeliveryRequest{Id: 1})
+				return err
+			},
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name: "missing scope",
+			ctx:  withAPIKey("dsk_read"),
+			call: func(ctx context.Context) error {
+				_, err := client.DeleteDelivery(ctx, &deliverypb.DeleteDeliveryRequest{Id: 1})
+				return err
+			},
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			name: "missing scope on stream",
+			ctx:  withAPIKey("dsk_unknown"),
+			call: func(ctx context.Context) error {
+				stream, err := client.WatchDeliveries(ctx, &deliverypb.WatchDeliveriesRequest{})
+				if err != nil {
+					return err
+				}
+				_, err = stream.Recv()
+				return err
+			},
+			expectedCode: codes.Unauthenticated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedCode, status.Code(tt.call(tt.ctx)))
+		})
+	}
+}
+
+func TestDeliveryServer_RateLimit(t *testing.T) {
+	service := MockDeliveryService{
+		GetDeliveryFn: func(ctx context.Context, id int) (*delivery.DeliveryResponse, error) {
+			return testResponse, nil
+		},
+	}
+
+	t.Run("limit per client after authentication", func(t *testing.T) {
+		client := newTestClientWithLimits(t, service, nil, Limits{
+			RateLimit: middleware.NewRateLimiter(ratelimit.NewMemoryStore(), middleware.RateLimitOptions{
+				Default: ratelimit.Limit{Requests: 1, Period: time.Hour},
+			}),
+		})
+
+		var header metadata.MD
+		_, err := client.GetDelivery(withAPIKey("dsk_read"), &deliverypb.GetDeliveryRequest{Id: 1}, grpc.Header(&header))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"1"}, header.Get(middleware.RateLimitLimitHeader))
+		assert.Equal(t, []string{"0"}, header.Get(middleware.RateLimitRemainingHeader))
+
+		_, err = client.GetDelivery(withAPIKey("dsk_read"), &deliverypb.GetDeliveryRequest{Id: 1}, grpc.Header(&header))
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+		assert.Equal(t, []string{"3600"}, header.Get("retry-after"))
+
+		// Outra API key possui o próprio balde
+		_, err = client.GetDelivery(withAPIKey("dsk_write"), &deliverypb.GetDeliveryRequest{Id: 1})
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid credentials are limited before authentication", func(t *testing.T) {
+		client := newTestClientWithLimits(t, service, nil, Limits{
+			RateLimit: middleware.NewRateLimiter(ratelimit.NewMemoryStore(), middleware.RateLimitOptions{
+				Unauthenticated: ratelimit.Limit{Requests: 2, Period: time.Hour},
+			}),
+		})
+
+		codesReceived := make([]codes.Code, 0, 3)
+		for i := 0; i < 3; i++ {
+			_, err := client.GetDelivery(withAPIKey("dsk_unknown"), &deliverypb.GetDeliveryRequest{Id: 1})
+			codesReceived = append(codesReceived, status.Code(err))
+		}
+		assert.Equal(t, []codes.Code{codes.Unauthenticated, codes.Unauthenticated, codes.ResourceExhausted}, codesReceived)
+
+		stream, err := client.WatchDeliveries(withAPIKey("dsk_read"), &deliverypb.WatchDeliveriesRequest{})
+		require.NoError(t, err)
+		_, err = stream.Recv()
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	})
+}
+
+func TestDeliveryServer_Concurrency(t *testing.T) {
+	events := delivery.NewEventBroker(10)
+	limiter := concurrency.NewLimiter(concurrency.Options{InitialLimit: 1, MinLimit: 1, MaxLimit: 1, MaxQueue: 0, MaxWait: time.Second})
+	shedder := middleware.NewLoadShedder(limiter, 3*time.Second)
+
+	client := newTestClientWithLimits(t, MockDeliveryService{
+		GetDeliveryFn: func(ctx context.Context, id int) (*delivery.DeliveryResponse, error) {
+			return testResponse, nil
+		},
+	}, events, Limits{Concurrency: shedder})
+
+	// A stream libera a vaga ao enviar os headers, sem ocupá-la enquanto aguarda os eventos
+	ctx, cancel := context.WithCancel(withAPIKey("dsk_read"))
+	defer cancel()
+	stream, err := client.WatchDeliveries(ctx, &deliverypb.WatchDeliveriesRequest{})
+	require.NoError(t, err)
+	_, err = stream.Header()
+	require.NoError(t, err)
+
+	_, err = client.GetDelivery(withAPIKey("dsk_read"), &deliverypb.GetDeliveryRequest{Id: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, limiter.InFlight())
+
+	// Com a única vaga ocupada, a chamada é descartada
+	release, err := shedder.Acquire(context.Background(), concurrency.ClassRead)
+	require.NoError(t, err)
+	defer release()
+
+	var header metadata.MD
+	_, err = client.GetDelivery(withAPIKey("dsk_read"), &deliverypb.GetDeliveryRequest{Id: 1}, grpc.Header(&header))
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Equal(t, []string{"3"}, header.Get("retry-after"))
+}
+
+func TestDeliveryServer_WatchDeliveries(t *testing.T) {
+	events := delivery.NewEventBroker(10)
+	service := delivery.WithEvents(MockDeliveryService{
+		CreateDeliveryFn: func(ctx context.Context, req *delivery.CreateDeliveryRequest) (*delivery.DeliveryResponse, error) {
+			return testResponse, nil
+		},
+		DeleteDeliveryFn: func(ctx context.Context, id int) (*delivery.DeliveryResponse, error) {
+			deleted := *testResponse
+			deletedAt := time.Now()
+			deleted.DataExclusao = &deletedAt
+			return &deleted, nil
+		},
+	}, events)
+	client := newTestClient(t, service, events)
+
+	ctx, cancel := context.WithCancel(withAPIKey("dsk_write"))
+	defer cancel()
+
+	stream, err := client.WatchDeliveries(ctx, &deliverypb.WatchDeliveriesRequest{City: "Recife"})
+	require.NoError(t, err)
+
+	// Os headers são enviados após a inscrição ser registrada
+	_, err = stream.Header()
+	require.NoError(t, err)
+
+	_, err = client.CreateDelivery(withAPIKey("dsk_write"), validCreateRequest())
+	require.NoError(t, err)
+	_, err = client.DeleteDelivery(withAPIKey("dsk_write"), &deliverypb.DeleteDeliveryRequest{Id: 1})
+	require.NoError(t, err)
+
+	created, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, deliverypb.DeliveryAction_DELIVERY_ACTION_CREATE, created.GetAction())
+	assert.Equal(t, deliverypb.DeliveryStatus_DELIVERY_STATUS_ACTIVE, created.GetStatus())
+	assert.Equal(t, int64(1), created.GetDelivery().GetId())
+
+	deleted, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, deliverypb.DeliveryAction_DELIVERY_ACTION_DELETE, deleted.GetAction())
+	assert.Equal(t, deliverypb.DeliveryStatus_DELIVERY_STATUS_DELETED, deleted.GetStatus())
+	assert.NotNil(t, deleted.GetDelivery().GetDeletedAt())
+
+	cancel()
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Canceled, status.Code(err))
+}
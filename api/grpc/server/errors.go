@@ -0,0 +1,63 @@
+package server
+
+import (
+	"context"
+	"errors"
+
+	"github.com/samluiz/delivery-service/api/http/utils"
+	"github.com/samluiz/delivery-service/internal/delivery"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	ErrInvalidID        = errors.New("id must be a positive integer")
+	ErrInvalidPageToken = errors.New("page token is invalid")
+	ErrInvalidPageSize  = errors.New("page size must not be negative")
+)
+
+// Função responsável por converter os erros do service para os códigos gRPC equivalentes aos status da API HTTP.
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, delivery.ErrDeliveryNotFound), errors.Is(err, delivery.ErrVersionNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, delivery.ErrSubscriptionLagging):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	}
+
+	return status.Error(codes.Internal, err.Error())
+}
+
+// Função responsável por criar o erro de argumento inválido com a falha de cada campo nos detalhes (BadRequest).
+// Os campos e as mensagens são os mesmos da validação da API HTTP, no idioma do metadado accept-language.
+func (s *DeliveryServer) validationStatus(ctx context.Context, errs []utils.ValidationError) error {
+	lang := utils.LanguageFromHeader(incomingValue(ctx, "accept-language"))
+	fields := s.validator.NewFieldErrors(lang, errs)
+
+	violations := make([]*errdetails.BadRequest_FieldViolation, 0, len(fields))
+	for _, field := range fields {
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{Field: field.Campo, Description: field.Mensagem})
+	}
+
+	st := status.New(codes.InvalidArgument, fields[0].Campo+": "+fields[0].Mensagem)
+	if detailed, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: violations}); err == nil {
+		st = detailed
+	}
+
+	return st.Err()
+}
+
+// Função responsável por validar o request com as regras da API HTTP.
+func (s *DeliveryServer) validate(ctx context.Context, request any) error {
+	errs, err := s.validator.Validate(request)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if len(errs) > 0 {
+		return s.validationStatus(ctx, errs)
+	}
+	return nil
+}
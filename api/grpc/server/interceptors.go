@@ -0,0 +1,220 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/samluiz/delivery-service/api/http/middleware"
+	"github.com/samluiz/delivery-service/internal/auth"
+	"github.com/samluiz/delivery-service/internal/concurrency"
+	"github.com/samluiz/delivery-service/internal/ratelimit"
+	"github.com/samluiz/delivery-service/internal/requestmeta"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Chave dos metadados utilizada para propagar o identificador da requisição.
+var requestIDKey = strings.ToLower(middleware.RequestIDHeader)
+
+// Stream com o contexto substituído pelos interceptors.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s contextStream) Context() context.Context {
+	return s.ctx
+}
+
+func unaryRequestMetadata(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	return handler(withRequestMetadata(ctx), req)
+}
+
+func streamRequestMetadata(srv any, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, contextStream{ServerStream: stream, ctx: withRequestMetadata(stream.Context())})
+}
+
+// Função responsável por identificar a chamada e o cliente que a originou, como o middleware RequestMetadata da API HTTP.
+// O x-request-id recebido é reaproveitado quando válido, caso contrário um novo é gerado, e ele é sempre devolvido nos headers da resposta.
+func withRequestMetadata(ctx context.Context) context.Context {
+	requestID := incomingValue(ctx, requestIDKey)
+	if !middleware.ValidRequestID(requestID) {
+		requestID = middleware.NewRequestID()
+	}
+
+	grpc.SetHeader(ctx, metadata.Pairs(requestIDKey, requestID))
+
+	var clientIP string
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		clientIP = p.Addr.String()
+		if host, _, err := net.SplitHostPort(clientIP); err == nil {
+			clientIP = host
+		}
+	}
+
+	return requestmeta.WithMetadata(ctx, requestmeta.Metadata{RequestID: requestID, ClientIP: clientIP})
+}
+
+func unaryAuthentication(authenticator *middleware.Authenticator, policies map[string]auth.Policy) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticate(ctx, authenticator, policies, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func streamAuthentication(authenticator *middleware.Authenticator, policies map[string]auth.Policy) grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(stream.Context(), authenticator, policies, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, contextStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+// Função responsável por autenticar a chamada com a política do método.
+// As credenciais são enviadas nos metadados x-api-key ou authorization, com os mesmos formatos dos headers HTTP.
+// Métodos sem política são negados.
+func authenticate(ctx context.Context, authenticator *middleware.Authenticator, policies map[string]auth.Policy, method string) (context.Context, error) {
+	policy, ok := policies[method]
+	if !ok {
+		return nil, status.Errorf(codes.PermissionDenied, "method %s has no access policy", method)
+	}
+
+	ctx, err := authenticator.Authenticate(ctx, incomingValue(ctx, "x-api-key"), incomingValue(ctx, "authorization"), policy)
+
+	var authError *middleware.AuthError
+	switch {
+	case err == nil:
+		return ctx, nil
+	case !errors.As(err, &authError):
+		return nil, status.Error(codes.Internal, err.Error())
+	case authError.Status == http.StatusForbidden:
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	default:
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+}
+
+// Função responsável por buscar o primeiro valor da chave nos metadados recebidos.
+func incomingValue(ctx context.Context, key string) string {
+	values := metadata.ValueFromIncomingContext(ctx, key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// Função que consome um token do balde da chamada, como o RateLimiter.Take e o RateLimiter.TakeUnauthenticated.
+type rateLimitTake func(ctx context.Context, method string) (ratelimit.Result, bool)
+
+func unaryRateLimit(take rateLimitTake) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := rateLimit(ctx, take, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func streamRateLimit(take rateLimitTake) grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := rateLimit(stream.Context(), take, info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, stream)
+	}
+}
+
+// Função responsável por limitar a chamada com os mesmos baldes da API HTTP, retornando ResourceExhausted quando o
+// balde está vazio. Os headers do rate limiting são devolvidos nos metadados, com os nomes em minúsculas.
+func rateLimit(ctx context.Context, take rateLimitTake, method string) error {
+	result, ok := take(ctx, method)
+	if !ok {
+		return nil
+	}
+
+	md := metadata.MD{}
+	for name, value := range middleware.RateLimitHeaders(result) {
+		md.Set(name, value)
+	}
+	grpc.SetHeader(ctx, md)
+
+	if !result.Allowed {
+		return status.Error(codes.ResourceExhausted, middleware.RateLimitError(result).Error())
+	}
+	return nil
+}
+
+func unaryConcurrency(shedder *middleware.LoadShedder, classes map[string]concurrency.Class) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		release, err := acquire(ctx, shedder, classes, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+
+		return handler(ctx, req)
+	}
+}
+
+// A vaga das streams é liberada quando a stream envia os headers ou a primeira mensagem, para que as inscrições
+// de longa duração (ex: WatchDeliveries) não ocupem o limite enquanto aguardam os eventos.
+func streamConcurrency(shedder *middleware.LoadShedder, classes map[string]concurrency.Class) grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		release, err := acquire(stream.Context(), shedder, classes, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		// A função de liberação do limitador pode ser chamada mais de uma vez
+		defer release()
+
+		return handler(srv, releaseStream{ServerStream: stream, release: release})
+	}
+}
+
+// Função responsável por aguardar uma vaga no limite de concorrência da API HTTP, com a classe do método.
+// Chamadas recusadas recebem UNAVAILABLE com o metadado retry-after; métodos sem classe são da classe write.
+func acquire(ctx context.Context, shedder *middleware.LoadShedder, classes map[string]concurrency.Class, method string) (func(), error) {
+	class, ok := classes[method]
+	if !ok {
+		class = concurrency.ClassWrite
+	}
+
+	release, err := shedder.Acquire(ctx, class)
+	switch {
+	case err == nil:
+		return release, nil
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return nil, status.FromContextError(err).Err()
+	}
+
+	grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.Itoa(shedder.RetryAfter())))
+	return nil, status.Error(codes.Unavailable, err.Error())
+}
+
+// Stream que libera a vaga do limite de concorrência ao enviar os headers ou a primeira mensagem.
+type releaseStream struct {
+	grpc.ServerStream
+	release func()
+}
+
+func (s releaseStream) SendHeader(md metadata.MD) error {
+	s.release()
+	return s.ServerStream.SendHeader(md)
+}
+
+func (s releaseStream) SendMsg(m any) error {
+	s.release()
+	return s.ServerStream.SendMsg(m)
+}
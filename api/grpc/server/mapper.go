@@ -0,0 +1,104 @@
+package server
+
+import (
+	"github.com/samluiz/delivery-service/api/grpc/deliverypb"
+	"github.com/samluiz/delivery-service/internal/audit"
+	"github.com/samluiz/delivery-service/internal/delivery"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Conversão entre as mensagens do protobuf e os tipos do service.
+// Os requests são convertidos para os da v2 da API HTTP, que possuem os mesmos campos, para compartilhar a validação.
+
+var actions = map[audit.Action]deliverypb.DeliveryAction{
+	audit.ActionCreate:  deliverypb.DeliveryAction_DELIVERY_ACTION_CREATE,
+	audit.ActionUpdate:  deliverypb.DeliveryAction_DELIVERY_ACTION_UPDATE,
+	audit.ActionDelete:  deliverypb.DeliveryAction_DELIVERY_ACTION_DELETE,
+	audit.ActionRestore: deliverypb.DeliveryAction_DELIVERY_ACTION_RESTORE,
+	audit.ActionRevert:  deliverypb.DeliveryAction_DELIVERY_ACTION_REVERT,
+}
+
+func toAddressV2(address *deliverypb.Address) delivery.AddressV2 {
+	return delivery.AddressV2{
+		Line:         address.GetLine(),
+		Street:       address.GetStreet(),
+		Number:       address.GetNumber(),
+		Complement:   address.GetComplement(),
+		Neighborhood: address.GetNeighborhood(),
+		City:         address.GetCity(),
+		State:        address.GetState(),
+		Country:      address.GetCountry(),
+	}
+}
+
+func toCoordinatesV2(coordinates *deliverypb.Coordinates) *delivery.CoordinatesV2 {
+	if coordinates == nil {
+		return nil
+	}
+
+	latitude, longitude := coordinates.GetLatitude(), coordinates.GetLongitude()
+	return &delivery.CoordinatesV2{Latitude: &latitude, Longitude: &longitude}
+}
+
+func toCreateDeliveryRequestV2(request *deliverypb.CreateDeliveryRequest) *delivery.CreateDeliveryRequestV2 {
+	return &delivery.CreateDeliveryRequestV2{
+		Customer:    request.GetCustomer(),
+		Weight:      request.GetWeight(),
+		Address:     toAddressV2(request.GetAddress()),
+		Coordinates: toCoordinatesV2(request.GetCoordinates()),
+		Driver:      request.GetDriver(),
+	}
+}
+
+func toUpdateDeliveryRequestV2(request *deliverypb.UpdateDeliveryRequest) *delivery.UpdateDeliveryRequestV2 {
+	return &delivery.UpdateDeliveryRequestV2{
+		Weight:      request.GetWeight(),
+		Address:     toAddressV2(request.GetAddress()),
+		Coordinates: toCoordinatesV2(request.GetCoordinates()),
+		Driver:      request.GetDriver(),
+	}
+}
+
+func toDeliveryProto(response *delivery.DeliveryResponse) *deliverypb.Delivery {
+	v2 := response.ToDeliveryResponseV2()
+
+	message := &deliverypb.Delivery{
+		Id:       int64(v2.ID),
+		Customer: v2.Customer,
+		Weight:   v2.Weight,
+		Address: &deliverypb.Address{
+			Line:         v2.Address.Line,
+			Street:       v2.Address.Street,
+			Number:       v2.Address.Number,
+			Complement:   v2.Address.Complement,
+			Neighborhood: v2.Address.Neighborhood,
+			City:         v2.Address.City,
+			State:        v2.Address.State,
+			Country:      v2.Address.Country,
+		},
+		Coordinates: &deliverypb.Coordinates{Latitude: *v2.Coordinates.Latitude, Longitude: *v2.Coordinates.Longitude},
+		Driver:      v2.Driver,
+		CreatedAt:   timestamppb.New(v2.CreatedAt),
+		UpdatedAt:   timestamppb.New(v2.UpdatedAt),
+	}
+
+	if v2.DeletedAt != nil {
+		message.DeletedAt = timestamppb.New(*v2.DeletedAt)
+	}
+
+	return message
+}
+
+func toDeliveryEventProto(event delivery.Event) *deliverypb.DeliveryEvent {
+	status := deliverypb.DeliveryStatus_DELIVERY_STATUS_ACTIVE
+	if event.Excluida() {
+		status = deliverypb.DeliveryStatus_DELIVERY_STATUS_DELETED
+	}
+
+	return &deliverypb.DeliveryEvent{
+		Action:     actions[event.Acao],
+		Status:     status,
+		Delivery:   toDeliveryProto(event.Entrega),
+		OccurredAt: timestamppb.New(event.DataEvento),
+	}
+}
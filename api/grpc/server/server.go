@@ -0,0 +1,81 @@
+package server
+
+import (
+	"context"
+
+	"github.com/samluiz/delivery-service/api/grpc/deliverypb"
+	"github.com/samluiz/delivery-service/api/http/middleware"
+	"github.com/samluiz/delivery-service/internal/auth"
+	"github.com/samluiz/delivery-service/internal/concurrency"
+	"github.com/samluiz/delivery-service/internal/ratelimit"
+	"google.golang.org/grpc"
+)
+
+// Políticas de acesso dos métodos da API gRPC, as mesmas das rotas HTTP equivalentes.
+type Policies struct {
+	Read   auth.Policy // Consultas e acompanhamento das entregas
+	Create auth.Policy // Criação de entregas
+	Write  auth.Policy // Atualização e exclusão de entregas
+}
+
+// Limites aplicados às chamadas, compartilhados com a API HTTP. Limites nulos não são aplicados.
+type Limits struct {
+	RateLimit   *middleware.RateLimiter // Limite de requisições por IP antes da autenticação e por cliente após
+	Concurrency *middleware.LoadShedder // Limite de concorrência, antes da autenticação como na API HTTP
+}
+
+// Função responsável por instanciar o servidor gRPC com a API de entregas registrada.
+// Todas as chamadas são identificadas (metadados da requisição) e autenticadas com as mesmas credenciais da API HTTP.
+func NewServer(authenticator *middleware.Authenticator, policies Policies, limits Limits, deliveries deliverypb.DeliveryServiceServer, options ...grpc.ServerOption) *grpc.Server {
+	methodPolicies := map[string]auth.Policy{
+		deliverypb.DeliveryService_CreateDelivery_FullMethodName:  policies.Create,
+		deliverypb.DeliveryService_GetDelivery_FullMethodName:     policies.Read,
+		deliverypb.DeliveryService_ListDeliveries_FullMethodName:  policies.Read,
+		deliverypb.DeliveryService_UpdateDelivery_FullMethodName:  policies.Write,
+		deliverypb.DeliveryService_DeleteDelivery_FullMethodName:  policies.Write,
+		deliverypb.DeliveryService_WatchDeliveries_FullMethodName: policies.Read,
+	}
+
+	// Classe de cada método no limite de concorrência, pela política: consultas são read e alterações write
+	methodClasses := map[string]concurrency.Class{
+		deliverypb.DeliveryService_CreateDelivery_FullMethodName:  concurrency.ClassWrite,
+		deliverypb.DeliveryService_GetDelivery_FullMethodName:     concurrency.ClassRead,
+		deliverypb.DeliveryService_ListDeliveries_FullMethodName:  concurrency.ClassRead,
+		deliverypb.DeliveryService_UpdateDelivery_FullMethodName:  concurrency.ClassWrite,
+		deliverypb.DeliveryService_DeleteDelivery_FullMethodName:  concurrency.ClassWrite,
+		deliverypb.DeliveryService_WatchDeliveries_FullMethodName: concurrency.ClassRead,
+	}
+
+	unary := []grpc.UnaryServerInterceptor{unaryRequestMetadata}
+	stream := []grpc.StreamServerInterceptor{streamRequestMetadata}
+
+	// O limite por IP fica antes da autenticação, para que as credenciais inválidas também sejam contabilizadas
+	if limits.RateLimit != nil {
+		unauthenticated := func(ctx context.Context, _ string) (ratelimit.Result, bool) {
+			return limits.RateLimit.TakeUnauthenticated(ctx)
+		}
+		unary = append(unary, unaryRateLimit(unauthenticated))
+		stream = append(stream, streamRateLimit(unauthenticated))
+	}
+
+	// O limite de concorrência fica antes da autenticação, para que a consulta das API keys também aguarde uma vaga
+	if limits.Concurrency != nil {
+		unary = append(unary, unaryConcurrency(limits.Concurrency, methodClasses))
+		stream = append(stream, streamConcurrency(limits.Concurrency, methodClasses))
+	}
+
+	unary = append(unary, unaryAuthentication(authenticator, methodPolicies))
+	stream = append(stream, streamAuthentication(authenticator, methodPolicies))
+
+	if limits.RateLimit != nil {
+		unary = append(unary, unaryRateLimit(limits.RateLimit.Take))
+		stream = append(stream, streamRateLimit(limits.RateLimit.Take))
+	}
+
+	options = append(options, grpc.ChainUnaryInterceptor(unary...), grpc.ChainStreamInterceptor(stream...))
+
+	srv := grpc.NewServer(options...)
+	deliverypb.RegisterDeliveryServiceServer(srv, deliveries)
+
+	return srv
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/samluiz/delivery-service/api/http/utils"
+	"github.com/samluiz/delivery-service/internal/apikey"
+)
+
+type APIKeyHandler struct {
+	apiKeyService apikey.IAPIKeyService
+	validator     *utils.XValidator
+}
+
+func NewAPIKeyHandler(apiKeyService apikey.IAPIKeyService, validator *utils.XValidator) *APIKeyHandler {
+	return &APIKeyHandler{apiKeyService: apiKeyService, validator: validator}
+}
+
+func (h APIKeyHandler) HandleCreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	var request apikey.CreateAPIKeyRequest
+
+	// Serializando o request body para o struct
+	if decodeError := utils.DecodeJSONBody(w, r, &request); decodeError != nil {
+		utils.NewJSONResponse(w, decodeError.Status, decodeError)
+		return
+	}
+
+	// Validando o request body
+	validationError := h.validator.ValidateBody(r, &request)
+
+	if validationError != nil {
+		utils.NewJSONResponse(w, http.StatusBadRequest, validationError)
+		return
+	}
+
+	response, err := h.apiKeyService.CreateAPIKey(r.Context(), &request)
+
+	if err != nil {
+		utils.NewJSONResponse(w, http.StatusInternalServerError, utils.NewInternalServerError(err, r))
+		return
+	}
+
+	utils.NewJSONResponse(w, http.StatusCreated, response)
+}
+
+func (h APIKeyHandler) HandleGetAPIKeys(w http.ResponseWriter, r *http.Request) {
+	response, err := h.apiKeyService.GetAPIKeys(r.Context())
+
+	if err != nil {
+		utils.NewJSONResponse(w, http.StatusInternalServerError, utils.NewInternalServerError(err, r))
+		return
+	}
+
+	utils.NewJSONResponse(w, http.StatusOK, response)
+}
+
+func (h APIKeyHandler) HandleRevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	// Buscando o ID da chave no path
+	id, err := strconv.Atoi(r.PathValue("id"))
+
+	if err != nil {
+		utils.NewJSONResponse(w, http.StatusBadRequest, utils.NewBadRequestError(err, r))
+		return
+	}
+
+	err = h.apiKeyService.RevokeAPIKey(r.Context(), id)
+
+	if err != nil {
+		// Verificando se o erro aconteceu por não encontrar a chave
+		if errors.Is(err, apikey.ErrAPIKeyNotFound) {
+			utils.NewJSONResponse(w, http.StatusNotFound, utils.NewNotFoundError(err, r))
+			return
+		}
+		utils.NewJSONResponse(w, http.StatusInternalServerError, utils.NewInternalServerError(err, r))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/samluiz/delivery-service/internal/apikey"
+	"github.com/stretchr/testify/assert"
+)
+
+// Mocks do service de chaves de API
+
+type MockAPIKeyService struct {
+	CreateAPIKeyFn func(req *apikey.CreateAPIKeyRequest) (*apikey.CreateAPIKeyResponse, error)
+	GetAPIKeysFn   func() ([]*apikey.APIKeyResponse, error)
+	RevokeAPIKeyFn func(id int) error
+}
+
+func (m MockAPIKeyService) CreateAPIKey(ctx context.Context, req *apikey.CreateAPIKeyRequest) (*apikey.CreateAPIKeyResponse, error) {
+	return m.CreateAPIKeyFn(req)
+}
+
+func (m MockAPIKeyService) GetAPIKeys(ctx context.Context) ([]*apikey.APIKeyResponse, error) {
+	return m.GetAPIKeysFn()
+}
+
+func (m MockAPIKeyService) RevokeAPIKey(ctx context.Context, id int) error {
+	return m.RevokeAPIKeyFn(id)
+}
+
+func (m MockAPIKeyService) Authenticate(ctx context.Context, rawKey string) (*apikey.APIKey, error) {
+	return nil, apikey.ErrAPIKeyInvalid
+}
+
+// Testes dos handlers de administração de chaves de API
+
+func TestHandleCreateAPIKey(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    interface{}
+		expectedStatus int
+	}{
+		{
+			name:           "valid request",
+			requestBody:    &apikey.CreateAPIKeyRequest{Nome: "Integração", Escopos: []string{"read", "write"}},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "invalid scope",
+			requestBody:    &apikey.CreateAPIKeyRequest{Nome: "Integração", Escopos: []string{"root"}},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing scopes",
+			requestBody:    &apikey.CreateAPIKeyRequest{Nome: "Integração"},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewAPIKeyHandler(MockAPIKeyService{
+				CreateAPIKeyFn: func(req *apikey.CreateAPIKeyRequest) (*apikey.CreateAPIKeyResponse, error) {
+					return &apikey.CreateAPIKeyResponse{
+						APIKeyResponse: apikey.APIKeyResponse{ID: 1, Nome: req.Nome},
+						Chave:          "dsk_secret",
+					}, nil
+				},
+			}, newValidator(t))
+
+			body, _ := json.Marshal(tt.requestBody)
+			req := httptest.NewRequest("POST", "/admin/api-keys", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			handler.HandleCreateAPIKey(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestHandleGetAPIKeys(t *testing.T) {
+	handler := NewAPIKeyHandler(MockAPIKeyService{
+		GetAPIKeysFn: func() ([]*apikey.APIKeyResponse, error) {
+			return []*apikey.APIKeyResponse{{ID: 1, Nome: "Integração"}}, nil
+		},
+	}, newValidator(t))
+
+	req := httptest.NewRequest("GET", "/admin/api-keys", nil)
+	w := httptest.NewRecorder()
+
+	handler.HandleGetAPIKeys(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "hash")
+}
+
+func TestHandleRevokeAPIKey(t *testing.T) {
+	tests := []struct {
+		name           string
+		id             string
+		expectedStatus int
+		expectedError  error
+	}{
+		{
+			name:           "valid request",
+			id:             "1",
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "invalid id",
+			id:             "invalid",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "not found",
+			id:             "2",
+			expectedStatus: http.StatusNotFound,
+			expectedError:  apikey.ErrAPIKeyNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewAPIKeyHandler(MockAPIKeyService{
+				RevokeAPIKeyFn: func(id int) error {
+					return tt.expectedError
+				},
+			}, newValidator(t))
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("/admin/api-keys/{id}", handler.HandleRevokeAPIKey)
+
+			req := httptest.NewRequest("DELETE", fmt.Sprintf("/admin/api-keys/%s", tt.id), nil)
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
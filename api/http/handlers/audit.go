@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/samluiz/delivery-service/api/http/utils"
+	"github.com/samluiz/delivery-service/internal/audit"
+)
+
+type AuditHandler struct {
+	auditService audit.IAuditService
+}
+
+func NewAuditHandler(auditService audit.IAuditService) *AuditHandler {
+	return &AuditHandler{auditService: auditService}
+}
+
+// Função responsável por consultar a auditoria.
+// Filtros aceitos: entity, id, actor, action, from e to (RFC 3339), page e page_size.
+func (h AuditHandler) HandleGetAuditEntries(w http.ResponseWriter, r *http.Request) {
+	filter, err := auditFilterFromQuery(r)
+
+	if err != nil {
+		utils.NewJSONResponse(w, http.StatusBadRequest, utils.NewBadRequestError(err, r))
+		return
+	}
+
+	response, err := h.auditService.GetEntries(r.Context(), filter)
+
+	if err != nil {
+		utils.NewJSONResponse(w, http.StatusInternalServerError, utils.NewInternalServerError(err, r))
+		return
+	}
+
+	utils.NewJSONResponse(w, http.StatusOK, response)
+}
+
+// Função responsável por montar os filtros da auditoria a partir dos query params.
+func auditFilterFromQuery(r *http.Request) (*audit.Filter, error) {
+	query := r.URL.Query()
+
+	filter := &audit.Filter{
+		Entidade: query.Get("entity"),
+		Ator:     query.Get("actor"),
+		Acao:     audit.Action(query.Get("action")),
+	}
+
+	// Parâmetros numéricos
+	for param, target := range map[string]*int{
+		"id":        &filter.EntidadeID,
+		"page":      &filter.Pagina,
+		"page_size": &filter.TamanhoPagina,
+	} {
+		if value := query.Get(param); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", param, err)
+			}
+			*target = parsed
+		}
+	}
+
+	// Período de consulta
+	for param, target := range map[string]**time.Time{
+		"from": &filter.De,
+		"to":   &filter.Ate,
+	} {
+		if value := query.Get(param); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", param, err)
+			}
+			*target = &parsed
+		}
+	}
+
+	return filter, nil
+}
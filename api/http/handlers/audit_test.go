@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/samluiz/delivery-service/internal/audit"
+	"github.com/stretchr/testify/assert"
+)
+
+// Mocks do service de auditoria
+
+type MockAuditService struct {
+	GetEntriesFn func(filter *audit.Filter) (*audit.EntriesPageResponse, error)
+}
+
+func (m MockAuditService) GetEntries(ctx context.Context, filter *audit.Filter) (*audit.EntriesPageResponse, error) {
+	return m.GetEntriesFn(filter)
+}
+
+func TestHandleGetAuditEntries(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		expectedStatus int
+		expectedFilter *audit.Filter
+	}{
+		{
+			name:           "Consulta por entidade com paginação",
+			url:            "/audit?entity=delivery&id=1&action=update&page=2&page_size=10",
+			expectedStatus: http.StatusOK,
+			expectedFilter: &audit.Filter{Entidade: "delivery", EntidadeID: 1, Acao: audit.ActionUpdate, Pagina: 2, TamanhoPagina: 10},
+		},
+		{
+			name:           "Consulta por período",
+			url:            "/audit?actor=user-1&from=2024-01-01T00:00:00Z",
+			expectedStatus: http.StatusOK,
+			expectedFilter: &audit.Filter{Ator: "user-1", De: func() *time.Time { t := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC); return &t }()},
+		},
+		{
+			name:           "ID inválido",
+			url:            "/audit?entity=delivery&id=abc",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Data inválida",
+			url:            "/audit?to=ontem",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewAuditHandler(MockAuditService{
+				GetEntriesFn: func(filter *audit.Filter) (*audit.EntriesPageResponse, error) {
+					assert.Equal(t, tt.expectedFilter, filter)
+					return &audit.EntriesPageResponse{Itens: []*audit.EntryResponse{}}, nil
+				},
+			})
+
+			req := httptest.NewRequest("GET", tt.url, nil)
+			w := httptest.NewRecorder()
+
+			handler.HandleGetAuditEntries(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Result().StatusCode)
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samluiz/delivery-service/api/docs"
+	"github.com/samluiz/delivery-service/api/http/middleware"
+	"github.com/samluiz/delivery-service/internal/audit"
+	"github.com/samluiz/delivery-service/internal/delivery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Teste das respostas do DeliveryHandler (v1 e v2) contra o documento OpenAPI.
+// O validador roda com a validação das respostas habilitada, que responde 500 quando a resposta diverge do contrato.
+
+func TestDeliveryHandler_Contract(t *testing.T) {
+	validator, err := middleware.NewOpenAPIValidator(docs.OpenAPI, newValidator(t), middleware.OpenAPIOptions{ValidateResponses: true})
+	require.NoError(t, err)
+
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	response := &delivery.DeliveryResponse{
+		ID: 1, Cliente: "Cliente A", Peso: 10.5, Endereco: "Rua A", Logradouro: "Logradouro A", Numero: "123",
+		Bairro: "Bairro A", Complemento: "Apto 1", Cidade: "Recife", Estado: "PE", Pais: "BR",
+		Latitude: -8.05, Longitude: -34.9, DataInclusao: now, DataAlteracao: now,
+	}
+	deleted := *response
+	deleted.DataExclusao = &now
+
+	service := MockDeliveryService{
+		CreateDeliveryFn: func(req *delivery.CreateDeliveryRequest) (*delivery.DeliveryResponse, error) {
+			return response, nil
+		},
+		GetDeliveryFn: func(id int) (*delivery.DeliveryResponse, error) {
+			if id != 1 {
+				return nil, delivery.ErrDeliveryNotFound
+			}
+			return response, nil
+		},
+		GetDeliveriesFn: func(filter *delivery.DeliveryFilter) ([]*delivery.DeliveryResponse, error) {
+			return []*delivery.DeliveryResponse{response}, nil
+		},
+		UpdateDeliveryFn: func(req *delivery.UpdateDeliveryRequest, id int) (*delivery.DeliveryResponse, error) {
+			return response, nil
+		},
+		DeleteDeliveryFn: func(id int) (*delivery.DeliveryResponse, error) {
+			return &deleted, nil
+		},
+		GetDeletedDeliveriesFn: func(filter *delivery.DeliveryFilter) ([]*delivery.DeliveryResponse, error) {
+			return []*delivery.DeliveryResponse{&deleted}, nil
+		},
+		RestoreDeliveryFn: func(id int) (*delivery.DeliveryResponse, error) {
+			return response, nil
+		},
+		PreviewDeleteDeliveriesFn: func(filter *delivery.DeliveryFilter) (*delivery.DeleteDeliveriesPreview, error) {
+			return &delivery.DeleteDeliveriesPreview{Quantidade: 2, TokenConfirmacao: "token", ExpiraEm: now}, nil
+		},
+		DeleteDeliveriesFn: func(filter *delivery.DeliveryFilter, token string) (*delivery.DeleteDeliveriesResult, error) {
+			return &delivery.DeleteDeliveriesResult{Quantidade: 2}, nil
+		},
+		GetDeliveryAsOfFn: func(id int, asOf time.Time) (*delivery.DeliveryResponse, error) {
+			return response, nil
+		},
+		GetDeliveryVersionsFn: func(id int) ([]*delivery.DeliveryVersionResponse, error) {
+			return []*delivery.DeliveryVersionResponse{{Versao: 1, Acao: audit.ActionCreate, DataVersao: now, Entrega: response}}, nil
+		},
+		RevertDeliveryFn: func(id int, version int) (*delivery.DeliveryResponse, error) {
+			return nil, delivery.ErrVersionNotFound
+		},
+	}
+	mux := http.NewServeMux()
+	registerContractRoutes(mux, "/v1", NewDeliveryHandler(service, newValidator(t)), validator)
+	registerContractRoutes(mux, "/v2", NewDeliveryHandlerV2(service, newValidator(t)), validator)
+
+	fields := `"cliente":"Cliente A","peso":10.5,"endereco":"Rua A","logradouro":"Logradouro A","numero":"123",
+		"bairro":"Bairro A","complemento":"Apto 1","cidade":"Recife","estado":"PE","pais":"BR"`
+	body := `{` + fields + `,"latitude":-8.05,"longitude":-34.9}`
+	address := `"address":{"line":"Rua A, 123","street":"Rua A","number":"123","complement":"Apto 1",
+		"neighborhood":"Bairro A","city":"Recife","state":"PE","country":"BR"}`
+	bodyV2 := `{"customer":"Cliente A","weight":10.5,` + address + `,"coordinates":{"latitude":0,"longitude":-34.9}}`
+	bodyV2XML := `<delivery><customer>Cliente A</customer><weight>10.5</weight><address><line>Rua A, 123</line><street>Rua A</street>
+		<number>123</number><complement>Apto 1</complement><neighborhood>Bairro A</neighborhood><city>Recife</city><state>PE</state>
+		<country>BR</country></address><coordinates><latitude>-8.05</latitude><longitude>-34.9</longitude></coordinates></delivery>`
+
+	tests := []struct {
+		name           string
+		method         string
+		target         string
+		body           string
+		contentType    string
+		accept         string
+		token          string
+		expectedStatus int
+	}{
+		{name: "create", method: "POST", target: "/v1/deliveries", body: body, expectedStatus: http.StatusCreated},
+		{name: "create with invalid state", method: "POST", target: "/v1/deliveries", body: strings.Replace(body, `"PE"`, `"XX"`, 1), expectedStatus: http.StatusBadRequest},
+		{name: "create with Brasil as country", method: "POST", target: "/v1/deliveries", body: strings.Replace(body, `"BR"`, `"Brasil"`, 1), expectedStatus: http.StatusCreated},
+		{name: "create without coordinates", method: "POST", target: "/v1/deliveries", body: `{` + fields + `}`, expectedStatus: http.StatusBadRequest},
+		{name: "create with malformed json", method: "POST", target: "/v1/deliveries", body: `{"cliente":`, expectedStatus: http.StatusBadRequest},
+		{name: "list", method: "GET", target: "/v1/deliveries?city=Recife", expectedStatus: http.StatusOK},
+		{name: "get", method: "GET", target: "/v1/deliveries/1", expectedStatus: http.StatusOK},
+		{name: "get as of", method: "GET", target: "/v1/deliveries/1?as_of=2024-05-10T12:00:00Z", expectedStatus: http.StatusOK},
+		{name: "get not found", method: "GET", target: "/v1/deliveries/2", expectedStatus: http.StatusNotFound},
+		{name: "get not found as problem", method: "GET", target: "/v1/deliveries/2", accept: "application/problem+json", expectedStatus: http.StatusNotFound},
+		{name: "get with invalid id", method: "GET", target: "/v1/deliveries/abc", expectedStatus: http.StatusBadRequest},
+		{name: "update", method: "PUT", target: "/v1/deliveries/1", body: "{" + body[strings.Index(body, `"peso"`):], expectedStatus: http.StatusOK},
+		{name: "delete", method: "DELETE", target: "/v1/deliveries/1", expectedStatus: http.StatusNoContent},
+		{name: "trash", method: "GET", target: "/v1/deliveries/trash", expectedStatus: http.StatusOK},
+		{name: "restore", method: "POST", target: "/v1/deliveries/1/restore", expectedStatus: http.StatusOK},
+		{name: "versions", method: "GET", target: "/v1/deliveries/1/versions", expectedStatus: http.StatusOK},
+		{name: "revert missing version", method: "POST", target: "/v1/deliveries/1/versions/9/revert", expectedStatus: http.StatusNotFound},
+		{name: "bulk delete dry run", method: "DELETE", target: "/v1/deliveries?city=Recife&dry_run=true", expectedStatus: http.StatusOK},
+		{name: "bulk delete", method: "DELETE", target: "/v1/deliveries?city=Recife", token: "token", expectedStatus: http.StatusOK},
+
+		{name: "v2 create", method: "POST", target: "/v2/deliveries", body: bodyV2, expectedStatus: http.StatusCreated},
+		{name: "v2 create without coordinates", method: "POST", target: "/v2/deliveries", body: `{"customer":"Cliente A","weight":1,` + address + `}`, expectedStatus: http.StatusBadRequest},
+		{name: "v2 create with invalid state", method: "POST", target: "/v2/deliveries", body: strings.Replace(bodyV2, `"PE"`, `"XX"`, 1), expectedStatus: http.StatusBadRequest},
+		{name: "v2 create with v1 fields", method: "POST", target: "/v2/deliveries", body: body, expectedStatus: http.StatusBadRequest},
+		{name: "v2 list", method: "GET", target: "/v2/deliveries", expectedStatus: http.StatusOK},
+		{name: "v2 get", method: "GET", target: "/v2/deliveries/1", expectedStatus: http.StatusOK},
+		{name: "v2 get not found", method: "GET", target: "/v2/deliveries/2", expectedStatus: http.StatusNotFound},
+		{name: "v2 update", method: "PUT", target: "/v2/deliveries/1", body: `{"weight":2,` + address + `,"coordinates":{"latitude":0,"longitude":0}}`, expectedStatus: http.StatusOK},
+		{name: "v2 trash", method: "GET", target: "/v2/deliveries/trash", expectedStatus: http.StatusOK},
+		{name: "v2 versions", method: "GET", target: "/v2/deliveries/1/versions", expectedStatus: http.StatusOK},
+		{name: "v2 bulk delete dry run", method: "DELETE", target: "/v2/deliveries?dry_run=true", expectedStatus: http.StatusOK},
+		{name: "v2 bulk delete", method: "DELETE", target: "/v2/deliveries", token: "token", expectedStatus: http.StatusOK},
+
+		{name: "v2 create from xml", method: "POST", target: "/v2/deliveries", body: bodyV2XML, contentType: "application/xml", accept: "application/xml", expectedStatus: http.StatusCreated},
+		{name: "v2 list as csv", method: "GET", target: "/v2/deliveries", accept: "text/csv", expectedStatus: http.StatusOK},
+		{name: "v2 get as msgpack", method: "GET", target: "/v2/deliveries/1", accept: "application/msgpack", expectedStatus: http.StatusOK},
+		{name: "v2 get not found as xml", method: "GET", target: "/v2/deliveries/2", accept: "application/xml", expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			if tt.token != "" {
+				req.Header.Set(ConfirmationTokenHeader, tt.token)
+			}
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+		})
+	}
+}
+
+// Função responsável por registrar as rotas de entregas de uma versão com a validação do contrato.
+func registerContractRoutes(mux *http.ServeMux, prefix string, handler *DeliveryHandler, validator *middleware.OpenAPIValidator) {
+	mux.HandleFunc("POST "+prefix+"/deliveries", validator.Validate(handler.HandleCreateDelivery))
+	mux.HandleFunc("GET "+prefix+"/deliveries", validator.Validate(handler.HandleGetDeliveries))
+	mux.HandleFunc("GET "+prefix+"/deliveries/{id}", validator.Validate(handler.HandleGetDelivery))
+	mux.HandleFunc("PUT "+prefix+"/deliveries/{id}", validator.Validate(handler.HandleUpdateDelivery))
+	mux.HandleFunc("DELETE "+prefix+"/deliveries/{id}", validator.Validate(handler.HandleDeleteDelivery))
+	mux.HandleFunc("GET "+prefix+"/deliveries/trash", validator.Validate(handler.HandleGetDeletedDeliveries))
+	mux.HandleFunc("POST "+prefix+"/deliveries/{id}/restore", validator.Validate(handler.HandleRestoreDelivery))
+	mux.HandleFunc("GET "+prefix+"/deliveries/{id}/versions", validator.Validate(handler.HandleGetDeliveryVersions))
+	mux.HandleFunc("POST "+prefix+"/deliveries/{id}/versions/{version}/revert", validator.Validate(handler.HandleRevertDelivery))
+	mux.HandleFunc("DELETE "+prefix+"/deliveries", validator.Validate(handler.HandleDeleteDeliveries))
+}
@@ -1,162 +1,357 @@
 package handlers
 
 import (
-	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/samluiz/delivery-service/api/http/utils"
 	"github.com/samluiz/delivery-service/internal/delivery"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// Tracer utilizado pelos handlers de entregas.
+var tracer = otel.Tracer("github.com/samluiz/delivery-service/api/http/handlers")
+
+// Função responsável por registrar no span do handler o erro retornado pelo service, marcando o span com status de erro.
+func recordError(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
 type DeliveryHandler struct {
 	deliveryService delivery.IDeliveryService
+	mapper          deliveryMapper
 }
 
-func NewDeliveryHandler(deliveryService delivery.IDeliveryService) *DeliveryHandler {
-	return &DeliveryHandler{deliveryService: deliveryService}
+// Função responsável por instanciar o handler da v1 da API de entregas.
+// O validator deve ter as regras de domínio das entregas registradas.
+func NewDeliveryHandler(deliveryService delivery.IDeliveryService, validator *utils.XValidator) *DeliveryHandler {
+	return &DeliveryHandler{deliveryService: deliveryService, mapper: v1Mapper{validator: validator}}
 }
 
-func (h DeliveryHandler) HandleCreateDelivery(w http.ResponseWriter, r *http.Request) {
-	var request delivery.CreateDeliveryRequest
+// Função responsável por instanciar o handler da v2 da API de entregas.
+// O validator deve ter as regras de domínio das entregas registradas.
+func NewDeliveryHandlerV2(deliveryService delivery.IDeliveryService, validator *utils.XValidator) *DeliveryHandler {
+	return &DeliveryHandler{deliveryService: deliveryService, mapper: v2Mapper{validator: validator}}
+}
 
-	// Serializando o request body para o struct
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		utils.NewJSONResponse(w, http.StatusBadRequest, utils.NewBadRequestError(err, r))
-		return
-	}
+func (h DeliveryHandler) HandleCreateDelivery(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracer.Start(r.Context(), "DeliveryHandler.HandleCreateDelivery")
+	defer span.End()
 
-	// Validando o request body
-	validationError := utils.ValidateBody(r, &request)
+	// Desserializando e validando o request body no formato da versão
+	request, requestError := h.mapper.createRequest(w, r)
 
-	if validationError != nil {
-		utils.NewJSONResponse(w, http.StatusBadRequest, validationError)
+	if requestError != nil {
+		utils.NewResponse(w, r, requestError.Status, requestError)
 		return
 	}
 
-	response, err := h.deliveryService.CreateDelivery(&request)
+	response, err := h.deliveryService.CreateDelivery(ctx, request)
 
 	if err != nil {
-		utils.NewJSONResponse(w, http.StatusInternalServerError, utils.NewInternalServerError(err, r))
+		recordError(span, err)
+		utils.NewResponse(w, r, http.StatusInternalServerError, utils.NewInternalServerError(err, r))
 		return
 	}
 
-	utils.NewJSONResponse(w, http.StatusCreated, response)
+	utils.NewResponse(w, r, http.StatusCreated, h.mapper.delivery(response))
 }
 
 func (h DeliveryHandler) HandleGetDelivery(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracer.Start(r.Context(), "DeliveryHandler.HandleGetDelivery")
+	defer span.End()
+
 	// Buscando o ID da entrega no path
 	id, err := strconv.Atoi(r.PathValue("id"))
 
 	if err != nil {
-		utils.NewJSONResponse(w, http.StatusBadRequest, utils.NewBadRequestError(err, r))
+		utils.NewResponse(w, r, http.StatusBadRequest, utils.NewBadRequestError(err, r))
 		return
 	}
 
-	response, err := h.deliveryService.GetDelivery(id)
+	var response *delivery.DeliveryResponse
+
+	// Com as_of a entrega é buscada como estava no instante informado
+	if value := r.URL.Query().Get("as_of"); value != "" {
+		asOf, parseErr := time.Parse(time.RFC3339, value)
+
+		if parseErr != nil {
+			utils.NewResponse(w, r, http.StatusBadRequest, utils.NewBadRequestError(fmt.Errorf("invalid as_of: %w", parseErr), r))
+			return
+		}
+
+		response, err = h.deliveryService.GetDeliveryAsOf(ctx, id, asOf)
+	} else {
+		response, err = h.deliveryService.GetDelivery(ctx, id)
+	}
 
 	if err != nil {
+		recordError(span, err)
 		// Verificando se o erro aconteceu por não encontrar a entrega
 		if errors.Is(err, delivery.ErrDeliveryNotFound) {
 			// Retornando o erro de não encontrado
-			utils.NewJSONResponse(w, http.StatusNotFound, utils.NewNotFoundError(err, r))
+			utils.NewResponse(w, r, http.StatusNotFound, utils.NewNotFoundError(err, r))
 			return
 		}
-		utils.NewJSONResponse(w, http.StatusInternalServerError, utils.NewInternalServerError(err, r))
+		utils.NewResponse(w, r, http.StatusInternalServerError, utils.NewInternalServerError(err, r))
 		return
 	}
 
-	utils.NewJSONResponse(w, http.StatusOK, response)
+	utils.NewResponse(w, r, http.StatusOK, h.mapper.delivery(response))
 }
 
 func (h DeliveryHandler) HandleGetDeliveries(w http.ResponseWriter, r *http.Request) {
-	// Buscando o query param de cidade
-	city := r.URL.Query().Get("city")
+	ctx, span := tracer.Start(r.Context(), "DeliveryHandler.HandleGetDeliveries")
+	defer span.End()
 
-	response, err := h.deliveryService.GetDeliveries(city)
+	response, err := h.deliveryService.GetDeliveries(ctx, filterFromQuery(r))
 
 	if err != nil {
-		utils.NewJSONResponse(w, http.StatusInternalServerError, utils.NewInternalServerError(err, r))
+		recordError(span, err)
+		utils.NewResponse(w, r, http.StatusInternalServerError, utils.NewInternalServerError(err, r))
 		return
 	}
 
-	utils.NewJSONResponse(w, http.StatusOK, response)
+	utils.NewResponse(w, r, http.StatusOK, h.mapper.deliveries(response))
 }
 
 func (h DeliveryHandler) HandleUpdateDelivery(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracer.Start(r.Context(), "DeliveryHandler.HandleUpdateDelivery")
+	defer span.End()
+
 	// Buscando o ID da entrega no path
 	id, err := strconv.Atoi(r.PathValue("id"))
 
 	if err != nil {
-		utils.NewJSONResponse(w, http.StatusBadRequest, utils.NewBadRequestError(err, r))
+		utils.NewResponse(w, r, http.StatusBadRequest, utils.NewBadRequestError(err, r))
+		return
+	}
+
+	// Desserializando e validando o request body no formato da versão
+	request, requestError := h.mapper.updateRequest(w, r)
+
+	if requestError != nil {
+		utils.NewResponse(w, r, requestError.Status, requestError)
 		return
 	}
 
-	var request delivery.UpdateDeliveryRequest
+	response, err := h.deliveryService.UpdateDelivery(ctx, request, id)
 
-	// Serializando o request body para o struct
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		utils.NewJSONResponse(w, http.StatusBadRequest, utils.NewBadRequestError(err, r))
+	if err != nil {
+		recordError(span, err)
+		// Verificando se o erro aconteceu por não encontrar a entrega
+		if errors.Is(err, delivery.ErrDeliveryNotFound) {
+			// Retornando o erro de não encontrado
+			utils.NewResponse(w, r, http.StatusNotFound, utils.NewNotFoundError(err, r))
+			return
+		}
+		utils.NewResponse(w, r, http.StatusInternalServerError, utils.NewInternalServerError(err, r))
 		return
 	}
 
-	// Validando o request body
-	validationError := utils.ValidateBody(r, &request)
+	utils.NewResponse(w, r, http.StatusOK, h.mapper.delivery(response))
+}
+
+func (h DeliveryHandler) HandleDeleteDelivery(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracer.Start(r.Context(), "DeliveryHandler.HandleDeleteDelivery")
+	defer span.End()
+
+	// Buscando o ID da entrega no path
+	id, err := strconv.Atoi(r.PathValue("id"))
 
-	if validationError != nil {
-		utils.NewJSONResponse(w, http.StatusBadRequest, validationError)
+	if err != nil {
+		utils.NewResponse(w, r, http.StatusBadRequest, utils.NewBadRequestError(err, r))
 		return
 	}
 
-	response, err := h.deliveryService.UpdateDelivery(&request, id)
+	_, err = h.deliveryService.DeleteDelivery(ctx, id)
 
 	if err != nil {
+		recordError(span, err)
 		// Verificando se o erro aconteceu por não encontrar a entrega
 		if errors.Is(err, delivery.ErrDeliveryNotFound) {
 			// Retornando o erro de não encontrado
-			utils.NewJSONResponse(w, http.StatusNotFound, utils.NewNotFoundError(err, r))
+			utils.NewResponse(w, r, http.StatusNotFound, utils.NewNotFoundError(err, r))
 			return
 		}
-		utils.NewJSONResponse(w, http.StatusInternalServerError, utils.NewInternalServerError(err, r))
+		utils.NewResponse(w, r, http.StatusInternalServerError, utils.NewInternalServerError(err, r))
 		return
 	}
 
-	utils.NewJSONResponse(w, http.StatusOK, response)
+	w.WriteHeader(http.StatusNoContent)
 }
 
-func (h DeliveryHandler) HandleDeleteDelivery(w http.ResponseWriter, r *http.Request) {
+// Função responsável por listar as entregas da lixeira, aceitando os mesmos filtros da listagem.
+func (h DeliveryHandler) HandleGetDeletedDeliveries(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracer.Start(r.Context(), "DeliveryHandler.HandleGetDeletedDeliveries")
+	defer span.End()
+
+	response, err := h.deliveryService.GetDeletedDeliveries(ctx, filterFromQuery(r))
+
+	if err != nil {
+		recordError(span, err)
+		utils.NewResponse(w, r, http.StatusInternalServerError, utils.NewInternalServerError(err, r))
+		return
+	}
+
+	utils.NewResponse(w, r, http.StatusOK, h.mapper.deliveries(response))
+}
+
+func (h DeliveryHandler) HandleRestoreDelivery(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracer.Start(r.Context(), "DeliveryHandler.HandleRestoreDelivery")
+	defer span.End()
+
 	// Buscando o ID da entrega no path
 	id, err := strconv.Atoi(r.PathValue("id"))
 
 	if err != nil {
-		utils.NewJSONResponse(w, http.StatusBadRequest, utils.NewBadRequestError(err, r))
+		utils.NewResponse(w, r, http.StatusBadRequest, utils.NewBadRequestError(err, r))
 		return
 	}
 
-	err = h.deliveryService.DeleteDelivery(id)
+	response, err := h.deliveryService.RestoreDelivery(ctx, id)
 
 	if err != nil {
+		recordError(span, err)
+		// Verificando se o erro aconteceu por não encontrar a entrega na lixeira
+		if errors.Is(err, delivery.ErrDeliveryNotFound) {
+			// Retornando o erro de não encontrado
+			utils.NewResponse(w, r, http.StatusNotFound, utils.NewNotFoundError(err, r))
+			return
+		}
+		utils.NewResponse(w, r, http.StatusInternalServerError, utils.NewInternalServerError(err, r))
+		return
+	}
+
+	utils.NewResponse(w, r, http.StatusOK, h.mapper.delivery(response))
+}
+
+// Função responsável por listar o histórico de versões de uma entrega.
+func (h DeliveryHandler) HandleGetDeliveryVersions(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracer.Start(r.Context(), "DeliveryHandler.HandleGetDeliveryVersions")
+	defer span.End()
+
+	// Buscando o ID da entrega no path
+	id, err := strconv.Atoi(r.PathValue("id"))
+
+	if err != nil {
+		utils.NewResponse(w, r, http.StatusBadRequest, utils.NewBadRequestError(err, r))
+		return
+	}
+
+	response, err := h.deliveryService.GetDeliveryVersions(ctx, id)
+
+	if err != nil {
+		recordError(span, err)
 		// Verificando se o erro aconteceu por não encontrar a entrega
 		if errors.Is(err, delivery.ErrDeliveryNotFound) {
 			// Retornando o erro de não encontrado
-			utils.NewJSONResponse(w, http.StatusNotFound, utils.NewNotFoundError(err, r))
+			utils.NewResponse(w, r, http.StatusNotFound, utils.NewNotFoundError(err, r))
 			return
 		}
-		utils.NewJSONResponse(w, http.StatusInternalServerError, utils.NewInternalServerError(err, r))
+		utils.NewResponse(w, r, http.StatusInternalServerError, utils.NewInternalServerError(err, r))
 		return
 	}
 
-	w.WriteHeader(http.StatusNoContent)
+	utils.NewResponse(w, r, http.StatusOK, h.mapper.versions(response))
 }
 
-func (h DeliveryHandler) HandleDeleteAllDeliveries(w http.ResponseWriter, r *http.Request) {
-	err := h.deliveryService.DeleteAllDeliveries()
+// Função responsável por reverter uma entrega para a versão informada no path.
+func (h DeliveryHandler) HandleRevertDelivery(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracer.Start(r.Context(), "DeliveryHandler.HandleRevertDelivery")
+	defer span.End()
+
+	// Buscando o ID da entrega e o número da versão no path
+	id, err := strconv.Atoi(r.PathValue("id"))
+
+	if err != nil {
+		utils.NewResponse(w, r, http.StatusBadRequest, utils.NewBadRequestError(err, r))
+		return
+	}
+
+	version, err := strconv.Atoi(r.PathValue("version"))
 
 	if err != nil {
-		utils.NewJSONResponse(w, http.StatusInternalServerError, utils.NewInternalServerError(err, r))
+		utils.NewResponse(w, r, http.StatusBadRequest, utils.NewBadRequestError(err, r))
 		return
 	}
 
-	w.WriteHeader(http.StatusNoContent)
+	response, err := h.deliveryService.RevertDelivery(ctx, id, version)
+
+	if err != nil {
+		recordError(span, err)
+		// Verificando se o erro aconteceu por não encontrar a entrega ou a versão
+		if errors.Is(err, delivery.ErrDeliveryNotFound) || errors.Is(err, delivery.ErrVersionNotFound) {
+			// Retornando o erro de não encontrado
+			utils.NewResponse(w, r, http.StatusNotFound, utils.NewNotFoundError(err, r))
+			return
+		}
+		utils.NewResponse(w, r, http.StatusInternalServerError, utils.NewInternalServerError(err, r))
+		return
+	}
+
+	utils.NewResponse(w, r, http.StatusOK, h.mapper.delivery(response))
+}
+
+// Header com o token de confirmação da exclusão em massa. O token não é aceito na query string, que é
+// registrada nos logs de acesso, nos proxies e nos traces (atributo http.url).
+const ConfirmationTokenHeader = "X-Confirmation-Token"
+
+// Função responsável pela exclusão em massa de entregas.
+// Com dry_run=true apenas informa a quantidade de entregas afetadas e o token de confirmação,
+// que deve ser enviado no header X-Confirmation-Token para efetivar a exclusão com os mesmos filtros.
+func (h DeliveryHandler) HandleDeleteDeliveries(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracer.Start(r.Context(), "DeliveryHandler.HandleDeleteDeliveries")
+	defer span.End()
+
+	filter := filterFromQuery(r)
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+
+	var (
+		preview *delivery.DeleteDeliveriesPreview
+		result  *delivery.DeleteDeliveriesResult
+		err     error
+	)
+
+	if dryRun {
+		preview, err = h.deliveryService.PreviewDeleteDeliveries(ctx, filter)
+	} else {
+		result, err = h.deliveryService.DeleteDeliveries(ctx, filter, r.Header.Get(ConfirmationTokenHeader))
+	}
+
+	if err != nil {
+		recordError(span, err)
+		switch {
+		case errors.Is(err, delivery.ErrBulkDeleteDisabled):
+			utils.NewResponse(w, r, http.StatusForbidden, utils.NewForbiddenError(err, r))
+		case errors.Is(err, delivery.ErrMissingConfirmationToken), errors.Is(err, delivery.ErrInvalidConfirmationToken):
+			utils.NewResponse(w, r, http.StatusBadRequest, utils.NewBadRequestError(err, r))
+		case errors.Is(err, delivery.ErrConfirmationMismatch):
+			utils.NewResponse(w, r, http.StatusConflict, utils.NewConflictError(err, r))
+		default:
+			utils.NewResponse(w, r, http.StatusInternalServerError, utils.NewInternalServerError(err, r))
+		}
+		return
+	}
+
+	if dryRun {
+		utils.NewResponse(w, r, http.StatusOK, h.mapper.preview(preview))
+		return
+	}
+
+	utils.NewResponse(w, r, http.StatusOK, h.mapper.result(result))
+}
+
+// Função responsável por montar os filtros de entregas a partir dos query params.
+func filterFromQuery(r *http.Request) *delivery.DeliveryFilter {
+	return &delivery.DeliveryFilter{
+		Cidade:    r.URL.Query().Get("city"),
+		Motorista: r.URL.Query().Get("driver"),
+	}
 }
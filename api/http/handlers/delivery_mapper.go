@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/samluiz/delivery-service/api/http/utils"
+	"github.com/samluiz/delivery-service/internal/delivery"
+)
+
+// Interface responsável por converter os requests e as respostas entre o contrato de uma versão da API e o service.
+// Todas as versões utilizam o mesmo IDeliveryService, mudando apenas o formato dos dados.
+type deliveryMapper interface {
+	// Desserializa e valida o corpo no formato da versão, retornando o request do service
+	createRequest(w http.ResponseWriter, r *http.Request) (*delivery.CreateDeliveryRequest, *utils.Error)
+	updateRequest(w http.ResponseWriter, r *http.Request) (*delivery.UpdateDeliveryRequest, *utils.Error)
+
+	delivery(response *delivery.DeliveryResponse) any
+	deliveries(response []*delivery.DeliveryResponse) any
+	versions(response []*delivery.DeliveryVersionResponse) any
+	preview(response *delivery.DeleteDeliveriesPreview) any
+	result(response *delivery.DeleteDeliveriesResult) any
+}
+
+// Função responsável por desserializar e validar o corpo da requisição no struct informado.
+func decodeRequest[T any](w http.ResponseWriter, r *http.Request, validator *utils.XValidator) (*T, *utils.Error) {
+	var request T
+
+	// Desserializando o request body (JSON ou XML) para o struct
+	if decodeError := utils.DecodeBody(w, r, &request); decodeError != nil {
+		return nil, decodeError
+	}
+
+	// Validando o request body
+	if validationError := validator.ValidateBody(r, &request); validationError != nil {
+		return nil, validationError
+	}
+
+	return &request, nil
+}
+
+// Mapper da v1, cujo contrato é o próprio formato do service (campos em português).
+type v1Mapper struct {
+	validator *utils.XValidator
+}
+
+func (m v1Mapper) createRequest(w http.ResponseWriter, r *http.Request) (*delivery.CreateDeliveryRequest, *utils.Error) {
+	return decodeRequest[delivery.CreateDeliveryRequest](w, r, m.validator)
+}
+
+func (m v1Mapper) updateRequest(w http.ResponseWriter, r *http.Request) (*delivery.UpdateDeliveryRequest, *utils.Error) {
+	return decodeRequest[delivery.UpdateDeliveryRequest](w, r, m.validator)
+}
+
+func (v1Mapper) delivery(response *delivery.DeliveryResponse) any {
+	return response
+}
+
+func (v1Mapper) deliveries(response []*delivery.DeliveryResponse) any {
+	return response
+}
+
+func (v1Mapper) versions(response []*delivery.DeliveryVersionResponse) any {
+	return response
+}
+
+func (v1Mapper) preview(response *delivery.DeleteDeliveriesPreview) any {
+	return response
+}
+
+func (v1Mapper) result(response *delivery.DeleteDeliveriesResult) any {
+	return response
+}
+
+// Mapper da v2, com campos em inglês e endereço e coordenadas aninhados.
+type v2Mapper struct {
+	validator *utils.XValidator
+}
+
+func (m v2Mapper) createRequest(w http.ResponseWriter, r *http.Request) (*delivery.CreateDeliveryRequest, *utils.Error) {
+	request, err := decodeRequest[delivery.CreateDeliveryRequestV2](w, r, m.validator)
+	if err != nil {
+		return nil, err
+	}
+	return request.ToCreateDeliveryRequest(), nil
+}
+
+func (m v2Mapper) updateRequest(w http.ResponseWriter, r *http.Request) (*delivery.UpdateDeliveryRequest, *utils.Error) {
+	request, err := decodeRequest[delivery.UpdateDeliveryRequestV2](w, r, m.validator)
+	if err != nil {
+		return nil, err
+	}
+	return request.ToUpdateDeliveryRequest(), nil
+}
+
+func (v2Mapper) delivery(response *delivery.DeliveryResponse) any {
+	return response.ToDeliveryResponseV2()
+}
+
+func (v2Mapper) deliveries(response []*delivery.DeliveryResponse) any {
+	deliveries := make([]*delivery.DeliveryResponseV2, 0, len(response))
+	for _, d := range response {
+		deliveries = append(deliveries, d.ToDeliveryResponseV2())
+	}
+	return deliveries
+}
+
+func (v2Mapper) versions(response []*delivery.DeliveryVersionResponse) any {
+	versions := make([]*delivery.DeliveryVersionResponseV2, 0, len(response))
+	for _, v := range response {
+		versions = append(versions, v.ToDeliveryVersionResponseV2())
+	}
+	return versions
+}
+
+func (v2Mapper) preview(response *delivery.DeleteDeliveriesPreview) any {
+	return response.ToDeleteDeliveriesPreviewV2()
+}
+
+func (v2Mapper) result(response *delivery.DeleteDeliveriesResult) any {
+	return response.ToDeleteDeliveriesResultV2()
+}
@@ -2,50 +2,115 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/samluiz/delivery-service/api/http/utils"
 	"github.com/samluiz/delivery-service/internal/delivery"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
+// Função responsável por criar o validator com as regras de domínio das entregas, assim como na inicialização do serviço.
+func newValidator(t *testing.T) *utils.XValidator {
+	t.Helper()
+
+	validator, err := utils.NewXValidator(delivery.DefaultValidationConfig)
+	require.NoError(t, err)
+
+	return validator
+}
+
 // Mocks do service que o handler chama
 
 type MockDeliveryService struct {
-	CreateDeliveryFn      func(req *delivery.CreateDeliveryRequest) (*delivery.DeliveryResponse, error)
-	GetDeliveryFn         func(id int) (*delivery.DeliveryResponse, error)
-	GetDeliveriesFn       func(city string) ([]*delivery.DeliveryResponse, error)
-	UpdateDeliveryFn      func(req *delivery.UpdateDeliveryRequest, id int) (*delivery.DeliveryResponse, error)
-	DeleteDeliveryFn      func(id int) error
-	DeleteAllDeliveriesFn func() error
+	CreateDeliveryFn          func(req *delivery.CreateDeliveryRequest) (*delivery.DeliveryResponse, error)
+	GetDeliveryFn             func(id int) (*delivery.DeliveryResponse, error)
+	GetDeliveriesFn           func(filter *delivery.DeliveryFilter) ([]*delivery.DeliveryResponse, error)
+	GetDeliveriesByIDsFn      func(ids []int) ([]*delivery.DeliveryResponse, error)
+	UpdateDeliveryFn          func(req *delivery.UpdateDeliveryRequest, id int) (*delivery.DeliveryResponse, error)
+	DeleteDeliveryFn          func(id int) (*delivery.DeliveryResponse, error)
+	GetDeletedDeliveriesFn    func(filter *delivery.DeliveryFilter) ([]*delivery.DeliveryResponse, error)
+	RestoreDeliveryFn         func(id int) (*delivery.DeliveryResponse, error)
+	PreviewDeleteDeliveriesFn func(filter *delivery.DeliveryFilter) (*delivery.DeleteDeliveriesPreview, error)
+	DeleteDeliveriesFn        func(filter *delivery.DeliveryFilter, token string) (*delivery.DeleteDeliveriesResult, error)
+	GetDeliveryAsOfFn         func(id int, asOf time.Time) (*delivery.DeliveryResponse, error)
+	GetDeliveryVersionsFn     func(id int) ([]*delivery.DeliveryVersionResponse, error)
+	RevertDeliveryFn          func(id int, version int) (*delivery.DeliveryResponse, error)
 }
 
-func (m MockDeliveryService) CreateDelivery(req *delivery.CreateDeliveryRequest) (*delivery.DeliveryResponse, error) {
+func (m MockDeliveryService) CreateDelivery(ctx context.Context, req *delivery.CreateDeliveryRequest) (*delivery.DeliveryResponse, error) {
 	return m.CreateDeliveryFn(req)
 }
 
-func (m MockDeliveryService) GetDelivery(id int) (*delivery.DeliveryResponse, error) {
+func (m MockDeliveryService) GetDelivery(ctx context.Context, id int) (*delivery.DeliveryResponse, error) {
 	return m.GetDeliveryFn(id)
 }
 
-func (m MockDeliveryService) GetDeliveries(city string) ([]*delivery.DeliveryResponse, error) {
-	return m.GetDeliveriesFn(city)
+func (m MockDeliveryService) GetDeliveries(ctx context.Context, filter *delivery.DeliveryFilter) ([]*delivery.DeliveryResponse, error) {
+	return m.GetDeliveriesFn(filter)
+}
+
+func (m MockDeliveryService) GetDeliveriesByIDs(ctx context.Context, ids []int) ([]*delivery.DeliveryResponse, error) {
+	return m.GetDeliveriesByIDsFn(ids)
 }
 
-func (m MockDeliveryService) UpdateDelivery(req *delivery.UpdateDeliveryRequest, id int) (*delivery.DeliveryResponse, error) {
+func (m MockDeliveryService) UpdateDelivery(ctx context.Context, req *delivery.UpdateDeliveryRequest, id int) (*delivery.DeliveryResponse, error) {
 	return m.UpdateDeliveryFn(req, id)
 }
 
-func (m MockDeliveryService) DeleteDelivery(id int) error {
+func (m MockDeliveryService) DeleteDelivery(ctx context.Context, id int) (*delivery.DeliveryResponse, error) {
 	return m.DeleteDeliveryFn(id)
 }
 
-func (m MockDeliveryService) DeleteAllDeliveries() error {
-	return m.DeleteAllDeliveriesFn()
+func (m MockDeliveryService) GetDeletedDeliveries(ctx context.Context, filter *delivery.DeliveryFilter) ([]*delivery.DeliveryResponse, error) {
+	return m.GetDeletedDeliveriesFn(filter)
+}
+
+func (m MockDeliveryService) RestoreDelivery(ctx context.Context, id int) (*delivery.DeliveryResponse, error) {
+	return m.RestoreDeliveryFn(id)
+}
+
+func (m MockDeliveryService) PurgeDeletedDeliveries(ctx context.Context, retention time.Duration) (int64, error) {
+	return 0, nil
+}
+
+func (m MockDeliveryService) PreviewDeleteDeliveries(ctx context.Context, filter *delivery.DeliveryFilter) (*delivery.DeleteDeliveriesPreview, error) {
+	return m.PreviewDeleteDeliveriesFn(filter)
+}
+
+func (m MockDeliveryService) DeleteDeliveries(ctx context.Context, filter *delivery.DeliveryFilter, token string) (*delivery.DeleteDeliveriesResult, error) {
+	return m.DeleteDeliveriesFn(filter, token)
+}
+
+func (m MockDeliveryService) GetDeliveryAsOf(ctx context.Context, id int, asOf time.Time) (*delivery.DeliveryResponse, error) {
+	return m.GetDeliveryAsOfFn(id, asOf)
+}
+
+func (m MockDeliveryService) GetDeliveryVersions(ctx context.Context, id int) ([]*delivery.DeliveryVersionResponse, error) {
+	return m.GetDeliveryVersionsFn(id)
+}
+
+func (m MockDeliveryService) RevertDelivery(ctx context.Context, id int, version int) (*delivery.DeliveryResponse, error) {
+	return m.RevertDeliveryFn(id, version)
+}
+
+// Função auxiliar que retorna o ponteiro de uma coordenada dos requests
+func coordinate(value float64) *float64 {
+	return &value
 }
 
 // Testes dos handlers do servidor HTTP para garantir que as rotas estão respondendo corretamente
@@ -69,10 +134,10 @@ func TestHandleCreateDelivery(t *testing.T) {
 				Numero:      "123",
 				Bairro:      "Bairro A",
 				Cidade:      "Cidade A",
-				Estado:      "Estado A",
+				Estado:      "PE",
 				Pais:        "Brasil",
-				Latitude:    45.5,
-				Longitude:   12.5,
+				Latitude:    coordinate(45.5),
+				Longitude:   coordinate(12.5),
 			},
 			expectedStatus: http.StatusCreated,
 		},
@@ -95,10 +160,11 @@ func TestHandleCreateDelivery(t *testing.T) {
 					return &delivery.DeliveryResponse{ID: 1, Cliente: req.Cliente}, nil
 				},
 			}
-			handler := NewDeliveryHandler(deliveryServiceMock)
+			handler := NewDeliveryHandler(deliveryServiceMock, newValidator(t))
 
 			body, _ := json.Marshal(tt.requestBody)
 			req := httptest.NewRequest("POST", "/deliveries", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
 			w := httptest.NewRecorder()
 
 			handler.HandleCreateDelivery(w, req)
@@ -109,6 +175,104 @@ func TestHandleCreateDelivery(t *testing.T) {
 	}
 }
 
+func TestHandleCreateDelivery_StrictDecoding(t *testing.T) {
+	tests := []struct {
+		name           string
+		contentType    string
+		body           string
+		expectedStatus int
+	}{
+		{
+			name:           "Content-Type não suportado",
+			contentType:    "text/plain",
+			body:           `{"cliente":"Cliente A"}`,
+			expectedStatus: http.StatusUnsupportedMediaType,
+		},
+		{
+			name:           "Campo desconhecido",
+			contentType:    "application/json",
+			body:           `{"cliente":"Cliente A","prioridade":1}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Mais de um documento",
+			contentType:    "application/json",
+			body:           `{"cliente":"Cliente A"}{"cliente":"Cliente B"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deliveryServiceMock := MockDeliveryService{
+				CreateDeliveryFn: func(req *delivery.CreateDeliveryRequest) (*delivery.DeliveryResponse, error) {
+					t.Fatal("o service não deve ser chamado")
+					return nil, nil
+				},
+			}
+			handler := NewDeliveryHandler(deliveryServiceMock, newValidator(t))
+
+			req := httptest.NewRequest("POST", "/deliveries", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			w := httptest.NewRecorder()
+
+			handler.HandleCreateDelivery(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Result().StatusCode)
+		})
+	}
+}
+
+func TestHandleCreateDelivery_XML(t *testing.T) {
+	deliveryServiceMock := MockDeliveryService{
+		CreateDeliveryFn: func(req *delivery.CreateDeliveryRequest) (*delivery.DeliveryResponse, error) {
+			assert.Equal(t, "Cliente A", req.Cliente)
+			assert.Equal(t, "Recife", req.Cidade)
+			assert.Equal(t, -8.05, *req.Latitude)
+			return &delivery.DeliveryResponse{ID: 1, Cliente: req.Cliente, Cidade: req.Cidade}, nil
+		},
+	}
+	handler := NewDeliveryHandlerV2(deliveryServiceMock, newValidator(t))
+
+	body := `<delivery><customer>Cliente A</customer><weight>10.5</weight><address><line>Rua A, 123</line><street>Rua A</street>
+		<number>123</number><complement>Apto 1</complement><neighborhood>Bairro A</neighborhood><city>Recife</city><state>PE</state>
+		<country>BR</country></address><coordinates><latitude>-8.05</latitude><longitude>-34.9</longitude></coordinates></delivery>`
+
+	req := httptest.NewRequest("POST", "/v2/deliveries", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/xml")
+	req.Header.Set("Accept", "application/xml")
+	w := httptest.NewRecorder()
+
+	handler.HandleCreateDelivery(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "application/xml; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), "<response><id>1</id><customer>Cliente A</customer>")
+}
+
+func TestHandleGetDeliveries_CSV(t *testing.T) {
+	deliveryServiceMock := MockDeliveryService{
+		GetDeliveriesFn: func(filter *delivery.DeliveryFilter) ([]*delivery.DeliveryResponse, error) {
+			return []*delivery.DeliveryResponse{{ID: 1, Cliente: "Cliente A", Cidade: "Recife"}}, nil
+		},
+	}
+	handler := NewDeliveryHandlerV2(deliveryServiceMock, newValidator(t))
+
+	req := httptest.NewRequest("GET", "/v2/deliveries", nil)
+	req.Header.Set("Accept", "text/csv")
+	w := httptest.NewRecorder()
+
+	handler.HandleGetDeliveries(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+
+	lines := strings.Split(w.Body.String(), "\n")
+	assert.Equal(t, "id,customer,weight,address.line,address.street,address.number,address.complement,address.neighborhood,"+
+		"address.city,address.state,address.country,coordinates.latitude,coordinates.longitude,driver,created_at,updated_at", lines[0])
+	assert.True(t, strings.HasPrefix(lines[1], "1,Cliente A,0,"))
+}
+
 func TestHandleGetDelivery(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -145,7 +309,7 @@ func TestHandleGetDelivery(t *testing.T) {
 				},
 			}
 
-			handler := NewDeliveryHandler(deliveryServiceMock)
+			handler := NewDeliveryHandler(deliveryServiceMock, newValidator(t))
 
 			mux := http.NewServeMux()
 			mux.HandleFunc("/deliveries/{id}", handler.HandleGetDelivery)
@@ -161,6 +325,80 @@ func TestHandleGetDelivery(t *testing.T) {
 	}
 }
 
+func TestHandleGetDelivery_SpanError(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(noop.NewTracerProvider())
+
+	tests := []struct {
+		name           string
+		id             string
+		serviceError   error
+		expectedStatus int
+		expectedSpan   codes.Code
+	}{
+		{
+			name:           "service error",
+			id:             "1",
+			serviceError:   errors.New("database unavailable"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedSpan:   codes.Error,
+		},
+		{
+			name:           "not found",
+			id:             "1",
+			serviceError:   delivery.ErrDeliveryNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedSpan:   codes.Error,
+		},
+		{
+			name:           "invalid id",
+			id:             "invalid",
+			expectedStatus: http.StatusBadRequest,
+			expectedSpan:   codes.Unset,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			deliveryServiceMock := MockDeliveryService{
+				GetDeliveryFn: func(id int) (*delivery.DeliveryResponse, error) {
+					return nil, tt.serviceError
+				},
+			}
+
+			handler := NewDeliveryHandler(deliveryServiceMock, newValidator(t))
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("/deliveries/{id}", handler.HandleGetDelivery)
+
+			req := httptest.NewRequest("GET", fmt.Sprintf("/deliveries/%s", tt.id), nil)
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Result().StatusCode)
+
+			// O erro do service é registrado no span do handler
+			spans := recorder.Ended()
+			require.NotEmpty(t, spans)
+
+			span := spans[len(spans)-1]
+			assert.Equal(t, "DeliveryHandler.HandleGetDelivery", span.Name())
+			assert.Equal(t, tt.expectedSpan, span.Status().Code)
+
+			if tt.serviceError != nil {
+				assert.Equal(t, tt.serviceError.Error(), span.Status().Description)
+				require.Len(t, span.Events(), 1)
+				assert.Equal(t, "exception", span.Events()[0].Name)
+			} else {
+				assert.Empty(t, span.Events())
+			}
+		})
+	}
+}
+
 func TestHandleGetDeliveries(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -183,16 +421,17 @@ func TestHandleGetDeliveries(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			deliveryServiceMock := MockDeliveryService{
-				GetDeliveriesFn: func(city string) ([]*delivery.DeliveryResponse, error) {
+				GetDeliveriesFn: func(filter *delivery.DeliveryFilter) ([]*delivery.DeliveryResponse, error) {
 					if tt.expectedError != nil {
 						return nil, tt.expectedError
 					}
+					assert.Equal(t, tt.city, filter.Cidade)
 					return []*delivery.DeliveryResponse{
-						{ID: 1, Cliente: "Client A", Cidade: city},
+						{ID: 1, Cliente: "Client A", Cidade: filter.Cidade},
 					}, nil
 				},
 			}
-			handler := NewDeliveryHandler(deliveryServiceMock)
+			handler := NewDeliveryHandler(deliveryServiceMock, newValidator(t))
 
 			cityEncoded := url.QueryEscape(tt.city)
 			var req *http.Request
@@ -232,10 +471,10 @@ func TestHandleUpdateDelivery(t *testing.T) {
 				Numero:      "123",
 				Bairro:      "Bairro A",
 				Cidade:      "Cidade A",
-				Estado:      "Estado A",
+				Estado:      "PE",
 				Pais:        "Brasil",
-				Latitude:    45.5,
-				Longitude:   12.5,
+				Latitude:    coordinate(45.5),
+				Longitude:   coordinate(12.5),
 			},
 			expectedStatus: http.StatusOK,
 		},
@@ -255,10 +494,10 @@ func TestHandleUpdateDelivery(t *testing.T) {
 				Numero:      "123",
 				Bairro:      "Bairro A",
 				Cidade:      "Cidade A",
-				Estado:      "Estado A",
+				Estado:      "PE",
 				Pais:        "Brasil",
-				Latitude:    45.5,
-				Longitude:   12.5,
+				Latitude:    coordinate(45.5),
+				Longitude:   coordinate(12.5),
 			},
 			expectedStatus: http.StatusNotFound,
 			expectedError:  delivery.ErrDeliveryNotFound,
@@ -275,13 +514,14 @@ func TestHandleUpdateDelivery(t *testing.T) {
 					return &delivery.DeliveryResponse{ID: id, Cliente: "Client A"}, nil
 				},
 			}
-			handler := NewDeliveryHandler(deliveryServiceMock)
+			handler := NewDeliveryHandler(deliveryServiceMock, newValidator(t))
 
 			mux := http.NewServeMux()
 			mux.HandleFunc("/deliveries/{id}", handler.HandleUpdateDelivery)
 
 			body, _ := json.Marshal(tt.requestBody)
 			req := httptest.NewRequest("PUT", fmt.Sprintf("/deliveries/%s", tt.id), bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
 
 			w := httptest.NewRecorder()
 
@@ -321,14 +561,14 @@ func TestHandleDeleteDelivery(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			deliveryServiceMock := MockDeliveryService{
-				DeleteDeliveryFn: func(id int) error {
+				DeleteDeliveryFn: func(id int) (*delivery.DeliveryResponse, error) {
 					if tt.expectedError != nil {
-						return tt.expectedError
+						return nil, tt.expectedError
 					}
-					return nil
+					return &delivery.DeliveryResponse{ID: id}, nil
 				},
 			}
-			handler := NewDeliveryHandler(deliveryServiceMock)
+			handler := NewDeliveryHandler(deliveryServiceMock, newValidator(t))
 
 			mux := http.NewServeMux()
 			mux.HandleFunc("/deliveries/{id}", handler.HandleDeleteDelivery)
@@ -345,19 +585,324 @@ func TestHandleDeleteDelivery(t *testing.T) {
 	}
 }
 
-func TestHandleDeleteAllDeliveries(t *testing.T) {
+func TestHandleGetDeletedDeliveries(t *testing.T) {
 	deliveryServiceMock := MockDeliveryService{
-		DeleteAllDeliveriesFn: func() error {
-			return nil
+		GetDeletedDeliveriesFn: func(filter *delivery.DeliveryFilter) ([]*delivery.DeliveryResponse, error) {
+			assert.Equal(t, "Cidade A", filter.Cidade)
+			return []*delivery.DeliveryResponse{{ID: 1}}, nil
 		},
 	}
-	handler := NewDeliveryHandler(deliveryServiceMock)
+	handler := NewDeliveryHandler(deliveryServiceMock, newValidator(t))
 
-	req := httptest.NewRequest("DELETE", "/deliveries", nil)
+	req := httptest.NewRequest("GET", "/deliveries/trash?city=Cidade+A", nil)
 	w := httptest.NewRecorder()
 
-	handler.HandleDeleteAllDeliveries(w, req)
+	handler.HandleGetDeletedDeliveries(w, req)
 
 	res := w.Result()
-	assert.Equal(t, http.StatusNoContent, res.StatusCode)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+}
+
+func TestHandleRestoreDelivery(t *testing.T) {
+	tests := []struct {
+		name           string
+		id             string
+		expectedStatus int
+		expectedError  error
+	}{
+		{
+			name:           "Restauração com sucesso",
+			id:             "1",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "ID inválido",
+			id:             "abc",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Entrega fora da lixeira",
+			id:             "2",
+			expectedStatus: http.StatusNotFound,
+			expectedError:  delivery.ErrDeliveryNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deliveryServiceMock := MockDeliveryService{
+				RestoreDeliveryFn: func(id int) (*delivery.DeliveryResponse, error) {
+					if tt.expectedError != nil {
+						return nil, tt.expectedError
+					}
+					return &delivery.DeliveryResponse{ID: id}, nil
+				},
+			}
+			handler := NewDeliveryHandler(deliveryServiceMock, newValidator(t))
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("POST /deliveries/{id}/restore", handler.HandleRestoreDelivery)
+
+			req := httptest.NewRequest("POST", "/deliveries/"+tt.id+"/restore", nil)
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			res := w.Result()
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+		})
+	}
+}
+
+func TestHandleGetDeliveryAsOf(t *testing.T) {
+	tests := []struct {
+		name           string
+		asOf           string
+		expectedStatus int
+		expectedError  error
+	}{
+		{
+			name:           "Entrega no instante informado",
+			asOf:           "2024-01-01T12:00:00Z",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Data inválida",
+			asOf:           "ontem",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Entrega inexistente no instante informado",
+			asOf:           "2020-01-01T00:00:00Z",
+			expectedStatus: http.StatusNotFound,
+			expectedError:  delivery.ErrDeliveryNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deliveryServiceMock := MockDeliveryService{
+				GetDeliveryAsOfFn: func(id int, asOf time.Time) (*delivery.DeliveryResponse, error) {
+					assert.Equal(t, tt.asOf, asOf.Format(time.RFC3339))
+					if tt.expectedError != nil {
+						return nil, tt.expectedError
+					}
+					return &delivery.DeliveryResponse{ID: id}, nil
+				},
+			}
+			handler := NewDeliveryHandler(deliveryServiceMock, newValidator(t))
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /deliveries/{id}", handler.HandleGetDelivery)
+
+			req := httptest.NewRequest("GET", "/deliveries/1?as_of="+url.QueryEscape(tt.asOf), nil)
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			res := w.Result()
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+		})
+	}
+}
+
+func TestHandleGetDeliveryVersions(t *testing.T) {
+	tests := []struct {
+		name           string
+		id             string
+		expectedStatus int
+		expectedError  error
+	}{
+		{
+			name:           "Histórico de versões",
+			id:             "1",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "ID inválido",
+			id:             "abc",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Entrega inexistente",
+			id:             "2",
+			expectedStatus: http.StatusNotFound,
+			expectedError:  delivery.ErrDeliveryNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deliveryServiceMock := MockDeliveryService{
+				GetDeliveryVersionsFn: func(id int) ([]*delivery.DeliveryVersionResponse, error) {
+					if tt.expectedError != nil {
+						return nil, tt.expectedError
+					}
+					return []*delivery.DeliveryVersionResponse{{Versao: 1, Entrega: &delivery.DeliveryResponse{ID: id}}}, nil
+				},
+			}
+			handler := NewDeliveryHandler(deliveryServiceMock, newValidator(t))
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /deliveries/{id}/versions", handler.HandleGetDeliveryVersions)
+
+			req := httptest.NewRequest("GET", "/deliveries/"+tt.id+"/versions", nil)
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			res := w.Result()
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+		})
+	}
+}
+
+func TestHandleRevertDelivery(t *testing.T) {
+	tests := []struct {
+		name           string
+		id             string
+		version        string
+		expectedStatus int
+		expectedError  error
+	}{
+		{
+			name:           "Reversão com sucesso",
+			id:             "1",
+			version:        "2",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Versão inválida",
+			id:             "1",
+			version:        "abc",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Versão inexistente",
+			id:             "1",
+			version:        "9",
+			expectedStatus: http.StatusNotFound,
+			expectedError:  delivery.ErrVersionNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deliveryServiceMock := MockDeliveryService{
+				RevertDeliveryFn: func(id int, version int) (*delivery.DeliveryResponse, error) {
+					if tt.expectedError != nil {
+						return nil, tt.expectedError
+					}
+					return &delivery.DeliveryResponse{ID: id}, nil
+				},
+			}
+			handler := NewDeliveryHandler(deliveryServiceMock, newValidator(t))
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("POST /deliveries/{id}/versions/{version}/revert", handler.HandleRevertDelivery)
+
+			req := httptest.NewRequest("POST", "/deliveries/"+tt.id+"/versions/"+tt.version+"/revert", nil)
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			res := w.Result()
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+		})
+	}
+}
+
+func TestHandleDeleteDeliveries(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		token          string
+		previewErr     error
+		deleteErr      error
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "Dry run",
+			url:            "/deliveries?dry_run=true&city=Cidade+A",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"token_confirmacao":"token"`,
+		},
+		{
+			name:           "Exclusão confirmada",
+			url:            "/deliveries?city=Cidade+A",
+			token:          "token",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"quantidade":2`,
+		},
+		{
+			name:           "Exclusão desabilitada",
+			url:            "/deliveries?dry_run=true&city=Cidade+A",
+			previewErr:     delivery.ErrBulkDeleteDisabled,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Sem token de confirmação",
+			url:            "/deliveries",
+			deleteErr:      delivery.ErrMissingConfirmationToken,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Token de confirmação inválido",
+			url:            "/deliveries",
+			token:          "invalid",
+			deleteErr:      delivery.ErrInvalidConfirmationToken,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Entregas alteradas desde o dry run",
+			url:            "/deliveries",
+			token:          "token",
+			deleteErr:      delivery.ErrConfirmationMismatch,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "Erro interno",
+			url:            "/deliveries",
+			token:          "token",
+			deleteErr:      fmt.Errorf("internal error"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deliveryServiceMock := MockDeliveryService{
+				PreviewDeleteDeliveriesFn: func(filter *delivery.DeliveryFilter) (*delivery.DeleteDeliveriesPreview, error) {
+					assert.Equal(t, "Cidade A", filter.Cidade)
+					if tt.previewErr != nil {
+						return nil, tt.previewErr
+					}
+					return &delivery.DeleteDeliveriesPreview{Quantidade: 2, TokenConfirmacao: "token"}, nil
+				},
+				DeleteDeliveriesFn: func(filter *delivery.DeliveryFilter, token string) (*delivery.DeleteDeliveriesResult, error) {
+					if tt.deleteErr != nil {
+						return nil, tt.deleteErr
+					}
+					assert.Equal(t, "token", token)
+					return &delivery.DeleteDeliveriesResult{Quantidade: 2}, nil
+				},
+			}
+			handler := NewDeliveryHandler(deliveryServiceMock, newValidator(t))
+
+			req := httptest.NewRequest("DELETE", tt.url, nil)
+			if tt.token != "" {
+				req.Header.Set(ConfirmationTokenHeader, tt.token)
+			}
+			w := httptest.NewRecorder()
+
+			handler.HandleDeleteDeliveries(w, req)
+
+			res := w.Result()
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+			if tt.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBody)
+			}
+		})
+	}
 }
@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/samluiz/delivery-service/api/docs"
+)
+
+type DocsHandler struct{}
+
+func NewDocsHandler() *DocsHandler {
+	return &DocsHandler{}
+}
+
+// Função responsável por servir o documento OpenAPI embutido no binário.
+func (h DocsHandler) HandleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(docs.OpenAPI)
+}
+
+// Função responsável por servir a página do Swagger UI, que consome /openapi.json.
+func (h DocsHandler) HandleDocs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(docs.Index)
+}
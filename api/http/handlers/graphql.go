@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/samluiz/delivery-service/api/graphql/server"
+	"github.com/samluiz/delivery-service/api/http/utils"
+	"github.com/samluiz/delivery-service/internal/concurrency"
+)
+
+type GraphQLHandler struct {
+	server    *server.Server
+	validator *utils.XValidator
+}
+
+func NewGraphQLHandler(server *server.Server, validator *utils.XValidator) *GraphQLHandler {
+	return &GraphQLHandler{server: server, validator: validator}
+}
+
+// Função responsável por executar as operações da API GraphQL.
+// Requisições rejeitadas antes da execução (sintaxe, schema ou limites) retornam 400; as executadas retornam 200,
+// com os erros de cada campo (ex: validação, permissão) na lista errors do resultado.
+func (h GraphQLHandler) HandleGraphQL(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracer.Start(r.Context(), "GraphQLHandler.HandleGraphQL")
+	defer span.End()
+
+	var request server.Request
+
+	if requestError := utils.DecodeJSONBody(w, r, &request); requestError != nil {
+		utils.NewJSONResponse(w, requestError.Status, requestError)
+		return
+	}
+
+	if requestError := h.validator.ValidateBody(r, &request); requestError != nil {
+		utils.NewJSONResponse(w, requestError.Status, requestError)
+		return
+	}
+
+	result, executed := h.server.Execute(ctx, request, utils.LanguageFromRequest(r))
+
+	// Registrando no span os erros da operação, inclusive os dos campos executados
+	for _, err := range result.Errors {
+		recordError(span, err)
+	}
+
+	if !executed {
+		utils.NewJSONResponse(w, http.StatusBadRequest, result)
+		return
+	}
+
+	utils.NewJSONResponse(w, http.StatusOK, result)
+}
+
+// Função responsável por classificar a requisição GraphQL para o limite de concorrência pelo tipo da operação:
+// queries são da classe read, e mutations ou requisições que não puderem ser interpretadas, da classe write.
+// O corpo é lido até o tamanho máximo aceito e devolvido à requisição, para ser lido novamente pelo handler.
+func (h GraphQLHandler) RequestClass(r *http.Request) concurrency.Class {
+	body, err := io.ReadAll(io.LimitReader(r.Body, utils.MaxBodyBytes()+1))
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), r.Body), r.Body}
+
+	if err != nil || int64(len(body)) > utils.MaxBodyBytes() {
+		return concurrency.ClassWrite
+	}
+
+	var request server.Request
+	if err := json.Unmarshal(body, &request); err != nil || !server.IsQuery(request) {
+		return concurrency.ClassWrite
+	}
+	return concurrency.ClassRead
+}
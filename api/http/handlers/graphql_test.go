@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/samluiz/delivery-service/api/graphql/server"
+	"github.com/samluiz/delivery-service/api/http/utils"
+	"github.com/samluiz/delivery-service/internal/apikey"
+	"github.com/samluiz/delivery-service/internal/auth"
+	"github.com/samluiz/delivery-service/internal/concurrency"
+	"github.com/samluiz/delivery-service/internal/delivery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Testes do handler da API GraphQL
+
+func TestHandleGraphQL(t *testing.T) {
+	policy := auth.Policy{Scope: apikey.ScopeRead, Roles: []auth.Role{auth.RoleAdmin}}
+	graphqlServer, err := server.NewServer(MockDeliveryService{
+		GetDeliveriesByIDsFn: func(ids []int) ([]*delivery.DeliveryResponse, error) {
+			return []*delivery.DeliveryResponse{{ID: 1, Cliente: "Client A"}}, nil
+		},
+	}, newValidator(t), server.Policies{Read: policy, Create: policy, Write: policy}, server.Limits{MaxDepth: 4})
+	require.NoError(t, err)
+
+	handler := NewGraphQLHandler(graphqlServer, newValidator(t))
+
+	tests := []struct {
+		name           string
+		contentType    string
+		body           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "query",
+			contentType:    "application/json",
+			body:           `{"query": "query($id: ID!) { delivery(id: $id) { id customer } }", "variables": {"id": "1"}}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"data": {"delivery": {"id": "1", "customer": "Client A"}}}`,
+		},
+		{
+			name:           "field error",
+			contentType:    "application/json",
+			body:           `{"query": "{ delivery(id: \"abc\") { id } }"}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "syntax error",
+			contentType:    "application/json",
+			body:           `{"query": "{ delivery(id: \"1\") {"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "too deep",
+			contentType:    "application/json",
+			body:           `{"query": "{ deliveries { edges { node { address { city } } } } }"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing query",
+			contentType:    "application/json",
+			body:           `{"variables": {}}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unsupported content type",
+			contentType:    "application/graphql",
+			body:           `{ delivery(id: "1") { id } }`,
+			expectedStatus: http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/graphql", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+
+			// O principal é registrado pelo Authenticator.RequireAuthentication
+			ctx := apikey.WithAPIKey(context.Background(), &apikey.APIKey{ID: 1, Escopos: []apikey.Scope{apikey.ScopeRead}})
+			ctx = auth.WithPrincipal(ctx, &auth.Principal{Type: auth.PrincipalAPIKey, Subject: "apikey:1"})
+			req = req.WithContext(ctx)
+
+			w := httptest.NewRecorder()
+
+			handler.HandleGraphQL(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+			if tt.expectedStatus == http.StatusOK && tt.expectedBody == "" {
+				var response map[string]interface{}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.NotEmpty(t, response["errors"])
+			}
+		})
+	}
+}
+
+func TestGraphQLHandler_RequestClass(t *testing.T) {
+	handler := NewGraphQLHandler(nil, newValidator(t))
+
+	tests := []struct {
+		name     string
+		body     string
+		expected concurrency.Class
+	}{
+		{"query", `{"query": "{ delivery(id: \"1\") { id } }"}`, concurrency.ClassRead},
+		{"mutation", `{"query": "mutation { deleteDelivery(id: \"1\") }"}`, concurrency.ClassWrite},
+		{"invalid json", `{"query": `, concurrency.ClassWrite},
+		{"body too large", `{"query": "{ delivery(id: \"1\") { id } }", "padding": "` + strings.Repeat("a", int(utils.MaxBodyBytes())) + `"}`, concurrency.ClassWrite},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/graphql", strings.NewReader(tt.body))
+
+			assert.Equal(t, tt.expected, handler.RequestClass(req))
+
+			// O corpo continua disponível para o handler
+			body, err := io.ReadAll(req.Body)
+			require.NoError(t, err)
+			assert.Equal(t, tt.body, string(body))
+		})
+	}
+}
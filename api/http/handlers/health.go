@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/samluiz/delivery-service/api/http/utils"
+	"github.com/samluiz/delivery-service/internal/health"
+)
+
+type HealthHandler struct {
+	healthService health.IHealthService
+}
+
+func NewHealthHandler(healthService health.IHealthService) *HealthHandler {
+	return &HealthHandler{healthService: healthService}
+}
+
+func (h HealthHandler) HandleLiveness(w http.ResponseWriter, r *http.Request) {
+	utils.NewJSONResponse(w, http.StatusOK, h.healthService.Liveness())
+}
+
+func (h HealthHandler) HandleReadiness(w http.ResponseWriter, r *http.Request) {
+	report := h.healthService.Readiness(r.Context())
+
+	// Retornando 503 para que o orquestrador pare de enviar tráfego para a instância
+	if report.Status != health.StatusUp {
+		utils.NewJSONResponse(w, http.StatusServiceUnavailable, report)
+		return
+	}
+
+	utils.NewJSONResponse(w, http.StatusOK, report)
+}
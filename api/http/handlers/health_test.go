@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/samluiz/delivery-service/internal/health"
+	"github.com/stretchr/testify/assert"
+)
+
+// Mocks do service de saúde
+
+type MockHealthService struct {
+	ReadinessFn func(ctx context.Context) *health.Report
+}
+
+func (m MockHealthService) Liveness() *health.Report {
+	return &health.Report{Status: health.StatusUp}
+}
+
+func (m MockHealthService) Readiness(ctx context.Context) *health.Report {
+	return m.ReadinessFn(ctx)
+}
+
+// Testes dos handlers de liveness e readiness
+
+func TestHandleLiveness(t *testing.T) {
+	handler := NewHealthHandler(MockHealthService{})
+
+	req := httptest.NewRequest("GET", "/health/live", nil)
+	w := httptest.NewRecorder()
+
+	handler.HandleLiveness(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"status":"up"}`, w.Body.String())
+}
+
+func TestHandleReadiness(t *testing.T) {
+	tests := []struct {
+		name           string
+		report         *health.Report
+		expectedStatus int
+	}{
+		{
+			name: "all dependencies up",
+			report: &health.Report{Status: health.StatusUp, Checks: []health.CheckResult{
+				{Name: "database", Status: health.StatusUp, LatencyMs: 1.5},
+			}},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "database down",
+			report: &health.Report{Status: health.StatusDown, Checks: []health.CheckResult{
+				{Name: "database", Status: health.StatusDown, Error: "connection refused"},
+			}},
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewHealthHandler(MockHealthService{
+				ReadinessFn: func(ctx context.Context) *health.Report {
+					return tt.report
+				},
+			})
+
+			req := httptest.NewRequest("GET", "/health/ready", nil)
+			w := httptest.NewRecorder()
+
+			handler.HandleReadiness(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var report health.Report
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&report))
+			assert.Equal(t, tt.report.Status, report.Status)
+			assert.Equal(t, tt.report.Checks[0].Name, report.Checks[0].Name)
+		})
+	}
+}
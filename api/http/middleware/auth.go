@@ -0,0 +1,166 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/samluiz/delivery-service/api/http/utils"
+	"github.com/samluiz/delivery-service/internal/apikey"
+	"github.com/samluiz/delivery-service/internal/auth"
+	"github.com/samluiz/delivery-service/internal/tenant"
+)
+
+var (
+	ErrMissingCredentials = auth.ErrUnauthenticated
+	ErrJWTDisabled        = errors.New("jwt authentication is not configured")
+)
+
+// Struct responsável por autenticar as requisições por chave de API ou JWT.
+type Authenticator struct {
+	apiKeyService apikey.IAPIKeyService
+	jwtValidator  auth.IJWTValidator
+}
+
+// Função responsável por instanciar o autenticador.
+// O validador de JWT é opcional, quando nulo apenas chaves de API são aceitas.
+func NewAuthenticator(apiKeyService apikey.IAPIKeyService, jwtValidator auth.IJWTValidator) *Authenticator {
+	return &Authenticator{apiKeyService: apiKeyService, jwtValidator: jwtValidator}
+}
+
+// Erro de autenticação, com o status que deve ser retornado ao cliente:
+// 401 para credenciais ausentes ou inválidas e 403 para credenciais sem permissão.
+type AuthError struct {
+	Status int
+	Err    error
+}
+
+func (e *AuthError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *AuthError) Unwrap() error {
+	return e.Err
+}
+
+// Função responsável por exigir credenciais válidas que satisfaçam a política da rota.
+// Chaves de API podem ser enviadas em "X-API-Key: <chave>" ou "Authorization: Bearer <chave>",
+// e JWTs em "Authorization: Bearer <token>".
+// O principal autenticado fica disponível no contexto da requisição (auth.FromContext).
+func (a *Authenticator) Require(policy auth.Policy) func(http.HandlerFunc) http.HandlerFunc {
+	return a.middleware(func(r *http.Request) (context.Context, error) {
+		return a.Authenticate(r.Context(), r.Header.Get("X-API-Key"), r.Header.Get("Authorization"), policy)
+	})
+}
+
+// Função responsável por exigir credenciais válidas sem verificar nenhuma política.
+// Utilizada por rotas que autorizam cada operação separadamente (auth.Authorize), como a da API GraphQL.
+func (a *Authenticator) RequireAuthentication() func(http.HandlerFunc) http.HandlerFunc {
+	return a.middleware(func(r *http.Request) (context.Context, error) {
+		return a.identify(r.Context(), r.Header.Get("X-API-Key"), r.Header.Get("Authorization"))
+	})
+}
+
+// Função responsável por montar o middleware que responde as falhas de autenticação com o status do *AuthError.
+func (a *Authenticator) middleware(authenticate func(r *http.Request) (context.Context, error)) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			ctx, err := authenticate(r)
+
+			var authError *AuthError
+			switch {
+			case err == nil:
+				next(w, r.WithContext(ctx))
+			case !errors.As(err, &authError):
+				utils.NewJSONResponse(w, http.StatusInternalServerError, utils.NewInternalServerError(err, r))
+			case authError.Status == http.StatusForbidden:
+				utils.NewJSONResponse(w, http.StatusForbidden, utils.NewForbiddenError(err, r))
+			default:
+				unauthorized(w, r, err)
+			}
+		}
+	}
+}
+
+// Função responsável por autenticar a credencial enviada nos headers X-API-Key ou Authorization
+// e verificar se ela satisfaz a política. Retorna o contexto com o principal autenticado.
+// Falhas de autenticação são retornadas como *AuthError, as demais indicam erro interno.
+// Utilizada também pela API gRPC, que recebe os mesmos headers nos metadados.
+func (a *Authenticator) Authenticate(ctx context.Context, apiKeyHeader string, authorizationHeader string, policy auth.Policy) (context.Context, error) {
+	ctx, err := a.identify(ctx, apiKeyHeader, authorizationHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := auth.Authorize(ctx, policy); err != nil {
+		status := http.StatusForbidden
+		if errors.Is(err, auth.ErrUnauthenticated) {
+			status = http.StatusUnauthorized
+		}
+		return nil, &AuthError{Status: status, Err: err}
+	}
+
+	return ctx, nil
+}
+
+// Função responsável por validar a credencial e registrar o principal (e a chave de API) no contexto,
+// junto com o tenant da credencial: o da chave de API ou o da claim do JWT.
+func (a *Authenticator) identify(ctx context.Context, apiKeyHeader string, authorizationHeader string) (context.Context, error) {
+	credential, isAPIKey := extractCredential(apiKeyHeader, authorizationHeader)
+
+	if credential == "" {
+		return nil, &AuthError{Status: http.StatusUnauthorized, Err: ErrMissingCredentials}
+	}
+
+	if isAPIKey {
+		key, err := a.apiKeyService.Authenticate(ctx, credential)
+
+		if err != nil {
+			// Chave inexistente ou revogada
+			if errors.Is(err, apikey.ErrAPIKeyInvalid) {
+				return nil, &AuthError{Status: http.StatusUnauthorized, Err: err}
+			}
+			return nil, err
+		}
+
+		principal := &auth.Principal{Type: auth.PrincipalAPIKey, Subject: "apikey:" + strconv.Itoa(key.ID), Tenant: key.TenantID}
+		ctx = tenant.WithTenant(apikey.WithAPIKey(ctx, key), key.TenantID)
+		return auth.WithPrincipal(ctx, principal), nil
+	}
+
+	if a.jwtValidator == nil {
+		return nil, &AuthError{Status: http.StatusUnauthorized, Err: ErrJWTDisabled}
+	}
+
+	user, err := a.jwtValidator.Validate(credential)
+
+	if err != nil {
+		return nil, &AuthError{Status: http.StatusUnauthorized, Err: err}
+	}
+
+	return auth.WithPrincipal(tenant.WithTenant(ctx, user.Tenant), user), nil
+}
+
+// Função responsável por buscar a credencial nos headers X-API-Key e Authorization.
+// Retorna também se a credencial é uma chave de API (em vez de um JWT).
+func extractCredential(apiKeyHeader string, authorizationHeader string) (string, bool) {
+	if key := strings.TrimSpace(apiKeyHeader); key != "" {
+		return key, true
+	}
+
+	scheme, token, found := strings.Cut(authorizationHeader, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+
+	token = strings.TrimSpace(token)
+	return token, apikey.IsAPIKey(token)
+}
+
+// Função responsável por responder 401 indicando o esquema de autenticação esperado.
+func unauthorized(w http.ResponseWriter, r *http.Request, err error) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="delivery-service"`)
+	utils.NewJSONResponse(w, http.StatusUnauthorized, utils.NewUnauthorizedError(err, r))
+}
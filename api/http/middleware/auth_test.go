@@ -0,0 +1,279 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/samluiz/delivery-service/internal/apikey"
+	"github.com/samluiz/delivery-service/internal/auth"
+	"github.com/samluiz/delivery-service/internal/tenant"
+	"github.com/stretchr/testify/assert"
+)
+
+// Mock do service de chaves de API
+
+type MockAPIKeyService struct {
+	keys map[string]*apikey.APIKey
+}
+
+func (m MockAPIKeyService) CreateAPIKey(ctx context.Context, request *apikey.CreateAPIKeyRequest) (*apikey.CreateAPIKeyResponse, error) {
+	return nil, nil
+}
+
+func (m MockAPIKeyService) GetAPIKeys(ctx context.Context) ([]*apikey.APIKeyResponse, error) {
+	return nil, nil
+}
+
+func (m MockAPIKeyService) RevokeAPIKey(ctx context.Context, id int) error {
+	return nil
+}
+
+func (m MockAPIKeyService) Authenticate(ctx context.Context, rawKey string) (*apikey.APIKey, error) {
+	if rawKey == "dsk_error" {
+		return nil, errors.New("database error")
+	}
+	if key, ok := m.keys[rawKey]; ok {
+		return key, nil
+	}
+	return nil, apikey.ErrAPIKeyInvalid
+}
+
+// Mock do validador de JWTs
+
+type MockJWTValidator struct {
+	principals map[string]*auth.Principal
+}
+
+func (m MockJWTValidator) Validate(token string) (*auth.Principal, error) {
+	if principal, ok := m.principals[token]; ok {
+		return principal, nil
+	}
+	return nil, auth.ErrInvalidToken
+}
+
+// Testes do middleware de autenticação e autorização
+
+func TestRequire(t *testing.T) {
+	service := MockAPIKeyService{keys: map[string]*apikey.APIKey{
+		"dsk_reader": {ID: 1, Escopos: []apikey.Scope{apikey.ScopeRead}},
+		"dsk_admin":  {ID: 2, Escopos: []apikey.Scope{apikey.ScopeAdmin}},
+	}}
+	validator := MockJWTValidator{principals: map[string]*auth.Principal{
+		"jwt.driver": {Type: auth.PrincipalUser, Subject: "driver-1", Roles: []auth.Role{auth.RoleDriver}},
+		"jwt.admin":  {Type: auth.PrincipalUser, Subject: "admin-1", Roles: []auth.Role{auth.RoleAdmin}},
+	}}
+
+	readPolicy := auth.Policy{Scope: apikey.ScopeRead, Roles: []auth.Role{auth.RoleAdmin, auth.RoleDispatcher, auth.RoleDriver}}
+	adminPolicy := auth.Policy{Scope: apikey.ScopeAdmin, Roles: []auth.Role{auth.RoleAdmin}}
+
+	tests := []struct {
+		name            string
+		policy          auth.Policy
+		headers         map[string]string
+		expectedStatus  int
+		expectedSubject string
+	}{
+		{
+			name:           "missing credentials",
+			policy:         readPolicy,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "invalid key",
+			policy:         readPolicy,
+			headers:        map[string]string{"X-API-Key": "dsk_unknown"},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:            "bearer key with scope",
+			policy:          readPolicy,
+			headers:         map[string]string{"Authorization": "Bearer dsk_reader"},
+			expectedStatus:  http.StatusOK,
+			expectedSubject: "apikey:1",
+		},
+		{
+			name:            "x-api-key with scope",
+			policy:          readPolicy,
+			headers:         map[string]string{"X-API-Key": "dsk_reader"},
+			expectedStatus:  http.StatusOK,
+			expectedSubject: "apikey:1",
+		},
+		{
+			name:           "key without scope",
+			policy:         adminPolicy,
+			headers:        map[string]string{"X-API-Key": "dsk_reader"},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:            "admin key",
+			policy:          adminPolicy,
+			headers:         map[string]string{"Authorization": "bearer dsk_admin"},
+			expectedStatus:  http.StatusOK,
+			expectedSubject: "apikey:2",
+		},
+		{
+			name:           "authentication error",
+			policy:         readPolicy,
+			headers:        map[string]string{"X-API-Key": "dsk_error"},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:            "jwt with role",
+			policy:          readPolicy,
+			headers:         map[string]string{"Authorization": "Bearer jwt.driver"},
+			expectedStatus:  http.StatusOK,
+			expectedSubject: "driver-1",
+		},
+		{
+			name:           "jwt without role",
+			policy:         adminPolicy,
+			headers:        map[string]string{"Authorization": "Bearer jwt.driver"},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "invalid jwt",
+			policy:         readPolicy,
+			headers:        map[string]string{"Authorization": "Bearer jwt.invalid"},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:            "admin jwt",
+			policy:          adminPolicy,
+			headers:         map[string]string{"Authorization": "Bearer jwt.admin"},
+			expectedStatus:  http.StatusOK,
+			expectedSubject: "admin-1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var principal *auth.Principal
+			authenticator := NewAuthenticator(service, validator)
+			handler := authenticator.Require(tt.policy)(func(w http.ResponseWriter, r *http.Request) {
+				principal, _ = auth.FromContext(r.Context())
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest("GET", "/deliveries", nil)
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+			w := httptest.NewRecorder()
+
+			handler(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, tt.expectedSubject, principal.Subject)
+			}
+			if tt.expectedStatus == http.StatusUnauthorized {
+				assert.NotEmpty(t, w.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func TestRequire_ResolvesTenant(t *testing.T) {
+	service := MockAPIKeyService{keys: map[string]*apikey.APIKey{
+		"dsk_acme": {ID: 1, Escopos: []apikey.Scope{apikey.ScopeRead}, TenantID: "acme"},
+	}}
+	validator := MockJWTValidator{principals: map[string]*auth.Principal{
+		"jwt.globex": {Type: auth.PrincipalUser, Subject: "user-1", Roles: []auth.Role{auth.RoleAdmin}, Tenant: "globex"},
+	}}
+	authenticator := NewAuthenticator(service, validator)
+
+	tests := []struct {
+		name     string
+		header   string
+		value    string
+		expected string
+	}{
+		{"Tenant da chave de API", "X-API-Key", "dsk_acme", "acme"},
+		{"Tenant da claim do JWT", "Authorization", "Bearer jwt.globex", "globex"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resolved string
+			handler := authenticator.Require(auth.Policy{Scope: apikey.ScopeRead, Roles: []auth.Role{auth.RoleAdmin}})(
+				func(w http.ResponseWriter, r *http.Request) {
+					resolved, _ = tenant.FromContext(r.Context())
+					w.WriteHeader(http.StatusOK)
+				})
+
+			req := httptest.NewRequest("GET", "/deliveries", nil)
+			req.Header.Set(tt.header, tt.value)
+			w := httptest.NewRecorder()
+
+			handler(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.expected, resolved)
+		})
+	}
+}
+
+func TestRequire_JWTDisabled(t *testing.T) {
+	authenticator := NewAuthenticator(MockAPIKeyService{}, nil)
+	handler := authenticator.Require(auth.Policy{Scope: apikey.ScopeRead, Roles: []auth.Role{auth.RoleAdmin}})(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})
+
+	req := httptest.NewRequest("GET", "/deliveries", nil)
+	req.Header.Set("Authorization", "Bearer jwt.admin")
+	w := httptest.NewRecorder()
+
+	handler(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestRequireAuthentication(t *testing.T) {
+	service := MockAPIKeyService{keys: map[string]*apikey.APIKey{
+		"dsk_reader": {ID: 1, Escopos: []apikey.Scope{apikey.ScopeRead}},
+	}}
+	validator := MockJWTValidator{principals: map[string]*auth.Principal{
+		"jwt.driver": {Type: auth.PrincipalUser, Subject: "driver-1", Roles: []auth.Role{auth.RoleDriver}},
+	}}
+	authenticator := NewAuthenticator(service, validator)
+
+	readPolicy := auth.Policy{Scope: apikey.ScopeRead, Roles: []auth.Role{auth.RoleDriver}}
+	adminPolicy := auth.Policy{Scope: apikey.ScopeAdmin, Roles: []auth.Role{auth.RoleAdmin}}
+
+	tests := []struct {
+		name           string
+		headers        map[string]string
+		expectedStatus int
+	}{
+		{name: "missing credentials", expectedStatus: http.StatusUnauthorized},
+		{name: "invalid key", headers: map[string]string{"X-API-Key": "dsk_unknown"}, expectedStatus: http.StatusUnauthorized},
+		{name: "api key", headers: map[string]string{"X-API-Key": "dsk_reader"}, expectedStatus: http.StatusOK},
+		{name: "jwt", headers: map[string]string{"Authorization": "Bearer jwt.driver"}, expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A autorização fica a cargo do handler
+			handler := authenticator.RequireAuthentication()(func(w http.ResponseWriter, r *http.Request) {
+				assert.NoError(t, auth.Authorize(r.Context(), readPolicy))
+				assert.ErrorIs(t, auth.Authorize(r.Context(), adminPolicy), auth.ErrForbidden)
+
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest("POST", "/graphql", nil)
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+			w := httptest.NewRecorder()
+
+			handler(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
@@ -0,0 +1,398 @@
+package middleware
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/samluiz/delivery-service/api/http/utils"
+)
+
+// Tamanho mínimo padrão do corpo para que a resposta seja comprimida (1 KiB).
+const DefaultCompressionMinSize = 1024
+
+// Codificações suportadas, na ordem de preferência do servidor.
+const (
+	encodingZstd    = "zstd"
+	encodingGzip    = "gzip"
+	encodingDeflate = "deflate"
+)
+
+var encodings = []string{encodingZstd, encodingGzip, encodingDeflate}
+
+// Content types que já são comprimidos e não se beneficiam de uma nova compressão.
+var compressedContentTypes = map[string]bool{
+	"application/gzip":             true,
+	"application/x-gzip":           true,
+	"application/zip":              true,
+	"application/zstd":             true,
+	"application/x-bzip2":          true,
+	"application/x-7z-compressed":  true,
+	"application/x-rar-compressed": true,
+	"application/pdf":              true,
+	"application/octet-stream":     true,
+	"application/grpc":             true,
+}
+
+// Prefixos de content types de mídia, que já são comprimidos pelo próprio formato.
+var compressedContentTypePrefixes = []string{"image/", "video/", "audio/", "font/woff"}
+
+// Opções da compressão das respostas.
+type CompressionOptions struct {
+	// Tamanho mínimo do corpo, em bytes, para que a resposta seja comprimida. Corpos menores são enviados sem compressão,
+	// pois o ganho não compensa o custo. Valores menores ou iguais a zero utilizam o DefaultCompressionMinSize.
+	MinSize int
+}
+
+// Pools dos encoders, reaproveitados entre as respostas para evitar alocar as tabelas de compressão a cada requisição.
+var (
+	gzipWriters    = sync.Pool{New: func() any { return gzip.NewWriter(io.Discard) }}
+	deflateWriters = sync.Pool{New: func() any { return zlib.NewWriter(io.Discard) }}
+	zstdWriters    = sync.Pool{New: func() any {
+		encoder, _ := zstd.NewWriter(io.Discard, zstd.WithEncoderConcurrency(1))
+		return encoder
+	}}
+)
+
+// Encoder de uma resposta, devolvido ao pool ao final da requisição.
+type compressor interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// Função responsável por comprimir as respostas com zstd, gzip ou deflate, de acordo com o header Accept-Encoding.
+// A decisão acontece quando o corpo atinge o tamanho mínimo: respostas menores, de HEAD, sem corpo (204 e 304),
+// com Content-Encoding já definido ou com content types já comprimidos (ex: imagens) são enviadas sem alteração.
+// O header Vary: Accept-Encoding é enviado em todas as respostas, para que caches não misturem as representações.
+func Compress(options CompressionOptions) func(http.Handler) http.Handler {
+	minSize := options.MinSize
+	if minSize <= 0 {
+		minSize = DefaultCompressionMinSize
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+
+			encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+			if encoding == "" || r.Method == http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &compressResponseWriter{ResponseWriter: w, encoding: encoding, minSize: minSize}
+			defer cw.close()
+
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// Função responsável por escolher a codificação da resposta a partir do header Accept-Encoding.
+// Codificações com o mesmo q são desempatadas pela ordem de preferência do servidor, e "*" vale para as codificações
+// não listadas. Retorna vazio quando nenhuma codificação suportada é aceita.
+func negotiateEncoding(acceptEncoding string) string {
+	if acceptEncoding == "" {
+		return ""
+	}
+
+	qualities := map[string]float64{}
+	wildcard := -1.0
+
+	for _, value := range strings.Split(acceptEncoding, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(value), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+
+		q := 1.0
+		if name, value, found := strings.Cut(strings.TrimSpace(params), "="); found && strings.TrimSpace(name) == "q" {
+			if parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+				q = parsed
+			}
+		}
+
+		if coding == "*" {
+			wildcard = q
+		} else {
+			qualities[coding] = q
+		}
+	}
+
+	selected, selectedQ := "", 0.0
+	for _, encoding := range encodings {
+		q, ok := qualities[encoding]
+		if !ok && wildcard >= 0 {
+			q = wildcard
+		}
+		if q > selectedQ {
+			selected, selectedQ = encoding, q
+		}
+	}
+
+	return selected
+}
+
+// ResponseWriter que acumula o início do corpo até decidir se a resposta será comprimida.
+type compressResponseWriter struct {
+	http.ResponseWriter
+	encoding string
+	minSize  int
+
+	status  int
+	buffer  bytes.Buffer
+	decided bool
+	encoder compressor
+}
+
+func (c *compressResponseWriter) WriteHeader(status int) {
+	if c.status != 0 {
+		return
+	}
+
+	// Respostas informativas (1xx) não encerram os headers e são repassadas diretamente
+	if status >= 100 && status < 200 && status != http.StatusSwitchingProtocols {
+		c.ResponseWriter.WriteHeader(status)
+		return
+	}
+
+	c.status = status
+
+	// Respostas sem corpo são enviadas sem compressão
+	if status == http.StatusNoContent || status == http.StatusNotModified {
+		c.decide(false)
+	}
+}
+
+func (c *compressResponseWriter) Write(data []byte) (int, error) {
+	if c.status == 0 {
+		c.WriteHeader(http.StatusOK)
+	}
+
+	if !c.decided {
+		c.buffer.Write(data)
+		if c.buffer.Len() < c.minSize {
+			return len(data), nil
+		}
+
+		c.decide(c.compressible())
+		return len(data), c.flushBuffer()
+	}
+
+	if c.encoder != nil {
+		return c.encoder.Write(data)
+	}
+	return c.ResponseWriter.Write(data)
+}
+
+// Função responsável por enviar o conteúdo acumulado até o momento, comprimindo a resposta se ela for elegível.
+// Usada por respostas em stream, que não podem esperar o tamanho mínimo.
+func (c *compressResponseWriter) Flush() {
+	if !c.decided {
+		if c.status == 0 {
+			c.WriteHeader(http.StatusOK)
+		}
+		c.decide(c.buffer.Len() > 0 && c.compressible())
+		c.flushBuffer()
+	}
+
+	if c.encoder != nil {
+		c.encoder.Flush()
+	}
+	http.NewResponseController(c.ResponseWriter).Flush()
+}
+
+// Função responsável por assumir a conexão, repassando ao ResponseWriter original (ex: WebSockets).
+func (c *compressResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return http.NewResponseController(c.ResponseWriter).Hijack()
+}
+
+func (c *compressResponseWriter) Unwrap() http.ResponseWriter {
+	return c.ResponseWriter
+}
+
+// Função responsável por verificar se a resposta pode ser comprimida, pelos headers definidos pelo handler.
+func (c *compressResponseWriter) compressible() bool {
+	header := c.Header()
+
+	if header.Get("Content-Encoding") != "" || header.Get("Content-Range") != "" {
+		return false
+	}
+
+	contentType := header.Get("Content-Type")
+	if contentType == "" {
+		contentType = http.DetectContentType(c.buffer.Bytes())
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return true
+	}
+
+	if compressedContentTypes[mediaType] {
+		return false
+	}
+	for _, prefix := range compressedContentTypePrefixes {
+		if strings.HasPrefix(mediaType, prefix) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Função responsável por enviar os headers, com ou sem a codificação escolhida.
+func (c *compressResponseWriter) decide(compress bool) {
+	c.decided = true
+
+	if compress {
+		header := c.Header()
+		header.Set("Content-Encoding", c.encoding)
+		header.Del("Content-Length")
+		c.encoder = newCompressor(c.encoding, c.ResponseWriter)
+	}
+
+	c.ResponseWriter.WriteHeader(c.status)
+}
+
+func (c *compressResponseWriter) flushBuffer() error {
+	if c.buffer.Len() == 0 {
+		return nil
+	}
+
+	var err error
+	if c.encoder != nil {
+		_, err = c.encoder.Write(c.buffer.Bytes())
+	} else {
+		_, err = c.ResponseWriter.Write(c.buffer.Bytes())
+	}
+	c.buffer.Reset()
+
+	return err
+}
+
+// Função responsável por finalizar a resposta ao fim do handler.
+// Respostas que não atingiram o tamanho mínimo são enviadas sem compressão.
+func (c *compressResponseWriter) close() {
+	if !c.decided {
+		if c.status == 0 {
+			if c.buffer.Len() == 0 {
+				// O handler não escreveu nada; o net/http responde 200 sem corpo
+				return
+			}
+			c.status = http.StatusOK
+		}
+		c.decide(false)
+		c.flushBuffer()
+	}
+
+	if c.encoder != nil {
+		c.encoder.Close()
+		releaseCompressor(c.encoding, c.encoder)
+		c.encoder = nil
+	}
+}
+
+func newCompressor(encoding string, w io.Writer) compressor {
+	var encoder compressor
+
+	switch encoding {
+	case encodingZstd:
+		encoder = zstdWriters.Get().(*zstd.Encoder)
+	case encodingGzip:
+		encoder = gzipWriters.Get().(*gzip.Writer)
+	default:
+		encoder = deflateWriters.Get().(*zlib.Writer)
+	}
+
+	encoder.Reset(w)
+	return encoder
+}
+
+func releaseCompressor(encoding string, encoder compressor) {
+	// Desassociando o encoder da resposta para que ela não fique referenciada pelo pool
+	encoder.Reset(io.Discard)
+
+	switch encoding {
+	case encodingZstd:
+		zstdWriters.Put(encoder)
+	case encodingGzip:
+		gzipWriters.Put(encoder)
+	default:
+		deflateWriters.Put(encoder)
+	}
+}
+
+// Função responsável por descomprimir o corpo das requisições enviado com Content-Encoding gzip, deflate ou zstd.
+// O corpo comprimido é limitado ao tamanho máximo das requisições, assim como o corpo descomprimido pelos handlers,
+// o que impede que um corpo pequeno se expanda sem limite. Codificações não suportadas são rejeitadas com 415
+// e corpos que não estão no formato informado com 400.
+func Decompress(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		encoding := strings.ToLower(strings.TrimSpace(r.Header.Get("Content-Encoding")))
+
+		if encoding == "identity" {
+			r.Header.Del("Content-Encoding")
+			encoding = ""
+		}
+
+		if encoding == "" || r.Body == nil || r.Body == http.NoBody {
+			next(w, r)
+			return
+		}
+
+		body := http.MaxBytesReader(w, r.Body, utils.MaxBodyBytes())
+
+		var (
+			reader io.ReadCloser
+			err    error
+		)
+
+		switch encoding {
+		case encodingGzip, "x-gzip":
+			reader, err = gzip.NewReader(body)
+		case encodingDeflate:
+			reader, err = zlib.NewReader(body)
+		case encodingZstd:
+			var decoder *zstd.Decoder
+			decoder, err = zstd.NewReader(body, zstd.WithDecoderConcurrency(1), zstd.WithDecoderMaxMemory(uint64(utils.MaxBodyBytes())))
+			if err == nil {
+				reader = decoder.IOReadCloser()
+			}
+		default:
+			utils.NewJSONResponse(w, http.StatusUnsupportedMediaType, utils.NewUnsupportedMediaTypeError(
+				fmt.Errorf("content encoding must be %s", strings.Join(encodings, ", ")), r))
+			return
+		}
+
+		if err != nil {
+			var maxBytesError *http.MaxBytesError
+			if errors.As(err, &maxBytesError) {
+				utils.NewJSONResponse(w, http.StatusRequestEntityTooLarge, utils.NewPayloadTooLargeError(
+					fmt.Errorf("request body must not exceed %d bytes", maxBytesError.Limit), r))
+				return
+			}
+			utils.NewJSONResponse(w, http.StatusBadRequest, utils.NewBadRequestError(
+				fmt.Errorf("request body is not valid %s content", encoding), r))
+			return
+		}
+		defer reader.Close()
+
+		r.Body = reader
+		r.ContentLength = -1
+		r.Header.Del("Content-Encoding")
+		r.Header.Del("Content-Length")
+
+		next(w, r)
+	}
+}
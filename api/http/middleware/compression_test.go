@@ -0,0 +1,242 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/samluiz/delivery-service/api/http/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Testes da compressão das respostas e da descompressão do corpo das requisições
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		acceptEncoding string
+		expected       string
+	}{
+		{acceptEncoding: "", expected: ""},
+		{acceptEncoding: "gzip", expected: "gzip"},
+		{acceptEncoding: "deflate", expected: "deflate"},
+		{acceptEncoding: "gzip, deflate, br, zstd", expected: "zstd"},
+		{acceptEncoding: "gzip;q=1.0, zstd;q=0.5", expected: "gzip"},
+		{acceptEncoding: "GZIP", expected: "gzip"},
+		{acceptEncoding: "*", expected: "zstd"},
+		{acceptEncoding: "zstd;q=0, *;q=0.5", expected: "gzip"},
+		{acceptEncoding: "gzip;q=0", expected: ""},
+		{acceptEncoding: "identity", expected: ""},
+		{acceptEncoding: "br", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.acceptEncoding, func(t *testing.T) {
+			assert.Equal(t, tt.expected, negotiateEncoding(tt.acceptEncoding))
+		})
+	}
+}
+
+func TestCompress(t *testing.T) {
+	large := `{"entregas":"` + strings.Repeat("entrega ", 200) + `"}`
+
+	tests := []struct {
+		name             string
+		method           string
+		acceptEncoding   string
+		contentType      string
+		contentEncoding  string
+		status           int
+		body             string
+		expectedEncoding string
+	}{
+		{name: "gzip", acceptEncoding: "gzip", body: large, expectedEncoding: "gzip"},
+		{name: "deflate", acceptEncoding: "deflate", body: large, expectedEncoding: "deflate"},
+		{name: "zstd", acceptEncoding: "zstd, gzip", body: large, expectedEncoding: "zstd"},
+		{name: "without accept-encoding", body: large},
+		{name: "small body", acceptEncoding: "gzip", body: `{"id":1}`},
+		{name: "already compressed content type", acceptEncoding: "gzip", contentType: "image/png", body: large},
+		{name: "content encoding defined by the handler", acceptEncoding: "gzip", contentEncoding: "br", body: large},
+		{name: "no content", acceptEncoding: "gzip", status: http.StatusNoContent},
+		{name: "head", method: http.MethodHead, acceptEncoding: "gzip", body: large},
+		{name: "error status", acceptEncoding: "gzip", status: http.StatusBadRequest, body: large, expectedEncoding: "gzip"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := Compress(CompressionOptions{MinSize: 512})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				contentType := tt.contentType
+				if contentType == "" {
+					contentType = "application/json"
+				}
+				w.Header().Set("Content-Type", contentType)
+				if tt.contentEncoding != "" {
+					w.Header().Set("Content-Encoding", tt.contentEncoding)
+				}
+				if tt.status != 0 {
+					w.WriteHeader(tt.status)
+				}
+				// Escrevendo em partes menores que o tamanho mínimo
+				for i := 0; i < len(tt.body); i += 100 {
+					w.Write([]byte(tt.body[i:min(i+100, len(tt.body))]))
+				}
+			}))
+
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			r := httptest.NewRequest(method, "/v2/deliveries", nil)
+			if tt.acceptEncoding != "" {
+				r.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, r)
+
+			expectedStatus := tt.status
+			if expectedStatus == 0 {
+				expectedStatus = http.StatusOK
+			}
+			assert.Equal(t, expectedStatus, w.Code)
+			assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+
+			if tt.expectedEncoding == "" {
+				assert.NotEqual(t, "gzip", w.Header().Get("Content-Encoding"))
+				assert.Equal(t, tt.body, w.Body.String())
+				return
+			}
+
+			assert.Equal(t, tt.expectedEncoding, w.Header().Get("Content-Encoding"))
+			assert.Less(t, w.Body.Len(), len(tt.body))
+			assert.Equal(t, tt.body, decompress(t, tt.expectedEncoding, w.Body.Bytes()))
+		})
+	}
+}
+
+func TestCompress_Flush(t *testing.T) {
+	handler := Compress(CompressionOptions{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte("data: 1\n\n"))
+		http.NewResponseController(w).Flush()
+		w.Write([]byte("data: 2\n\n"))
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/events", nil)
+	r.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, r)
+
+	// O flush envia o conteúdo acumulado sem esperar o tamanho mínimo
+	assert.True(t, w.Flushed)
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "data: 1\n\ndata: 2\n\n", decompress(t, "gzip", w.Body.Bytes()))
+}
+
+func TestDecompress(t *testing.T) {
+	body := `{"cliente":"Cliente A"}`
+
+	tests := []struct {
+		name            string
+		contentEncoding string
+		body            []byte
+		expectedStatus  int
+		expectedCause   string
+	}{
+		{name: "without encoding", body: []byte(body), expectedStatus: http.StatusOK},
+		{name: "identity", contentEncoding: "identity", body: []byte(body), expectedStatus: http.StatusOK},
+		{name: "gzip", contentEncoding: "gzip", body: compress(t, "gzip", body), expectedStatus: http.StatusOK},
+		{name: "x-gzip", contentEncoding: "x-gzip", body: compress(t, "gzip", body), expectedStatus: http.StatusOK},
+		{name: "deflate", contentEncoding: "deflate", body: compress(t, "deflate", body), expectedStatus: http.StatusOK},
+		{name: "zstd", contentEncoding: "zstd", body: compress(t, "zstd", body), expectedStatus: http.StatusOK},
+		{name: "unsupported encoding", contentEncoding: "br", body: []byte(body), expectedStatus: http.StatusUnsupportedMediaType, expectedCause: "content encoding must be zstd, gzip, deflate"},
+		{name: "invalid gzip", contentEncoding: "gzip", body: []byte(body), expectedStatus: http.StatusBadRequest, expectedCause: "request body is not valid gzip content"},
+		{name: "expanded body above the limit", contentEncoding: "gzip", body: compress(t, "gzip", `{"cliente":"`+strings.Repeat("a", 4096)+`"}`), expectedStatus: http.StatusRequestEntityTooLarge},
+	}
+
+	utils.ConfigureMaxBodyBytes(1024)
+	defer utils.ConfigureMaxBodyBytes(utils.DefaultMaxBodyBytes)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := Decompress(func(w http.ResponseWriter, r *http.Request) {
+				assert.Empty(t, r.Header.Get("Content-Encoding"))
+
+				var request struct {
+					Cliente string `json:"cliente"`
+				}
+				if err := utils.DecodeJSONBody(w, r, &request); err != nil {
+					utils.NewJSONResponse(w, err.Status, err)
+					return
+				}
+				assert.Equal(t, "Cliente A", request.Cliente)
+				w.WriteHeader(http.StatusOK)
+			})
+
+			r := httptest.NewRequest(http.MethodPost, "/v2/deliveries", bytes.NewReader(tt.body))
+			r.Header.Set("Content-Type", "application/json")
+			if tt.contentEncoding != "" {
+				r.Header.Set("Content-Encoding", tt.contentEncoding)
+			}
+			w := httptest.NewRecorder()
+
+			handler(w, r)
+
+			assert.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			if tt.expectedCause != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedCause)
+			}
+		})
+	}
+}
+
+// Função auxiliar responsável por comprimir o conteúdo com a codificação informada.
+func compress(t *testing.T, encoding string, content string) []byte {
+	var buffer bytes.Buffer
+	var writer io.WriteCloser
+
+	switch encoding {
+	case "gzip":
+		writer = gzip.NewWriter(&buffer)
+	case "deflate":
+		writer = zlib.NewWriter(&buffer)
+	case "zstd":
+		encoder, err := zstd.NewWriter(&buffer)
+		require.NoError(t, err)
+		writer = encoder
+	}
+
+	_, err := writer.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	return buffer.Bytes()
+}
+
+// Função auxiliar responsável por descomprimir o conteúdo com a codificação informada.
+func decompress(t *testing.T, encoding string, content []byte) string {
+	var reader io.Reader
+	var err error
+
+	switch encoding {
+	case "gzip":
+		reader, err = gzip.NewReader(bytes.NewReader(content))
+	case "deflate":
+		reader, err = zlib.NewReader(bytes.NewReader(content))
+	case "zstd":
+		reader, err = zstd.NewReader(bytes.NewReader(content))
+	}
+	require.NoError(t, err)
+
+	decoded, err := io.ReadAll(reader)
+	require.NoError(t, err)
+
+	return string(decoded)
+}
@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/samluiz/delivery-service/api/http/utils"
+	"github.com/samluiz/delivery-service/internal/concurrency"
+)
+
+// Tempo padrão informado no Retry-After das requisições descartadas.
+const DefaultShedRetryAfter = time.Second
+
+// Limitador de concorrência na frente dos handlers, para que as requisições excedentes sejam descartadas
+// em vez de aguardarem uma conexão do pool do banco até o timeout do cliente.
+type LoadShedder struct {
+	limiter    *concurrency.Limiter
+	retryAfter time.Duration
+
+	// Classificação das rotas que não podem ser classificadas pelo método, indexadas pelo padrão do http.ServeMux
+	classifiers map[string]func(*http.Request) concurrency.Class
+}
+
+// Função responsável por instanciar o limitador de carga. O retryAfter é o valor enviado no header Retry-After.
+func NewLoadShedder(limiter *concurrency.Limiter, retryAfter time.Duration) *LoadShedder {
+	if retryAfter <= 0 {
+		retryAfter = DefaultShedRetryAfter
+	}
+	return &LoadShedder{limiter: limiter, retryAfter: retryAfter, classifiers: make(map[string]func(*http.Request) concurrency.Class)}
+}
+
+// Função responsável por registrar a classificação de uma rota (ex: "POST /graphql", que recebe consultas e
+// alterações pelo mesmo método). Deve ser chamada antes do servidor receber requisições.
+func (s *LoadShedder) Classify(pattern string, classify func(*http.Request) concurrency.Class) {
+	s.classifiers[pattern] = classify
+}
+
+// Função responsável por aguardar uma vaga no limitador antes de executar o handler.
+// Consultas (GET e HEAD) são da classe read e as demais requisições da classe write, exceto nas rotas com
+// classificação própria. Requisições recusadas pela fila cheia, pelo tempo de espera ou por uma requisição
+// prioritária recebem 503 com Retry-After.
+func (s *LoadShedder) Limit(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		release, err := s.Acquire(r.Context(), s.requestClass(r))
+		if err != nil {
+			// O cliente desistiu enquanto aguardava, não há para quem responder
+			if errors.Is(err, context.Canceled) {
+				return
+			}
+
+			w.Header().Set("Retry-After", strconv.Itoa(s.RetryAfter()))
+			utils.NewJSONResponse(w, http.StatusServiceUnavailable, utils.NewServiceUnavailableError(err, r))
+			return
+		}
+		defer release()
+
+		next(w, r)
+	}
+}
+
+// Função responsável por aguardar uma vaga no limitador, também utilizada pelas chamadas gRPC.
+// Retorna a função que libera a vaga ou o motivo da recusa.
+func (s *LoadShedder) Acquire(ctx context.Context, class concurrency.Class) (func(), error) {
+	return s.limiter.Acquire(ctx, class)
+}
+
+// Função responsável por retornar o tempo, em segundos, informado aos clientes das requisições descartadas.
+func (s *LoadShedder) RetryAfter() int {
+	return max(1, ceilSeconds(s.retryAfter))
+}
+
+// Função responsável por classificar a requisição pela classificação da rota, quando registrada, ou pelo método.
+func (s *LoadShedder) requestClass(r *http.Request) concurrency.Class {
+	if classify, ok := s.classifiers[r.Pattern]; ok {
+		return classify(r)
+	}
+	return requestClass(r)
+}
+
+// Função responsável por classificar a requisição em leitura ou escrita pelo método.
+func requestClass(r *http.Request) concurrency.Class {
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		return concurrency.ClassRead
+	}
+	return concurrency.ClassWrite
+}
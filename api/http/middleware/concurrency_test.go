@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/samluiz/delivery-service/api/http/utils"
+	"github.com/samluiz/delivery-service/internal/concurrency"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestClass(t *testing.T) {
+	assert.Equal(t, concurrency.ClassRead, requestClass(httptest.NewRequest("GET", "/v2/deliveries", nil)))
+	assert.Equal(t, concurrency.ClassRead, requestClass(httptest.NewRequest("HEAD", "/v2/deliveries", nil)))
+	assert.Equal(t, concurrency.ClassWrite, requestClass(httptest.NewRequest("POST", "/v2/deliveries", nil)))
+	assert.Equal(t, concurrency.ClassWrite, requestClass(httptest.NewRequest("DELETE", "/v2/deliveries/1", nil)))
+}
+
+func TestLoadShedder_Classify(t *testing.T) {
+	shedder := NewLoadShedder(concurrency.NewLimiter(concurrency.DefaultOptions), 0)
+	shedder.Classify("POST /graphql", func(r *http.Request) concurrency.Class {
+		return concurrency.ClassRead
+	})
+
+	r := httptest.NewRequest("POST", "/graphql", nil)
+	r.Pattern = "POST /graphql"
+	assert.Equal(t, concurrency.ClassRead, shedder.requestClass(r))
+
+	// As demais rotas são classificadas pelo método
+	r = httptest.NewRequest("POST", "/v2/deliveries", nil)
+	r.Pattern = "POST /v2/deliveries"
+	assert.Equal(t, concurrency.ClassWrite, shedder.requestClass(r))
+}
+
+func TestLoadShedder_Limit(t *testing.T) {
+	limiter := concurrency.NewLimiter(concurrency.Options{InitialLimit: 1, MinLimit: 1, MaxLimit: 1, MaxQueue: 0, MaxWait: time.Second})
+	shedder := NewLoadShedder(limiter, 3*time.Second)
+
+	started := make(chan struct{})
+	finish := make(chan struct{})
+	handler := shedder.Limit(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-finish
+		w.WriteHeader(http.StatusOK)
+	})
+
+	// A primeira requisição ocupa a única vaga
+	first := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		handler(first, httptest.NewRequest("GET", "/v2/deliveries", nil))
+		close(done)
+	}()
+	<-started
+
+	// Sem fila, a segunda requisição é descartada
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest("POST", "/v2/deliveries", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "3", w.Header().Get("Retry-After"))
+
+	var response utils.Error
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, http.StatusServiceUnavailable, response.Status)
+	assert.Equal(t, concurrency.ErrQueueFull.Error(), response.Cause)
+
+	close(finish)
+	<-done
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, 0, limiter.InFlight())
+}
+
+func TestLoadShedder_ClientCanceled(t *testing.T) {
+	limiter := concurrency.NewLimiter(concurrency.Options{InitialLimit: 1, MinLimit: 1, MaxLimit: 1, MaxQueue: 10, MaxWait: time.Second})
+	shedder := NewLoadShedder(limiter, 0)
+
+	release, err := limiter.Acquire(context.Background(), concurrency.ClassRead)
+	assert.NoError(t, err)
+	defer release()
+
+	called := false
+	handler := shedder.Limit(func(w http.ResponseWriter, r *http.Request) { called = true })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest("GET", "/v2/deliveries", nil).WithContext(ctx))
+
+	// Não há resposta para o cliente que desistiu
+	assert.False(t, called)
+	assert.Empty(t, w.Body.String())
+	assert.Empty(t, w.Header().Get("Retry-After"))
+}
+
+func TestLoadShedder_QueueTimeout(t *testing.T) {
+	limiter := concurrency.NewLimiter(concurrency.Options{InitialLimit: 1, MinLimit: 1, MaxLimit: 1, MaxQueue: 10, MaxWait: 10 * time.Millisecond})
+	shedder := NewLoadShedder(limiter, 0)
+
+	release, err := limiter.Acquire(context.Background(), concurrency.ClassRead)
+	assert.NoError(t, err)
+	defer release()
+
+	w := httptest.NewRecorder()
+	shedder.Limit(func(w http.ResponseWriter, r *http.Request) {})(w, httptest.NewRequest("GET", "/v2/deliveries", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+}
@@ -0,0 +1,225 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/samluiz/delivery-service/api/http/utils"
+)
+
+// Headers aceitos por padrão nas requisições de outras origens.
+var DefaultCORSAllowedHeaders = []string{
+	"Accept", "Accept-Language", "Authorization", "Content-Type", "Content-Encoding",
+	"X-API-Key", "X-Confirmation-Token", RequestIDHeader, "traceparent", "tracestate",
+}
+
+// Headers das respostas expostos por padrão ao JavaScript das outras origens.
+var DefaultCORSExposedHeaders = []string{
+	RequestIDHeader, "Location", "Deprecation", "Sunset", "Link", "Retry-After",
+	RateLimitLimitHeader, RateLimitRemainingHeader, RateLimitResetHeader, RateLimitPolicyHeader,
+}
+
+// Métodos verificados no router para montar o Access-Control-Allow-Methods do preflight.
+var corsMethods = []string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+// Configuração do CORS.
+type CORSOptions struct {
+	// Origens permitidas: origens exatas (ex: https://console.example.com), com um curinga no subdomínio
+	// (ex: https://*.example.com) ou "*" para qualquer origem. Sem origens o CORS fica desabilitado.
+	AllowedOrigins []string
+
+	// Métodos permitidos. Quando vazio, são permitidos todos os métodos registrados no router para o path.
+	AllowedMethods []string
+
+	// Headers que o navegador pode enviar. "*" aceita qualquer header.
+	AllowedHeaders []string
+
+	// Headers das respostas que o JavaScript da outra origem pode ler.
+	ExposedHeaders []string
+
+	// Permite o envio de cookies e do header Authorization pelo navegador. Não vale para as origens liberadas
+	// por "*", pois o navegador recusa credenciais quando o Access-Control-Allow-Origin é "*".
+	AllowCredentials bool
+
+	// Tempo que o navegador pode guardar o resultado do preflight. Zero não envia o Access-Control-Max-Age.
+	MaxAge time.Duration
+}
+
+// Função responsável por aplicar a política de CORS às requisições.
+// O preflight (OPTIONS com Access-Control-Request-Method) é respondido pelo middleware, pois o router só possui
+// padrões por método (ex: "GET /v2/deliveries") e responderia 405. Os métodos permitidos são os registrados no
+// router para o path da requisição, limitados ao AllowedMethods quando configurado.
+func CORS(options CORSOptions, router *http.ServeMux) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if len(options.AllowedOrigins) == 0 {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			header := w.Header()
+
+			if r.Method == http.MethodOptions && origin != "" && r.Header.Get("Access-Control-Request-Method") != "" {
+				header.Add("Vary", "Origin")
+				header.Add("Vary", "Access-Control-Request-Method")
+				header.Add("Vary", "Access-Control-Request-Headers")
+				preflight(w, r, options, router)
+				return
+			}
+
+			header.Add("Vary", "Origin")
+
+			if origin != "" {
+				if allowOrigin, credentials, ok := matchOrigin(options, origin); ok {
+					setAllowOrigin(header, options, allowOrigin, credentials)
+					if len(options.ExposedHeaders) > 0 {
+						header.Set("Access-Control-Expose-Headers", strings.Join(options.ExposedHeaders, ", "))
+					}
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Função responsável por responder o preflight. Origens, métodos e headers fora da política recebem 403,
+// sem os headers de CORS, e paths sem rotas recebem 404.
+func preflight(w http.ResponseWriter, r *http.Request, options CORSOptions, router *http.ServeMux) {
+	origin := r.Header.Get("Origin")
+
+	allowOrigin, credentials, ok := matchOrigin(options, origin)
+	if !ok {
+		utils.NewJSONResponse(w, http.StatusForbidden, utils.NewForbiddenError(fmt.Errorf("origin %q is not allowed", origin), r))
+		return
+	}
+
+	methods := routeMethods(r, options, router)
+	if len(methods) == 0 {
+		utils.NewJSONResponse(w, http.StatusNotFound, utils.NewNotFoundError(fmt.Errorf("no route matches path %q", r.URL.Path), r))
+		return
+	}
+
+	method := strings.ToUpper(strings.TrimSpace(r.Header.Get("Access-Control-Request-Method")))
+	if !containsFold(methods, method) {
+		utils.NewJSONResponse(w, http.StatusForbidden, utils.NewForbiddenError(
+			fmt.Errorf("method %q is not allowed, must be one of: %s", method, strings.Join(methods, ", ")), r))
+		return
+	}
+
+	requestedHeaders := splitHeaderList(r.Header.Get("Access-Control-Request-Headers"))
+	allowAnyHeader := containsFold(options.AllowedHeaders, "*")
+	if !allowAnyHeader {
+		for _, requested := range requestedHeaders {
+			if !containsFold(options.AllowedHeaders, requested) {
+				utils.NewJSONResponse(w, http.StatusForbidden, utils.NewForbiddenError(fmt.Errorf("header %q is not allowed", requested), r))
+				return
+			}
+		}
+	}
+
+	header := w.Header()
+	setAllowOrigin(header, options, allowOrigin, credentials)
+	header.Set("Access-Control-Allow-Methods", strings.Join(methods, ", "))
+
+	// Com "*" os headers solicitados são devolvidos, pois o "*" literal não vale para requisições com credenciais
+	if allowAnyHeader {
+		if len(requestedHeaders) > 0 {
+			header.Set("Access-Control-Allow-Headers", strings.Join(requestedHeaders, ", "))
+		}
+	} else if len(options.AllowedHeaders) > 0 {
+		header.Set("Access-Control-Allow-Headers", strings.Join(options.AllowedHeaders, ", "))
+	}
+
+	if options.MaxAge > 0 {
+		header.Set("Access-Control-Max-Age", strconv.Itoa(int(options.MaxAge.Seconds())))
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Função responsável por definir os headers da origem permitida.
+func setAllowOrigin(header http.Header, options CORSOptions, allowOrigin string, credentials bool) {
+	header.Set("Access-Control-Allow-Origin", allowOrigin)
+	if options.AllowCredentials && credentials {
+		header.Set("Access-Control-Allow-Credentials", "true")
+	}
+}
+
+// Função responsável por verificar se a origem é permitida, retornando o valor do Access-Control-Allow-Origin e se
+// a origem pode receber credenciais. Origens exatas e com curinga têm precedência sobre "*", para que recebam credenciais.
+func matchOrigin(options CORSOptions, origin string) (string, bool, bool) {
+	anyOrigin := false
+
+	for _, allowed := range options.AllowedOrigins {
+		if allowed == "*" {
+			anyOrigin = true
+			continue
+		}
+		if matchOriginPattern(allowed, origin) {
+			return origin, true, true
+		}
+	}
+
+	if anyOrigin {
+		return "*", false, true
+	}
+	return "", false, false
+}
+
+// Função responsável por comparar a origem com uma origem permitida, que pode ter um curinga (ex: https://*.example.com).
+// O curinga corresponde a um ou mais caracteres e não alcança a origem sem subdomínio (https://example.com).
+func matchOriginPattern(pattern string, origin string) bool {
+	pattern = strings.ToLower(pattern)
+	origin = strings.ToLower(origin)
+
+	prefix, suffix, wildcard := strings.Cut(pattern, "*")
+	if !wildcard {
+		return pattern == origin
+	}
+
+	return len(origin) > len(prefix)+len(suffix) && strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix)
+}
+
+// Função responsável por buscar os métodos registrados no router para o path da requisição.
+func routeMethods(r *http.Request, options CORSOptions, router *http.ServeMux) []string {
+	candidates := corsMethods
+	if len(options.AllowedMethods) > 0 {
+		candidates = options.AllowedMethods
+	}
+
+	methods := make([]string, 0, len(candidates))
+	for _, method := range candidates {
+		probe := r.Clone(r.Context())
+		probe.Method = strings.ToUpper(method)
+
+		if _, pattern := router.Handler(probe); pattern != "" {
+			methods = append(methods, probe.Method)
+		}
+	}
+
+	return methods
+}
+
+// Função responsável por separar uma lista de headers (ex: "content-type, x-api-key").
+func splitHeaderList(value string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
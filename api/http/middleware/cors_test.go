@@ -0,0 +1,308 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Router com os padrões por método, como o da API
+func newCORSRouter() *http.ServeMux {
+	router := http.NewServeMux()
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+
+	router.HandleFunc("GET /v2/deliveries", ok)
+	router.HandleFunc("POST /v2/deliveries", ok)
+	router.HandleFunc("GET /v2/deliveries/{id}", ok)
+	router.HandleFunc("PUT /v2/deliveries/{id}", ok)
+	router.HandleFunc("DELETE /v2/deliveries/{id}", ok)
+
+	return router
+}
+
+func TestMatchOriginPattern(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		origin   string
+		expected bool
+	}{
+		{"https://console.example.com", "https://console.example.com", true},
+		{"https://console.example.com", "HTTPS://Console.Example.com", true},
+		{"https://console.example.com", "http://console.example.com", false},
+		{"https://console.example.com", "https://console.example.com:8443", false},
+		{"https://*.example.com", "https://console.example.com", true},
+		{"https://*.example.com", "https://a.b.example.com", true},
+		{"https://*.example.com", "https://example.com", false},
+		{"https://*.example.com", "https://.example.com", false},
+		{"https://*.example.com", "https://example.com.evil.com", false},
+		{"https://*.example.com", "http://console.example.com", false},
+		{"http://localhost:*", "http://localhost:3000", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.origin, func(t *testing.T) {
+			assert.Equal(t, tt.expected, matchOriginPattern(tt.pattern, tt.origin))
+		})
+	}
+}
+
+func TestCORS(t *testing.T) {
+	tests := []struct {
+		name            string
+		options         CORSOptions
+		method          string
+		path            string
+		headers         map[string]string
+		expectedStatus  int
+		expectedHeaders map[string]string
+	}{
+		{
+			name:            "CORS desabilitado não altera as respostas",
+			options:         CORSOptions{},
+			method:          "GET",
+			path:            "/v2/deliveries",
+			headers:         map[string]string{"Origin": "https://console.example.com"},
+			expectedStatus:  http.StatusOK,
+			expectedHeaders: map[string]string{"Access-Control-Allow-Origin": "", "Vary": ""},
+		},
+		{
+			name:            "CORS desabilitado não responde o preflight",
+			options:         CORSOptions{},
+			method:          "OPTIONS",
+			path:            "/v2/deliveries",
+			headers:         map[string]string{"Origin": "https://console.example.com", "Access-Control-Request-Method": "POST"},
+			expectedStatus:  http.StatusMethodNotAllowed,
+			expectedHeaders: map[string]string{"Access-Control-Allow-Origin": ""},
+		},
+		{
+			name:           "Origem exata",
+			options:        CORSOptions{AllowedOrigins: []string{"https://console.example.com"}, ExposedHeaders: []string{"X-Request-ID", "Location"}},
+			method:         "GET",
+			path:           "/v2/deliveries",
+			headers:        map[string]string{"Origin": "https://console.example.com"},
+			expectedStatus: http.StatusOK,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin":      "https://console.example.com",
+				"Access-Control-Allow-Credentials": "",
+				"Access-Control-Expose-Headers":    "X-Request-ID, Location",
+				"Vary":                             "Origin",
+			},
+		},
+		{
+			name:           "Origem não permitida não recebe os headers",
+			options:        CORSOptions{AllowedOrigins: []string{"https://console.example.com"}},
+			method:         "GET",
+			path:           "/v2/deliveries",
+			headers:        map[string]string{"Origin": "https://evil.com"},
+			expectedStatus: http.StatusOK,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin": "",
+				"Vary":                        "Origin",
+			},
+		},
+		{
+			name:            "Requisição sem Origin",
+			options:         CORSOptions{AllowedOrigins: []string{"*"}},
+			method:          "GET",
+			path:            "/v2/deliveries",
+			expectedStatus:  http.StatusOK,
+			expectedHeaders: map[string]string{"Access-Control-Allow-Origin": ""},
+		},
+		{
+			name:           "Origem com curinga",
+			options:        CORSOptions{AllowedOrigins: []string{"https://*.example.com"}, AllowCredentials: true},
+			method:         "GET",
+			path:           "/v2/deliveries",
+			headers:        map[string]string{"Origin": "https://dispatch.example.com"},
+			expectedStatus: http.StatusOK,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin":      "https://dispatch.example.com",
+				"Access-Control-Allow-Credentials": "true",
+			},
+		},
+		{
+			name:           "Qualquer origem não recebe credenciais",
+			options:        CORSOptions{AllowedOrigins: []string{"*"}, AllowCredentials: true},
+			method:         "GET",
+			path:           "/v2/deliveries",
+			headers:        map[string]string{"Origin": "https://other.com"},
+			expectedStatus: http.StatusOK,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin":      "*",
+				"Access-Control-Allow-Credentials": "",
+			},
+		},
+		{
+			name:           "Origem exata tem precedência sobre qualquer origem",
+			options:        CORSOptions{AllowedOrigins: []string{"*", "https://console.example.com"}, AllowCredentials: true},
+			method:         "GET",
+			path:           "/v2/deliveries",
+			headers:        map[string]string{"Origin": "https://console.example.com"},
+			expectedStatus: http.StatusOK,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin":      "https://console.example.com",
+				"Access-Control-Allow-Credentials": "true",
+			},
+		},
+		{
+			name: "Preflight com os métodos registrados no router",
+			options: CORSOptions{
+				AllowedOrigins:   []string{"https://console.example.com"},
+				AllowedHeaders:   []string{"Authorization", "Content-Type"},
+				AllowCredentials: true,
+				MaxAge:           10 * time.Minute,
+			},
+			method: "OPTIONS",
+			path:   "/v2/deliveries/42",
+			headers: map[string]string{
+				"Origin":                         "https://console.example.com",
+				"Access-Control-Request-Method":  "PUT",
+				"Access-Control-Request-Headers": "content-type, authorization",
+			},
+			expectedStatus: http.StatusNoContent,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin":      "https://console.example.com",
+				"Access-Control-Allow-Methods":     "GET, HEAD, PUT, DELETE",
+				"Access-Control-Allow-Headers":     "Authorization, Content-Type",
+				"Access-Control-Allow-Credentials": "true",
+				"Access-Control-Max-Age":           "600",
+			},
+		},
+		{
+			name:    "Preflight limitado aos métodos configurados",
+			options: CORSOptions{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"GET", "POST"}},
+			method:  "OPTIONS",
+			path:    "/v2/deliveries",
+			headers: map[string]string{
+				"Origin":                        "https://console.example.com",
+				"Access-Control-Request-Method": "POST",
+			},
+			expectedStatus: http.StatusNoContent,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin":  "*",
+				"Access-Control-Allow-Methods": "GET, POST",
+				"Access-Control-Max-Age":       "",
+			},
+		},
+		{
+			name:    "Preflight com método não registrado para o path",
+			options: CORSOptions{AllowedOrigins: []string{"*"}},
+			method:  "OPTIONS",
+			path:    "/v2/deliveries",
+			headers: map[string]string{
+				"Origin":                        "https://console.example.com",
+				"Access-Control-Request-Method": "DELETE",
+			},
+			expectedStatus:  http.StatusForbidden,
+			expectedHeaders: map[string]string{"Access-Control-Allow-Origin": ""},
+		},
+		{
+			name:    "Preflight com método fora da configuração",
+			options: CORSOptions{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"GET"}},
+			method:  "OPTIONS",
+			path:    "/v2/deliveries/42",
+			headers: map[string]string{
+				"Origin":                        "https://console.example.com",
+				"Access-Control-Request-Method": "DELETE",
+			},
+			expectedStatus:  http.StatusForbidden,
+			expectedHeaders: map[string]string{"Access-Control-Allow-Origin": ""},
+		},
+		{
+			name:    "Preflight de path sem rotas",
+			options: CORSOptions{AllowedOrigins: []string{"*"}},
+			method:  "OPTIONS",
+			path:    "/v3/deliveries",
+			headers: map[string]string{
+				"Origin":                        "https://console.example.com",
+				"Access-Control-Request-Method": "GET",
+			},
+			expectedStatus:  http.StatusNotFound,
+			expectedHeaders: map[string]string{"Access-Control-Allow-Origin": ""},
+		},
+		{
+			name:    "Preflight de origem não permitida",
+			options: CORSOptions{AllowedOrigins: []string{"https://*.example.com"}},
+			method:  "OPTIONS",
+			path:    "/v2/deliveries",
+			headers: map[string]string{
+				"Origin":                        "https://example.com",
+				"Access-Control-Request-Method": "GET",
+			},
+			expectedStatus:  http.StatusForbidden,
+			expectedHeaders: map[string]string{"Access-Control-Allow-Origin": ""},
+		},
+		{
+			name:    "Preflight com header não permitido",
+			options: CORSOptions{AllowedOrigins: []string{"*"}, AllowedHeaders: []string{"Content-Type"}},
+			method:  "OPTIONS",
+			path:    "/v2/deliveries",
+			headers: map[string]string{
+				"Origin":                         "https://console.example.com",
+				"Access-Control-Request-Method":  "POST",
+				"Access-Control-Request-Headers": "content-type, x-custom",
+			},
+			expectedStatus:  http.StatusForbidden,
+			expectedHeaders: map[string]string{"Access-Control-Allow-Headers": ""},
+		},
+		{
+			name:    "Preflight com qualquer header devolve os headers solicitados",
+			options: CORSOptions{AllowedOrigins: []string{"*"}, AllowedHeaders: []string{"*"}},
+			method:  "OPTIONS",
+			path:    "/v2/deliveries",
+			headers: map[string]string{
+				"Origin":                         "https://console.example.com",
+				"Access-Control-Request-Method":  "POST",
+				"Access-Control-Request-Headers": "content-type, x-custom",
+			},
+			expectedStatus:  http.StatusNoContent,
+			expectedHeaders: map[string]string{"Access-Control-Allow-Headers": "content-type, x-custom"},
+		},
+		{
+			name:            "OPTIONS sem Access-Control-Request-Method segue para o router",
+			options:         CORSOptions{AllowedOrigins: []string{"*"}},
+			method:          "OPTIONS",
+			path:            "/v2/deliveries",
+			headers:         map[string]string{"Origin": "https://console.example.com"},
+			expectedStatus:  http.StatusMethodNotAllowed,
+			expectedHeaders: map[string]string{"Access-Control-Allow-Origin": "*"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newCORSRouter()
+			handler := CORS(tt.options, router)(router)
+
+			r := httptest.NewRequest(tt.method, tt.path, nil)
+			for header, value := range tt.headers {
+				r.Header.Set(header, value)
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, r)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			for header, value := range tt.expectedHeaders {
+				assert.Equal(t, value, w.Header().Get(header), header)
+			}
+		})
+	}
+}
+
+func TestCORS_PreflightVary(t *testing.T) {
+	router := newCORSRouter()
+	handler := CORS(CORSOptions{AllowedOrigins: []string{"*"}}, router)(router)
+
+	r := httptest.NewRequest("OPTIONS", "/v2/deliveries", nil)
+	r.Header.Set("Origin", "https://console.example.com")
+	r.Header.Set("Access-Control-Request-Method", "GET")
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, r)
+
+	assert.Equal(t, []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"}, w.Header().Values("Vary"))
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Configuração da descontinuação de uma versão da API.
+type Deprecation struct {
+	DeprecatedAt time.Time // Data a partir da qual a versão está descontinuada (header Deprecation, RFC 9745)
+	Sunset       time.Time // Data a partir da qual a versão deixará de responder (header Sunset, RFC 8594)
+	Successor    string    // Caminho da versão que a substitui, informado no header Link
+}
+
+// Função responsável por informar aos clientes que a versão da rota está descontinuada.
+// Os headers são enviados em todas as respostas, inclusive nas de erro.
+func Deprecated(deprecation Deprecation) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			header := w.Header()
+
+			header.Set("Deprecation", fmt.Sprintf("@%d", deprecation.DeprecatedAt.Unix()))
+
+			if !deprecation.Sunset.IsZero() {
+				header.Set("Sunset", deprecation.Sunset.UTC().Format(http.TimeFormat))
+			}
+
+			if deprecation.Successor != "" {
+				header.Add("Link", fmt.Sprintf(`<%s>; rel="successor-version"`, deprecation.Successor))
+			}
+
+			next(w, r)
+		}
+	}
+}
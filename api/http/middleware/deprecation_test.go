@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Testes do middleware de descontinuação de versões
+
+func TestDeprecated(t *testing.T) {
+	tests := []struct {
+		name           string
+		deprecation    Deprecation
+		status         int
+		expectedSunset string
+		expectedLink   string
+	}{
+		{
+			name: "all headers",
+			deprecation: Deprecation{
+				DeprecatedAt: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+				Sunset:       time.Date(2027, 4, 1, 0, 0, 0, 0, time.FixedZone("BRT", -3*60*60)),
+				Successor:    "/v2/deliveries",
+			},
+			status:         http.StatusOK,
+			expectedSunset: "Thu, 01 Apr 2027 03:00:00 GMT",
+			expectedLink:   `</v2/deliveries>; rel="successor-version"`,
+		},
+		{
+			name:        "error response without sunset and successor",
+			deprecation: Deprecation{DeprecatedAt: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)},
+			status:      http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := Deprecated(tt.deprecation)(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			})
+
+			req := httptest.NewRequest("GET", "/v1/deliveries", nil)
+			w := httptest.NewRecorder()
+
+			handler(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, "@1790812800", w.Header().Get("Deprecation"))
+			assert.Equal(t, tt.expectedSunset, w.Header().Get("Sunset"))
+			assert.Equal(t, tt.expectedLink, w.Header().Get("Link"))
+		})
+	}
+}
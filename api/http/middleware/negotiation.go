@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/samluiz/delivery-service/api/http/utils"
+)
+
+// Função responsável por rejeitar com 406 as requisições cujo header Accept não aceita nenhum dos formatos
+// suportados pelo utils.NewResponse. A verificação acontece antes do handler, para que operações de escrita
+// não sejam executadas sem que a resposta possa ser enviada. O erro é renderizado em JSON.
+func Negotiate(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := utils.NegotiateFormat(r.Header.Get("Accept")); !ok {
+			utils.NewJSONResponse(w, http.StatusNotAcceptable, utils.NewNotAcceptableError(
+				fmt.Errorf("accept must allow one of: %s", utils.SupportedFormats()), r))
+			return
+		}
+
+		next(w, r)
+	}
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/samluiz/delivery-service/api/http/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Testes do middleware de negociação do formato das respostas
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name           string
+		accept         string
+		expectedStatus int
+	}{
+		{name: "without accept", expectedStatus: http.StatusOK},
+		{name: "supported format", accept: "text/csv", expectedStatus: http.StatusOK},
+		{name: "wildcard", accept: "application/pdf, */*;q=0.1", expectedStatus: http.StatusOK},
+		{name: "unsupported format", accept: "application/pdf", expectedStatus: http.StatusNotAcceptable},
+		{name: "all formats refused", accept: "application/json;q=0, text/*;q=0", expectedStatus: http.StatusNotAcceptable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			handler := Negotiate(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				w.WriteHeader(http.StatusOK)
+			})
+
+			r := httptest.NewRequest("POST", "/v2/deliveries", nil)
+			if tt.accept != "" {
+				r.Header.Set("Accept", tt.accept)
+			}
+			w := httptest.NewRecorder()
+
+			handler(w, r)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedStatus == http.StatusOK, called)
+
+			if tt.expectedStatus == http.StatusNotAcceptable {
+				assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+				var response utils.Error
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+				assert.Equal(t, "accept must allow one of: application/json, application/xml, application/msgpack, text/csv", response.Cause)
+			}
+		})
+	}
+}
@@ -0,0 +1,374 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy"
+	"github.com/samluiz/delivery-service/api/http/utils"
+)
+
+// Opções da validação do contrato OpenAPI.
+type OpenAPIOptions struct {
+	// Valida também as respostas, retornando 500 quando divergem do documento.
+	// Indicado apenas para testes, pois a resposta é mantida em memória até ser validada.
+	ValidateResponses bool
+}
+
+// Struct responsável por validar as requisições contra o documento OpenAPI antes de chegarem aos handlers.
+type OpenAPIValidator struct {
+	router    routers.Router
+	validator *utils.XValidator
+	options   OpenAPIOptions
+}
+
+// Função responsável por instanciar o validador a partir do documento OpenAPI (JSON ou YAML).
+// As mensagens das falhas são as do validator informado, o mesmo utilizado pelos handlers.
+// Retorna erro quando o documento é inválido.
+func NewOpenAPIValidator(document []byte, validator *utils.XValidator, options OpenAPIOptions) (*OpenAPIValidator, error) {
+	loader := openapi3.NewLoader()
+
+	spec, err := loader.LoadFromData(document)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load openapi document: %w", err)
+	}
+
+	if err := spec.Validate(loader.Context); err != nil {
+		return nil, fmt.Errorf("invalid openapi document: %w", err)
+	}
+
+	router, err := legacy.NewRouter(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build openapi router: %w", err)
+	}
+
+	return &OpenAPIValidator{router: router, validator: validator, options: options}, nil
+}
+
+// Função responsável por validar os parâmetros de path e query e o corpo da requisição contra o documento.
+// Requisições inválidas recebem 400 com a falha de cada campo. Corpos que não são JSON válido seguem
+// para o handler, que retorna o erro específico (415, 413 ou 400). A autenticação fica a cargo do Authenticator.
+func (v *OpenAPIValidator) Validate(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		route, pathParams, err := v.router.FindRoute(r)
+
+		// Rotas não documentadas são detectadas pelo teste das rotas, não na requisição
+		if err != nil {
+			next(w, r)
+			return
+		}
+
+		input := &openapi3filter.RequestValidationInput{
+			Request:    r,
+			PathParams: pathParams,
+			Route:      route,
+			Options: &openapi3filter.Options{
+				// As credenciais são verificadas pelo Authenticator
+				AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+				MultiError:         true,
+			},
+		}
+
+		var body []byte
+
+		if route.Operation.RequestBody != nil && r.Body != nil && r.Body != http.NoBody {
+			body, err = io.ReadAll(http.MaxBytesReader(w, r.Body, utils.MaxBodyBytes()))
+
+			if err != nil {
+				var maxBytesError *http.MaxBytesError
+				if errors.As(err, &maxBytesError) {
+					utils.NewJSONResponse(w, http.StatusRequestEntityTooLarge, utils.NewPayloadTooLargeError(
+						fmt.Errorf("request body must not exceed %d bytes", maxBytesError.Limit), r))
+					return
+				}
+				utils.NewJSONResponse(w, http.StatusBadRequest, utils.NewBadRequestError(err, r))
+				return
+			}
+
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			if !utils.IsJSONContentType(r.Header.Get("Content-Type")) || !json.Valid(body) {
+				input.Options.ExcludeRequestBody = true
+			}
+		}
+
+		if err := openapi3filter.ValidateRequest(r.Context(), input); err != nil {
+			violations := contractViolations(err)
+			utils.NewJSONResponse(w, http.StatusBadRequest, v.validator.NewValidationError(violationsCause(violations), violations, r))
+			return
+		}
+
+		// O corpo é consumido pela validação, por isso é restaurado para o handler
+		if body != nil {
+			r.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		if !v.options.ValidateResponses {
+			next(w, r)
+			return
+		}
+
+		v.validateResponse(w, r, input, next)
+	}
+}
+
+// Função responsável por executar o handler e validar a resposta contra o documento antes de enviá-la.
+func (v *OpenAPIValidator) validateResponse(w http.ResponseWriter, r *http.Request, input *openapi3filter.RequestValidationInput, next http.HandlerFunc) {
+	response := newBufferedResponse()
+
+	next(response, r)
+
+	responseInput := &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: input,
+		Status:                 response.status,
+		Header:                 response.header,
+		Options: &openapi3filter.Options{
+			IncludeResponseStatus: true,
+			MultiError:            true,
+			// Corpos em XML, MessagePack ou CSV não possuem decoder no kin-openapi; apenas o status e o content type são validados
+			ExcludeResponseBody: !isJSONResponse(response.header.Get("Content-Type")),
+		},
+	}
+	responseInput.SetBodyBytes(response.body.Bytes())
+
+	if err := openapi3filter.ValidateResponse(r.Context(), responseInput); err != nil {
+		utils.NewJSONResponse(w, http.StatusInternalServerError, utils.NewInternalServerError(
+			fmt.Errorf("response does not match the openapi document: %w", violationsCause(contractViolations(err))), r))
+		return
+	}
+
+	for key, values := range response.header {
+		w.Header()[key] = values
+	}
+	w.WriteHeader(response.status)
+	w.Write(response.body.Bytes())
+}
+
+// Função responsável por verificar se a resposta é JSON (application/json ou application/problem+json).
+func isJSONResponse(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mediaType == "application/json" || mediaType == utils.ProblemContentType)
+}
+
+// Função responsável por converter os erros da validação do contrato nas falhas de cada campo.
+// Parâmetros são identificados pelo nome e campos do corpo pelo caminho no JSON (ex: escopos[0]).
+// Os erros são percorridos pelo tipo concreto, pois errors.As atravessaria o RequestError e perderia o parâmetro.
+func contractViolations(err error) []utils.ValidationError {
+	switch e := err.(type) {
+	case openapi3.MultiError:
+		violations := make([]utils.ValidationError, 0, len(e))
+		for _, err := range e {
+			violations = append(violations, contractViolations(err)...)
+		}
+		return violations
+	case *openapi3filter.RequestError:
+		if e.Err == nil {
+			violation := utils.ValidationError{Error: true, Tag: "required", Value: e.Reason}
+			if e.Parameter != nil {
+				violation.Field = e.Parameter.Name
+			}
+			return []utils.ValidationError{violation}
+		}
+
+		violations := contractViolations(e.Err)
+
+		// Falhas nos parâmetros são identificadas pelo nome do parâmetro
+		if e.Parameter != nil {
+			for i := range violations {
+				violations[i].Field = e.Parameter.Name
+				if violations[i].Tag == tagUnparsable && e.Parameter.Schema != nil && e.Parameter.Schema.Value != nil {
+					violations[i].Tag = "type"
+					violations[i].Param = strings.Join(e.Parameter.Schema.Value.Type.Slice(), " ")
+				}
+			}
+		}
+		return violations
+	case *openapi3filter.ResponseError:
+		if e.Err == nil {
+			return []utils.ValidationError{{Error: true, Tag: "response", Value: e.Reason}}
+		}
+		return contractViolations(e.Err)
+	case *openapi3filter.ParseError:
+		return []utils.ValidationError{{Error: true, Tag: tagUnparsable, Value: e.Error()}}
+	case *openapi3.SchemaError:
+		// Com exclusiveMinimum (ou exclusiveMaximum) o mesmo limite é reportado duas vezes, mantendo apenas a regra exclusiva
+		if e.Schema != nil && ((e.SchemaField == "minimum" && e.Schema.ExclusiveMin) || (e.SchemaField == "maximum" && e.Schema.ExclusiveMax)) {
+			return nil
+		}
+
+		violation := utils.ValidationError{
+			Error: true,
+			Field: jsonPath(e.JSONPointer()),
+			Tag:   e.SchemaField,
+			Param: schemaParam(e),
+			Value: e.Reason,
+		}
+
+		// Usando as regras equivalentes do validator, para que as duas validações tenham as mesmas regras e mensagens
+		if tag, ok := schemaTags[e.SchemaField]; ok {
+			violation.Tag = tag
+		}
+
+		// Propriedades fora do schema são identificadas pelo nome, assim como no DecodeJSONBody
+		if property, ok := unsupportedProperty(e.Reason); ok {
+			violation.Field = joinPath(violation.Field, property)
+			violation.Tag = "unknown"
+		}
+
+		// Removendo o padrão da expressão regular dos formatos, que não ajuda o cliente
+		if reason, _, found := strings.Cut(e.Reason, " (string doesn't match pattern"); found {
+			violation.Value = reason
+		}
+
+		return []utils.ValidationError{violation}
+	}
+
+	return []utils.ValidationError{{Error: true, Tag: tagUnparsable, Value: err.Error()}}
+}
+
+// Regras do validator equivalentes às palavras-chave do schema OpenAPI.
+var schemaTags = map[string]string{
+	"maxLength":        "max",
+	"minLength":        "min",
+	"minItems":         "min",
+	"maxItems":         "max",
+	"maximum":          "lte",
+	"minimum":          "gte",
+	"exclusiveMaximum": "lt",
+	"exclusiveMinimum": "gt",
+	"enum":             "oneof",
+}
+
+// Regra das falhas que não vêm de uma regra do schema (ex: parâmetro que não pode ser convertido).
+const tagUnparsable = "schema"
+
+// Função responsável por extrair o nome da propriedade das falhas de propriedade não suportada.
+// A kin-openapi não possui um campo para essa informação, apenas a mensagem.
+func unsupportedProperty(reason string) (string, bool) {
+	property, found := strings.CutPrefix(reason, "property \"")
+	if !found {
+		return "", false
+	}
+
+	property, found = strings.CutSuffix(property, "\" is unsupported")
+	return property, found
+}
+
+// Função responsável por montar a causa do erro a partir das falhas de cada campo.
+func violationsCause(violations []utils.ValidationError) error {
+	causes := make([]string, 0, len(violations))
+
+	for _, violation := range violations {
+		if violation.Field == "" {
+			causes = append(causes, fmt.Sprint(violation.Value))
+			continue
+		}
+		causes = append(causes, fmt.Sprintf("%s: %v", violation.Field, violation.Value))
+	}
+
+	return errors.New(strings.Join(causes, "; "))
+}
+
+// Função responsável por converter um JSON pointer no caminho do campo (ex: ["escopos", "0"] em escopos[0]).
+func jsonPath(pointer []string) string {
+	var path strings.Builder
+
+	for _, part := range pointer {
+		if _, err := strconv.Atoi(part); err == nil {
+			path.WriteString("[" + part + "]")
+			continue
+		}
+		if path.Len() > 0 {
+			path.WriteString(".")
+		}
+		path.WriteString(part)
+	}
+
+	return path.String()
+}
+
+// Função responsável por adicionar uma propriedade ao caminho do campo.
+func joinPath(path string, property string) string {
+	if path == "" {
+		return property
+	}
+	return path + "." + property
+}
+
+// Função responsável por buscar o valor da regra do schema que falhou (ex: 255 para maxLength).
+func schemaParam(err *openapi3.SchemaError) string {
+	schema := err.Schema
+	if schema == nil {
+		return ""
+	}
+
+	switch err.SchemaField {
+	case "type":
+		if schema.Type != nil {
+			return strings.Join(schema.Type.Slice(), " ")
+		}
+	case "format":
+		return schema.Format
+	case "maxLength":
+		if schema.MaxLength != nil {
+			return strconv.FormatUint(*schema.MaxLength, 10)
+		}
+	case "minLength":
+		return strconv.FormatUint(schema.MinLength, 10)
+	case "minimum", "exclusiveMinimum":
+		if schema.Min != nil {
+			return strconv.FormatFloat(*schema.Min, 'f', -1, 64)
+		}
+	case "maximum", "exclusiveMaximum":
+		if schema.Max != nil {
+			return strconv.FormatFloat(*schema.Max, 'f', -1, 64)
+		}
+	case "minItems":
+		return strconv.FormatUint(schema.MinItems, 10)
+	case "maxItems":
+		if schema.MaxItems != nil {
+			return strconv.FormatUint(*schema.MaxItems, 10)
+		}
+	case "enum":
+		values := make([]string, 0, len(schema.Enum))
+		for _, value := range schema.Enum {
+			values = append(values, fmt.Sprint(value))
+		}
+		return strings.Join(values, " ")
+	}
+
+	return ""
+}
+
+// Struct que mantém a resposta do handler em memória para ser validada antes do envio.
+type bufferedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func newBufferedResponse() *bufferedResponse {
+	return &bufferedResponse{header: http.Header{}, status: http.StatusOK}
+}
+
+func (b *bufferedResponse) Header() http.Header {
+	return b.header
+}
+
+func (b *bufferedResponse) WriteHeader(status int) {
+	b.status = status
+}
+
+func (b *bufferedResponse) Write(data []byte) (int, error) {
+	return b.body.Write(data)
+}
@@ -0,0 +1,275 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/samluiz/delivery-service/api/docs"
+	"github.com/samluiz/delivery-service/api/http/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const validDeliveryBody = `{"cliente":"Cliente A","peso":10.5,"endereco":"Rua A","logradouro":"Logradouro A",
+	"numero":"123","bairro":"Bairro A","complemento":"Apto 1","cidade":"Recife","estado":"PE","pais":"BR",
+	"latitude":-8.05,"longitude":-34.9}`
+
+// Testes do middleware de validação do contrato OpenAPI
+
+func TestNewOpenAPIValidator_InvalidDocument(t *testing.T) {
+	_, err := NewOpenAPIValidator([]byte(`{"openapi":"3.0.3"`), newValidator(t), OpenAPIOptions{})
+	assert.Error(t, err)
+
+	_, err = NewOpenAPIValidator([]byte(`{"openapi":"3.0.3","info":{"title":"x","version":"1"},"paths":{"/x":{"get":{}}}}`), newValidator(t), OpenAPIOptions{})
+	assert.Error(t, err)
+}
+
+func TestOpenAPIValidator_Validate(t *testing.T) {
+	validator, err := NewOpenAPIValidator(docs.OpenAPI, newValidator(t), OpenAPIOptions{})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		method         string
+		target         string
+		contentType    string
+		body           string
+		expectedStatus int
+		expectedFields []utils.FieldError
+	}{
+		{
+			name:           "valid path parameter",
+			method:         "GET",
+			target:         "/v1/deliveries/1",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "non integer path parameter",
+			method:         "GET",
+			target:         "/v1/deliveries/abc",
+			expectedStatus: http.StatusBadRequest,
+			expectedFields: []utils.FieldError{{Campo: "id", Regra: "type", Parametro: "integer", Mensagem: "id deve ser do tipo integer"}},
+		},
+		{
+			name:           "path parameter below minimum",
+			method:         "DELETE",
+			target:         "/v1/deliveries/0",
+			expectedStatus: http.StatusBadRequest,
+			expectedFields: []utils.FieldError{{Campo: "id", Regra: "gte", Parametro: "1", Mensagem: "id deve ser 1 ou superior"}},
+		},
+		{
+			name:           "invalid query parameter format",
+			method:         "GET",
+			target:         "/v1/deliveries/1?as_of=yesterday",
+			expectedStatus: http.StatusBadRequest,
+			expectedFields: []utils.FieldError{{Campo: "as_of", Regra: "format", Parametro: "date-time", Mensagem: "as_of deve estar no formato date-time"}},
+		},
+		{
+			name:           "invalid query parameter enum",
+			method:         "GET",
+			target:         "/audit?action=archive",
+			expectedStatus: http.StatusBadRequest,
+			expectedFields: []utils.FieldError{{
+				Campo:     "action",
+				Regra:     "oneof",
+				Parametro: "create update delete restore revert purge",
+				Mensagem:  "action deve ser um de [create update delete restore revert purge]",
+			}},
+		},
+		{
+			name:           "valid body",
+			method:         "POST",
+			target:         "/v1/deliveries",
+			contentType:    "application/json",
+			body:           validDeliveryBody,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "body violating schema",
+			method:         "POST",
+			target:         "/v1/deliveries",
+			contentType:    "application/json",
+			body:           strings.NewReplacer(`"peso":10.5`, `"peso":0,"extra":true`, `"latitude":-8.05`, `"latitude":91`).Replace(validDeliveryBody),
+			expectedStatus: http.StatusBadRequest,
+			expectedFields: []utils.FieldError{
+				{Campo: "extra", Regra: "unknown", Mensagem: "extra não é um campo permitido"},
+				{Campo: "latitude", Regra: "lte", Parametro: "90", Mensagem: "latitude deve ser 90 ou menor"},
+				{Campo: "peso", Regra: "gt", Parametro: "0", Mensagem: "peso deve ser maior do que 0"},
+			},
+		},
+		{
+			name:           "body missing coordinates",
+			method:         "POST",
+			target:         "/v1/deliveries",
+			contentType:    "application/json",
+			body:           strings.Replace(validDeliveryBody, `"latitude":-8.05,"longitude":-34.9`, `"motorista":""`, 1),
+			expectedStatus: http.StatusBadRequest,
+			expectedFields: []utils.FieldError{
+				{Campo: "latitude", Regra: "required", Mensagem: "latitude é um campo obrigatório"},
+				{Campo: "longitude", Regra: "required", Mensagem: "longitude é um campo obrigatório"},
+			},
+		},
+		{
+			name:           "body missing required field",
+			method:         "POST",
+			target:         "/admin/api-keys",
+			contentType:    "application/json",
+			body:           `{"escopos":["read","owner"]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedFields: []utils.FieldError{
+				{Campo: "nome", Regra: "required", Mensagem: "nome é um campo obrigatório"},
+				{Campo: "escopos[1]", Regra: "oneof", Parametro: "read write admin", Mensagem: "escopos[1] deve ser um de [read write admin]"},
+			},
+		},
+		{
+			// O handler retorna o 415
+			name:           "non json content type is left to the handler",
+			method:         "POST",
+			target:         "/v1/deliveries",
+			contentType:    "text/plain",
+			body:           "cliente",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			// O handler retorna o erro de sintaxe
+			name:           "malformed json is left to the handler",
+			method:         "POST",
+			target:         "/v1/deliveries",
+			contentType:    "application/json",
+			body:           `{"cliente":`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "undocumented route",
+			method:         "GET",
+			target:         "/undocumented",
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var receivedBody string
+			handler := validator.Validate(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				receivedBody = string(body)
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			w := httptest.NewRecorder()
+
+			handler(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			if tt.expectedStatus == http.StatusOK {
+				// O corpo consumido pela validação é restaurado para o handler
+				assert.Equal(t, tt.body, receivedBody)
+				return
+			}
+
+			var response utils.Error
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.ElementsMatch(t, tt.expectedFields, response.Fields)
+			assert.NotEmpty(t, response.Cause)
+		})
+	}
+}
+
+func TestOpenAPIValidator_PayloadTooLarge(t *testing.T) {
+	utils.ConfigureMaxBodyBytes(16)
+	defer utils.ConfigureMaxBodyBytes(utils.DefaultMaxBodyBytes)
+
+	validator, err := NewOpenAPIValidator(docs.OpenAPI, newValidator(t), OpenAPIOptions{})
+	require.NoError(t, err)
+
+	handler := validator.Validate(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest("POST", "/v1/deliveries", strings.NewReader(validDeliveryBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler(w, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+}
+
+func TestOpenAPIValidator_ValidateResponses(t *testing.T) {
+	validator, err := NewOpenAPIValidator(docs.OpenAPI, newValidator(t), OpenAPIOptions{ValidateResponses: true})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		status         int
+		body           string
+		expectedStatus int
+	}{
+		{
+			name:           "response matching the document",
+			status:         http.StatusOK,
+			body:           `{"quantidade":3}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "response with wrong field type",
+			status:         http.StatusOK,
+			body:           `{"quantidade":"3"}`,
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "undocumented status",
+			status:         http.StatusAccepted,
+			body:           `{"quantidade":3}`,
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := validator.Validate(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("X-Custom", "value")
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			})
+
+			req := httptest.NewRequest("DELETE", "/v1/deliveries", nil)
+			req.Header.Set("X-Confirmation-Token", "abc")
+			w := httptest.NewRecorder()
+
+			handler(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			if tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, tt.body, w.Body.String())
+				assert.Equal(t, "value", w.Header().Get("X-Custom"))
+				return
+			}
+
+			var response utils.Error
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Contains(t, response.Cause, "response does not match the openapi document")
+		})
+	}
+}
+
+// Função responsável por criar o validator que monta as mensagens das falhas do contrato.
+func newValidator(t *testing.T) *utils.XValidator {
+	t.Helper()
+
+	validator, err := utils.NewXValidator()
+	require.NoError(t, err)
+
+	return validator
+}
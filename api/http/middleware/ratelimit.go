@@ -0,0 +1,246 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/samluiz/delivery-service/api/http/utils"
+	"github.com/samluiz/delivery-service/internal/apikey"
+	"github.com/samluiz/delivery-service/internal/auth"
+	"github.com/samluiz/delivery-service/internal/ratelimit"
+	"github.com/samluiz/delivery-service/internal/requestmeta"
+	"github.com/samluiz/delivery-service/internal/tenant"
+)
+
+// Headers do rate limiting (draft-ietf-httpapi-ratelimit-headers).
+const (
+	RateLimitLimitHeader     = "RateLimit-Limit"
+	RateLimitRemainingHeader = "RateLimit-Remaining"
+	RateLimitResetHeader     = "RateLimit-Reset"
+	RateLimitPolicyHeader    = "RateLimit-Policy"
+)
+
+// Configuração dos limites de requisições.
+type RateLimitOptions struct {
+	// Limite aplicado aos clientes sem um limite específico. O limite zero desabilita o rate limiting.
+	Default ratelimit.Limit
+
+	// Limites por rota, indexados pelo padrão do http.ServeMux (ex: "POST /v2/deliveries").
+	// Cada rota com limite próprio possui um balde separado por cliente.
+	Routes map[string]ratelimit.Limit
+
+	// Limites por escopo das API keys. Quando a key possui vários escopos, vale o maior limite.
+	Scopes map[apikey.Scope]ratelimit.Limit
+
+	// Limites por tenant, aplicados aos clientes do tenant sem limite de rota ou de escopo.
+	Tenants map[string]ratelimit.Limit
+
+	// Limite por IP aplicado antes da autenticação, com um balde próprio. Contabiliza as requisições sem credencial ou
+	// com credencial inválida, que não chegam ao limite por cliente, mas consultam as API keys no banco.
+	Unauthenticated ratelimit.Limit
+}
+
+// Rate limiter por token bucket, identificando o cliente pela API key, pelo usuário do JWT ou pelo IP.
+type RateLimiter struct {
+	store   ratelimit.Store
+	options RateLimitOptions
+}
+
+// Função responsável por instanciar o rate limiter.
+func NewRateLimiter(store ratelimit.Store, options RateLimitOptions) *RateLimiter {
+	return &RateLimiter{store: store, options: options}
+}
+
+// Função responsável por limitar as requisições de cada cliente, respondendo 429 quando o balde está vazio.
+// Deve ser aplicado após a autenticação, para que o cliente seja identificado pela credencial e não apenas pelo IP.
+// Falhas do armazenamento não bloqueiam as requisições.
+func (l *RateLimiter) Limit(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key, limit := l.resolve(r)
+		if l.allow(w, r, key, limit) {
+			next(w, r)
+		}
+	}
+}
+
+// Função responsável por limitar as requisições de cada IP antes da autenticação, para que as tentativas sem
+// credencial ou com credencial inválida também sejam contabilizadas antes da consulta das API keys.
+func (l *RateLimiter) LimitUnauthenticated(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if l.allow(w, r, unauthenticatedKey(ipKey(r.Context(), r)), l.options.Unauthenticated) {
+			next(w, r)
+		}
+	}
+}
+
+// Função responsável por consumir um token do balde e escrever os headers do rate limiting.
+// Retorna false quando a requisição foi recusada com 429.
+func (l *RateLimiter) allow(w http.ResponseWriter, r *http.Request, key string, limit ratelimit.Limit) bool {
+	result, ok := l.take(r.Context(), key, limit)
+	if !ok {
+		return true
+	}
+
+	header := w.Header()
+	for name, value := range RateLimitHeaders(result) {
+		header.Set(name, value)
+	}
+
+	if !result.Allowed {
+		utils.NewJSONResponse(w, http.StatusTooManyRequests, utils.NewTooManyRequestsError(RateLimitError(result), r))
+		return false
+	}
+
+	return true
+}
+
+// Função responsável por montar os headers do rate limiting, também enviados nos metadados das chamadas gRPC.
+// O Retry-After é incluído apenas quando a requisição foi recusada.
+func RateLimitHeaders(result ratelimit.Result) map[string]string {
+	headers := map[string]string{
+		RateLimitLimitHeader:     strconv.Itoa(result.Limit.Requests),
+		RateLimitRemainingHeader: strconv.Itoa(result.Remaining),
+		RateLimitResetHeader:     strconv.Itoa(ceilSeconds(result.Reset)),
+		RateLimitPolicyHeader:    fmt.Sprintf("%d;w=%d", result.Limit.Requests, ceilSeconds(result.Limit.Period)),
+	}
+	if !result.Allowed {
+		headers["Retry-After"] = strconv.Itoa(ceilSeconds(result.RetryAfter))
+	}
+	return headers
+}
+
+// Função responsável por consumir um token do balde do cliente da chamada gRPC, com o limite do método (pelo nome
+// completo, ex: "/delivery.v1.DeliveryService/CreateDelivery"), do escopo da API key, do tenant ou o padrão.
+// Deve ser chamada após a autenticação. Retorna false quando o limite está desabilitado ou o armazenamento falhou,
+// casos em que a chamada é liberada.
+func (l *RateLimiter) Take(ctx context.Context, method string) (ratelimit.Result, bool) {
+	key, limit := l.resolveContext(ctx, method, clientKey(ctx, nil))
+	return l.take(ctx, key, limit)
+}
+
+// Função responsável por consumir um token do balde por IP da chamada gRPC antes da autenticação.
+func (l *RateLimiter) TakeUnauthenticated(ctx context.Context) (ratelimit.Result, bool) {
+	return l.take(ctx, unauthenticatedKey(ipKey(ctx, nil)), l.options.Unauthenticated)
+}
+
+// Função responsável por consumir um token do balde. Falhas do armazenamento não bloqueiam as requisições.
+func (l *RateLimiter) take(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, bool) {
+	if !limit.Enabled() {
+		return ratelimit.Result{}, false
+	}
+
+	result, err := l.store.Take(ctx, key, limit)
+	if err != nil {
+		log.Printf("Erro ao consultar o limite de requisições de %s: %v", key, err)
+		return ratelimit.Result{}, false
+	}
+	result.Limit = limit
+
+	return result, true
+}
+
+// Função responsável por descrever a recusa do rate limiting, no corpo do 429 e no status das chamadas gRPC.
+func RateLimitError(result ratelimit.Result) error {
+	return fmt.Errorf("rate limit of %d requests per %s exceeded, retry in %d seconds",
+		result.Limit.Requests, result.Limit.Period, ceilSeconds(result.RetryAfter))
+}
+
+// Função responsável por definir o balde e o limite da requisição.
+// O limite da rota tem precedência sobre o limite do escopo da API key, seguido pelo limite do tenant e pelo padrão.
+func (l *RateLimiter) resolve(r *http.Request) (string, ratelimit.Limit) {
+	return l.resolveContext(r.Context(), r.Pattern, clientKey(r.Context(), r))
+}
+
+// Função responsável por definir o balde e o limite do cliente, com a rota HTTP ou o método gRPC.
+func (l *RateLimiter) resolveContext(ctx context.Context, route string, client string) (string, ratelimit.Limit) {
+	if limit, ok := l.options.Routes[route]; ok {
+		return client + "|" + route, limit
+	}
+
+	if key, ok := apikey.FromContext(ctx); ok {
+		if limit, ok := l.scopeLimit(key.Escopos); ok {
+			return client, limit
+		}
+	}
+
+	if id, ok := tenant.FromContext(ctx); ok {
+		if limit, ok := l.options.Tenants[id]; ok {
+			return client, limit
+		}
+	}
+
+	return client, l.options.Default
+}
+
+// Função responsável por buscar o maior limite entre os escopos da API key. Um escopo com limite desabilitado
+// libera a key do rate limiting.
+func (l *RateLimiter) scopeLimit(scopes []apikey.Scope) (ratelimit.Limit, bool) {
+	var (
+		best  ratelimit.Limit
+		found bool
+	)
+
+	for _, scope := range scopes {
+		limit, ok := l.options.Scopes[scope]
+		if !ok {
+			continue
+		}
+		if !found || rate(limit) > rate(best) {
+			best, found = limit, true
+		}
+	}
+
+	return best, found
+}
+
+// Função responsável por calcular a taxa do limite, em requisições por segundo. Limites desabilitados têm taxa infinita.
+func rate(limit ratelimit.Limit) float64 {
+	if !limit.Enabled() {
+		return math.Inf(1)
+	}
+	return float64(limit.Requests) / limit.Period.Seconds()
+}
+
+// Função responsável por identificar o cliente da requisição: a API key, o usuário do JWT ou o IP.
+// Sem requisição HTTP (chamadas gRPC), o IP vem apenas dos metadados da requisição.
+func clientKey(ctx context.Context, r *http.Request) string {
+	if principal, ok := auth.FromContext(ctx); ok {
+		if principal.Type == auth.PrincipalAPIKey {
+			return principal.Subject
+		}
+		// O mesmo usuário pode existir em mais de um provedor de identidade, um por tenant
+		if principal.Tenant != "" && principal.Tenant != tenant.Default {
+			return "user:" + principal.Tenant + ":" + principal.Subject
+		}
+		return "user:" + principal.Subject
+	}
+
+	return ipKey(ctx, r)
+}
+
+// Função responsável por identificar o cliente pelo IP.
+func ipKey(ctx context.Context, r *http.Request) string {
+	if metadata, ok := requestmeta.FromContext(ctx); ok && metadata.ClientIP != "" {
+		return "ip:" + metadata.ClientIP
+	}
+
+	if r == nil {
+		return "ip:unknown"
+	}
+	return "ip:" + clientIP(r, false)
+}
+
+// Função responsável por definir o balde do limite aplicado antes da autenticação, separado do balde do cliente.
+func unauthenticatedKey(ip string) string {
+	return "unauthenticated|" + ip
+}
+
+// Função responsável por arredondar a duração para cima, em segundos inteiros.
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,368 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/samluiz/delivery-service/api/http/utils"
+	"github.com/samluiz/delivery-service/internal/apikey"
+	"github.com/samluiz/delivery-service/internal/auth"
+	"github.com/samluiz/delivery-service/internal/ratelimit"
+	"github.com/samluiz/delivery-service/internal/requestmeta"
+	"github.com/samluiz/delivery-service/internal/tenant"
+	"github.com/stretchr/testify/assert"
+)
+
+// Mock do armazenamento dos baldes, que registra as chaves e os limites consultados
+
+type MockRateLimitStore struct {
+	results map[string]ratelimit.Result
+	err     error
+	keys    []string
+	limits  []ratelimit.Limit
+}
+
+func (m *MockRateLimitStore) Take(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error) {
+	m.keys = append(m.keys, key)
+	m.limits = append(m.limits, limit)
+
+	if m.err != nil {
+		return ratelimit.Result{}, m.err
+	}
+	if result, ok := m.results[key]; ok {
+		return result, nil
+	}
+	return ratelimit.Result{Limit: limit, Allowed: true, Remaining: limit.Requests - 1, Reset: time.Second}, nil
+}
+
+func withAPIKey(r *http.Request, key *apikey.APIKey) *http.Request {
+	ctx := apikey.WithAPIKey(r.Context(), key)
+	ctx = auth.WithPrincipal(ctx, &auth.Principal{Type: auth.PrincipalAPIKey, Subject: "apikey:1"})
+	return r.WithContext(ctx)
+}
+
+func TestRateLimiter_Resolve(t *testing.T) {
+	perMinute := ratelimit.Limit{Requests: 100, Period: time.Minute}
+	perSecond := ratelimit.Limit{Requests: 5, Period: time.Second}
+	readLimit := ratelimit.Limit{Requests: 1000, Period: time.Minute}
+	unlimited := ratelimit.Limit{Requests: 0, Period: time.Minute}
+	tenantLimit := ratelimit.Limit{Requests: 50, Period: time.Minute}
+
+	options := RateLimitOptions{
+		Default: perMinute,
+		Routes:  map[string]ratelimit.Limit{"POST /v2/deliveries": perSecond},
+		Scopes: map[apikey.Scope]ratelimit.Limit{
+			apikey.ScopeRead:  readLimit,
+			apikey.ScopeWrite: {Requests: 200, Period: time.Minute},
+			apikey.ScopeAdmin: unlimited,
+		},
+		Tenants: map[string]ratelimit.Limit{"acme": tenantLimit},
+	}
+
+	reader := &apikey.APIKey{ID: 1, Escopos: []apikey.Scope{apikey.ScopeRead}}
+	readerWriter := &apikey.APIKey{ID: 1, Escopos: []apikey.Scope{apikey.ScopeWrite, apikey.ScopeRead}}
+	admin := &apikey.APIKey{ID: 1, Escopos: []apikey.Scope{apikey.ScopeRead, apikey.ScopeAdmin}}
+
+	tests := []struct {
+		name          string
+		pattern       string
+		request       func(r *http.Request) *http.Request
+		expectedKey   string
+		expectedLimit ratelimit.Limit
+	}{
+		{
+			name:          "Cliente anônimo identificado pelo IP",
+			pattern:       "GET /v2/deliveries",
+			request:       func(r *http.Request) *http.Request { return r },
+			expectedKey:   "ip:10.0.0.1",
+			expectedLimit: perMinute,
+		},
+		{
+			name:    "Usuário do JWT com o limite padrão",
+			pattern: "GET /v2/deliveries",
+			request: func(r *http.Request) *http.Request {
+				return r.WithContext(auth.WithPrincipal(r.Context(), &auth.Principal{Type: auth.PrincipalUser, Subject: "maria"}))
+			},
+			expectedKey:   "user:maria",
+			expectedLimit: perMinute,
+		},
+		{
+			name:    "Usuário do JWT com o limite do tenant",
+			pattern: "GET /v2/deliveries",
+			request: func(r *http.Request) *http.Request {
+				ctx := auth.WithPrincipal(r.Context(), &auth.Principal{Type: auth.PrincipalUser, Subject: "maria", Tenant: "acme"})
+				return r.WithContext(tenant.WithTenant(ctx, "acme"))
+			},
+			expectedKey:   "user:acme:maria",
+			expectedLimit: tenantLimit,
+		},
+		{
+			name:    "Tenant sem limite próprio usa o padrão",
+			pattern: "GET /v2/deliveries",
+			request: func(r *http.Request) *http.Request {
+				ctx := auth.WithPrincipal(r.Context(), &auth.Principal{Type: auth.PrincipalUser, Subject: "maria", Tenant: "globex"})
+				return r.WithContext(tenant.WithTenant(ctx, "globex"))
+			},
+			expectedKey:   "user:globex:maria",
+			expectedLimit: perMinute,
+		},
+		{
+			name:    "Escopo da API key tem precedência sobre o tenant",
+			pattern: "GET /v2/deliveries",
+			request: func(r *http.Request) *http.Request {
+				return withAPIKey(r.WithContext(tenant.WithTenant(r.Context(), "acme")), reader)
+			},
+			expectedKey:   "apikey:1",
+			expectedLimit: readLimit,
+		},
+		{
+			name:          "API key com o limite do escopo",
+			pattern:       "GET /v2/deliveries",
+			request:       func(r *http.Request) *http.Request { return withAPIKey(r, reader) },
+			expectedKey:   "apikey:1",
+			expectedLimit: readLimit,
+		},
+		{
+			name:          "API key com vários escopos usa o maior limite",
+			pattern:       "GET /v2/deliveries",
+			request:       func(r *http.Request) *http.Request { return withAPIKey(r, readerWriter) },
+			expectedKey:   "apikey:1",
+			expectedLimit: readLimit,
+		},
+		{
+			name:          "Escopo sem limite libera a API key",
+			pattern:       "GET /v2/deliveries",
+			request:       func(r *http.Request) *http.Request { return withAPIKey(r, admin) },
+			expectedKey:   "apikey:1",
+			expectedLimit: unlimited,
+		},
+		{
+			name:          "Limite da rota tem precedência sobre o escopo",
+			pattern:       "POST /v2/deliveries",
+			request:       func(r *http.Request) *http.Request { return withAPIKey(r, admin) },
+			expectedKey:   "apikey:1|POST /v2/deliveries",
+			expectedLimit: perSecond,
+		},
+	}
+
+	limiter := NewRateLimiter(&MockRateLimitStore{}, options)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/v2/deliveries", nil)
+			r.Pattern = tt.pattern
+			r = r.WithContext(requestmeta.WithMetadata(r.Context(), requestmeta.Metadata{ClientIP: "10.0.0.1"}))
+
+			key, limit := limiter.resolve(tt.request(r))
+
+			assert.Equal(t, tt.expectedKey, key)
+			assert.Equal(t, tt.expectedLimit, limit)
+		})
+	}
+}
+
+func TestRateLimiter_Limit(t *testing.T) {
+	limit := ratelimit.Limit{Requests: 10, Period: time.Minute}
+
+	tests := []struct {
+		name            string
+		options         RateLimitOptions
+		store           *MockRateLimitStore
+		expectedStatus  int
+		expectedHeaders map[string]string
+		expectedTakes   int
+	}{
+		{
+			name:           "Requisição dentro do limite",
+			options:        RateLimitOptions{Default: limit},
+			store:          &MockRateLimitStore{},
+			expectedStatus: http.StatusOK,
+			expectedHeaders: map[string]string{
+				RateLimitLimitHeader:     "10",
+				RateLimitRemainingHeader: "9",
+				RateLimitResetHeader:     "1",
+				RateLimitPolicyHeader:    "10;w=60",
+				"Retry-After":            "",
+			},
+			expectedTakes: 1,
+		},
+		{
+			name:    "Requisição acima do limite",
+			options: RateLimitOptions{Default: limit},
+			store: &MockRateLimitStore{results: map[string]ratelimit.Result{
+				"ip:192.0.2.1": {Limit: limit, Remaining: 0, Reset: 59500 * time.Millisecond, RetryAfter: 5500 * time.Millisecond},
+			}},
+			expectedStatus: http.StatusTooManyRequests,
+			expectedHeaders: map[string]string{
+				RateLimitLimitHeader:     "10",
+				RateLimitRemainingHeader: "0",
+				RateLimitResetHeader:     "60",
+				"Retry-After":            "6",
+			},
+			expectedTakes: 1,
+		},
+		{
+			name:            "Rate limiting desabilitado",
+			options:         RateLimitOptions{},
+			store:           &MockRateLimitStore{},
+			expectedStatus:  http.StatusOK,
+			expectedHeaders: map[string]string{RateLimitLimitHeader: ""},
+			expectedTakes:   0,
+		},
+		{
+			name:            "Falha do armazenamento não bloqueia a requisição",
+			options:         RateLimitOptions{Default: limit},
+			store:           &MockRateLimitStore{err: errors.New("connection refused")},
+			expectedStatus:  http.StatusOK,
+			expectedHeaders: map[string]string{RateLimitLimitHeader: ""},
+			expectedTakes:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			handler := NewRateLimiter(tt.store, tt.options).Limit(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				w.WriteHeader(http.StatusOK)
+			})
+
+			r := httptest.NewRequest("GET", "/v2/deliveries", nil)
+			w := httptest.NewRecorder()
+			handler(w, r)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedStatus == http.StatusOK, called)
+			assert.Len(t, tt.store.keys, tt.expectedTakes)
+			for header, value := range tt.expectedHeaders {
+				assert.Equal(t, value, w.Header().Get(header), header)
+			}
+
+			if tt.expectedStatus == http.StatusTooManyRequests {
+				var response utils.Error
+				assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+				assert.Equal(t, http.StatusTooManyRequests, response.Status)
+				assert.Equal(t, "rate limit of 10 requests per 1m0s exceeded, retry in 6 seconds", response.Cause)
+			}
+		})
+	}
+}
+
+func TestRateLimiter_LimitUnauthenticated(t *testing.T) {
+	limit := ratelimit.Limit{Requests: 10, Period: time.Minute}
+
+	tests := []struct {
+		name           string
+		options        RateLimitOptions
+		store          *MockRateLimitStore
+		expectedStatus int
+		expectedKeys   []string
+	}{
+		{
+			name:           "Requisição dentro do limite",
+			options:        RateLimitOptions{Unauthenticated: limit},
+			store:          &MockRateLimitStore{},
+			expectedStatus: http.StatusOK,
+			expectedKeys:   []string{"unauthenticated|ip:192.0.2.1"},
+		},
+		{
+			name:    "Requisição acima do limite",
+			options: RateLimitOptions{Unauthenticated: limit},
+			store: &MockRateLimitStore{results: map[string]ratelimit.Result{
+				"unauthenticated|ip:192.0.2.1": {Limit: limit, RetryAfter: 5500 * time.Millisecond},
+			}},
+			expectedStatus: http.StatusTooManyRequests,
+			expectedKeys:   []string{"unauthenticated|ip:192.0.2.1"},
+		},
+		{
+			// O limite padrão é aplicado apenas após a autenticação
+			name:           "Limite desabilitado",
+			options:        RateLimitOptions{Default: limit},
+			store:          &MockRateLimitStore{},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			handler := NewRateLimiter(tt.store, tt.options).LimitUnauthenticated(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				w.WriteHeader(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			handler(w, httptest.NewRequest("GET", "/v2/deliveries", nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedStatus == http.StatusOK, called)
+			assert.Equal(t, tt.expectedKeys, tt.store.keys)
+			if tt.expectedStatus == http.StatusTooManyRequests {
+				assert.Equal(t, "6", w.Header().Get("Retry-After"))
+			}
+		})
+	}
+}
+
+func TestRateLimiter_Take(t *testing.T) {
+	limit := ratelimit.Limit{Requests: 10, Period: time.Minute}
+	store := &MockRateLimitStore{}
+	limiter := NewRateLimiter(store, RateLimitOptions{
+		Default:         limit,
+		Routes:          map[string]ratelimit.Limit{"/delivery.v1.DeliveryService/CreateDelivery": {Requests: 1, Period: time.Second}},
+		Unauthenticated: limit,
+	})
+
+	ctx := requestmeta.WithMetadata(context.Background(), requestmeta.Metadata{ClientIP: "10.0.0.1"})
+
+	result, ok := limiter.TakeUnauthenticated(ctx)
+	assert.True(t, ok)
+	assert.True(t, result.Allowed)
+
+	ctx = auth.WithPrincipal(ctx, &auth.Principal{Type: auth.PrincipalAPIKey, Subject: "apikey:1"})
+	_, ok = limiter.Take(ctx, "/delivery.v1.DeliveryService/GetDelivery")
+	assert.True(t, ok)
+	_, ok = limiter.Take(ctx, "/delivery.v1.DeliveryService/CreateDelivery")
+	assert.True(t, ok)
+
+	assert.Equal(t, []string{
+		"unauthenticated|ip:10.0.0.1",
+		"apikey:1",
+		"apikey:1|/delivery.v1.DeliveryService/CreateDelivery",
+	}, store.keys)
+
+	// Limite desabilitado ou falha do armazenamento liberam a chamada
+	_, ok = NewRateLimiter(store, RateLimitOptions{}).Take(ctx, "")
+	assert.False(t, ok)
+	_, ok = NewRateLimiter(&MockRateLimitStore{err: errors.New("connection refused")}, RateLimitOptions{Default: limit}).Take(ctx, "")
+	assert.False(t, ok)
+}
+
+func TestRateLimiter_MemoryStore(t *testing.T) {
+	limiter := NewRateLimiter(ratelimit.NewMemoryStore(), RateLimitOptions{
+		Default: ratelimit.Limit{Requests: 2, Period: time.Hour},
+	})
+	handler := limiter.Limit(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	statuses := make([]int, 0, 3)
+	for i := 0; i < 3; i++ {
+		w := httptest.NewRecorder()
+		handler(w, httptest.NewRequest("GET", "/v2/deliveries", nil))
+		statuses = append(statuses, w.Code)
+	}
+	assert.Equal(t, []int{http.StatusNoContent, http.StatusNoContent, http.StatusTooManyRequests}, statuses)
+
+	// Outro cliente possui o próprio balde
+	r := httptest.NewRequest("GET", "/v2/deliveries", nil)
+	r.RemoteAddr = "198.51.100.7:1234"
+	w := httptest.NewRecorder()
+	handler(w, r)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/samluiz/delivery-service/internal/requestmeta"
+)
+
+// Header utilizado para propagar o identificador da requisição.
+const RequestIDHeader = "X-Request-ID"
+
+// Tamanho máximo aceito para identificadores de requisição enviados pelo cliente.
+const maxRequestIDLength = 64
+
+// Função responsável por identificar a requisição e o cliente que a originou.
+// O X-Request-ID recebido é reaproveitado quando válido, caso contrário um novo é gerado,
+// e ele é sempre devolvido na resposta. O header X-Forwarded-For só é considerado
+// quando trustProxy estiver habilitado, pois pode ser forjado pelo cliente.
+func RequestMetadata(trustProxy bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestID := r.Header.Get(RequestIDHeader)
+			if !ValidRequestID(requestID) {
+				requestID = NewRequestID()
+			}
+
+			w.Header().Set(RequestIDHeader, requestID)
+
+			ctx := requestmeta.WithMetadata(r.Context(), requestmeta.Metadata{
+				RequestID: requestID,
+				ClientIP:  clientIP(r, trustProxy),
+			})
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// Função responsável por buscar o IP do cliente.
+func clientIP(r *http.Request, trustProxy bool) string {
+	if trustProxy {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			first, _, _ := strings.Cut(forwarded, ",")
+			return strings.TrimSpace(first)
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// Função responsável por validar o identificador de requisição enviado pelo cliente.
+// São aceitos apenas caracteres alfanuméricos, hífen e underscore.
+func ValidRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+
+	for _, c := range requestID {
+		isAlphanumeric := (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+		if !isAlphanumeric && c != '-' && c != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+// Função responsável por gerar um novo identificador de requisição aleatório.
+func NewRequestID() string {
+	bytes := make([]byte, 16)
+	rand.Read(bytes)
+	return hex.EncodeToString(bytes)
+}
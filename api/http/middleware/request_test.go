@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/samluiz/delivery-service/internal/requestmeta"
+	"github.com/stretchr/testify/assert"
+)
+
+// Testes da identificação da requisição e do cliente
+
+func TestRequestMetadata(t *testing.T) {
+	tests := []struct {
+		name              string
+		trustProxy        bool
+		requestID         string
+		forwardedFor      string
+		expectedRequestID string
+		expectedIP        string
+	}{
+		{
+			name:              "Request ID do cliente reaproveitado",
+			requestID:         "abc-123",
+			expectedRequestID: "abc-123",
+			expectedIP:        "192.0.2.1",
+		},
+		{
+			name:       "Request ID inválido substituído",
+			requestID:  "abc 123\n",
+			expectedIP: "192.0.2.1",
+		},
+		{
+			name:         "X-Forwarded-For ignorado sem proxy confiável",
+			forwardedFor: "203.0.113.7",
+			expectedIP:   "192.0.2.1",
+		},
+		{
+			name:         "X-Forwarded-For com proxy confiável",
+			trustProxy:   true,
+			forwardedFor: "203.0.113.7, 10.0.0.1",
+			expectedIP:   "203.0.113.7",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var metadata requestmeta.Metadata
+
+			handler := RequestMetadata(tt.trustProxy)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				metadata, _ = requestmeta.FromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest("GET", "/deliveries", nil)
+			if tt.requestID != "" {
+				req.Header.Set(RequestIDHeader, tt.requestID)
+			}
+			if tt.forwardedFor != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwardedFor)
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			if tt.expectedRequestID != "" {
+				assert.Equal(t, tt.expectedRequestID, metadata.RequestID)
+			} else {
+				assert.Len(t, metadata.RequestID, 32)
+			}
+			assert.Equal(t, metadata.RequestID, w.Header().Get(RequestIDHeader))
+			assert.Equal(t, tt.expectedIP, metadata.ClientIP)
+		})
+	}
+}
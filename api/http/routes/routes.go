@@ -0,0 +1,119 @@
+// Pacote com a tabela de rotas da API, compartilhada entre o main e o teste que compara as rotas com o documento OpenAPI.
+package routes
+
+import (
+	"net/http"
+
+	"github.com/samluiz/delivery-service/api/http/handlers"
+)
+
+// Handlers registrados nas rotas
+type Handlers struct {
+	Delivery   *handlers.DeliveryHandler // v1
+	DeliveryV2 *handlers.DeliveryHandler
+	Audit      *handlers.AuditHandler
+	APIKey     *handlers.APIKeyHandler
+	Health     *handlers.HealthHandler
+	Docs       *handlers.DocsHandler
+	GraphQL    *handlers.GraphQLHandler
+}
+
+// Políticas de acesso aplicadas às rotas autenticadas
+type Policies struct {
+	Read   func(http.HandlerFunc) http.HandlerFunc
+	Create func(http.HandlerFunc) http.HandlerFunc
+	Write  func(http.HandlerFunc) http.HandlerFunc
+	Admin  func(http.HandlerFunc) http.HandlerFunc
+
+	// Apenas autenticação, para rotas que autorizam cada operação no handler (GraphQL)
+	Authenticated func(http.HandlerFunc) http.HandlerFunc
+
+	// Validação das requisições contra o documento OpenAPI, aplicada após a autenticação
+	Contract func(http.HandlerFunc) http.HandlerFunc
+
+	// Headers de descontinuação das rotas da v1
+	Deprecated func(http.HandlerFunc) http.HandlerFunc
+
+	// Negociação do formato das respostas de entregas (406 quando o header Accept não aceita nenhum formato)
+	Negotiate func(http.HandlerFunc) http.HandlerFunc
+
+	// Descompressão dos corpos enviados com Content-Encoding, aplicada às rotas que recebem corpo
+	Decompress func(http.HandlerFunc) http.HandlerFunc
+
+	// Limite de requisições por cliente, aplicado após a autenticação para identificar o cliente pela credencial
+	RateLimit func(http.HandlerFunc) http.HandlerFunc
+
+	// Limite de requisições por IP, aplicado antes da autenticação para contabilizar as credenciais ausentes ou inválidas
+	RateLimitUnauthenticated func(http.HandlerFunc) http.HandlerFunc
+
+	// Limite de concorrência com fila, aplicado antes da autenticação, que também consulta o banco
+	Concurrency func(http.HandlerFunc) http.HandlerFunc
+}
+
+// Rota no formato do http.ServeMux (ex: "GET /deliveries/{id}")
+type Route struct {
+	Pattern string
+	Handler http.HandlerFunc
+}
+
+// Função responsável por montar a tabela de rotas da API.
+// Toda rota adicionada aqui precisa estar documentada em api/docs/openapi.json.
+func Routes(h Handlers, p Policies) []Route {
+	c := p.Contract
+	l := p.RateLimit
+	u := p.RateLimitUnauthenticated
+	s := p.Concurrency
+
+	routes := deliveryRoutes("/v1", h.Delivery, p, p.Deprecated)
+	routes = append(routes, deliveryRoutes("/v2", h.DeliveryV2, p, func(next http.HandlerFunc) http.HandlerFunc { return next })...)
+
+	return append(routes, []Route{
+		{"GET /audit", u(s(p.Admin(l(c(h.Audit.HandleGetAuditEntries)))))},
+
+		{"POST /admin/api-keys", u(s(p.Admin(l(c(h.APIKey.HandleCreateAPIKey)))))},
+		{"GET /admin/api-keys", u(s(p.Admin(l(c(h.APIKey.HandleGetAPIKeys)))))},
+		{"DELETE /admin/api-keys/{id}", u(s(p.Admin(l(c(h.APIKey.HandleRevokeAPIKey)))))},
+
+		{"POST /graphql", u(s(p.Authenticated(l(c(h.GraphQL.HandleGraphQL)))))},
+
+		{"GET /health/live", h.Health.HandleLiveness},
+		{"GET /health/ready", h.Health.HandleReadiness},
+
+		{"GET /openapi.json", h.Docs.HandleOpenAPI},
+		{"GET /docs", h.Docs.HandleDocs},
+	}...)
+}
+
+// Função responsável por montar as rotas de entregas de uma versão da API.
+// O middleware da versão é aplicado antes da autenticação, para que também alcance as respostas de erro,
+// e a negociação do formato antes da autenticação, para que o 406 seja retornado sem consultar as credenciais.
+// O limite de concorrência fica antes da autenticação, para que a consulta das API keys também aguarde uma vaga,
+// e o limite por IP antes dele, para que as credenciais inválidas sejam recusadas sem ocupar uma vaga.
+func deliveryRoutes(prefix string, h *handlers.DeliveryHandler, p Policies, version func(http.HandlerFunc) http.HandlerFunc) []Route {
+	c := p.Contract
+	n := p.Negotiate
+	d := p.Decompress
+	l := p.RateLimit
+	u := p.RateLimitUnauthenticated
+	s := p.Concurrency
+
+	return []Route{
+		{"POST " + prefix + "/deliveries", version(n(u(s(p.Create(l(d(c(h.HandleCreateDelivery))))))))},
+		{"GET " + prefix + "/deliveries", version(n(u(s(p.Read(l(c(h.HandleGetDeliveries)))))))},
+		{"GET " + prefix + "/deliveries/{id}", version(n(u(s(p.Read(l(c(h.HandleGetDelivery)))))))},
+		{"PUT " + prefix + "/deliveries/{id}", version(n(u(s(p.Write(l(d(c(h.HandleUpdateDelivery))))))))},
+		{"DELETE " + prefix + "/deliveries/{id}", version(n(u(s(p.Write(l(c(h.HandleDeleteDelivery)))))))},
+		{"GET " + prefix + "/deliveries/trash", version(n(u(s(p.Write(l(c(h.HandleGetDeletedDeliveries)))))))},
+		{"POST " + prefix + "/deliveries/{id}/restore", version(n(u(s(p.Write(l(c(h.HandleRestoreDelivery)))))))},
+		{"GET " + prefix + "/deliveries/{id}/versions", version(n(u(s(p.Write(l(c(h.HandleGetDeliveryVersions)))))))},
+		{"POST " + prefix + "/deliveries/{id}/versions/{version}/revert", version(n(u(s(p.Write(l(c(h.HandleRevertDelivery)))))))},
+		{"DELETE " + prefix + "/deliveries", version(n(u(s(p.Admin(l(c(h.HandleDeleteDeliveries)))))))},
+	}
+}
+
+// Função responsável por registrar as rotas no router.
+func Register(mux *http.ServeMux, routes []Route) {
+	for _, route := range routes {
+		mux.HandleFunc(route.Pattern, route.Handler)
+	}
+}
@@ -0,0 +1,314 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/samluiz/delivery-service/api/docs"
+	"github.com/samluiz/delivery-service/api/graphql/server"
+	"github.com/samluiz/delivery-service/api/http/handlers"
+	"github.com/samluiz/delivery-service/api/http/utils"
+	"github.com/samluiz/delivery-service/internal/apikey"
+	"github.com/samluiz/delivery-service/internal/audit"
+	"github.com/samluiz/delivery-service/internal/delivery"
+	"github.com/samluiz/delivery-service/internal/health"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Subconjunto do documento OpenAPI usado na comparação com o código
+
+type spec struct {
+	Paths      map[string]map[string]json.RawMessage `json:"paths"`
+	Components struct {
+		Schemas map[string]*schema `json:"schemas"`
+	} `json:"components"`
+}
+
+type schema struct {
+	Ref        string             `json:"$ref"`
+	Type       string             `json:"type"`
+	Format     string             `json:"format"`
+	Properties map[string]*schema `json:"properties"`
+	Required   []string           `json:"required"`
+	Items      *schema            `json:"items"`
+}
+
+// Schemas documentados e os tipos do Go que eles descrevem.
+// Nos requests os campos obrigatórios são os com validate:"required";
+// nas respostas, os campos sem omitempty.
+var schemaTypes = map[string]struct {
+	Type    reflect.Type
+	Request bool
+}{
+	"CreateDeliveryRequest":     {reflect.TypeOf(delivery.CreateDeliveryRequest{}), true},
+	"UpdateDeliveryRequest":     {reflect.TypeOf(delivery.UpdateDeliveryRequest{}), true},
+	"DeliveryResponse":          {reflect.TypeOf(delivery.DeliveryResponse{}), false},
+	"DeleteDeliveriesPreview":   {reflect.TypeOf(delivery.DeleteDeliveriesPreview{}), false},
+	"DeleteDeliveriesResult":    {reflect.TypeOf(delivery.DeleteDeliveriesResult{}), false},
+	"DeliveryVersionResponse":   {reflect.TypeOf(delivery.DeliveryVersionResponse{}), false},
+	"AddressV2":                 {reflect.TypeOf(delivery.AddressV2{}), true},
+	"CoordinatesV2":             {reflect.TypeOf(delivery.CoordinatesV2{}), true},
+	"CreateDeliveryRequestV2":   {reflect.TypeOf(delivery.CreateDeliveryRequestV2{}), true},
+	"UpdateDeliveryRequestV2":   {reflect.TypeOf(delivery.UpdateDeliveryRequestV2{}), true},
+	"DeliveryResponseV2":        {reflect.TypeOf(delivery.DeliveryResponseV2{}), false},
+	"DeliveryVersionResponseV2": {reflect.TypeOf(delivery.DeliveryVersionResponseV2{}), false},
+	"DeleteDeliveriesPreviewV2": {reflect.TypeOf(delivery.DeleteDeliveriesPreviewV2{}), false},
+	"DeleteDeliveriesResultV2":  {reflect.TypeOf(delivery.DeleteDeliveriesResultV2{}), false},
+	"AuditChange":               {reflect.TypeOf(audit.Change{}), false},
+	"AuditEntryResponse":        {reflect.TypeOf(audit.EntryResponse{}), false},
+	"AuditEntriesPageResponse":  {reflect.TypeOf(audit.EntriesPageResponse{}), false},
+	"CreateAPIKeyRequest":       {reflect.TypeOf(apikey.CreateAPIKeyRequest{}), true},
+	"APIKeyResponse":            {reflect.TypeOf(apikey.APIKeyResponse{}), false},
+	"CreateAPIKeyResponse":      {reflect.TypeOf(apikey.CreateAPIKeyResponse{}), false},
+	"HealthCheckResult":         {reflect.TypeOf(health.CheckResult{}), false},
+	"HealthReport":              {reflect.TypeOf(health.Report{}), false},
+	"GraphQLRequest":            {reflect.TypeOf(server.Request{}), true},
+	"GraphQLResponse":           {reflect.TypeOf(graphql.Result{}), false},
+	"FieldError":                {reflect.TypeOf(utils.FieldError{}), false},
+	"Error":                     {reflect.TypeOf(utils.Error{}), false},
+	"Problem":                   {reflect.TypeOf(utils.Problem{}), false},
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func loadSpec(t *testing.T) *spec {
+	t.Helper()
+
+	var s spec
+	require.NoError(t, json.Unmarshal(docs.OpenAPI, &s))
+
+	return &s
+}
+
+func testRoutes() []Route {
+	identity := func(next http.HandlerFunc) http.HandlerFunc { return next }
+
+	return Routes(Handlers{
+		Delivery:   handlers.NewDeliveryHandler(nil, nil),
+		DeliveryV2: handlers.NewDeliveryHandlerV2(nil, nil),
+		Audit:      handlers.NewAuditHandler(nil),
+		APIKey:     handlers.NewAPIKeyHandler(nil, nil),
+		Health:     handlers.NewHealthHandler(nil),
+		Docs:       handlers.NewDocsHandler(),
+		GraphQL:    handlers.NewGraphQLHandler(nil, nil),
+	}, Policies{Read: identity, Create: identity, Write: identity, Admin: identity, Authenticated: identity, Contract: identity, Deprecated: identity, Negotiate: identity, Decompress: identity, RateLimit: identity, RateLimitUnauthenticated: identity, Concurrency: identity})
+}
+
+func TestRegister(t *testing.T) {
+	// O ServeMux entra em pânico com padrões inválidos ou conflitantes
+	assert.NotPanics(t, func() { Register(http.NewServeMux(), testRoutes()) })
+}
+
+func TestRoutes_RateLimitBeforeAuthentication(t *testing.T) {
+	identity := func(next http.HandlerFunc) http.HandlerFunc { return next }
+
+	var calls []string
+	record := func(name string, forward bool) func(http.HandlerFunc) http.HandlerFunc {
+		return func(next http.HandlerFunc) http.HandlerFunc {
+			return func(w http.ResponseWriter, r *http.Request) {
+				calls = append(calls, name)
+				if forward {
+					next(w, r)
+				}
+			}
+		}
+	}
+	// A autenticação recusa a requisição, como uma credencial inválida
+	authenticate := record("authenticate", false)
+
+	routes := Routes(Handlers{
+		Delivery:   handlers.NewDeliveryHandler(nil, nil),
+		DeliveryV2: handlers.NewDeliveryHandlerV2(nil, nil),
+		Audit:      handlers.NewAuditHandler(nil),
+		APIKey:     handlers.NewAPIKeyHandler(nil, nil),
+		Health:     handlers.NewHealthHandler(nil),
+		Docs:       handlers.NewDocsHandler(),
+		GraphQL:    handlers.NewGraphQLHandler(nil, nil),
+	}, Policies{Read: authenticate, Create: authenticate, Write: authenticate, Admin: authenticate, Authenticated: authenticate,
+		Contract: identity, Deprecated: identity, Negotiate: identity, Decompress: identity,
+		RateLimit: record("rate limit", true), RateLimitUnauthenticated: record("rate limit unauthenticated", true), Concurrency: identity})
+
+	for _, route := range routes {
+		if strings.Contains(route.Pattern, "/health/") || strings.Contains(route.Pattern, "/docs") || strings.Contains(route.Pattern, "/openapi.json") {
+			continue
+		}
+
+		calls = nil
+		route.Handler(nil, &http.Request{})
+
+		assert.Equal(t, []string{"rate limit unauthenticated", "authenticate"}, calls, route.Pattern)
+	}
+}
+
+func TestRoutesMatchOpenAPI(t *testing.T) {
+	s := loadSpec(t)
+
+	registered := map[string]bool{}
+	for _, route := range testRoutes() {
+		registered[route.Pattern] = true
+	}
+
+	documented := map[string]bool{}
+	for path, item := range s.Paths {
+		for method := range item {
+			if method == "parameters" {
+				continue
+			}
+			documented[strings.ToUpper(method)+" "+path] = true
+		}
+	}
+
+	for pattern := range registered {
+		assert.True(t, documented[pattern], "rota %q não está documentada em openapi.json", pattern)
+	}
+	for operation := range documented {
+		assert.True(t, registered[operation], "operação %q documentada em openapi.json não está registrada", operation)
+	}
+}
+
+func TestSchemasMatchStructs(t *testing.T) {
+	s := loadSpec(t)
+
+	for name, schema := range s.Components.Schemas {
+		if schema.Type == "object" {
+			_, ok := schemaTypes[name]
+			assert.True(t, ok, "schema %q não está associado a um tipo do Go", name)
+		}
+	}
+
+	for name, entry := range schemaTypes {
+		t.Run(name, func(t *testing.T) {
+			schema, ok := s.Components.Schemas[name]
+			require.True(t, ok, "schema %q não está documentado em openapi.json", name)
+
+			fields := jsonFields(entry.Type)
+
+			names := make([]string, 0, len(fields))
+			required := []string{}
+			for _, field := range fields {
+				names = append(names, field.Name)
+				if field.isRequired(entry.Request) {
+					required = append(required, field.Name)
+				}
+			}
+
+			properties := make([]string, 0, len(schema.Properties))
+			for property := range schema.Properties {
+				properties = append(properties, property)
+			}
+
+			assert.ElementsMatch(t, names, properties, "propriedades divergentes")
+			assert.ElementsMatch(t, required, schema.Required, "campos obrigatórios divergentes")
+
+			for _, field := range fields {
+				property, ok := schema.Properties[field.Name]
+				if !ok {
+					continue
+				}
+				assertType(t, s, field.Name, field.Type, property)
+			}
+		})
+	}
+}
+
+// Campo serializado em JSON de uma struct
+type jsonField struct {
+	Name      string
+	Type      reflect.Type
+	OmitEmpty bool
+	Validate  string
+}
+
+func (f jsonField) isRequired(request bool) bool {
+	if request {
+		return strings.Contains(","+f.Validate+",", ",required,")
+	}
+	return !f.OmitEmpty
+}
+
+// Função responsável por listar os campos JSON de uma struct, incluindo os das structs embutidas.
+func jsonFields(t reflect.Type) []jsonField {
+	fields := []jsonField{}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		if field.Anonymous {
+			fields = append(fields, jsonFields(field.Type)...)
+			continue
+		}
+
+		tag := field.Tag.Get("json")
+		if tag == "-" || !field.IsExported() {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = field.Name
+		}
+
+		fields = append(fields, jsonField{
+			Name:      name,
+			Type:      field.Type,
+			OmitEmpty: strings.Contains(options, "omitempty"),
+			Validate:  field.Tag.Get("validate"),
+		})
+	}
+
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Name < fields[j].Name })
+
+	return fields
+}
+
+// Função responsável por comparar o tipo de um campo do Go com o tipo documentado.
+func assertType(t *testing.T, s *spec, name string, goType reflect.Type, property *schema) {
+	t.Helper()
+
+	for property.Ref != "" {
+		referenced, ok := s.Components.Schemas[strings.TrimPrefix(property.Ref, "#/components/schemas/")]
+		if !assert.True(t, ok, "%s: referência %q inexistente", name, property.Ref) {
+			return
+		}
+		property = referenced
+	}
+
+	for goType.Kind() == reflect.Pointer {
+		goType = goType.Elem()
+	}
+
+	if goType == timeType {
+		assert.Equal(t, "string", property.Type, "%s: tipo divergente", name)
+		assert.Equal(t, "date-time", property.Format, "%s: formato divergente", name)
+		return
+	}
+
+	switch goType.Kind() {
+	case reflect.String:
+		assert.Equal(t, "string", property.Type, "%s: tipo divergente", name)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		assert.Equal(t, "integer", property.Type, "%s: tipo divergente", name)
+	case reflect.Float32, reflect.Float64:
+		assert.Equal(t, "number", property.Type, "%s: tipo divergente", name)
+	case reflect.Bool:
+		assert.Equal(t, "boolean", property.Type, "%s: tipo divergente", name)
+	case reflect.Slice:
+		if assert.Equal(t, "array", property.Type, "%s: tipo divergente", name) && assert.NotNil(t, property.Items, "%s: itens não documentados", name) {
+			assertType(t, s, name+"[]", goType.Elem(), property.Items)
+		}
+	case reflect.Struct, reflect.Map:
+		assert.Equal(t, "object", property.Type, "%s: tipo divergente", name)
+	case reflect.Interface:
+		// Campos do tipo any aceitam qualquer valor
+		assert.Empty(t, property.Type, "%s: tipo divergente", name)
+	default:
+		t.Errorf("%s: tipo %s não suportado na comparação", name, goType)
+	}
+}
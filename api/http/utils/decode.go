@@ -0,0 +1,115 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// Tamanho máximo padrão do corpo das requisições (1 MiB).
+const DefaultMaxBodyBytes int64 = 1 << 20
+
+// Tamanho máximo do corpo das requisições aceito pelo DecodeJSONBody.
+var maxBodyBytes = DefaultMaxBodyBytes
+
+// Função responsável por configurar o tamanho máximo do corpo das requisições.
+// Deve ser chamada na inicialização, antes do servidor receber requisições.
+func ConfigureMaxBodyBytes(limit int64) {
+	if limit > 0 {
+		maxBodyBytes = limit
+	}
+}
+
+// Função responsável por retornar o tamanho máximo configurado do corpo das requisições.
+func MaxBodyBytes() int64 {
+	return maxBodyBytes
+}
+
+// Função responsável por desserializar o corpo JSON da requisição de forma estrita.
+// Exige o Content-Type application/json, limita o tamanho do corpo, rejeita campos desconhecidos
+// e aceita apenas um documento JSON. Em caso de falha retorna o erro HTTP correspondente (415, 413 ou 400).
+func DecodeJSONBody(w http.ResponseWriter, r *http.Request, dst interface{}) *Error {
+	if !IsJSONContentType(r.Header.Get("Content-Type")) {
+		return NewUnsupportedMediaTypeError(errors.New("content type must be application/json"), r)
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxBodyBytes)
+
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(dst); err != nil {
+		return decodeError(err, r)
+	}
+
+	// Qualquer conteúdo após o primeiro documento invalida a requisição
+	if err := decoder.Decode(&struct{}{}); !errors.Is(err, io.EOF) {
+		var maxBytesError *http.MaxBytesError
+		if errors.As(err, &maxBytesError) {
+			return decodeError(err, r)
+		}
+		return NewBadRequestError(errors.New("request body must contain a single JSON document"), r)
+	}
+
+	return nil
+}
+
+// Função responsável por desserializar o corpo da requisição em JSON ou XML, de acordo com o Content-Type.
+// Corpos JSON seguem as regras do DecodeJSONBody. Corpos XML são convertidos pelos nomes das tags json do struct,
+// com as mesmas regras: campos desconhecidos são rejeitados e apenas um documento é aceito.
+func DecodeBody(w http.ResponseWriter, r *http.Request, dst interface{}) *Error {
+	contentType := r.Header.Get("Content-Type")
+
+	switch {
+	case IsJSONContentType(contentType):
+		return DecodeJSONBody(w, r, dst)
+	case IsXMLContentType(contentType):
+		return decodeXMLBody(w, r, dst)
+	}
+
+	return NewUnsupportedMediaTypeError(errors.New("content type must be application/json or application/xml"), r)
+}
+
+// Função responsável por converter o erro de desserialização para um erro HTTP com a causa específica.
+func decodeError(err error, r *http.Request) *Error {
+	var (
+		syntaxError        *json.SyntaxError
+		unmarshalTypeError *json.UnmarshalTypeError
+		maxBytesError      *http.MaxBytesError
+	)
+
+	switch {
+	case errors.As(err, &maxBytesError):
+		return NewPayloadTooLargeError(fmt.Errorf("request body must not exceed %d bytes", maxBytesError.Limit), r)
+	case errors.As(err, &syntaxError):
+		return NewBadRequestError(fmt.Errorf("request body contains malformed JSON at position %d", syntaxError.Offset), r)
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return NewBadRequestError(errors.New("request body contains malformed JSON"), r)
+	case errors.As(err, &unmarshalTypeError):
+		return NewBadRequestError(fmt.Errorf("request body contains an invalid value for field %q", unmarshalTypeError.Field), r)
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		// O encoding/json não possui um tipo para esse erro, apenas a mensagem
+		field := strings.TrimPrefix(err.Error(), "json: unknown field ")
+		return NewBadRequestError(fmt.Errorf("request body contains unknown field %s", field), r)
+	case errors.Is(err, io.EOF):
+		return NewBadRequestError(errors.New("request body must not be empty"), r)
+	}
+
+	return NewBadRequestError(err, r)
+}
+
+// Função responsável por verificar se o Content-Type é application/json (parâmetros como charset são aceitos).
+func IsJSONContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == "application/json"
+}
+
+// Função responsável por verificar se o Content-Type é application/xml ou text/xml (parâmetros como charset são aceitos).
+func IsXMLContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mediaType == "application/xml" || mediaType == "text/xml")
+}
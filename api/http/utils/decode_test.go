@@ -0,0 +1,170 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Struct utilizado para testar a desserialização do corpo das requisições
+type DecodedStruct struct {
+	Nome  string `json:"nome"`
+	Idade int    `json:"idade"`
+}
+
+// Testes da desserialização estrita do corpo das requisições
+
+func TestDecodeJSONBody(t *testing.T) {
+	tests := []struct {
+		name           string
+		contentType    string
+		body           string
+		expectedStatus int
+		expectedCause  string
+	}{
+		{name: "valid body", contentType: "application/json", body: `{"nome":"A","idade":1}`},
+		{name: "charset parameter", contentType: "application/json; charset=utf-8", body: `{"nome":"A"}`},
+		{name: "missing content type", body: `{"nome":"A"}`, expectedStatus: http.StatusUnsupportedMediaType, expectedCause: "content type must be application/json"},
+		{name: "wrong content type", contentType: "text/plain", body: `{"nome":"A"}`, expectedStatus: http.StatusUnsupportedMediaType, expectedCause: "content type must be application/json"},
+		{name: "empty body", contentType: "application/json", body: ``, expectedStatus: http.StatusBadRequest, expectedCause: "request body must not be empty"},
+		{name: "malformed JSON", contentType: "application/json", body: `{"nome":}`, expectedStatus: http.StatusBadRequest, expectedCause: "request body contains malformed JSON at position 9"},
+		{name: "truncated JSON", contentType: "application/json", body: `{"nome":"A"`, expectedStatus: http.StatusBadRequest, expectedCause: "request body contains malformed JSON"},
+		{name: "invalid type", contentType: "application/json", body: `{"idade":"um"}`, expectedStatus: http.StatusBadRequest, expectedCause: `request body contains an invalid value for field "idade"`},
+		{name: "unknown field", contentType: "application/json", body: `{"nome":"A","apelido":"B"}`, expectedStatus: http.StatusBadRequest, expectedCause: `request body contains unknown field "apelido"`},
+		{name: "multiple documents", contentType: "application/json", body: `{"nome":"A"}{"nome":"B"}`, expectedStatus: http.StatusBadRequest, expectedCause: "request body must contain a single JSON document"},
+		{name: "trailing garbage", contentType: "application/json", body: `{"nome":"A"} lixo`, expectedStatus: http.StatusBadRequest, expectedCause: "request body must contain a single JSON document"},
+		{name: "trailing whitespace", contentType: "application/json", body: "{\"nome\":\"A\"}\n"},
+		{name: "too large", contentType: "application/json", body: `{"nome":"` + strings.Repeat("a", 64) + `"}`, expectedStatus: http.StatusRequestEntityTooLarge, expectedCause: "request body must not exceed 32 bytes"},
+	}
+
+	ConfigureMaxBodyBytes(32)
+	defer ConfigureMaxBodyBytes(DefaultMaxBodyBytes)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/test", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				r.Header.Set("Content-Type", tt.contentType)
+			}
+
+			var dst DecodedStruct
+			err := DecodeJSONBody(httptest.NewRecorder(), r, &dst)
+
+			if tt.expectedStatus == 0 {
+				assert.Nil(t, err)
+				assert.Equal(t, "A", dst.Nome)
+				return
+			}
+
+			assert.NotNil(t, err)
+			assert.Equal(t, tt.expectedStatus, err.Status)
+			assert.Equal(t, tt.expectedCause, err.Cause)
+		})
+	}
+}
+
+// Struct utilizado para testar a desserialização de corpos XML com campos aninhados
+type DecodedNestedStruct struct {
+	Nome     string         `json:"nome"`
+	Ativo    bool           `json:"ativo"`
+	Peso     *float64       `json:"peso"`
+	Endereco DecodedStruct  `json:"endereco"`
+	Tags     []string       `json:"tags"`
+	Criado   time.Time      `json:"criado"`
+	Ignorado string         `json:"-"`
+	Extras   map[string]int `json:"extras"`
+}
+
+// Testes da desserialização do corpo das requisições pelo Content-Type (JSON ou XML)
+
+func TestDecodeBody(t *testing.T) {
+	tests := []struct {
+		name           string
+		contentType    string
+		body           string
+		expectedStatus int
+		expectedCause  string
+	}{
+		{name: "json", contentType: "application/json", body: `{"nome":"A"}`},
+		{name: "xml", contentType: "application/xml", body: `<request><nome>A</nome><idade>1</idade></request>`},
+		{name: "text xml com declaração", contentType: "text/xml; charset=utf-8", body: `<?xml version="1.0" encoding="UTF-8"?>` + "\n<request>\n  <nome>A</nome>\n</request>\n"},
+		{name: "content type não suportado", contentType: "text/plain", body: `<request/>`, expectedStatus: http.StatusUnsupportedMediaType, expectedCause: "content type must be application/json or application/xml"},
+		{name: "xml vazio", contentType: "application/xml", body: ``, expectedStatus: http.StatusBadRequest, expectedCause: "request body must not be empty"},
+		{name: "xml malformado", contentType: "application/xml", body: "<request>\n<nome>A</request>", expectedStatus: http.StatusBadRequest, expectedCause: "request body contains malformed XML at line 2"},
+		{name: "tipo inválido", contentType: "application/xml", body: `<request><idade>um</idade></request>`, expectedStatus: http.StatusBadRequest, expectedCause: `request body contains an invalid value for field "idade"`},
+		{name: "número que não cabe no tipo", contentType: "application/xml", body: `<request><idade>1.5</idade></request>`, expectedStatus: http.StatusBadRequest, expectedCause: `request body contains an invalid value for field "idade"`},
+		{name: "campo desconhecido", contentType: "application/xml", body: `<request><nome>A</nome><apelido>B</apelido></request>`, expectedStatus: http.StatusBadRequest, expectedCause: `request body contains unknown field "apelido"`},
+		{name: "mais de um documento", contentType: "application/xml", body: `<request><nome>A</nome></request><request/>`, expectedStatus: http.StatusBadRequest, expectedCause: "request body must contain a single XML document"},
+		{name: "conteúdo após o documento", contentType: "application/xml", body: `<request><nome>A</nome></request> lixo`, expectedStatus: http.StatusBadRequest, expectedCause: "request body must contain a single XML document"},
+		{name: "muito grande", contentType: "application/xml", body: `<request><nome>` + strings.Repeat("a", 200) + `</nome></request>`, expectedStatus: http.StatusRequestEntityTooLarge, expectedCause: "request body must not exceed 128 bytes"},
+	}
+
+	ConfigureMaxBodyBytes(128)
+	defer ConfigureMaxBodyBytes(DefaultMaxBodyBytes)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/test", strings.NewReader(tt.body))
+			r.Header.Set("Content-Type", tt.contentType)
+
+			var dst DecodedStruct
+			err := DecodeBody(httptest.NewRecorder(), r, &dst)
+
+			if tt.expectedStatus == 0 {
+				assert.Nil(t, err)
+				assert.Equal(t, "A", dst.Nome)
+				return
+			}
+
+			assert.NotNil(t, err)
+			assert.Equal(t, tt.expectedStatus, err.Status)
+			assert.Equal(t, tt.expectedCause, err.Cause)
+		})
+	}
+}
+
+func TestDecodeBody_NestedXML(t *testing.T) {
+	body := `<request>
+		<nome>A</nome>
+		<ativo>true</ativo>
+		<peso>0</peso>
+		<endereco><nome>Casa</nome><idade>3</idade></endereco>
+		<tags><item>a</item><item>b</item></tags>
+		<criado>2024-05-10T12:00:00Z</criado>
+		<extras><x>1</x></extras>
+	</request>`
+
+	r := httptest.NewRequest("POST", "/test", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/xml")
+
+	var dst DecodedNestedStruct
+	err := DecodeBody(httptest.NewRecorder(), r, &dst)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "A", dst.Nome)
+	assert.True(t, dst.Ativo)
+	assert.NotNil(t, dst.Peso)
+	assert.Equal(t, DecodedStruct{Nome: "Casa", Idade: 3}, dst.Endereco)
+	assert.Equal(t, []string{"a", "b"}, dst.Tags)
+	assert.Equal(t, time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC), dst.Criado)
+	assert.Equal(t, map[string]int{"x": 1}, dst.Extras)
+
+	// Campos aninhados são identificados pelo caminho e campos ignorados pelo JSON não são aceitos
+	for body, cause := range map[string]string{
+		`<request><endereco><idade>x</idade></endereco></request>`: `request body contains an invalid value for field "endereco.idade"`,
+		`<request><Ignorado>x</Ignorado></request>`:                `request body contains unknown field "Ignorado"`,
+		`<request><nome><a/></nome></request>`:                     `request body contains an invalid value for field "nome"`,
+	} {
+		r := httptest.NewRequest("POST", "/test", strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/xml")
+
+		err := DecodeBody(httptest.NewRecorder(), r, &DecodedNestedStruct{})
+
+		assert.NotNil(t, err)
+		assert.Equal(t, cause, err.Cause)
+	}
+}
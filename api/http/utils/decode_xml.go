@@ -0,0 +1,276 @@
+package utils
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Erro retornado quando há conteúdo após o elemento raiz do documento XML.
+var errMultipleXMLDocuments = errors.New("request body must contain a single XML document")
+
+var (
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// Elemento do documento XML da requisição. Atributos são ignorados.
+type xmlElement struct {
+	name     string
+	text     strings.Builder
+	children []*xmlElement
+}
+
+// Erro de campo desconhecido, com a mesma mensagem do DecodeJSONBody.
+type unknownFieldError struct {
+	field string
+}
+
+func (e *unknownFieldError) Error() string {
+	return fmt.Sprintf("request body contains unknown field %q", e.field)
+}
+
+// Função responsável por desserializar o corpo XML da requisição no struct informado.
+// O elemento raiz pode ter qualquer nome; os elementos filhos são associados aos campos pelo nome da tag json
+// e listas são representadas por elementos repetidos (ex: <item>). O documento é convertido para JSON, guiado
+// pelos tipos do struct, e desserializado pelo encoding/json, para que as regras e os erros sejam os mesmos do JSON.
+func decodeXMLBody(w http.ResponseWriter, r *http.Request, dst interface{}) *Error {
+	r.Body = http.MaxBytesReader(w, r.Body, maxBodyBytes)
+
+	root, err := readXMLDocument(xml.NewDecoder(r.Body))
+	if err != nil {
+		return xmlDecodeError(err, r)
+	}
+
+	value, err := xmlValue(root, reflect.TypeOf(dst), "")
+	if err != nil {
+		return xmlDecodeError(err, r)
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return NewBadRequestError(err, r)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(dst); err != nil {
+		return decodeError(err, r)
+	}
+
+	return nil
+}
+
+// Função responsável por converter o erro da leitura do XML para um erro HTTP com a causa específica.
+func xmlDecodeError(err error, r *http.Request) *Error {
+	var (
+		syntaxError  *xml.SyntaxError
+		unknownField *unknownFieldError
+	)
+
+	switch {
+	case errors.As(err, &syntaxError):
+		return NewBadRequestError(fmt.Errorf("request body contains malformed XML at line %d", syntaxError.Line), r)
+	case errors.As(err, &unknownField):
+		return NewBadRequestError(unknownField, r)
+	case errors.Is(err, errMultipleXMLDocuments):
+		return NewBadRequestError(err, r)
+	}
+
+	return decodeError(err, r)
+}
+
+// Função responsável por ler o documento XML, retornando o elemento raiz.
+// Comentários, instruções de processamento e declarações são ignorados.
+func readXMLDocument(decoder *xml.Decoder) (*xmlElement, error) {
+	var (
+		root  *xmlElement
+		stack []*xmlElement
+	)
+
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			if root == nil {
+				return nil, io.EOF
+			}
+			return root, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if root != nil && len(stack) == 0 {
+				return nil, errMultipleXMLDocuments
+			}
+
+			element := &xmlElement{name: t.Name.Local}
+			if root == nil {
+				root = element
+			} else {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, element)
+			}
+			stack = append(stack, element)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text.Write(t)
+			} else if len(bytes.TrimSpace(t)) > 0 {
+				return nil, errMultipleXMLDocuments
+			}
+		}
+	}
+}
+
+// Função responsável por converter o elemento para o valor JSON equivalente do tipo informado.
+// Retorna erros no formato do encoding/json, identificando o campo pelo caminho (ex: address.city).
+func xmlValue(element *xmlElement, t reflect.Type, path string) (interface{}, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	text := element.text.String()
+	typeError := &json.UnmarshalTypeError{Value: "string", Type: t, Field: path}
+
+	// Tipos com desserialização própria (ex: time.Time) recebem o texto do elemento
+	if reflect.PointerTo(t).Implements(jsonUnmarshalerType) || reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		if len(element.children) > 0 {
+			return nil, typeError
+		}
+		return text, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if strings.TrimSpace(text) != "" {
+			return nil, typeError
+		}
+
+		fields := jsonFields(t)
+		values := make(map[string]interface{}, len(element.children))
+
+		for _, child := range element.children {
+			field, ok := fields[child.name]
+			if !ok {
+				return nil, &unknownFieldError{field: child.name}
+			}
+
+			value, err := xmlValue(child, field, joinPath(path, child.name))
+			if err != nil {
+				return nil, err
+			}
+			values[child.name] = value
+		}
+		return values, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String || strings.TrimSpace(text) != "" {
+			return nil, typeError
+		}
+
+		values := make(map[string]interface{}, len(element.children))
+		for _, child := range element.children {
+			value, err := xmlValue(child, t.Elem(), joinPath(path, child.name))
+			if err != nil {
+				return nil, err
+			}
+			values[child.name] = value
+		}
+		return values, nil
+	case reflect.Slice, reflect.Array:
+		if strings.TrimSpace(text) != "" {
+			return nil, typeError
+		}
+
+		values := make([]interface{}, 0, len(element.children))
+		for _, child := range element.children {
+			value, err := xmlValue(child, t.Elem(), path)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		return values, nil
+	}
+
+	// Valores escalares não possuem elementos filhos
+	if len(element.children) > 0 {
+		return nil, typeError
+	}
+
+	switch t.Kind() {
+	case reflect.String, reflect.Interface:
+		return text, nil
+	case reflect.Bool:
+		value, err := strconv.ParseBool(strings.TrimSpace(text))
+		if err != nil {
+			return nil, typeError
+		}
+		return value, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		number := strings.TrimSpace(text)
+		if _, err := strconv.ParseFloat(number, 64); err != nil {
+			return nil, typeError
+		}
+		// O encoding/json valida se o número cabe no tipo do campo
+		return json.Number(number), nil
+	}
+
+	return nil, typeError
+}
+
+// Função responsável por mapear os nomes dos campos no JSON para os seus tipos, seguindo as regras do encoding/json:
+// a tag json define o nome, "-" ignora o campo e structs embutidos sem tag têm os campos promovidos.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		name, _, _ := strings.Cut(tag, ",")
+
+		if tag == "-" {
+			continue
+		}
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				for promoted, promotedType := range jsonFields(embedded) {
+					if _, ok := fields[promoted]; !ok {
+						fields[promoted] = promotedType
+					}
+				}
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field.Type
+	}
+
+	return fields
+}
@@ -2,94 +2,178 @@ package utils
 
 import (
 	"errors"
-	"fmt"
 	"net/http"
 	"strings"
 	"time"
 
-	"github.com/go-playground/validator/v10"
+	"github.com/samluiz/delivery-service/internal/requestmeta"
 )
 
-// Validator para validação de erros.
-var v = &XValidator{
-	Validator: validator.New(),
-}
-
 // Struct que representa um erro HTTP.
 type Error struct {
-	Status    int    `json:"status"`
-	Message   string `json:"message"`
-	Cause     string `json:"error"`
-	Timestamp string `json:"timestamp"`
-	Path      string `json:"path"`
+	Status    int          `json:"status"`
+	Message   string       `json:"message"`
+	Cause     string       `json:"error"`
+	Timestamp string       `json:"timestamp"`
+	Path      string       `json:"path"`
+	Fields    []FieldError `json:"campos,omitempty"`
+
+	Type      string   `json:"-"` // URI do tipo de problema (RFC 7807)
+	RequestID string   `json:"-"`
+	Language  Language `json:"-"` // Idioma das mensagens, escolhido pelo header Accept-Language
+	problem   bool     // Indica se o cliente prefere a resposta como application/problem+json
+	format    Format   // Formato da resposta, escolhido pelo header Accept
+}
+
+// Struct que representa a falha de validação de um campo do request body.
+type FieldError struct {
+	Campo     string `json:"campo"`
+	Regra     string `json:"regra"`
+	Parametro string `json:"parametro,omitempty"`
+	Mensagem  string `json:"mensagem"`
 }
 
 // Função responsável por validar um struct utilizando o validator.
-func ValidateBody(r *http.Request, body interface{}) *Error {
+// Em caso de erro, retorna as falhas de cada campo em Fields, identificados pelo nome no JSON.
+func (v *XValidator) ValidateBody(r *http.Request, body interface{}) *Error {
+
+	errs, err := v.Validate(body)
 
-	// Validando o struct e retornando os erros se houver
-	if errs := v.Validate(body); len(errs) > 0 && errs[0].Error {
+	// Corpos que não podem ser validados (ex: ponteiro nulo) são tratados como requisição inválida
+	if err != nil {
+		return NewBadRequestError(err, r)
+	}
+
+	// Retornando os erros de validação se houver
+	if len(errs) > 0 && errs[0].Error {
+		lang := LanguageFromRequest(r)
 		errMsgs := make([]string, 0)
 
 		// Iterando sobre os erros e adicionando a mensagem de erro para o usuário
 		for _, err := range errs {
-			errMsgs = append(errMsgs, fmt.Sprintf(
-				"[%s]: '%v' | Deve satisfazer a validação '%s'",
-				err.FailedField,
-				err.Value,
-				err.Tag,
-			))
+			errMsgs = append(errMsgs, translate(lang, msgValidationCause, err.Field, err.Value, err.Tag))
 		}
 
 		// Concatenando os erros para serem exibidos para o usuário
-		validationError := errors.New(strings.Join(errMsgs, " e "))
+		validationError := errors.New(strings.Join(errMsgs, translate(lang, msgValidationJoin)))
 
-		// Retornando o erro de validação
-		return NewBadRequestError(validationError, r)
+		return v.NewValidationError(validationError, errs, r)
 	}
 	return nil
 }
 
+// Função responsável por montar a mensagem do catálogo de uma regra, com o nome do campo e o parâmetro da regra.
+func ruleMessage(lang Language, tag string, field string, param string) string {
+	return translate(lang, "validation."+tag, field, param)
+}
+
+// Função responsável por criar um erro de validação (400), com a falha de cada campo em Fields.
+func (v *XValidator) NewValidationError(err error, errs []ValidationError, r *http.Request) *Error {
+	response := NewBadRequestError(err, r)
+	response.Type = ProblemTypeValidation
+	response.Fields = v.NewFieldErrors(response.Language, errs)
+
+	return response
+}
+
+// Função responsável por converter as falhas do validator para as falhas de cada campo, com as mensagens no idioma informado.
+func (v *XValidator) NewFieldErrors(lang Language, errs []ValidationError) []FieldError {
+	fields := make([]FieldError, 0, len(errs))
+
+	for _, err := range errs {
+		fields = append(fields, FieldError{
+			Campo:     err.Field,
+			Regra:     err.Tag,
+			Parametro: err.Param,
+			Mensagem:  v.message(lang, err),
+		})
+	}
+
+	return fields
+}
+
 // Função responsável por criar um erro HTTP.
 func NewError(status int, message string, cause string, r *http.Request) *Error {
-	return &Error{
+	e := newError(status, ProblemTypeBlank, "", cause, r)
+	e.Message = message
+	return e
+}
+
+// Função responsável por criar um erro HTTP do tipo de problema informado, com a mensagem no idioma da requisição.
+// Guarda os dados da requisição necessários para renderizar o erro como problem+json.
+func newError(status int, problemType string, messageKey string, cause string, r *http.Request) *Error {
+	lang := LanguageFromRequest(r)
+
+	e := &Error{
 		Status:    status,
-		Message:   message,
+		Message:   translate(lang, messageKey),
 		Cause:     cause,
 		Timestamp: time.Now().Format(time.RFC3339),
 		Path:      r.URL.Path,
+		Type:      problemType,
+		Language:  lang,
+		problem:   acceptsProblem(r.Header.Get("Accept")),
+		format:    formatFromAccept(r.Header.Get("Accept")),
+	}
+
+	if metadata, ok := requestmeta.FromContext(r.Context()); ok {
+		e.RequestID = metadata.RequestID
 	}
+
+	return e
 }
 
 // Função responsável por criar um erro de servidor interno.
 func NewInternalServerError(err error, r *http.Request) *Error {
-	return &Error{
-		Status:    http.StatusInternalServerError,
-		Message:   "Erro interno.",
-		Cause:     err.Error(),
-		Timestamp: time.Now().Format(time.RFC3339),
-		Path:      r.URL.Path,
-	}
+	return newError(http.StatusInternalServerError, ProblemTypeInternal, msgInternalError, err.Error(), r)
 }
 
 // Função responsável por criar um erro de recurso não encontrado.
 func NewNotFoundError(err error, r *http.Request) *Error {
-	return &Error{
-		Status:    http.StatusNotFound,
-		Message:   "O recurso não foi encontrado.",
-		Cause:     err.Error(),
-		Timestamp: time.Now().Format(time.RFC3339),
-		Path:      r.URL.Path,
-	}
+	return newError(http.StatusNotFound, ProblemTypeNotFound, msgNotFound, err.Error(), r)
 }
 
 // Função responsável por criar um erro de requisição inválida.
 func NewBadRequestError(err error, r *http.Request) *Error {
-	return &Error{
-		Status:    http.StatusBadRequest,
-		Message:   "Requisição inválida.",
-		Cause:     err.Error(),
-		Timestamp: time.Now().Format(time.RFC3339),
-		Path:      r.URL.Path,
-	}
+	return newError(http.StatusBadRequest, ProblemTypeBadRequest, msgBadRequest, err.Error(), r)
+}
+
+// Função responsável por criar um erro de requisição não autenticada.
+func NewUnauthorizedError(err error, r *http.Request) *Error {
+	return newError(http.StatusUnauthorized, ProblemTypeUnauthorized, msgUnauthorized, err.Error(), r)
+}
+
+// Função responsável por criar um erro de acesso negado.
+func NewForbiddenError(err error, r *http.Request) *Error {
+	return newError(http.StatusForbidden, ProblemTypeForbidden, msgForbidden, err.Error(), r)
+}
+
+// Função responsável por criar um erro de conflito com o estado atual do recurso.
+func NewConflictError(err error, r *http.Request) *Error {
+	return newError(http.StatusConflict, ProblemTypeConflict, msgConflict, err.Error(), r)
+}
+
+// Função responsável por criar um erro de corpo da requisição acima do tamanho máximo.
+func NewPayloadTooLargeError(err error, r *http.Request) *Error {
+	return newError(http.StatusRequestEntityTooLarge, ProblemTypePayloadTooLarge, msgPayloadTooLarge, err.Error(), r)
+}
+
+// Função responsável por criar um erro de Content-Type não suportado.
+func NewUnsupportedMediaTypeError(err error, r *http.Request) *Error {
+	return newError(http.StatusUnsupportedMediaType, ProblemTypeUnsupportedMediaType, msgUnsupportedMediaType, err.Error(), r)
+}
+
+// Função responsável por criar um erro de formato de resposta não suportado (406).
+func NewNotAcceptableError(err error, r *http.Request) *Error {
+	return newError(http.StatusNotAcceptable, ProblemTypeNotAcceptable, msgNotAcceptable, err.Error(), r)
+}
+
+// Função responsável por criar um erro de limite de requisições excedido (429).
+func NewTooManyRequestsError(err error, r *http.Request) *Error {
+	return newError(http.StatusTooManyRequests, ProblemTypeTooManyRequests, msgTooManyRequests, err.Error(), r)
+}
+
+// Função responsável por criar um erro de serviço indisponível (503), utilizado quando a carga é descartada.
+func NewServiceUnavailableError(err error, r *http.Request) *Error {
+	return newError(http.StatusServiceUnavailable, ProblemTypeServiceUnavailable, msgServiceUnavailable, err.Error(), r)
 }
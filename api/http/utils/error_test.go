@@ -27,7 +27,7 @@ func TestValidateBody_ValidBody(t *testing.T) {
 		Email: "john.doe@example.com",
 	}
 
-	err := ValidateBody(r, body)
+	err := newValidator(t).ValidateBody(r, body)
 	assert.Nil(t, err)
 }
 
@@ -39,7 +39,7 @@ func TestValidateBody_InvalidBody(t *testing.T) {
 		Email: "invalid-email",
 	}
 
-	err := ValidateBody(r, body)
+	err := newValidator(t).ValidateBody(r, body)
 	assert.NotNil(t, err)
 	assert.Equal(t, http.StatusBadRequest, err.Status)
 	assert.Contains(t, err.Cause, "Name")
@@ -47,6 +47,31 @@ func TestValidateBody_InvalidBody(t *testing.T) {
 	assert.Contains(t, err.Cause, "Email")
 }
 
+func TestValidateBody_FieldErrors(t *testing.T) {
+	r := httptest.NewRequest("POST", "/test", nil)
+	body := struct {
+		Nome  string `json:"nome" validate:"required"`
+		Idade int    `json:"idade" validate:"gte=18"`
+	}{Idade: 16}
+
+	err := newValidator(t).ValidateBody(r, body)
+	assert.NotNil(t, err)
+	assert.Equal(t, []FieldError{
+		{Campo: "nome", Regra: "required", Mensagem: "nome é um campo obrigatório"},
+		{Campo: "idade", Regra: "gte", Parametro: "18", Mensagem: "idade deve ser 18 ou superior"},
+	}, err.Fields)
+}
+
+func TestValidateBody_NilBody(t *testing.T) {
+	r := httptest.NewRequest("POST", "/test", nil)
+
+	var body *SampleStruct
+
+	err := newValidator(t).ValidateBody(r, body)
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusBadRequest, err.Status)
+}
+
 func TestNewError(t *testing.T) {
 	r := httptest.NewRequest("GET", "/test", nil)
 	err := NewError(http.StatusForbidden, "Access denied", "Forbidden access", r)
@@ -94,3 +119,23 @@ func TestNewBadRequestError(t *testing.T) {
 	_, parseErr := time.Parse(time.RFC3339, err.Timestamp)
 	assert.Nil(t, parseErr)
 }
+
+func TestNewUnauthorizedError(t *testing.T) {
+	r := httptest.NewRequest("GET", "/test", nil)
+	err := NewUnauthorizedError(errors.New("unauthorized"), r)
+
+	assert.Equal(t, http.StatusUnauthorized, err.Status)
+	assert.Equal(t, "Não autenticado.", err.Message)
+	assert.Equal(t, "unauthorized", err.Cause)
+	assert.Equal(t, "/test", err.Path)
+}
+
+func TestNewForbiddenError(t *testing.T) {
+	r := httptest.NewRequest("GET", "/test", nil)
+	err := NewForbiddenError(errors.New("forbidden"), r)
+
+	assert.Equal(t, http.StatusForbidden, err.Status)
+	assert.Equal(t, "Acesso negado.", err.Message)
+	assert.Equal(t, "forbidden", err.Cause)
+	assert.Equal(t, "/test", err.Path)
+}
@@ -0,0 +1,179 @@
+package utils
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Idioma das mensagens retornadas pela API.
+type Language string
+
+const (
+	LanguagePortuguese Language = "pt-BR"
+	LanguageEnglish    Language = "en"
+	LanguageSpanish    Language = "es"
+
+	DefaultLanguage = LanguagePortuguese
+)
+
+// Chaves das mensagens do catálogo.
+const (
+	msgInternalError        = "error.internal"
+	msgNotFound             = "error.not_found"
+	msgBadRequest           = "error.bad_request"
+	msgUnauthorized         = "error.unauthorized"
+	msgForbidden            = "error.forbidden"
+	msgConflict             = "error.conflict"
+	msgPayloadTooLarge      = "error.payload_too_large"
+	msgUnsupportedMediaType = "error.unsupported_media_type"
+	msgNotAcceptable        = "error.not_acceptable"
+	msgTooManyRequests      = "error.too_many_requests"
+	msgServiceUnavailable   = "error.service_unavailable"
+	msgValidationCause      = "validation.cause"
+	msgValidationJoin       = "validation.join"
+	msgValidationDefault    = "validation.default"
+	msgValidationWithArgs   = "validation.default_param"
+)
+
+// Catálogo de mensagens por idioma. As mensagens de validação, indexadas pela tag, cobrem apenas as regras de domínio
+// e as do contrato OpenAPI; as demais regras usam as traduções do validator. Assim como nas traduções do validator,
+// as mensagens de validação recebem o nome do campo e o parâmetro da regra.
+var messages = map[Language]map[string]string{
+	LanguagePortuguese: {
+		msgInternalError:        "Erro interno.",
+		msgNotFound:             "O recurso não foi encontrado.",
+		msgBadRequest:           "Requisição inválida.",
+		msgUnauthorized:         "Não autenticado.",
+		msgForbidden:            "Acesso negado.",
+		msgConflict:             "Conflito com o estado atual do recurso.",
+		msgPayloadTooLarge:      "Corpo da requisição muito grande.",
+		msgUnsupportedMediaType: "Tipo de conteúdo não suportado.",
+		msgNotAcceptable:        "Nenhum dos formatos aceitos pelo cliente é suportado.",
+		msgTooManyRequests:      "Limite de requisições excedido. Tente novamente mais tarde.",
+		msgServiceUnavailable:   "Serviço sobrecarregado. Tente novamente mais tarde.",
+		msgValidationCause:      "[%s]: '%v' | Deve satisfazer a validação '%s'",
+		msgValidationJoin:       " e ",
+		msgValidationDefault:    "%[1]s deve satisfazer a validação '%[2]s'",
+		msgValidationWithArgs:   "%[1]s deve satisfazer a validação '%[2]s=%[3]s'",
+		"validation.peso":       "%[1]s deve ser maior que 0 e menor ou igual a %[2]s",
+		"validation.uf":         "%[1]s deve ser a sigla de uma UF brasileira (ex: SP)",
+		"validation.pais":       "%[1]s deve ser um código de país ISO 3166-1 (ex: BR) ou Brasil",
+		"validation.type":       "%[1]s deve ser do tipo %[2]s",
+		"validation.format":     "%[1]s deve estar no formato %[2]s",
+		"validation.unknown":    "%[1]s não é um campo permitido",
+	},
+	LanguageEnglish: {
+		msgInternalError:        "Internal error.",
+		msgNotFound:             "The resource was not found.",
+		msgBadRequest:           "Invalid request.",
+		msgUnauthorized:         "Not authenticated.",
+		msgForbidden:            "Access denied.",
+		msgConflict:             "Conflict with the current state of the resource.",
+		msgPayloadTooLarge:      "Request body too large.",
+		msgUnsupportedMediaType: "Unsupported media type.",
+		msgNotAcceptable:        "None of the formats accepted by the client is supported.",
+		msgTooManyRequests:      "Rate limit exceeded. Try again later.",
+		msgServiceUnavailable:   "Service overloaded. Try again later.",
+		msgValidationCause:      "[%s]: '%v' | Must satisfy the '%s' validation",
+		msgValidationJoin:       " and ",
+		msgValidationDefault:    "%[1]s must satisfy the '%[2]s' validation",
+		msgValidationWithArgs:   "%[1]s must satisfy the '%[2]s=%[3]s' validation",
+		"validation.peso":       "%[1]s must be greater than 0 and less than or equal to %[2]s",
+		"validation.uf":         "%[1]s must be a Brazilian state code (e.g. SP)",
+		"validation.pais":       "%[1]s must be an ISO 3166-1 country code (e.g. BR) or Brasil",
+		"validation.type":       "%[1]s must be of type %[2]s",
+		"validation.format":     "%[1]s must be in the %[2]s format",
+		"validation.unknown":    "%[1]s is not an allowed field",
+	},
+	LanguageSpanish: {
+		msgInternalError:        "Error interno.",
+		msgNotFound:             "No se encontró el recurso.",
+		msgBadRequest:           "Solicitud inválida.",
+		msgUnauthorized:         "No autenticado.",
+		msgForbidden:            "Acceso denegado.",
+		msgConflict:             "Conflicto con el estado actual del recurso.",
+		msgPayloadTooLarge:      "Cuerpo de la solicitud demasiado grande.",
+		msgUnsupportedMediaType: "Tipo de contenido no soportado.",
+		msgNotAcceptable:        "Ninguno de los formatos aceptados por el cliente es compatible.",
+		msgTooManyRequests:      "Límite de solicitudes excedido. Inténtelo de nuevo más tarde.",
+		msgServiceUnavailable:   "Servicio sobrecargado. Inténtelo de nuevo más tarde.",
+		msgValidationCause:      "[%s]: '%v' | Debe cumplir la validación '%s'",
+		msgValidationJoin:       " y ",
+		msgValidationDefault:    "%[1]s debe cumplir la validación '%[2]s'",
+		msgValidationWithArgs:   "%[1]s debe cumplir la validación '%[2]s=%[3]s'",
+		"validation.peso":       "%[1]s debe ser mayor que 0 y menor o igual a %[2]s",
+		"validation.uf":         "%[1]s debe ser la sigla de un estado brasileño (ej: SP)",
+		"validation.pais":       "%[1]s debe ser un código de país ISO 3166-1 (ej: BR) o Brasil",
+		"validation.type":       "%[1]s debe ser del tipo %[2]s",
+		"validation.format":     "%[1]s debe estar en el formato %[2]s",
+		"validation.unknown":    "%[1]s no es un campo permitido",
+	},
+}
+
+// Função responsável por buscar a mensagem no catálogo do idioma, usando o idioma padrão quando ela não existir.
+func translate(lang Language, key string, args ...any) string {
+	message, ok := messages[lang][key]
+	if !ok {
+		message = messages[DefaultLanguage][key]
+	}
+
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// Função responsável por verificar se existe mensagem para a chave no catálogo do idioma padrão.
+func hasMessage(key string) bool {
+	_, ok := messages[DefaultLanguage][key]
+	return ok
+}
+
+// Função responsável por escolher o idioma da resposta pelo header Accept-Language.
+func LanguageFromRequest(r *http.Request) Language {
+	return LanguageFromHeader(r.Header.Get("Accept-Language"))
+}
+
+// Função responsável por escolher o idioma pelo valor de um header Accept-Language.
+// É escolhido o idioma suportado de maior prioridade, comparando apenas o idioma principal (ex: en-US → en).
+func LanguageFromHeader(acceptLanguage string) Language {
+	best, bestQ := DefaultLanguage, 0.0
+
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+
+		lang, ok := supportedLanguage(tag)
+		if ok && q > bestQ {
+			best, bestQ = lang, q
+		}
+	}
+
+	return best
+}
+
+// Função responsável por converter uma tag de idioma para um dos idiomas suportados.
+func supportedLanguage(tag string) (Language, bool) {
+	primary, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+
+	switch primary {
+	case "pt":
+		return LanguagePortuguese, true
+	case "en":
+		return LanguageEnglish, true
+	case "es":
+		return LanguageSpanish, true
+	}
+
+	return "", false
+}
@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Testes da escolha do idioma e do catálogo de mensagens
+
+func TestLanguageFromRequest(t *testing.T) {
+	tests := []struct {
+		acceptLanguage string
+		expected       Language
+	}{
+		{acceptLanguage: "", expected: LanguagePortuguese},
+		{acceptLanguage: "*", expected: LanguagePortuguese},
+		{acceptLanguage: "fr-FR", expected: LanguagePortuguese},
+		{acceptLanguage: "en-US", expected: LanguageEnglish},
+		{acceptLanguage: "es-419,es;q=0.9", expected: LanguageSpanish},
+		{acceptLanguage: "pt-PT", expected: LanguagePortuguese},
+		{acceptLanguage: "fr-FR, en;q=0.8, es;q=0.9", expected: LanguageSpanish},
+		{acceptLanguage: "en;q=0.5, pt-BR;q=0.7", expected: LanguagePortuguese},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.acceptLanguage, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/test", nil)
+			r.Header.Set("Accept-Language", tt.acceptLanguage)
+			assert.Equal(t, tt.expected, LanguageFromRequest(r))
+		})
+	}
+}
+
+func TestMessageCatalogsAreComplete(t *testing.T) {
+	for lang, catalog := range messages {
+		for key := range messages[DefaultLanguage] {
+			assert.Contains(t, catalog, key, "missing %s in %s", key, lang)
+		}
+	}
+}
+
+func TestErrorMessages_English(t *testing.T) {
+	r := httptest.NewRequest("GET", "/test", nil)
+	r.Header.Set("Accept-Language", "en-US")
+
+	assert.Equal(t, "Internal error.", NewInternalServerError(errors.New("error"), r).Message)
+	assert.Equal(t, "The resource was not found.", NewNotFoundError(errors.New("error"), r).Message)
+	assert.Equal(t, "Access denied.", NewForbiddenError(errors.New("error"), r).Message)
+}
+
+func TestValidateBody_Spanish(t *testing.T) {
+	r := httptest.NewRequest("POST", "/test", nil)
+	r.Header.Set("Accept-Language", "es")
+
+	body := struct {
+		Nome    string `json:"nome" validate:"required"`
+		Escopo  string `json:"escopo" validate:"oneof=read write"`
+		Contato string `json:"contato" validate:"email"`
+	}{Escopo: "delete", Contato: "invalido"}
+
+	err := newValidator(t).ValidateBody(r, body)
+	assert.NotNil(t, err)
+	assert.Equal(t, "Solicitud inválida.", err.Message)
+	assert.Equal(t, LanguageSpanish, err.Language)
+	assert.Equal(t, "nome es un campo requerido", err.Fields[0].Mensagem)
+	assert.Equal(t, "escopo debe ser uno de [read write]", err.Fields[1].Mensagem)
+	assert.Equal(t, "contato debe ser una dirección de correo electrónico válida", err.Fields[2].Mensagem)
+	assert.Contains(t, err.Cause, "Debe cumplir la validación")
+}
+
+func TestNewJSONResponse_ContentLanguage(t *testing.T) {
+	r := httptest.NewRequest("GET", "/test", nil)
+	r.Header.Set("Accept-Language", "en")
+
+	recorder := httptest.NewRecorder()
+	NewJSONResponse(recorder, http.StatusNotFound, NewNotFoundError(errors.New("error"), r))
+
+	assert.Equal(t, "en", recorder.Header().Get("Content-Language"))
+	assert.Equal(t, "Accept-Language", recorder.Header().Get("Vary"))
+}
@@ -7,8 +7,30 @@ import (
 
 // Função genérica responsável por criar uma resposta JSON.
 // Recebe um http.ResponseWriter, um status HTTP e um objeto que será serializado.
+// Erros são renderizados no formato negociado quando foram criados (ver NewResponse),
+// ou como application/problem+json quando o cliente prefere esse formato.
 func NewJSONResponse(w http.ResponseWriter, httpStatus int, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(httpStatus)
-	json.NewEncoder(w).Encode(data)
+	e, isError := data.(*Error)
+
+	if !isError {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(httpStatus)
+		json.NewEncoder(w).Encode(data)
+		return
+	}
+
+	if e.Language != "" {
+		w.Header().Set("Content-Language", string(e.Language))
+		w.Header().Add("Vary", "Accept-Language")
+	}
+	w.Header().Add("Vary", "Accept")
+
+	if e.problem {
+		w.Header().Set("Content-Type", ProblemContentType)
+		w.WriteHeader(httpStatus)
+		json.NewEncoder(w).Encode(e.Problem())
+		return
+	}
+
+	render(w, httpStatus, e.format, xmlErrorElement, e)
 }
@@ -0,0 +1,127 @@
+package utils
+
+import (
+	"mime"
+	"strconv"
+	"strings"
+)
+
+// Formato de serialização das respostas, identificado pelo content type.
+type Format string
+
+// Formatos suportados pela negociação de conteúdo, na ordem de preferência do servidor.
+const (
+	FormatJSON        Format = "application/json"
+	FormatXML         Format = "application/xml"
+	FormatMessagePack Format = "application/msgpack"
+	FormatCSV         Format = "text/csv"
+)
+
+// Formatos na ordem de preferência usada para desempatar media ranges com o mesmo q.
+var formats = []Format{FormatJSON, FormatXML, FormatMessagePack, FormatCSV}
+
+// Media types aceitos no header Accept para cada formato, incluindo os nomes alternativos usados pelos clientes.
+var formatMediaTypes = map[Format][]string{
+	FormatJSON:        {"application/json", ProblemContentType},
+	FormatXML:         {"application/xml", "text/xml"},
+	FormatMessagePack: {"application/msgpack", "application/x-msgpack", "application/vnd.msgpack"},
+	FormatCSV:         {"text/csv"},
+}
+
+// Função responsável por retornar os content types suportados pela negociação, separados por vírgula.
+func SupportedFormats() string {
+	values := make([]string, 0, len(formats))
+	for _, format := range formats {
+		values = append(values, string(format))
+	}
+	return strings.Join(values, ", ")
+}
+
+// Struct que representa um media range do header Accept.
+type mediaRange struct {
+	mediaType string
+	q         float64
+}
+
+// Função responsável por escolher o formato da resposta a partir do header Accept.
+// Sem o header a resposta é JSON. Para cada formato vale o q do media range mais específico que o aceita
+// (ex: text/csv antes de text/* e */*), e formatos com o mesmo q são desempatados pela ordem de preferência do servidor.
+// Retorna false quando nenhum dos formatos suportados é aceito pelo cliente.
+func NegotiateFormat(accept string) (Format, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return FormatJSON, true
+	}
+
+	ranges := parseAccept(accept)
+
+	selected, selectedQ := Format(""), 0.0
+	for _, format := range formats {
+		if q := formatQuality(format, ranges); q > selectedQ {
+			selected, selectedQ = format, q
+		}
+	}
+
+	return selected, selectedQ > 0
+}
+
+// Função responsável por retornar o formato negociado, utilizando JSON quando nenhum formato é aceito.
+func formatFromAccept(accept string) Format {
+	if format, ok := NegotiateFormat(accept); ok {
+		return format
+	}
+	return FormatJSON
+}
+
+func parseAccept(accept string) []mediaRange {
+	ranges := make([]mediaRange, 0)
+
+	for _, value := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(value))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := params["q"]; ok {
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				q = parsed
+			}
+		}
+
+		ranges = append(ranges, mediaRange{mediaType: mediaType, q: q})
+	}
+
+	return ranges
+}
+
+// Função responsável por calcular o q do formato, considerando o media range mais específico que o aceita.
+func formatQuality(format Format, ranges []mediaRange) float64 {
+	const (
+		noMatch = iota
+		anyType
+		anySubtype
+		exact
+	)
+
+	specificity, q := noMatch, 0.0
+
+	for _, r := range ranges {
+		for _, mediaType := range formatMediaTypes[format] {
+			match := noMatch
+			switch {
+			case r.mediaType == mediaType:
+				match = exact
+			case r.mediaType == strings.SplitN(mediaType, "/", 2)[0]+"/*":
+				match = anySubtype
+			case r.mediaType == "*/*":
+				match = anyType
+			}
+
+			if match > specificity || (match == specificity && match != noMatch && r.q > q) {
+				specificity, q = match, r.q
+			}
+		}
+	}
+
+	return q
+}
@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Testes da escolha do formato da resposta pelo header Accept
+
+func TestNegotiateFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		accept   string
+		expected Format
+		ok       bool
+	}{
+		{name: "sem header", accept: "", expected: FormatJSON, ok: true},
+		{name: "qualquer formato", accept: "*/*", expected: FormatJSON, ok: true},
+		{name: "json", accept: "application/json", expected: FormatJSON, ok: true},
+		{name: "problem json", accept: "application/problem+json", expected: FormatJSON, ok: true},
+		{name: "xml", accept: "application/xml", expected: FormatXML, ok: true},
+		{name: "text xml", accept: "text/xml; charset=utf-8", expected: FormatXML, ok: true},
+		{name: "msgpack", accept: "application/msgpack", expected: FormatMessagePack, ok: true},
+		{name: "msgpack alternativo", accept: "application/x-msgpack", expected: FormatMessagePack, ok: true},
+		{name: "csv", accept: "text/csv", expected: FormatCSV, ok: true},
+		{name: "maior q", accept: "application/json;q=0.5, text/csv", expected: FormatCSV, ok: true},
+		{name: "desempate pela preferência do servidor", accept: "text/csv, application/xml", expected: FormatXML, ok: true},
+		{name: "media range específico prevalece sobre o curinga", accept: "*/*;q=0.9, application/json;q=0.1", expected: FormatXML, ok: true},
+		{name: "subtipo curinga", accept: "application/*", expected: FormatJSON, ok: true},
+		{name: "formato recusado com q=0", accept: "application/json;q=0, */*", expected: FormatXML, ok: true},
+		{name: "não suportado", accept: "application/pdf", ok: false},
+		{name: "todos recusados", accept: "*/*;q=0", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, ok := NegotiateFormat(tt.accept)
+
+			assert.Equal(t, tt.ok, ok)
+			if tt.ok {
+				assert.Equal(t, tt.expected, format)
+			}
+		})
+	}
+}
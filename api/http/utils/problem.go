@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"mime"
+	"strconv"
+	"strings"
+)
+
+// Content type dos documentos de problema (RFC 7807).
+const ProblemContentType = "application/problem+json"
+
+// Catálogo de tipos de problema. As URIs são estáveis e identificam a categoria do erro para os clientes.
+const (
+	ProblemTypeBlank                = "about:blank"
+	ProblemTypeBaseURI              = "https://github.com/samluiz/delivery-service/problems/"
+	ProblemTypeBadRequest           = ProblemTypeBaseURI + "bad-request"
+	ProblemTypeValidation           = ProblemTypeBaseURI + "validation"
+	ProblemTypeUnauthorized         = ProblemTypeBaseURI + "unauthorized"
+	ProblemTypeForbidden            = ProblemTypeBaseURI + "forbidden"
+	ProblemTypeNotFound             = ProblemTypeBaseURI + "not-found"
+	ProblemTypeConflict             = ProblemTypeBaseURI + "conflict"
+	ProblemTypePayloadTooLarge      = ProblemTypeBaseURI + "payload-too-large"
+	ProblemTypeUnsupportedMediaType = ProblemTypeBaseURI + "unsupported-media-type"
+	ProblemTypeNotAcceptable        = ProblemTypeBaseURI + "not-acceptable"
+	ProblemTypeTooManyRequests      = ProblemTypeBaseURI + "too-many-requests"
+	ProblemTypeServiceUnavailable   = ProblemTypeBaseURI + "service-unavailable"
+	ProblemTypeInternal             = ProblemTypeBaseURI + "internal"
+)
+
+// Struct que representa um documento de problema (RFC 7807), com os membros de extensão da API.
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
+	Timestamp string       `json:"timestamp,omitempty"`
+	Campos    []FieldError `json:"campos,omitempty"`
+}
+
+// Função responsável por converter o erro para o documento de problema.
+func (e *Error) Problem() *Problem {
+	problemType := e.Type
+	if problemType == "" {
+		problemType = ProblemTypeBlank
+	}
+
+	return &Problem{
+		Type:      problemType,
+		Title:     e.Message,
+		Status:    e.Status,
+		Detail:    e.Cause,
+		Instance:  e.Path,
+		RequestID: e.RequestID,
+		Timestamp: e.Timestamp,
+		Campos:    e.Fields,
+	}
+}
+
+// Função responsável por verificar, pelo header Accept, se o cliente prefere application/problem+json.
+// O formato é escolhido apenas quando solicitado explicitamente com prioridade maior ou igual à de application/json.
+func acceptsProblem(accept string) bool {
+	problemQ, jsonQ := 0.0, 0.0
+
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := params["q"]; ok {
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				q = parsed
+			}
+		}
+
+		switch mediaType {
+		case ProblemContentType:
+			problemQ = max(problemQ, q)
+		case "application/json":
+			jsonQ = max(jsonQ, q)
+		}
+	}
+
+	return problemQ > 0 && problemQ >= jsonQ
+}
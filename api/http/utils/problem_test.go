@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/samluiz/delivery-service/internal/requestmeta"
+	"github.com/stretchr/testify/assert"
+)
+
+// Testes da negociação de conteúdo e da renderização dos erros como problem+json
+
+func TestAcceptsProblem(t *testing.T) {
+	tests := []struct {
+		accept   string
+		expected bool
+	}{
+		{accept: "", expected: false},
+		{accept: "*/*", expected: false},
+		{accept: "application/json", expected: false},
+		{accept: "application/problem+json", expected: true},
+		{accept: "application/json, application/problem+json", expected: true},
+		{accept: "application/problem+json;q=0.5, application/json", expected: false},
+		{accept: "application/problem+json, application/json;q=0.9", expected: true},
+		{accept: "application/problem+json;q=0", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			assert.Equal(t, tt.expected, acceptsProblem(tt.accept))
+		})
+	}
+}
+
+func TestNewJSONResponse_Problem(t *testing.T) {
+	r := httptest.NewRequest("POST", "/deliveries", nil)
+	r.Header.Set("Accept", "application/problem+json")
+	r = r.WithContext(requestmeta.WithMetadata(r.Context(), requestmeta.Metadata{RequestID: "req-1"}))
+
+	body := struct {
+		Nome string `json:"nome" validate:"required"`
+	}{}
+
+	recorder := httptest.NewRecorder()
+	NewJSONResponse(recorder, http.StatusBadRequest, newValidator(t).ValidateBody(r, body))
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Equal(t, ProblemContentType, recorder.Header().Get("Content-Type"))
+
+	var problem Problem
+	assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&problem))
+	assert.Equal(t, ProblemTypeValidation, problem.Type)
+	assert.Equal(t, "Requisição inválida.", problem.Title)
+	assert.Equal(t, http.StatusBadRequest, problem.Status)
+	assert.Equal(t, "/deliveries", problem.Instance)
+	assert.Equal(t, "req-1", problem.RequestID)
+	assert.Equal(t, []FieldError{{Campo: "nome", Regra: "required", Mensagem: "nome é um campo obrigatório"}}, problem.Campos)
+}
+
+func TestNewJSONResponse_ErrorWithoutProblem(t *testing.T) {
+	r := httptest.NewRequest("GET", "/deliveries/1", nil)
+	r.Header.Set("Accept", "application/json")
+
+	recorder := httptest.NewRecorder()
+	NewJSONResponse(recorder, http.StatusNotFound, NewNotFoundError(errors.New("delivery not found"), r))
+
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+
+	var response map[string]any
+	assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+	assert.Equal(t, "O recurso não foi encontrado.", response["message"])
+	assert.NotContains(t, response, "type")
+}
+
+func TestProblemTypes(t *testing.T) {
+	r := httptest.NewRequest("GET", "/test", nil)
+	err := errors.New("error")
+
+	assert.Equal(t, ProblemTypeNotFound, NewNotFoundError(err, r).Problem().Type)
+	assert.Equal(t, ProblemTypeBadRequest, NewBadRequestError(err, r).Problem().Type)
+	assert.Equal(t, ProblemTypeConflict, NewConflictError(err, r).Problem().Type)
+	assert.Equal(t, ProblemTypeUnauthorized, NewUnauthorizedError(err, r).Problem().Type)
+	assert.Equal(t, ProblemTypeForbidden, NewForbiddenError(err, r).Problem().Type)
+	assert.Equal(t, ProblemTypeTooManyRequests, NewTooManyRequestsError(err, r).Problem().Type)
+	assert.Equal(t, ProblemTypeServiceUnavailable, NewServiceUnavailableError(err, r).Problem().Type)
+	assert.Equal(t, ProblemTypeInternal, NewInternalServerError(err, r).Problem().Type)
+	assert.Equal(t, ProblemTypeBlank, NewError(http.StatusTeapot, "Teapot", "error", r).Problem().Type)
+}
@@ -0,0 +1,399 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Elemento raiz dos documentos XML. Listas são renderizadas com um elemento <item> por entrada.
+const (
+	xmlRootElement  = "response"
+	xmlErrorElement = "error"
+	xmlItemElement  = "item"
+)
+
+// Coluna utilizada no CSV quando as linhas não são objetos.
+const csvValueColumn = "value"
+
+// Função genérica responsável por criar a resposta no formato escolhido pelo header Accept da requisição
+// (JSON, XML, MessagePack ou CSV). Os nomes dos campos são os mesmos do JSON em todos os formatos.
+// Quando nenhum formato é aceito a resposta é JSON; o middleware Negotiate rejeita essas requisições antes do handler.
+func NewResponse(w http.ResponseWriter, r *http.Request, httpStatus int, data interface{}) {
+	if e, isError := data.(*Error); isError {
+		NewJSONResponse(w, httpStatus, e)
+		return
+	}
+
+	w.Header().Add("Vary", "Accept")
+	render(w, httpStatus, formatFromAccept(r.Header.Get("Accept")), xmlRootElement, data)
+}
+
+// Função responsável por serializar o objeto no formato informado e enviá-lo com o status HTTP.
+// O corpo é serializado antes do status, para que falhas de serialização resultem em 500.
+func render(w http.ResponseWriter, httpStatus int, format Format, root string, data interface{}) {
+	var body bytes.Buffer
+	var err error
+
+	switch format {
+	case FormatXML:
+		err = encodeXML(&body, root, data)
+	case FormatMessagePack:
+		err = encodeMessagePack(&body, data)
+	case FormatCSV:
+		err = encodeCSV(&body, data)
+	default:
+		format = FormatJSON
+		err = json.NewEncoder(&body).Encode(data)
+	}
+
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	contentType := string(format)
+	if format == FormatXML || format == FormatCSV {
+		contentType += "; charset=utf-8"
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(httpStatus)
+	w.Write(body.Bytes())
+}
+
+// Função responsável por serializar o objeto em MessagePack, utilizando os nomes e opções das tags json.
+// Datas são serializadas com a extensão de timestamp do MessagePack.
+func encodeMessagePack(w io.Writer, data interface{}) error {
+	encoder := msgpack.NewEncoder(w)
+	encoder.SetCustomStructTag("json")
+	encoder.UseCompactInts(true)
+	return encoder.Encode(data)
+}
+
+// Tipos de valor do documento intermediário.
+type nodeKind int
+
+const (
+	nodeNull nodeKind = iota
+	nodeScalar
+	nodeObject
+	nodeArray
+)
+
+// Documento intermediário obtido a partir do JSON do objeto, preservando a ordem dos campos.
+// Os encoders de XML e CSV partem dele para que os nomes, os campos omitidos e a serialização dos valores
+// (ex: datas em RFC 3339) sejam os mesmos da resposta JSON.
+type node struct {
+	kind    nodeKind
+	scalar  json.Token // string, json.Number ou bool
+	members []member
+	items   []*node
+}
+
+type member struct {
+	name  string
+	value *node
+}
+
+// Função responsável por converter o objeto para o documento intermediário.
+func toNode(data interface{}) (*node, error) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+
+	return readNode(decoder)
+}
+
+func readNode(decoder *json.Decoder) (*node, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		n := &node{kind: nodeObject, members: []member{}}
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := readNode(decoder)
+			if err != nil {
+				return nil, err
+			}
+			n.members = append(n.members, member{name: key.(string), value: value})
+		}
+		_, err := decoder.Token()
+		return n, err
+	case json.Delim('['):
+		n := &node{kind: nodeArray, items: []*node{}}
+		for decoder.More() {
+			item, err := readNode(decoder)
+			if err != nil {
+				return nil, err
+			}
+			n.items = append(n.items, item)
+		}
+		_, err := decoder.Token()
+		return n, err
+	case nil:
+		return &node{kind: nodeNull}, nil
+	}
+
+	return &node{kind: nodeScalar, scalar: token}, nil
+}
+
+// Função responsável por retornar o valor escalar como texto.
+func (n *node) text() string {
+	switch value := n.scalar.(type) {
+	case string:
+		return value
+	case json.Number:
+		return value.String()
+	case bool:
+		if value {
+			return "true"
+		}
+		return "false"
+	}
+	return ""
+}
+
+// Função responsável por serializar o objeto em XML, com o elemento raiz informado.
+// Campos nulos são omitidos e campos cujo nome não é um nome XML válido são renderizados como <entry key="...">.
+func encodeXML(w io.Writer, root string, data interface{}) error {
+	n, err := toNode(data)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	if err := writeXMLElement(encoder, root, n); err != nil {
+		return err
+	}
+	return encoder.Flush()
+}
+
+func writeXMLElement(encoder *xml.Encoder, name string, n *node) error {
+	if n.kind == nodeNull {
+		return nil
+	}
+
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	if !isXMLName(name) {
+		start = xml.StartElement{
+			Name: xml.Name{Local: "entry"},
+			Attr: []xml.Attr{{Name: xml.Name{Local: "key"}, Value: name}},
+		}
+	}
+
+	if err := encoder.EncodeToken(start); err != nil {
+		return err
+	}
+
+	switch n.kind {
+	case nodeObject:
+		for _, m := range n.members {
+			if err := writeXMLElement(encoder, m.name, m.value); err != nil {
+				return err
+			}
+		}
+	case nodeArray:
+		for _, item := range n.items {
+			if err := writeXMLElement(encoder, xmlItemElement, item); err != nil {
+				return err
+			}
+		}
+	default:
+		if err := encoder.EncodeToken(xml.CharData(n.text())); err != nil {
+			return err
+		}
+	}
+
+	return encoder.EncodeToken(start.End())
+}
+
+// Função responsável por verificar se o nome pode ser usado como nome de elemento XML.
+func isXMLName(name string) bool {
+	if name == "" || strings.HasPrefix(strings.ToLower(name), "xml") {
+		return false
+	}
+
+	for i, r := range name {
+		letter := r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || r > 0x7f
+		if i == 0 && !letter {
+			return false
+		}
+		if !letter && r != '-' && r != '.' && (r < '0' || r > '9') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Função responsável por serializar o objeto em CSV, com uma linha por item das listas (ou uma linha para objetos).
+// Objetos aninhados viram colunas com os nomes unidos por ponto (ex: address.city) e listas aninhadas são
+// serializadas como JSON na célula. Textos que começam com =, +, -, @ ou tabulação são prefixados com ',
+// para que não sejam interpretados como fórmulas pelas planilhas.
+func encodeCSV(w io.Writer, data interface{}) error {
+	n, err := toNode(data)
+	if err != nil {
+		return err
+	}
+
+	rows := []*node{n}
+	if n.kind == nodeArray {
+		rows = n.items
+	}
+
+	// Sem linhas, as colunas são obtidas a partir do valor zero do tipo dos itens da lista
+	columnRows := rows
+	if len(rows) == 0 {
+		if columnRows, err = zeroValueRows(data); err != nil {
+			return err
+		}
+	}
+
+	header := make([]string, 0)
+	columns := map[string]int{}
+	for _, row := range columnRows {
+		flattenCSV(row, "", map[string]string{}, func(column string) {
+			if _, ok := columns[column]; !ok {
+				columns[column] = len(header)
+				header = append(header, column)
+			}
+		})
+	}
+
+	writer := csv.NewWriter(w)
+	if len(header) > 0 {
+		if err := writer.Write(header); err != nil {
+			return err
+		}
+	}
+
+	for _, row := range rows {
+		line := make([]string, len(header))
+		for column, value := range flattenCSV(row, "", map[string]string{}, func(string) {}) {
+			line[columns[column]] = value
+		}
+		if err := writer.Write(line); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// Função responsável por montar uma linha com o valor zero do tipo dos itens da lista, usada apenas para as colunas.
+func zeroValueRows(data interface{}) ([]*node, error) {
+	t := reflect.TypeOf(data)
+	if t == nil || (t.Kind() != reflect.Slice && t.Kind() != reflect.Array) {
+		return nil, nil
+	}
+
+	elem := t.Elem()
+	for elem.Kind() == reflect.Pointer {
+		elem = elem.Elem()
+	}
+
+	row, err := toNode(reflect.New(elem).Interface())
+	if err != nil {
+		return nil, err
+	}
+	return []*node{row}, nil
+}
+
+// Função responsável por converter o valor para as células da linha, indexadas pelo nome da coluna.
+// As colunas são informadas ao addColumn na ordem em que aparecem no objeto.
+func flattenCSV(n *node, prefix string, record map[string]string, addColumn func(string)) map[string]string {
+	if n.kind == nodeObject {
+		for _, m := range n.members {
+			flattenCSV(m.value, joinPath(prefix, m.name), record, addColumn)
+		}
+		return record
+	}
+
+	column := prefix
+	if column == "" {
+		column = csvValueColumn
+	}
+	addColumn(column)
+
+	switch n.kind {
+	case nodeArray:
+		var encoded bytes.Buffer
+		writeJSON(&encoded, n)
+		record[column] = encoded.String()
+	case nodeScalar:
+		record[column] = csvCell(n)
+	}
+
+	return record
+}
+
+func csvCell(n *node) string {
+	text := n.text()
+	if _, isString := n.scalar.(string); isString && strings.IndexAny(text, "=+-@\t\r") == 0 {
+		return "'" + text
+	}
+	return text
+}
+
+func joinPath(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+// Função responsável por serializar o documento intermediário em JSON, preservando a ordem dos campos.
+func writeJSON(buffer *bytes.Buffer, n *node) {
+	switch n.kind {
+	case nodeObject:
+		buffer.WriteByte('{')
+		for i, m := range n.members {
+			if i > 0 {
+				buffer.WriteByte(',')
+			}
+			name, _ := json.Marshal(m.name)
+			buffer.Write(name)
+			buffer.WriteByte(':')
+			writeJSON(buffer, m.value)
+		}
+		buffer.WriteByte('}')
+	case nodeArray:
+		buffer.WriteByte('[')
+		for i, item := range n.items {
+			if i > 0 {
+				buffer.WriteByte(',')
+			}
+			writeJSON(buffer, item)
+		}
+		buffer.WriteByte(']')
+	case nodeScalar:
+		value, _ := json.Marshal(n.scalar)
+		buffer.Write(value)
+	default:
+		buffer.WriteString("null")
+	}
+}
@@ -0,0 +1,200 @@
+package utils
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Structs utilizados para testar a renderização das respostas
+type renderedAddress struct {
+	Cidade string `json:"cidade"`
+	Estado string `json:"estado"`
+}
+
+type renderedStruct struct {
+	ID       int             `json:"id"`
+	Nome     string          `json:"nome"`
+	Peso     float64         `json:"peso"`
+	Endereco renderedAddress `json:"endereco"`
+	Tags     []string        `json:"tags,omitempty"`
+	Criado   time.Time       `json:"criado"`
+	Excluido *time.Time      `json:"excluido,omitempty"`
+	Interno  string          `json:"-"`
+}
+
+var renderedTime = time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+
+func renderedItems() []*renderedStruct {
+	return []*renderedStruct{
+		{ID: 1, Nome: "Cliente A", Peso: 10.5, Endereco: renderedAddress{Cidade: "Recife", Estado: "PE"}, Criado: renderedTime, Interno: "x"},
+		{ID: 2, Nome: "=SOMA(A1)", Peso: 2, Endereco: renderedAddress{Cidade: "Natal", Estado: "RN"}, Tags: []string{"a", "b"}, Criado: renderedTime},
+	}
+}
+
+// Testes da função NewResponse, que serializa a resposta no formato escolhido pelo header Accept
+
+func TestNewResponse(t *testing.T) {
+	tests := []struct {
+		name                string
+		accept              string
+		data                interface{}
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			name:                "json por padrão",
+			data:                renderedItems()[0],
+			expectedContentType: "application/json",
+			expectedBody:        `{"id":1,"nome":"Cliente A","peso":10.5,"endereco":{"cidade":"Recife","estado":"PE"},"criado":"2024-05-10T12:00:00Z"}` + "\n",
+		},
+		{
+			name:                "xml de um objeto",
+			accept:              "application/xml",
+			data:                renderedItems()[0],
+			expectedContentType: "application/xml; charset=utf-8",
+			expectedBody: `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+				`<response><id>1</id><nome>Cliente A</nome><peso>10.5</peso><endereco><cidade>Recife</cidade><estado>PE</estado></endereco><criado>2024-05-10T12:00:00Z</criado></response>`,
+		},
+		{
+			name:                "xml de uma lista",
+			accept:              "text/xml",
+			data:                []string{"a", "<b>"},
+			expectedContentType: "application/xml; charset=utf-8",
+			expectedBody:        `<?xml version="1.0" encoding="UTF-8"?>` + "\n" + `<response><item>a</item><item>&lt;b&gt;</item></response>`,
+		},
+		{
+			name:                "xml com nome de campo inválido",
+			accept:              "application/xml",
+			data:                map[string]int{"1a": 1},
+			expectedContentType: "application/xml; charset=utf-8",
+			expectedBody:        `<?xml version="1.0" encoding="UTF-8"?>` + "\n" + `<response><entry key="1a">1</entry></response>`,
+		},
+		{
+			name:                "csv de uma lista",
+			accept:              "text/csv",
+			data:                renderedItems(),
+			expectedContentType: "text/csv; charset=utf-8",
+			expectedBody: "id,nome,peso,endereco.cidade,endereco.estado,criado,tags\n" +
+				"1,Cliente A,10.5,Recife,PE,2024-05-10T12:00:00Z,\n" +
+				"2,'=SOMA(A1),2,Natal,RN,2024-05-10T12:00:00Z,\"[\"\"a\"\",\"\"b\"\"]\"\n",
+		},
+		{
+			name:                "csv de uma lista vazia",
+			accept:              "text/csv",
+			data:                []*renderedStruct{},
+			expectedContentType: "text/csv; charset=utf-8",
+			expectedBody:        "id,nome,peso,endereco.cidade,endereco.estado,criado\n",
+		},
+		{
+			name:                "csv de um objeto",
+			accept:              "text/csv",
+			data:                renderedItems()[0],
+			expectedContentType: "text/csv; charset=utf-8",
+			expectedBody:        "id,nome,peso,endereco.cidade,endereco.estado,criado\n1,Cliente A,10.5,Recife,PE,2024-05-10T12:00:00Z\n",
+		},
+		{
+			name:                "csv de valores",
+			accept:              "text/csv",
+			data:                []int{1, -2},
+			expectedContentType: "text/csv; charset=utf-8",
+			expectedBody:        "value\n1\n-2\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/test", nil)
+			if tt.accept != "" {
+				r.Header.Set("Accept", tt.accept)
+			}
+			w := httptest.NewRecorder()
+
+			NewResponse(w, r, http.StatusOK, tt.data)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.expectedContentType, w.Header().Get("Content-Type"))
+			assert.Equal(t, "Accept", w.Header().Get("Vary"))
+			assert.Equal(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestNewResponse_MessagePack(t *testing.T) {
+	r := httptest.NewRequest("GET", "/test", nil)
+	r.Header.Set("Accept", "application/vnd.msgpack")
+	w := httptest.NewRecorder()
+
+	NewResponse(w, r, http.StatusCreated, renderedItems()[1])
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "application/msgpack", w.Header().Get("Content-Type"))
+
+	// Os campos utilizam os nomes das tags json, omitindo os campos vazios e os ignorados
+	var decoded map[string]interface{}
+	require.NoError(t, msgpack.Unmarshal(w.Body.Bytes(), &decoded))
+
+	assert.Equal(t, int8(2), decoded["id"])
+	assert.Equal(t, "=SOMA(A1)", decoded["nome"])
+	assert.Equal(t, map[string]interface{}{"cidade": "Natal", "estado": "RN"}, decoded["endereco"])
+	assert.Equal(t, []interface{}{"a", "b"}, decoded["tags"])
+	assert.True(t, renderedTime.Equal(decoded["criado"].(time.Time)))
+	assert.NotContains(t, decoded, "excluido")
+	assert.NotContains(t, decoded, "Interno")
+}
+
+// Testes da renderização dos erros no formato negociado na criação do erro
+
+func TestNewResponse_Error(t *testing.T) {
+	tests := []struct {
+		name                string
+		accept              string
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			name:                "xml",
+			accept:              "application/xml",
+			expectedContentType: "application/xml; charset=utf-8",
+			expectedBody:        `<error><status>404</status><message>O recurso não foi encontrado.</message><error>not found</error>`,
+		},
+		{
+			name:                "csv",
+			accept:              "text/csv",
+			expectedContentType: "text/csv; charset=utf-8",
+			expectedBody:        "status,message,error,timestamp,path\n404,O recurso não foi encontrado.,not found,",
+		},
+		{
+			name:                "problem json tem prioridade",
+			accept:              "application/problem+json, application/xml;q=0.5",
+			expectedContentType: ProblemContentType,
+			expectedBody:        `"type":"` + ProblemTypeNotFound + `"`,
+		},
+		{
+			name:                "formato não suportado usa json",
+			accept:              "application/pdf",
+			expectedContentType: "application/json",
+			expectedBody:        `"message":"O recurso não foi encontrado."`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/test", nil)
+			r.Header.Set("Accept", tt.accept)
+			w := httptest.NewRecorder()
+
+			NewResponse(w, r, http.StatusNotFound, NewNotFoundError(errors.New("not found"), r))
+
+			assert.Equal(t, http.StatusNotFound, w.Code)
+			assert.Equal(t, tt.expectedContentType, w.Header().Get("Content-Type"))
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+		})
+	}
+}
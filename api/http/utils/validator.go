@@ -2,50 +2,200 @@ package utils
 
 import (
 	"fmt"
+	"reflect"
+	"strings"
 
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/es"
+	"github.com/go-playground/locales/pt_BR"
+	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
+	en_translations "github.com/go-playground/validator/v10/translations/en"
+	es_translations "github.com/go-playground/validator/v10/translations/es"
+	pt_BR_translations "github.com/go-playground/validator/v10/translations/pt_BR"
 )
 
+// Interface das regras registradas no validator na sua criação, como as regras de domínio das entregas.
+type ValidationRules interface {
+	// Registra as tags e as regras de struct no validator.
+	RegisterValidationRules(validate *validator.Validate) error
+	// Retorna o parâmetro das tags cujo limite vem da configuração, e não da tag (ex: o peso máximo).
+	ValidationParam(tag string) (string, bool)
+}
+
 // Struct que representa o validator.
 type XValidator struct {
-	Validator *validator.Validate
+	Validator   *validator.Validate
+	rules       []ValidationRules
+	translators map[Language]ut.Translator
+}
+
+// Traduções do validator de cada idioma suportado, pelo nome do locale.
+var validatorTranslations = map[Language]struct {
+	locale   string
+	register func(*validator.Validate, ut.Translator) error
+}{
+	LanguagePortuguese: {"pt_BR", pt_BR_translations.RegisterDefaultTranslations},
+	LanguageEnglish:    {"en", en_translations.RegisterDefaultTranslations},
+	LanguageSpanish:    {"es", es_translations.RegisterDefaultTranslations},
+}
+
+// Tags das regras de domínio, que não possuem tradução no validator e usam as mensagens do catálogo.
+var domainTags = []string{"peso", "pais", "uf"}
+
+// Função responsável por criar o validator com os nomes de campos do JSON, as traduções das mensagens
+// e as regras informadas registradas.
+// O validator mantém cache da reflexão dos structs, por isso deve ser criado uma única vez na inicialização e reutilizado.
+func NewXValidator(rules ...ValidationRules) (*XValidator, error) {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(jsonFieldName)
+
+	for _, rule := range rules {
+		if err := rule.RegisterValidationRules(validate); err != nil {
+			return nil, fmt.Errorf("erro registrando as regras de validação: %w", err)
+		}
+	}
+
+	v := &XValidator{Validator: validate, rules: rules, translators: make(map[Language]ut.Translator)}
+
+	if err := v.registerTranslations(); err != nil {
+		return nil, fmt.Errorf("erro registrando as traduções do validator: %w", err)
+	}
+
+	return v, nil
+}
+
+// Função responsável por registrar as traduções do validator e as mensagens das regras de domínio em cada idioma.
+func (v *XValidator) registerTranslations() error {
+	universal := ut.New(pt_BR.New(), pt_BR.New(), en.New(), es.New())
+
+	for lang, translations := range validatorTranslations {
+		trans, _ := universal.GetTranslator(translations.locale)
+
+		if err := translations.register(v.Validator, trans); err != nil {
+			return err
+		}
+
+		for _, tag := range domainTags {
+			err := v.Validator.RegisterTranslation(tag, trans, func(ut.Translator) error { return nil },
+				func(_ ut.Translator, fe validator.FieldError) string {
+					return ruleMessage(lang, fe.Tag(), fe.Field(), v.param(fe.Tag(), fe.Param()))
+				})
+			if err != nil {
+				return err
+			}
+		}
+
+		v.translators[lang] = trans
+	}
+
+	return nil
 }
 
 // Struct que representa um erro de validação do validator.
 type ValidationError struct {
 	Error       bool
 	FailedField string
+	Field       string // Caminho do campo usando os nomes do JSON, ex: escopos[0]
 	Tag         string
+	Param       string
 	Value       interface{}
+
+	fieldError validator.FieldError // Falha original do validator, utilizada na tradução da mensagem
 }
 
 // Função responsável por validar um struct utilizando o validator.
-func (v XValidator) Validate(data interface{}) []ValidationError {
-	validate := validator.New()
-
+// Retorna as falhas de validação dos campos ou um erro quando o valor não pode ser validado (ex: ponteiro nulo).
+func (v *XValidator) Validate(data interface{}) ([]ValidationError, error) {
 	validationErrors := []ValidationError{}
 
 	// Validando o struct e retornando os erros se houver
-	errs := validate.Struct(data)
+	errs := v.Validator.Struct(data)
 	if errs != nil {
 		// Verificando se o erro é do tipo ValidationErrors
 		if _, ok := errs.(validator.ValidationErrors); !ok {
-			fmt.Printf("erro validando struct: %v", errs)
-			panic(errs)
+			return nil, fmt.Errorf("erro validando struct: %w", errs)
 		}
 
 		// Iterando sobre os erros e adicionando ao array de erros
 		for _, err := range errs.(validator.ValidationErrors) {
 			var elem ValidationError
 
-			elem.FailedField = err.Field()
+			elem.FailedField = err.StructField()
+			elem.Field = fieldPath(err.Namespace())
 			elem.Tag = err.Tag()
+			elem.Param = v.param(err.Tag(), err.Param())
 			elem.Value = err.Value()
 			elem.Error = true
+			elem.fieldError = err
 
 			validationErrors = append(validationErrors, elem)
 		}
 	}
 
-	return validationErrors
+	return validationErrors, nil
+}
+
+// Função responsável por montar a mensagem legível de uma falha de validação no idioma informado.
+// As falhas do validator usam as suas traduções; as demais (ex: as do contrato OpenAPI) usam a tradução da tag
+// equivalente do validator, quando existir, ou as mensagens do catálogo.
+func (v *XValidator) message(lang Language, err ValidationError) string {
+	trans, ok := v.translators[lang]
+	if !ok {
+		trans = v.translators[DefaultLanguage]
+	}
+
+	if err.fieldError != nil && trans != nil {
+		return err.fieldError.Translate(trans)
+	}
+
+	// Regras com parâmetro numérico são registradas pelo validator com o sufixo do tipo (ex: gte-number)
+	if trans != nil {
+		for _, key := range []string{err.Tag, err.Tag + "-number"} {
+			if message, terr := trans.T(key, err.Field, err.Param); terr == nil {
+				return message
+			}
+		}
+	}
+
+	if hasMessage("validation." + err.Tag) {
+		return ruleMessage(lang, err.Tag, err.Field, err.Param)
+	}
+	if err.Param != "" {
+		return translate(lang, msgValidationWithArgs, err.Field, err.Tag, err.Param)
+	}
+	return translate(lang, msgValidationDefault, err.Field, err.Tag)
+}
+
+// Função responsável por retornar o parâmetro da tag, priorizando o informado pelas regras registradas.
+func (v *XValidator) param(tag string, param string) string {
+	for _, rule := range v.rules {
+		if value, ok := rule.ValidationParam(tag); ok {
+			return value
+		}
+	}
+	return param
+}
+
+// Função responsável por retornar o nome do campo no JSON, usando o nome do campo no struct quando não houver a tag.
+func jsonFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return field.Name
+	}
+
+	return name
+}
+
+// Função responsável por remover o nome do struct validado do caminho do campo.
+func fieldPath(namespace string) string {
+	_, path, found := strings.Cut(namespace, ".")
+	if !found {
+		return namespace
+	}
+	return path
 }
@@ -1,10 +1,13 @@
 package utils
 
 import (
+	"errors"
+	"strconv"
 	"testing"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // Struct utilizado para testar o validator
@@ -14,6 +17,12 @@ type ValidatedUserStruct struct {
 	Email string `validate:"required,email"`
 }
 
+// Struct com nomes de campos no JSON diferentes dos nomes no struct
+type JSONTaggedStruct struct {
+	Nome    string   `json:"nome" validate:"required,max=5"`
+	Escopos []string `json:"escopos,omitempty" validate:"dive,oneof=read write"`
+}
+
 // Struct sem tags de validação
 type NoValidationStruct struct {
 	Name string
@@ -29,7 +38,8 @@ func TestXValidator_ValidStruct(t *testing.T) {
 		Email: "john.doe@example.com",
 	}
 
-	validationErrors := validator.Validate(data)
+	validationErrors, err := validator.Validate(data)
+	assert.NoError(t, err)
 	assert.Empty(t, validationErrors, "Expected no validation errors for valid struct")
 }
 
@@ -41,7 +51,8 @@ func TestXValidator_InvalidStruct(t *testing.T) {
 		Email: "invalid-email",
 	}
 
-	validationErrors := validator.Validate(data)
+	validationErrors, err := validator.Validate(data)
+	assert.NoError(t, err)
 
 	assert.NotEmpty(t, validationErrors, "Expected validation errors for invalid struct")
 	assert.Len(t, validationErrors, 3, "Expected three validation errors")
@@ -62,6 +73,160 @@ func TestXValidator_NoValidationTags(t *testing.T) {
 		Name: "Some Name",
 	}
 
-	validationErrors := validator.Validate(data)
+	validationErrors, err := validator.Validate(data)
+	assert.NoError(t, err)
 	assert.Empty(t, validationErrors, "Expected no validation errors for struct without validation tags")
 }
+
+func TestXValidator_JSONFieldNames(t *testing.T) {
+	validator := newValidator(t)
+	data := JSONTaggedStruct{
+		Nome:    "Nome muito longo",
+		Escopos: []string{"read", "delete"},
+	}
+
+	validationErrors, err := validator.Validate(data)
+	assert.NoError(t, err)
+	assert.Len(t, validationErrors, 2)
+
+	assert.Equal(t, "Nome", validationErrors[0].FailedField)
+	assert.Equal(t, "nome", validationErrors[0].Field)
+	assert.Equal(t, "max", validationErrors[0].Tag)
+	assert.Equal(t, "5", validationErrors[0].Param)
+
+	assert.Equal(t, "escopos[1]", validationErrors[1].Field)
+	assert.Equal(t, "oneof", validationErrors[1].Tag)
+	assert.Equal(t, "read write", validationErrors[1].Param)
+}
+
+func TestXValidator_InvalidValue(t *testing.T) {
+	validator := newValidator(t)
+
+	var data *ValidatedUserStruct
+
+	// Valores que não podem ser validados retornam erro em vez de interromper o processo
+	assert.NotPanics(t, func() {
+		validationErrors, err := validator.Validate(data)
+		assert.Error(t, err)
+		assert.Nil(t, validationErrors)
+	})
+
+	validationErrors, err := validator.Validate("not a struct")
+	assert.Error(t, err)
+	assert.Nil(t, validationErrors)
+}
+
+// Regras de teste com uma tag cujo limite vem da configuração
+type maxConfigRules struct {
+	max int
+}
+
+func (r maxConfigRules) RegisterValidationRules(validate *validator.Validate) error {
+	return validate.RegisterValidation("max_config", func(fl validator.FieldLevel) bool {
+		return int(fl.Field().Int()) <= r.max
+	})
+}
+
+func (r maxConfigRules) ValidationParam(tag string) (string, bool) {
+	if tag == "max_config" {
+		return strconv.Itoa(r.max), true
+	}
+	return "", false
+}
+
+type ConfiguredLimitStruct struct {
+	Quantidade int `json:"quantidade" validate:"max=100,max_config"`
+}
+
+func TestXValidator_Rules(t *testing.T) {
+	validator := newValidator(t, maxConfigRules{max: 10})
+
+	validationErrors, err := validator.Validate(ConfiguredLimitStruct{Quantidade: 5})
+	assert.NoError(t, err)
+	assert.Empty(t, validationErrors)
+
+	// O parâmetro da tag registrada vem das regras
+	validationErrors, err = validator.Validate(ConfiguredLimitStruct{Quantidade: 50})
+	assert.NoError(t, err)
+	assert.Len(t, validationErrors, 1)
+	assert.Equal(t, "quantidade", validationErrors[0].Field)
+	assert.Equal(t, "max_config", validationErrors[0].Tag)
+	assert.Equal(t, "10", validationErrors[0].Param)
+
+	// As demais tags mantêm o parâmetro da tag
+	validationErrors, err = validator.Validate(ConfiguredLimitStruct{Quantidade: 500})
+	assert.NoError(t, err)
+	assert.Len(t, validationErrors, 1)
+	assert.Equal(t, "max", validationErrors[0].Tag)
+	assert.Equal(t, "100", validationErrors[0].Param)
+}
+
+// Regras de teste cujo registro falha
+type failingRules struct{}
+
+func (failingRules) RegisterValidationRules(*validator.Validate) error {
+	return errors.New("tag inválida")
+}
+
+func (failingRules) ValidationParam(string) (string, bool) {
+	return "", false
+}
+
+func TestNewXValidator_RulesError(t *testing.T) {
+	// Falhas no registro das regras são retornadas em vez de interromper o processo
+	assert.NotPanics(t, func() {
+		validator, err := NewXValidator(failingRules{})
+		assert.ErrorContains(t, err, "tag inválida")
+		assert.Nil(t, validator)
+	})
+}
+
+// Regras de teste com uma das tags de domínio, cujo limite vem da configuração
+type pesoRules struct{}
+
+func (pesoRules) RegisterValidationRules(validate *validator.Validate) error {
+	return validate.RegisterValidation("peso", func(fl validator.FieldLevel) bool {
+		return fl.Field().Float() <= 10
+	})
+}
+
+func (pesoRules) ValidationParam(tag string) (string, bool) {
+	return "10", tag == "peso"
+}
+
+func TestXValidator_Translations(t *testing.T) {
+	validator := newValidator(t, pesoRules{})
+
+	errs, err := validator.Validate(struct {
+		Nome string  `json:"nome" validate:"required"`
+		Peso float64 `json:"peso" validate:"peso"`
+	}{Peso: 50})
+	assert.NoError(t, err)
+	assert.Len(t, errs, 2)
+
+	tests := []struct {
+		lang     Language
+		expected []string
+	}{
+		{lang: LanguagePortuguese, expected: []string{"nome é um campo obrigatório", "peso deve ser maior que 0 e menor ou igual a 10"}},
+		{lang: LanguageEnglish, expected: []string{"nome is a required field", "peso must be greater than 0 and less than or equal to 10"}},
+		{lang: LanguageSpanish, expected: []string{"nome es un campo requerido", "peso debe ser mayor que 0 y menor o igual a 10"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.lang), func(t *testing.T) {
+			fields := validator.NewFieldErrors(tt.lang, errs)
+			assert.Equal(t, tt.expected, []string{fields[0].Mensagem, fields[1].Mensagem})
+		})
+	}
+}
+
+// Função responsável por criar o validator dos testes com as regras informadas.
+func newValidator(t *testing.T, rules ...ValidationRules) *XValidator {
+	t.Helper()
+
+	validator, err := NewXValidator(rules...)
+	require.NoError(t, err)
+
+	return validator
+}
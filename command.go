@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/samluiz/delivery-service/api/http/utils"
+	"github.com/samluiz/delivery-service/config/db"
+	"github.com/samluiz/delivery-service/internal/apikey"
+	"github.com/samluiz/delivery-service/internal/tenant"
+)
+
+// Função responsável por executar os comandos administrativos de chaves de API.
+// Uso:
+//
+//	./main apikey create -nome <nome> -escopos read,write,admin [-tenant <tenant>]
+//	./main apikey list [-tenant <tenant>]
+//	./main apikey revoke -id <id> [-tenant <tenant>]
+//
+// Sem -tenant os comandos atuam no tenant padrão.
+func runAPIKeyCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("uso: apikey <create|list|revoke> [flags]")
+	}
+
+	conn := db.OpenMySQLConnection()
+	defer conn.Close()
+
+	service := apikey.NewAPIKeyService(apikey.NewAPIKeyRepository(conn))
+	ctx := context.Background()
+
+	switch args[0] {
+	case "create":
+		flags := flag.NewFlagSet("apikey create", flag.ExitOnError)
+		nome := flags.String("nome", "", "nome de identificação da chave")
+		escopos := flags.String("escopos", "read", "escopos separados por vírgula (read, write, admin)")
+		tenantID := tenantFlag(flags)
+		flags.Parse(args[1:])
+
+		ctx, err := withTenant(ctx, *tenantID)
+		if err != nil {
+			return err
+		}
+
+		request := &apikey.CreateAPIKeyRequest{Nome: *nome, Escopos: strings.Split(*escopos, ",")}
+
+		// Validando com as mesmas regras do endpoint de administração
+		validator, err := utils.NewXValidator()
+		if err != nil {
+			return err
+		}
+
+		errs, err := validator.Validate(request)
+		if err != nil {
+			return err
+		}
+		if len(errs) > 0 {
+			return fmt.Errorf("parâmetros inválidos: %v", errs)
+		}
+
+		response, err := service.CreateAPIKey(ctx, request)
+		if err != nil {
+			return err
+		}
+		return printJSON(response)
+	case "list":
+		flags := flag.NewFlagSet("apikey list", flag.ExitOnError)
+		tenantID := tenantFlag(flags)
+		flags.Parse(args[1:])
+
+		ctx, err := withTenant(ctx, *tenantID)
+		if err != nil {
+			return err
+		}
+
+		response, err := service.GetAPIKeys(ctx)
+		if err != nil {
+			return err
+		}
+		return printJSON(response)
+	case "revoke":
+		flags := flag.NewFlagSet("apikey revoke", flag.ExitOnError)
+		id := flags.Int("id", 0, "ID da chave a ser revogada")
+		tenantID := tenantFlag(flags)
+		flags.Parse(args[1:])
+
+		ctx, err := withTenant(ctx, *tenantID)
+		if err != nil {
+			return err
+		}
+
+		return service.RevokeAPIKey(ctx, *id)
+	default:
+		return fmt.Errorf("comando desconhecido: %s", args[0])
+	}
+}
+
+// Função responsável por registrar a flag do tenant dos comandos.
+func tenantFlag(flags *flag.FlagSet) *string {
+	return flags.String("tenant", tenant.Default, "tenant (transportadora) das chaves")
+}
+
+// Função responsável por validar o tenant informado e adicioná-lo ao contexto.
+func withTenant(ctx context.Context, id string) (context.Context, error) {
+	if err := tenant.Validate(id); err != nil {
+		return nil, fmt.Errorf("%w: %q", err, id)
+	}
+	return tenant.WithTenant(ctx, id), nil
+}
+
+// Função responsável por escrever um objeto em JSON na saída padrão.
+func printJSON(data interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(data)
+}
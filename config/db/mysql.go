@@ -2,6 +2,7 @@ package db
 
 import (
 	"database/sql"
+	"fmt"
 	"log"
 	"os"
 	"time"
@@ -9,6 +10,9 @@ import (
 	_ "github.com/go-sql-driver/mysql"
 )
 
+// Tabelas criadas pela aplicação, utilizadas para verificar se as migrations foram aplicadas.
+var Tables = []string{"entregas", "chaves_api", "auditoria", "entregas_versoes"}
+
 // Função que abre uma conexão com o banco de dados MySQL.
 func OpenMySQLConnection() *sql.DB {
 	db, err := sql.Open("mysql", os.Getenv("DATABASE_URL"))
@@ -36,8 +40,9 @@ func OpenMySQLConnection() *sql.DB {
 	return db
 }
 
-func InitTables(db *sql.DB) error {
-	sql := `CREATE TABLE IF NOT EXISTS entregas (
+// Instruções de criação das tabelas, executadas em ordem na inicialização.
+var createTablesQueries = []string{
+	`CREATE TABLE IF NOT EXISTS entregas (
     id INT PRIMARY KEY AUTO_INCREMENT,
     cliente VARCHAR(255) NOT NULL,
     peso FLOAT NOT NULL,
@@ -52,9 +57,125 @@ func InitTables(db *sql.DB) error {
     latitude DOUBLE,
     longitude DOUBLE,
     data_inclusao TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    data_alteracao TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP);`
+    data_alteracao TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP);`,
+
+	`CREATE TABLE IF NOT EXISTS chaves_api (
+    id INT PRIMARY KEY AUTO_INCREMENT,
+    nome VARCHAR(255) NOT NULL,
+    prefixo VARCHAR(20) NOT NULL,
+    hash CHAR(64) NOT NULL UNIQUE,
+    escopos VARCHAR(100) NOT NULL,
+    data_inclusao TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    data_revogacao TIMESTAMP NULL DEFAULT NULL);`,
+
+	`CREATE TABLE IF NOT EXISTS auditoria (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    entidade VARCHAR(50) NOT NULL,
+    entidade_id INT NOT NULL,
+    acao VARCHAR(20) NOT NULL,
+    ator VARCHAR(255) NOT NULL,
+    request_id VARCHAR(64) NOT NULL DEFAULT '',
+    ip VARCHAR(45) NOT NULL DEFAULT '',
+    alteracoes JSON NOT NULL,
+    data_inclusao TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_auditoria_entidade (entidade, entidade_id),
+    INDEX idx_auditoria_data_inclusao (data_inclusao));`,
+
+	`CREATE TABLE IF NOT EXISTS entregas_versoes (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    entrega_id INT NOT NULL,
+    versao INT NOT NULL,
+    acao VARCHAR(20) NOT NULL,
+    dados JSON NOT NULL,
+    data_versao TIMESTAMP(6) DEFAULT CURRENT_TIMESTAMP(6),
+    UNIQUE KEY uk_entregas_versoes (entrega_id, versao),
+    INDEX idx_entregas_versoes_data (entrega_id, data_versao),
+    FOREIGN KEY (entrega_id) REFERENCES entregas(id) ON DELETE CASCADE);`,
+}
+
+// Coluna adicionada a uma tabela após a sua criação.
+type column struct {
+	table      string
+	name       string
+	definition string // Tipo da coluna, seguido dos índices que dependem dela
+}
+
+// Colunas adicionadas às tabelas, executadas em ordem na inicialização apenas quando ainda não existem,
+// para que as bases criadas antes delas também sejam atualizadas. Novas colunas devem ser adicionadas ao final.
+var addedColumns = []column{
+	{"entregas", "motorista", `VARCHAR(255) NOT NULL DEFAULT ''`},
+	{"entregas", "data_exclusao", `TIMESTAMP NULL DEFAULT NULL`},
+
+	// Toda tabela com dados de um tenant deve possuir a coluna tenant_id, filtrada em todas as consultas.
+	// Os registros existentes pertencem ao tenant padrão.
+	{"entregas", "tenant_id", `VARCHAR(64) NOT NULL DEFAULT 'default', ADD INDEX idx_entregas_tenant (tenant_id, id)`},
+	{"entregas_versoes", "tenant_id", `VARCHAR(64) NOT NULL DEFAULT 'default', ADD INDEX idx_entregas_versoes_tenant (tenant_id, entrega_id)`},
+	{"auditoria", "tenant_id", `VARCHAR(64) NOT NULL DEFAULT 'default', ADD INDEX idx_auditoria_tenant (tenant_id, data_inclusao)`},
+	{"chaves_api", "tenant_id", `VARCHAR(64) NOT NULL DEFAULT 'default', ADD INDEX idx_chaves_api_tenant (tenant_id, id)`},
+}
+
+var (
+	columnExistsQuery = `SELECT COUNT(*) FROM information_schema.columns
+    WHERE table_schema = DATABASE() AND table_name = ? AND column_name = ?`
 
-	_, err := db.Exec(sql)
+	// Registra a versão inicial das entregas sem histórico, criadas antes do versionamento.
+	// As datas são formatadas em UTC, mesmo fuso com que o driver (loc padrão) interpreta as colunas TIMESTAMP.
+	createInitialVersionsQuery = `INSERT INTO entregas_versoes (tenant_id, entrega_id, versao, acao, dados, data_versao)
+    SELECT e.tenant_id, e.id, 1, 'create', JSON_OBJECT(
+        'id', e.id,
+        'cliente', e.cliente,
+        'peso', e.peso,
+        'endereco', e.endereco,
+        'logradouro', e.logradouro,
+        'numero', e.numero,
+        'bairro', e.bairro,
+        'complemento', e.complemento,
+        'cidade', e.cidade,
+        'estado', e.estado,
+        'pais', e.pais,
+        'latitude', e.latitude,
+        'longitude', e.longitude,
+        'data_inclusao', DATE_FORMAT(e.data_inclusao, '%Y-%m-%dT%H:%i:%sZ'),
+        'data_alteracao', DATE_FORMAT(e.data_alteracao, '%Y-%m-%dT%H:%i:%sZ'),
+        'motorista', e.motorista,
+        'data_exclusao', DATE_FORMAT(e.data_exclusao, '%Y-%m-%dT%H:%i:%sZ')),
+        e.data_alteracao
+    FROM entregas e
+    WHERE NOT EXISTS (SELECT 1 FROM entregas_versoes v WHERE v.entrega_id = e.id)`
+)
+
+// Função responsável por criar as tabelas e adicionar as colunas que ainda não existem.
+func InitTables(db *sql.DB) error {
+	for _, query := range createTablesQueries {
+		if _, err := db.Exec(query); err != nil {
+			return err
+		}
+	}
+
+	for _, c := range addedColumns {
+		if err := addColumn(db, c); err != nil {
+			return fmt.Errorf("erro ao adicionar a coluna %s.%s: %w", c.table, c.name, err)
+		}
+	}
+
+	if _, err := db.Exec(createInitialVersionsQuery); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Função responsável por adicionar a coluna à tabela, caso ela ainda não exista.
+func addColumn(db *sql.DB, c column) error {
+	var count int
+	if err := db.QueryRow(columnExistsQuery, c.table, c.name).Scan(&count); err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return nil
+	}
 
+	_, err := db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", c.table, c.name, c.definition))
 	return err
 }
@@ -3,10 +3,13 @@ package db
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"regexp"
 	"testing"
 	"time"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/docker/go-connections/nat"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/stretchr/testify/assert"
@@ -14,6 +17,69 @@ import (
 	"github.com/testcontainers/testcontainers-go/wait"
 )
 
+// Testes da criação das tabelas
+
+func TestInitTables(t *testing.T) {
+	tests := []struct {
+		name   string
+		exists bool
+	}{
+		{"base criada antes das colunas", false},
+		{"base atualizada", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			for _, query := range createTablesQueries {
+				mock.ExpectExec(regexp.QuoteMeta(query)).WillReturnResult(sqlmock.NewResult(0, 0))
+			}
+
+			for _, c := range addedColumns {
+				count := 0
+				if tt.exists {
+					count = 1
+				}
+				mock.ExpectQuery(`SELECT COUNT\(\*\) FROM information_schema.columns`).
+					WithArgs(c.table, c.name).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(count))
+
+				// As colunas existentes não são alteradas
+				if !tt.exists {
+					mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", c.table, c.name, c.definition))).
+						WillReturnResult(sqlmock.NewResult(0, 0))
+				}
+			}
+
+			mock.ExpectExec(`INSERT INTO entregas_versoes .* WHERE NOT EXISTS`).WillReturnResult(sqlmock.NewResult(0, 0))
+
+			assert.NoError(t, InitTables(db))
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestInitTables_Error(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	for _, query := range createTablesQueries {
+		mock.ExpectExec(regexp.QuoteMeta(query)).WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM information_schema.columns`).
+		WithArgs("entregas", "motorista").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectExec(`ALTER TABLE entregas ADD COLUMN motorista`).WillReturnError(errors.New("access denied"))
+
+	err = InitTables(db)
+	assert.ErrorContains(t, err, "entregas.motorista")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 // Teste de integração utilizando testcontainers para testar a conexão com o banco de dados
 
 func setupMySQLContainer(t *testing.T) (*sql.DB, func()) {
@@ -77,10 +143,12 @@ func TestInitTablesWithContainer(t *testing.T) {
 	err := InitTables(db)
 	assert.NoError(t, err)
 
-	var tableExists bool
-	row := db.QueryRow("SELECT COUNT(*) > 0 FROM information_schema.tables WHERE table_name = 'entregas'")
-	err = row.Scan(&tableExists)
+	for _, table := range Tables {
+		var tableExists bool
+		row := db.QueryRow("SELECT COUNT(*) > 0 FROM information_schema.tables WHERE table_name = ?", table)
+		err = row.Scan(&tableExists)
 
-	assert.NoError(t, err)
-	assert.True(t, tableExists)
+		assert.NoError(t, err)
+		assert.True(t, tableExists, table)
+	}
 }
@@ -0,0 +1,84 @@
+package env
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Função responsável por buscar uma variável de ambiente do tipo string.
+// Retorna o valor padrão caso a variável não esteja definida.
+func GetString(key string, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		return value
+	}
+	return fallback
+}
+
+// Função responsável por buscar uma variável de ambiente do tipo int.
+// Retorna o valor padrão caso a variável não esteja definida ou seja inválida.
+func GetInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+// Função responsável por buscar uma variável de ambiente do tipo float64.
+// Retorna o valor padrão caso a variável não esteja definida ou seja inválida.
+func GetFloat(key string, fallback float64) float64 {
+	value, err := strconv.ParseFloat(os.Getenv(key), 64)
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+// Função responsável por buscar uma variável de ambiente do tipo bool.
+// Retorna o valor padrão caso a variável não esteja definida ou seja inválida.
+func GetBool(key string, fallback bool) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+// Função responsável por buscar uma variável de ambiente do tipo time.Duration (ex: "5s", "1m").
+// Retorna o valor padrão caso a variável não esteja definida ou seja inválida.
+func GetDuration(key string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+// Função responsável por buscar uma variável de ambiente do tipo time.Time no formato RFC 3339 (ex: "2027-01-01T00:00:00Z").
+// Retorna o valor padrão caso a variável não esteja definida ou seja inválida.
+func GetTime(key string, fallback time.Time) time.Time {
+	value, err := time.Parse(time.RFC3339, os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+// Função responsável por buscar uma variável de ambiente com uma lista separada por vírgulas.
+// Retorna o valor padrão caso a variável não esteja definida.
+func GetList(key string, fallback []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	items := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
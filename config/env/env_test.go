@@ -0,0 +1,64 @@
+package env
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Testes de leitura das variáveis de ambiente
+
+func TestGetString(t *testing.T) {
+	t.Setenv("ENV_TEST_STRING", "valor")
+
+	assert.Equal(t, "valor", GetString("ENV_TEST_STRING", "padrao"))
+	assert.Equal(t, "padrao", GetString("ENV_TEST_UNDEFINED", "padrao"))
+}
+
+func TestGetInt(t *testing.T) {
+	t.Setenv("ENV_TEST_INT", "42")
+	t.Setenv("ENV_TEST_INVALID", "abc")
+
+	assert.Equal(t, 42, GetInt("ENV_TEST_INT", 1))
+	assert.Equal(t, 1, GetInt("ENV_TEST_INVALID", 1))
+	assert.Equal(t, 1, GetInt("ENV_TEST_UNDEFINED", 1))
+}
+
+func TestGetFloat(t *testing.T) {
+	t.Setenv("ENV_TEST_FLOAT", "10.5")
+
+	assert.Equal(t, 10.5, GetFloat("ENV_TEST_FLOAT", 1))
+	assert.Equal(t, 1.0, GetFloat("ENV_TEST_UNDEFINED", 1))
+}
+
+func TestGetBool(t *testing.T) {
+	t.Setenv("ENV_TEST_BOOL", "true")
+
+	assert.True(t, GetBool("ENV_TEST_BOOL", false))
+	assert.False(t, GetBool("ENV_TEST_UNDEFINED", false))
+}
+
+func TestGetDuration(t *testing.T) {
+	t.Setenv("ENV_TEST_DURATION", "5s")
+
+	assert.Equal(t, 5*time.Second, GetDuration("ENV_TEST_DURATION", time.Second))
+	assert.Equal(t, time.Second, GetDuration("ENV_TEST_UNDEFINED", time.Second))
+}
+
+func TestGetTime(t *testing.T) {
+	t.Setenv("ENV_TEST_TIME", "2027-01-01T00:00:00Z")
+	t.Setenv("ENV_TEST_INVALID_TIME", "2027-01-01")
+	fallback := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC), GetTime("ENV_TEST_TIME", fallback))
+	assert.Equal(t, fallback, GetTime("ENV_TEST_INVALID_TIME", fallback))
+	assert.Equal(t, fallback, GetTime("ENV_TEST_UNDEFINED", fallback))
+}
+
+func TestGetList(t *testing.T) {
+	t.Setenv("ENV_TEST_LIST", "a, b,,c ")
+
+	assert.Equal(t, []string{"a", "b", "c"}, GetList("ENV_TEST_LIST", nil))
+	assert.Equal(t, []string{"x"}, GetList("ENV_TEST_UNDEFINED", []string{"x"}))
+}
@@ -3,24 +3,53 @@ package server
 import (
 	"database/sql"
 	"net/http"
+	"time"
+
+	"github.com/samluiz/delivery-service/api/http/middleware"
+	"github.com/samluiz/delivery-service/config/env"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
 // Struct que representa o servidor HTTP.
 // Contém um mux e um banco de dados como atributos.
 type Server struct {
-	db     *sql.DB        // Banco de dados
-	Router *http.ServeMux // Mux (router)
+	db      *sql.DB        // Banco de dados
+	Router  *http.ServeMux // Mux (router)
+	handler http.Handler   // Router instrumentado com tracing, metadados da requisição, CORS e compressão
 }
 
 // Função responsável por instanciar um novo server.
 func NewServer(db *sql.DB) *Server {
+	router := http.NewServeMux()
+
+	// Comprimindo as respostas de acordo com o header Accept-Encoding
+	compressed := middleware.Compress(middleware.CompressionOptions{
+		MinSize: env.GetInt("COMPRESSION_MIN_BYTES", middleware.DefaultCompressionMinSize),
+	})(router)
+
+	// Liberando as origens configuradas, como o console de despacho servido em outro domínio
+	cors := middleware.CORS(middleware.CORSOptions{
+		AllowedOrigins:   env.GetList("CORS_ALLOWED_ORIGINS", nil),
+		AllowedMethods:   env.GetList("CORS_ALLOWED_METHODS", nil),
+		AllowedHeaders:   env.GetList("CORS_ALLOWED_HEADERS", middleware.DefaultCORSAllowedHeaders),
+		ExposedHeaders:   env.GetList("CORS_EXPOSED_HEADERS", middleware.DefaultCORSExposedHeaders),
+		AllowCredentials: env.GetBool("CORS_ALLOW_CREDENTIALS", false),
+		MaxAge:           env.GetDuration("CORS_MAX_AGE", 10*time.Minute),
+	}, router)(compressed)
+
 	return &Server{
 		db:     db,
-		Router: http.NewServeMux(),
+		Router: router,
+		// Extraindo o contexto W3C (traceparent) das requisições e criando o span raiz
+		handler: otelhttp.NewHandler(middleware.RequestMetadata(env.GetBool("TRUST_PROXY_HEADERS", false))(cors), "delivery-service",
+			otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+				return "HTTP " + r.Method
+			}),
+		),
 	}
 }
 
 // Delegando o método ServeHTTP para o request multiplexer (router).
 func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	s.Router.ServeHTTP(w, r)
+	s.handler.ServeHTTP(w, r)
 }
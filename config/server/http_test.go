@@ -1,12 +1,18 @@
 package server
 
 import (
+	"compress/gzip"
 	"database/sql"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Testes do servidor HTTP para garantir que ele está configurado corretamente
@@ -39,3 +45,90 @@ func TestServerServeHTTP(t *testing.T) {
 	body := recorder.Body.String()
 	assert.Equal(t, "OK", body)
 }
+
+func TestServerExtractsTraceParent(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	db := &sql.DB{}
+	server := NewServer(db)
+
+	var traceID string
+	server.Router.HandleFunc("/test", func(w http.ResponseWriter, r *http.Request) {
+		traceID = trace.SpanContextFromContext(r.Context()).TraceID().String()
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	recorder := httptest.NewRecorder()
+
+	server.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", traceID)
+}
+
+func TestServerCompressesResponses(t *testing.T) {
+	db := &sql.DB{}
+	server := NewServer(db)
+
+	body := strings.Repeat(`{"id":1,"cliente":"Cliente A"},`, 100)
+	server.Router.HandleFunc("/test", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	})
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	recorder := httptest.NewRecorder()
+
+	server.ServeHTTP(recorder, req)
+
+	assert.Equal(t, "gzip", recorder.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", recorder.Header().Get("Vary"))
+
+	reader, err := gzip.NewReader(recorder.Body)
+	assert.NoError(t, err)
+	decoded, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, body, string(decoded))
+}
+
+func TestServerHandlesCORSPreflight(t *testing.T) {
+	t.Setenv("CORS_ALLOWED_ORIGINS", "https://console.example.com")
+	t.Setenv("CORS_ALLOW_CREDENTIALS", "true")
+
+	db := &sql.DB{}
+	server := NewServer(db)
+
+	server.Router.HandleFunc("GET /v2/deliveries", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	server.Router.HandleFunc("POST /v2/deliveries", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	req := httptest.NewRequest("OPTIONS", "/v2/deliveries", nil)
+	req.Header.Set("Origin", "https://console.example.com")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	req.Header.Set("Access-Control-Request-Headers", "authorization, content-type")
+	recorder := httptest.NewRecorder()
+
+	server.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusNoContent, recorder.Code)
+	assert.Equal(t, "https://console.example.com", recorder.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "GET, HEAD, POST", recorder.Header().Get("Access-Control-Allow-Methods"))
+	assert.Equal(t, "true", recorder.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Equal(t, "600", recorder.Header().Get("Access-Control-Max-Age"))
+
+	req = httptest.NewRequest("POST", "/v2/deliveries", nil)
+	req.Header.Set("Origin", "https://console.example.com")
+	recorder = httptest.NewRecorder()
+
+	server.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	assert.Equal(t, "https://console.example.com", recorder.Header().Get("Access-Control-Allow-Origin"))
+	assert.Contains(t, recorder.Header().Get("Access-Control-Expose-Headers"), "X-Request-ID")
+}
@@ -0,0 +1,64 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// Função responsável por configurar as métricas da aplicação.
+// O exporter é escolhido pela variável OTEL_METRICS_EXPORTER:
+//   - "otlp": envia as métricas via OTLP/HTTP (endpoint em OTEL_EXPORTER_OTLP_ENDPOINT)
+//   - "stdout": escreve as métricas na saída padrão
+//   - "none" ou vazio: métricas desabilitadas
+//
+// O intervalo de envio segue a variável OTEL_METRIC_EXPORT_INTERVAL (padrão 60s).
+func InitMetrics(ctx context.Context) (ShutdownFunc, error) {
+	exporter, err := newMetricExporter(ctx, os.Getenv("OTEL_METRICS_EXPORTER"))
+	if err != nil {
+		return nil, err
+	}
+
+	// Métricas desabilitadas, o provider global continua sendo o noop
+	if exporter == nil {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithAttributes(attribute.String("service.name", defaultServiceName)),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter)),
+		sdkmetric.WithResource(res),
+	)
+	otel.SetMeterProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// Função responsável por instanciar o exporter de métricas de acordo com o tipo informado.
+func newMetricExporter(ctx context.Context, kind string) (sdkmetric.Exporter, error) {
+	switch kind {
+	case "", "none":
+		return nil, nil
+	case "otlp":
+		return otlpmetrichttp.New(ctx)
+	case "stdout":
+		return stdoutmetric.New(stdoutmetric.WithPrettyPrint())
+	default:
+		return nil, fmt.Errorf("exporter de métricas desconhecido: %q", kind)
+	}
+}
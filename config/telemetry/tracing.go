@@ -0,0 +1,108 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const (
+	// Nome padrão do serviço nos traces, pode ser sobrescrito pela variável OTEL_SERVICE_NAME.
+	defaultServiceName = "delivery-service"
+
+	// Arquivo padrão utilizado pelo exporter "file".
+	defaultTracesFile = "traces.json"
+)
+
+// Função responsável por desligar o provider de traces, enviando os spans pendentes.
+type ShutdownFunc func(ctx context.Context) error
+
+// Função responsável por configurar o tracing da aplicação.
+// O exporter é escolhido pela variável OTEL_TRACES_EXPORTER:
+//   - "otlp": envia os spans via OTLP/HTTP (endpoint em OTEL_EXPORTER_OTLP_ENDPOINT)
+//   - "stdout": escreve os spans na saída padrão
+//   - "file": escreve os spans no arquivo definido em OTEL_TRACES_FILE
+//   - "none" ou vazio: tracing desabilitado (apenas a propagação do contexto é configurada)
+func InitTracing(ctx context.Context) (ShutdownFunc, error) {
+	// Propagação do contexto no formato W3C (traceparent e baggage)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	exporter, closer, err := newExporter(ctx, os.Getenv("OTEL_TRACES_EXPORTER"))
+	if err != nil {
+		return nil, err
+	}
+
+	// Tracing desabilitado, o provider global continua sendo o noop
+	if exporter == nil {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithAttributes(attribute.String("service.name", defaultServiceName)),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if closer != nil {
+			closer.Close()
+		}
+		return err
+	}, nil
+}
+
+// Função responsável por instanciar o exporter de spans de acordo com o tipo informado.
+// Retorna também um io.Closer quando o exporter escreve em um arquivo.
+func newExporter(ctx context.Context, kind string) (sdktrace.SpanExporter, io.Closer, error) {
+	switch kind {
+	case "", "none":
+		return nil, nil, nil
+	case "otlp":
+		exporter, err := otlptracehttp.New(ctx)
+		return exporter, nil, err
+	case "stdout":
+		exporter, err := stdouttrace.New(stdouttrace.WithPrettyPrint())
+		return exporter, nil, err
+	case "file":
+		path := os.Getenv("OTEL_TRACES_FILE")
+		if path == "" {
+			path = defaultTracesFile
+		}
+
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(file))
+		if err != nil {
+			file.Close()
+			return nil, nil, err
+		}
+		return exporter, file, nil
+	default:
+		return nil, nil, fmt.Errorf("exporter de traces desconhecido: %q", kind)
+	}
+}
@@ -0,0 +1,71 @@
+package telemetry
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// Testes da configuração do tracing
+
+func TestInitTracing_Disabled(t *testing.T) {
+	t.Setenv("OTEL_TRACES_EXPORTER", "none")
+
+	shutdown, err := InitTracing(context.Background())
+	assert.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+	assert.NotNil(t, otel.GetTextMapPropagator())
+}
+
+func TestInitTracing_UnknownExporter(t *testing.T) {
+	t.Setenv("OTEL_TRACES_EXPORTER", "invalid")
+
+	shutdown, err := InitTracing(context.Background())
+	assert.Nil(t, shutdown)
+	assert.Error(t, err)
+}
+
+func TestInitTracing_FileExporter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traces.json")
+	t.Setenv("OTEL_TRACES_EXPORTER", "file")
+	t.Setenv("OTEL_TRACES_FILE", path)
+
+	shutdown, err := InitTracing(context.Background())
+	assert.NoError(t, err)
+
+	_, span := otel.Tracer("test").Start(context.Background(), "test-span")
+	assert.True(t, span.SpanContext().IsValid())
+	span.End()
+
+	assert.NoError(t, shutdown(context.Background()))
+
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "test-span")
+
+	// Restaurando o provider noop para não afetar outros testes
+	otel.SetTracerProvider(noop.NewTracerProvider())
+}
+
+// Testes da configuração das métricas
+
+func TestInitMetrics_Disabled(t *testing.T) {
+	t.Setenv("OTEL_METRICS_EXPORTER", "none")
+
+	shutdown, err := InitMetrics(context.Background())
+	assert.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+}
+
+func TestInitMetrics_UnknownExporter(t *testing.T) {
+	t.Setenv("OTEL_METRICS_EXPORTER", "invalid")
+
+	shutdown, err := InitMetrics(context.Background())
+	assert.Nil(t, shutdown)
+	assert.Error(t, err)
+}
@@ -0,0 +1,110 @@
+package apikey
+
+import (
+	"strings"
+	"time"
+)
+
+var (
+	TABLE_NAME = "chaves_api"
+)
+
+// Escopo de acesso concedido a uma chave de API.
+type Scope string
+
+const (
+	ScopeRead  Scope = "read"  // Consultas
+	ScopeWrite Scope = "write" // Criação, atualização e exclusão individual
+	ScopeAdmin Scope = "admin" // Operações administrativas (inclui todos os outros escopos)
+)
+
+type APIKey struct {
+	ID            int        `db:"id"`
+	Nome          string     `db:"nome"`
+	Prefixo       string     `db:"prefixo"`
+	Hash          string     `db:"hash"`
+	Escopos       []Scope    `db:"escopos"`
+	DataInclusao  time.Time  `db:"data_inclusao"`
+	DataRevogacao *time.Time `db:"data_revogacao"`
+	TenantID      string     `db:"tenant_id"`
+}
+
+type CreateAPIKeyRequest struct {
+	Nome    string   `json:"nome" validate:"required,max=255"`
+	Escopos []string `json:"escopos" validate:"required,min=1,dive,oneof=read write admin"`
+}
+
+type APIKeyResponse struct {
+	ID            int        `json:"id"`
+	Nome          string     `json:"nome"`
+	Prefixo       string     `json:"prefixo"`
+	Escopos       []Scope    `json:"escopos"`
+	DataInclusao  time.Time  `json:"data_inclusao"`
+	DataRevogacao *time.Time `json:"data_revogacao,omitempty"`
+}
+
+// Resposta da criação de uma chave, a única vez em que a chave em texto puro é exibida.
+type CreateAPIKeyResponse struct {
+	APIKeyResponse
+	Chave string `json:"chave"`
+}
+
+// Função responsável por verificar se a chave possui o escopo informado.
+// O escopo admin concede acesso a todos os outros escopos.
+func (k APIKey) HasScope(scope Scope) bool {
+	for _, s := range k.Escopos {
+		if s == scope || s == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// Função responsável por verificar se a chave foi revogada.
+func (k APIKey) IsRevoked() bool {
+	return k.DataRevogacao != nil
+}
+
+func (k APIKey) ToAPIKeyResponse() *APIKeyResponse {
+	return &APIKeyResponse{
+		ID:            k.ID,
+		Nome:          k.Nome,
+		Prefixo:       k.Prefixo,
+		Escopos:       k.Escopos,
+		DataInclusao:  k.DataInclusao,
+		DataRevogacao: k.DataRevogacao,
+	}
+}
+
+// Função responsável por serializar os escopos para a coluna do banco de dados.
+func joinScopes(scopes []Scope) string {
+	values := make([]string, len(scopes))
+	for i, scope := range scopes {
+		values[i] = string(scope)
+	}
+	return strings.Join(values, ",")
+}
+
+// Função responsável por desserializar os escopos da coluna do banco de dados.
+func splitScopes(value string) []Scope {
+	scopes := make([]Scope, 0)
+	for _, s := range strings.Split(value, ",") {
+		if s != "" {
+			scopes = append(scopes, Scope(s))
+		}
+	}
+	return scopes
+}
+
+var (
+	insertAPIKeyQuery = `INSERT INTO chaves_api (nome, prefixo, hash, escopos, tenant_id) VALUES (?, ?, ?, ?, ?)`
+
+	getAPIKeyQuery = `SELECT id, nome, prefixo, hash, escopos, data_inclusao, data_revogacao, tenant_id FROM chaves_api WHERE id = ? AND tenant_id = ?`
+
+	// A autenticação busca a chave em todos os tenants, é ela que define o tenant da requisição
+	getAPIKeyByHashQuery = `SELECT id, nome, prefixo, hash, escopos, data_inclusao, data_revogacao, tenant_id FROM chaves_api WHERE hash = ?`
+
+	getAPIKeysQuery = `SELECT id, nome, prefixo, hash, escopos, data_inclusao, data_revogacao, tenant_id FROM chaves_api WHERE tenant_id = ? ORDER BY id DESC`
+
+	revokeAPIKeyQuery = `UPDATE chaves_api SET data_revogacao = CURRENT_TIMESTAMP WHERE id = ? AND tenant_id = ? AND data_revogacao IS NULL`
+)
@@ -0,0 +1,209 @@
+package apikey
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/samluiz/delivery-service/internal/tenant"
+)
+
+type APIKeyRepository struct {
+	db *sql.DB
+}
+
+// As consultas são filtradas pelo tenant do contexto, exceto a busca pelo hash utilizada na autenticação.
+type IAPIKeyRepository interface {
+	CreateAPIKey(ctx context.Context, key *APIKey) (*APIKey, error)
+	GetAPIKey(ctx context.Context, id int) (*APIKey, error)
+	GetAPIKeyByHash(ctx context.Context, hash string) (*APIKey, error)
+	GetAPIKeys(ctx context.Context) ([]*APIKey, error)
+	RevokeAPIKey(ctx context.Context, id int) error
+}
+
+func NewAPIKeyRepository(db *sql.DB) IAPIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+// Interface comum entre *sql.Row e *sql.Rows para reaproveitar o scan.
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// Função responsável por escanear uma linha da tabela de chaves para o model.
+func scanAPIKey(row scanner) (*APIKey, error) {
+	var key APIKey
+	var escopos string
+	var dataRevogacao sql.NullTime
+
+	err := row.Scan(
+		&key.ID,
+		&key.Nome,
+		&key.Prefixo,
+		&key.Hash,
+		&escopos,
+		&key.DataInclusao,
+		&dataRevogacao,
+		&key.TenantID,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	key.Escopos = splitScopes(escopos)
+	if dataRevogacao.Valid {
+		key.DataRevogacao = &dataRevogacao.Time
+	}
+
+	return &key, nil
+}
+
+// Função responsável por inserir uma nova chave de API no banco de dados.
+func (r APIKeyRepository) CreateAPIKey(ctx context.Context, key *APIKey) (*APIKey, error) {
+	// Criando transação para possibilitar rollback em caso de erro
+	tx, err := r.db.BeginTx(ctx, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Executando a query usando o contexto e transação
+	res, err := tx.ExecContext(ctx, insertAPIKeyQuery,
+		key.Nome,
+		key.Prefixo,
+		key.Hash,
+		joinScopes(key.Escopos),
+		key.TenantID,
+	)
+
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Commitando a transação
+	err = tx.Commit()
+
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := res.LastInsertId()
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Buscando a chave recém-criada no tenant em que ela foi criada
+	return r.GetAPIKey(tenant.WithTenant(ctx, key.TenantID), int(id))
+}
+
+// Função responsável por buscar uma chave de API pelo seu ID.
+func (r APIKeyRepository) GetAPIKey(ctx context.Context, id int) (*APIKey, error) {
+	tenantID, err := tenant.Require(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := scanAPIKey(r.db.QueryRowContext(ctx, getAPIKeyQuery, id, tenantID))
+
+	if err != nil {
+		// Verificando se o erro aconteceu por não encontrar a chave
+		if err == sql.ErrNoRows {
+			return nil, ErrAPIKeyNotFound
+		}
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// Função responsável por buscar uma chave de API pelo hash do seu valor.
+func (r APIKeyRepository) GetAPIKeyByHash(ctx context.Context, hash string) (*APIKey, error) {
+	key, err := scanAPIKey(r.db.QueryRowContext(ctx, getAPIKeyByHashQuery, hash))
+
+	if err != nil {
+		// Verificando se o erro aconteceu por não encontrar a chave
+		if err == sql.ErrNoRows {
+			return nil, ErrAPIKeyNotFound
+		}
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// Função responsável por buscar todas as chaves de API do tenant.
+func (r APIKeyRepository) GetAPIKeys(ctx context.Context) ([]*APIKey, error) {
+	var keys []*APIKey = make([]*APIKey, 0)
+
+	tenantID, err := tenant.Require(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Executando a query de consulta sem necessidade de transação
+	rows, err := r.db.QueryContext(ctx, getAPIKeysQuery, tenantID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Fechando a conexão com o cursor em caso de erro
+	defer rows.Close()
+
+	// Iterando sobre os resultados da consulta
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+// Função responsável por revogar uma chave de API do tenant pelo seu ID.
+func (r APIKeyRepository) RevokeAPIKey(ctx context.Context, id int) error {
+	tenantID, err := tenant.Require(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	// Criando transação para possibilitar rollback em caso de erro
+	tx, err := r.db.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	// Executando a query usando o contexto e transação
+	res, err := tx.ExecContext(ctx, revokeAPIKeyQuery, id, tenantID)
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// Verificando se alguma chave ativa foi revogada
+	affected, err := res.RowsAffected()
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if affected == 0 {
+		tx.Rollback()
+		return ErrAPIKeyNotFound
+	}
+
+	// Commitando a transação
+	return tx.Commit()
+}
@@ -0,0 +1,175 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/samluiz/delivery-service/internal/tenant"
+	"github.com/stretchr/testify/assert"
+)
+
+var apiKeyColumns = []string{"id", "nome", "prefixo", "hash", "escopos", "data_inclusao", "data_revogacao", "tenant_id"}
+
+// Contexto das operações do tenant utilizado nos testes
+var tenantContext = tenant.WithTenant(context.Background(), "acme")
+
+// Testes das consultas na tabela de chaves de API
+
+func TestCreateAPIKeyRepository(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAPIKeyRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO chaves_api`).
+		WithArgs("Integração", "dsk_12345678", "hash", "read,write", "acme").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery(`SELECT (.+) FROM chaves_api WHERE id = \? AND tenant_id = \?`).
+		WithArgs(1, "acme").
+		WillReturnRows(sqlmock.NewRows(apiKeyColumns).
+			AddRow(1, "Integração", "dsk_12345678", "hash", "read,write", time.Now(), nil, "acme"))
+
+	// A chave é criada no tenant informado, mesmo sem tenant no contexto
+	key, err := repo.CreateAPIKey(context.Background(), &APIKey{
+		Nome:     "Integração",
+		Prefixo:  "dsk_12345678",
+		Hash:     "hash",
+		Escopos:  []Scope{ScopeRead, ScopeWrite},
+		TenantID: "acme",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, key.ID)
+	assert.Equal(t, "acme", key.TenantID)
+	assert.Equal(t, []Scope{ScopeRead, ScopeWrite}, key.Escopos)
+	assert.Nil(t, key.DataRevogacao)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetAPIKeyByHashRepository(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAPIKeyRepository(db)
+	revokedAt := time.Now()
+
+	mock.ExpectQuery(`SELECT (.+) FROM chaves_api WHERE hash = \?`).
+		WithArgs("hash").
+		WillReturnRows(sqlmock.NewRows(apiKeyColumns).
+			AddRow(1, "Integração", "dsk_12345678", "hash", "admin", time.Now(), revokedAt, "acme"))
+
+	key, err := repo.GetAPIKeyByHash(context.Background(), "hash")
+
+	assert.NoError(t, err)
+	assert.True(t, key.IsRevoked())
+	assert.Equal(t, "acme", key.TenantID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetAPIKeyByHashRepository_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAPIKeyRepository(db)
+
+	mock.ExpectQuery(`SELECT (.+) FROM chaves_api WHERE hash = \?`).
+		WithArgs("hash").
+		WillReturnRows(sqlmock.NewRows(apiKeyColumns))
+
+	key, err := repo.GetAPIKeyByHash(context.Background(), "hash")
+
+	assert.Nil(t, key)
+	assert.ErrorIs(t, err, ErrAPIKeyNotFound)
+}
+
+func TestGetAPIKeysRepository(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAPIKeyRepository(db)
+
+	mock.ExpectQuery(`SELECT (.+) FROM chaves_api WHERE tenant_id = \? ORDER BY id DESC`).
+		WithArgs("acme").
+		WillReturnRows(sqlmock.NewRows(apiKeyColumns).
+			AddRow(2, "B", "dsk_22222222", "hash2", "read", time.Now(), nil, "acme").
+			AddRow(1, "A", "dsk_11111111", "hash1", "admin", time.Now(), nil, "acme"))
+
+	keys, err := repo.GetAPIKeys(tenantContext)
+
+	assert.NoError(t, err)
+	assert.Len(t, keys, 2)
+}
+
+func TestGetAPIKeysRepository_TenantRequired(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAPIKeyRepository(db)
+
+	_, err = repo.GetAPIKeys(context.Background())
+	assert.ErrorIs(t, err, tenant.ErrTenantRequired)
+	assert.ErrorIs(t, repo.RevokeAPIKey(context.Background(), 1), tenant.ErrTenantRequired)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRevokeAPIKeyRepository(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAPIKeyRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE chaves_api SET data_revogacao`).
+		WithArgs(1, "acme").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.RevokeAPIKey(tenantContext, 1))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRevokeAPIKeyRepository_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAPIKeyRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE chaves_api SET data_revogacao`).
+		WithArgs(1, "acme").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	assert.ErrorIs(t, repo.RevokeAPIKey(tenantContext, 1), ErrAPIKeyNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateAPIKeyRepository_ExecError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAPIKeyRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO chaves_api`).WillReturnError(errors.New("exec error"))
+	mock.ExpectRollback()
+
+	key, err := repo.CreateAPIKey(context.Background(), &APIKey{})
+
+	assert.Nil(t, key)
+	assert.Equal(t, "exec error", err.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
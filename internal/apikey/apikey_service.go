@@ -0,0 +1,134 @@
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"github.com/samluiz/delivery-service/internal/tenant"
+)
+
+const (
+	// Prefixo que identifica as chaves emitidas pelo serviço
+	keyPrefix = "dsk_"
+
+	// Quantidade de bytes aleatórios de cada chave
+	keyBytes = 32
+
+	// Quantidade de caracteres da chave armazenados em texto puro para identificação
+	displayPrefixLength = len(keyPrefix) + 8
+)
+
+type APIKeyService struct {
+	repository IAPIKeyRepository
+}
+
+type IAPIKeyService interface {
+	CreateAPIKey(ctx context.Context, request *CreateAPIKeyRequest) (*CreateAPIKeyResponse, error)
+	GetAPIKeys(ctx context.Context) ([]*APIKeyResponse, error)
+	RevokeAPIKey(ctx context.Context, id int) error
+	Authenticate(ctx context.Context, rawKey string) (*APIKey, error)
+}
+
+func NewAPIKeyService(repository IAPIKeyRepository) IAPIKeyService {
+	return &APIKeyService{repository: repository}
+}
+
+// Função responsável por emitir uma nova chave de API.
+// Apenas o hash da chave é armazenado, o valor em texto puro é retornado uma única vez.
+// A chave pertence ao tenant do contexto, que passa a ser o tenant das requisições autenticadas por ela.
+func (s APIKeyService) CreateAPIKey(ctx context.Context, request *CreateAPIKeyRequest) (*CreateAPIKeyResponse, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rawKey, err := generateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := make([]Scope, len(request.Escopos))
+	for i, scope := range request.Escopos {
+		scopes[i] = Scope(scope)
+	}
+
+	key, err := s.repository.CreateAPIKey(ctx, &APIKey{
+		Nome:     request.Nome,
+		Prefixo:  rawKey[:displayPrefixLength],
+		Hash:     HashKey(rawKey),
+		Escopos:  scopes,
+		TenantID: tenantID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &CreateAPIKeyResponse{
+		APIKeyResponse: *key.ToAPIKeyResponse(),
+		Chave:          rawKey,
+	}, nil
+}
+
+func (s APIKeyService) GetAPIKeys(ctx context.Context) ([]*APIKeyResponse, error) {
+	keys, err := s.repository.GetAPIKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]*APIKeyResponse, len(keys))
+	for i, key := range keys {
+		response[i] = key.ToAPIKeyResponse()
+	}
+	return response, nil
+}
+
+func (s APIKeyService) RevokeAPIKey(ctx context.Context, id int) error {
+	return s.repository.RevokeAPIKey(ctx, id)
+}
+
+// Função responsável por autenticar uma chave de API recebida na requisição.
+// Retorna ErrAPIKeyInvalid para chaves inexistentes ou revogadas.
+func (s APIKeyService) Authenticate(ctx context.Context, rawKey string) (*APIKey, error) {
+	if rawKey == "" {
+		return nil, ErrAPIKeyInvalid
+	}
+
+	key, err := s.repository.GetAPIKeyByHash(ctx, HashKey(rawKey))
+	if err != nil {
+		if errors.Is(err, ErrAPIKeyNotFound) {
+			return nil, ErrAPIKeyInvalid
+		}
+		return nil, err
+	}
+
+	if key.IsRevoked() {
+		return nil, ErrAPIKeyInvalid
+	}
+
+	return key, nil
+}
+
+// Função responsável por verificar se a credencial possui o formato das chaves emitidas pelo serviço.
+func IsAPIKey(credential string) bool {
+	return strings.HasPrefix(credential, keyPrefix)
+}
+
+// Função responsável por calcular o hash (SHA-256) de uma chave.
+// As chaves possuem alta entropia, portanto um hash rápido é suficiente.
+func HashKey(rawKey string) string {
+	sum := sha256.Sum256([]byte(rawKey))
+	return hex.EncodeToString(sum[:])
+}
+
+// Função responsável por gerar uma nova chave aleatória.
+func generateKey() (string, error) {
+	bytes := make([]byte, keyBytes)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return keyPrefix + hex.EncodeToString(bytes), nil
+}
@@ -0,0 +1,137 @@
+package apikey
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samluiz/delivery-service/internal/tenant"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
+// Mocks de funções do repositório que o service chama
+
+func (m *MockAPIKeyRepository) CreateAPIKey(ctx context.Context, key *APIKey) (*APIKey, error) {
+	args := m.Called(ctx, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) GetAPIKey(ctx context.Context, id int) (*APIKey, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) GetAPIKeyByHash(ctx context.Context, hash string) (*APIKey, error) {
+	args := m.Called(ctx, hash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) GetAPIKeys(ctx context.Context) ([]*APIKey, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) RevokeAPIKey(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// Testes das funções do service de chaves de API
+
+func TestCreateAPIKey(t *testing.T) {
+	mockRepo := new(MockAPIKeyRepository)
+	service := NewAPIKeyService(mockRepo)
+
+	var stored *APIKey
+	mockRepo.On("CreateAPIKey", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { stored = args.Get(1).(*APIKey) }).
+		Return(&APIKey{ID: 1, Nome: "Integração", Escopos: []Scope{ScopeRead}}, nil)
+
+	response, err := service.CreateAPIKey(tenantContext, &CreateAPIKeyRequest{
+		Nome:    "Integração",
+		Escopos: []string{"read"},
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(response.Chave, keyPrefix))
+	assert.Equal(t, 1, response.ID)
+
+	// Apenas o hash e o prefixo da chave são persistidos
+	assert.Equal(t, HashKey(response.Chave), stored.Hash)
+	assert.Equal(t, response.Chave[:displayPrefixLength], stored.Prefixo)
+	assert.NotContains(t, stored.Hash, response.Chave)
+	assert.Equal(t, "acme", stored.TenantID)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateAPIKey_TenantRequired(t *testing.T) {
+	mockRepo := new(MockAPIKeyRepository)
+	service := NewAPIKeyService(mockRepo)
+
+	_, err := service.CreateAPIKey(context.Background(), &CreateAPIKeyRequest{Nome: "Integração", Escopos: []string{"read"}})
+
+	assert.ErrorIs(t, err, tenant.ErrTenantRequired)
+	mockRepo.AssertNotCalled(t, "CreateAPIKey", mock.Anything, mock.Anything)
+}
+
+func TestAuthenticate(t *testing.T) {
+	mockRepo := new(MockAPIKeyRepository)
+	service := NewAPIKeyService(mockRepo)
+
+	key := &APIKey{ID: 1, Escopos: []Scope{ScopeRead}}
+	mockRepo.On("GetAPIKeyByHash", mock.Anything, HashKey("dsk_valid")).Return(key, nil)
+
+	response, err := service.Authenticate(context.Background(), "dsk_valid")
+
+	assert.NoError(t, err)
+	assert.Equal(t, key, response)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAuthenticate_Invalid(t *testing.T) {
+	now := time.Now()
+	mockRepo := new(MockAPIKeyRepository)
+	service := NewAPIKeyService(mockRepo)
+
+	mockRepo.On("GetAPIKeyByHash", mock.Anything, HashKey("dsk_unknown")).Return(nil, ErrAPIKeyNotFound)
+	mockRepo.On("GetAPIKeyByHash", mock.Anything, HashKey("dsk_revoked")).Return(&APIKey{ID: 2, DataRevogacao: &now}, nil)
+
+	_, err := service.Authenticate(context.Background(), "")
+	assert.ErrorIs(t, err, ErrAPIKeyInvalid)
+
+	_, err = service.Authenticate(context.Background(), "dsk_unknown")
+	assert.ErrorIs(t, err, ErrAPIKeyInvalid)
+
+	_, err = service.Authenticate(context.Background(), "dsk_revoked")
+	assert.ErrorIs(t, err, ErrAPIKeyInvalid)
+}
+
+func TestRevokeAPIKey(t *testing.T) {
+	mockRepo := new(MockAPIKeyRepository)
+	service := NewAPIKeyService(mockRepo)
+
+	mockRepo.On("RevokeAPIKey", mock.Anything, 1).Return(nil)
+
+	assert.NoError(t, service.RevokeAPIKey(context.Background(), 1))
+	mockRepo.AssertExpectations(t)
+}
+
+func TestIsAPIKey(t *testing.T) {
+	assert.True(t, IsAPIKey("dsk_0123456789abcdef"))
+	assert.False(t, IsAPIKey("eyJhbGciOiJIUzI1NiJ9.e30.signature"))
+}
@@ -0,0 +1,42 @@
+package apikey
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Testes das regras de escopo e revogação
+
+func TestHasScope(t *testing.T) {
+	reader := APIKey{Escopos: []Scope{ScopeRead}}
+	writer := APIKey{Escopos: []Scope{ScopeRead, ScopeWrite}}
+	admin := APIKey{Escopos: []Scope{ScopeAdmin}}
+
+	assert.True(t, reader.HasScope(ScopeRead))
+	assert.False(t, reader.HasScope(ScopeWrite))
+	assert.False(t, reader.HasScope(ScopeAdmin))
+
+	assert.True(t, writer.HasScope(ScopeWrite))
+	assert.False(t, writer.HasScope(ScopeAdmin))
+
+	assert.True(t, admin.HasScope(ScopeRead))
+	assert.True(t, admin.HasScope(ScopeWrite))
+	assert.True(t, admin.HasScope(ScopeAdmin))
+}
+
+func TestIsRevoked(t *testing.T) {
+	now := time.Now()
+
+	assert.False(t, APIKey{}.IsRevoked())
+	assert.True(t, APIKey{DataRevogacao: &now}.IsRevoked())
+}
+
+func TestScopesSerialization(t *testing.T) {
+	scopes := []Scope{ScopeRead, ScopeWrite}
+
+	assert.Equal(t, "read,write", joinScopes(scopes))
+	assert.Equal(t, scopes, splitScopes("read,write"))
+	assert.Empty(t, splitScopes(""))
+}
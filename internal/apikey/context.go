@@ -0,0 +1,16 @@
+package apikey
+
+import "context"
+
+type contextKey struct{}
+
+// Função responsável por adicionar a chave autenticada ao contexto da requisição.
+func WithAPIKey(ctx context.Context, key *APIKey) context.Context {
+	return context.WithValue(ctx, contextKey{}, key)
+}
+
+// Função responsável por buscar a chave autenticada no contexto da requisição.
+func FromContext(ctx context.Context) (*APIKey, bool) {
+	key, ok := ctx.Value(contextKey{}).(*APIKey)
+	return key, ok
+}
@@ -0,0 +1,8 @@
+package apikey
+
+import "errors"
+
+var (
+	ErrAPIKeyNotFound = errors.New("api key not found")
+	ErrAPIKeyInvalid  = errors.New("api key invalid or revoked")
+)
@@ -0,0 +1,133 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/samluiz/delivery-service/internal/auth"
+	"github.com/samluiz/delivery-service/internal/requestmeta"
+	"github.com/samluiz/delivery-service/internal/tenant"
+)
+
+var (
+	TABLE_NAME = "auditoria"
+)
+
+// Ator registrado para operações executadas sem um principal autenticado (ex: jobs).
+const SystemActor = "system"
+
+// Entidades auditadas.
+const EntityDelivery = "delivery"
+
+// Ação auditada.
+type Action string
+
+const (
+	ActionCreate  Action = "create"  // Criação
+	ActionUpdate  Action = "update"  // Atualização
+	ActionDelete  Action = "delete"  // Exclusão lógica (lixeira)
+	ActionRestore Action = "restore" // Restauração da lixeira
+	ActionPurge   Action = "purge"   // Exclusão definitiva
+	ActionRevert  Action = "revert"  // Reversão para uma versão anterior
+)
+
+// Alteração de um campo, com os valores antes e depois da operação.
+type Change struct {
+	Campo  string `json:"campo"`
+	Antes  any    `json:"antes"`
+	Depois any    `json:"depois"`
+}
+
+type Entry struct {
+	ID           int64     `db:"id"`
+	Entidade     string    `db:"entidade"`
+	EntidadeID   int       `db:"entidade_id"`
+	Acao         Action    `db:"acao"`
+	Ator         string    `db:"ator"`
+	RequestID    string    `db:"request_id"`
+	IP           string    `db:"ip"`
+	Alteracoes   []Change  `db:"alteracoes"`
+	DataInclusao time.Time `db:"data_inclusao"`
+	TenantID     string    `db:"tenant_id"`
+}
+
+// Filtros da consulta da auditoria. Campos vazios não são aplicados.
+type Filter struct {
+	Entidade      string
+	EntidadeID    int
+	Ator          string
+	Acao          Action
+	De            *time.Time
+	Ate           *time.Time
+	Pagina        int
+	TamanhoPagina int
+}
+
+type EntryResponse struct {
+	ID           int64     `json:"id"`
+	Entidade     string    `json:"entidade"`
+	EntidadeID   int       `json:"entidade_id"`
+	Acao         Action    `json:"acao"`
+	Ator         string    `json:"ator"`
+	RequestID    string    `json:"request_id"`
+	IP           string    `json:"ip"`
+	Alteracoes   []Change  `json:"alteracoes"`
+	DataInclusao time.Time `json:"data_inclusao"`
+}
+
+// Página de registros da auditoria.
+type EntriesPageResponse struct {
+	Itens         []*EntryResponse `json:"itens"`
+	Pagina        int              `json:"pagina"`
+	TamanhoPagina int              `json:"tamanho_pagina"`
+	Total         int64            `json:"total"`
+}
+
+func (e Entry) ToEntryResponse() *EntryResponse {
+	return &EntryResponse{
+		ID:           e.ID,
+		Entidade:     e.Entidade,
+		EntidadeID:   e.EntidadeID,
+		Acao:         e.Acao,
+		Ator:         e.Ator,
+		RequestID:    e.RequestID,
+		IP:           e.IP,
+		Alteracoes:   e.Alteracoes,
+		DataInclusao: e.DataInclusao,
+	}
+}
+
+// Função responsável por montar um registro de auditoria a partir do contexto da operação.
+// O ator é o principal autenticado, o tenant vem do contexto e o request ID e IP dos metadados da requisição.
+func NewEntry(ctx context.Context, entity string, entityID int, action Action, changes []Change) *Entry {
+	entry := &Entry{
+		Entidade:   entity,
+		EntidadeID: entityID,
+		Acao:       action,
+		Ator:       SystemActor,
+		Alteracoes: changes,
+	}
+
+	if principal, ok := auth.FromContext(ctx); ok {
+		entry.Ator = principal.Subject
+	}
+
+	if id, ok := tenant.FromContext(ctx); ok {
+		entry.TenantID = id
+	}
+
+	if metadata, ok := requestmeta.FromContext(ctx); ok {
+		entry.RequestID = metadata.RequestID
+		entry.IP = metadata.ClientIP
+	}
+
+	return entry
+}
+
+var (
+	insertEntryQuery = `INSERT INTO auditoria (entidade, entidade_id, acao, ator, request_id, ip, alteracoes, tenant_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+
+	getEntriesQuery = `SELECT id, entidade, entidade_id, acao, ator, request_id, ip, alteracoes, data_inclusao FROM auditoria`
+
+	countEntriesQuery = `SELECT COUNT(*) FROM auditoria`
+)
@@ -0,0 +1,168 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+
+	"github.com/samluiz/delivery-service/internal/tenant"
+)
+
+type AuditRepository struct {
+	db *sql.DB
+}
+
+type IAuditRepository interface {
+	Record(ctx context.Context, tx *sql.Tx, entry *Entry) error
+	GetEntries(ctx context.Context, filter *Filter) ([]*Entry, int64, error)
+}
+
+func NewAuditRepository(db *sql.DB) IAuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// Função responsável por gravar um registro de auditoria.
+// Recebe a transação da operação auditada, garantindo que ambos sejam gravados ou descartados juntos.
+// Registros sem tenant são recusados, para que não fiquem visíveis a nenhum tenant.
+func (r AuditRepository) Record(ctx context.Context, tx *sql.Tx, entry *Entry) error {
+	if entry.TenantID == "" {
+		return tenant.ErrTenantRequired
+	}
+
+	changes, err := json.Marshal(entry.Alteracoes)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, insertEntryQuery,
+		entry.Entidade,
+		entry.EntidadeID,
+		entry.Acao,
+		entry.Ator,
+		entry.RequestID,
+		entry.IP,
+		changes,
+		entry.TenantID,
+	)
+
+	return err
+}
+
+// Interface comum entre *sql.Row e *sql.Rows para reaproveitar o scan.
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// Função responsável por escanear uma linha da tabela de auditoria para o model.
+func scanEntry(row scanner) (*Entry, error) {
+	var entry Entry
+	var changes []byte
+
+	err := row.Scan(
+		&entry.ID,
+		&entry.Entidade,
+		&entry.EntidadeID,
+		&entry.Acao,
+		&entry.Ator,
+		&entry.RequestID,
+		&entry.IP,
+		&changes,
+		&entry.DataInclusao,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(changes, &entry.Alteracoes); err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// Função responsável por montar a cláusula WHERE a partir dos filtros informados.
+// Os valores são sempre passados como parâmetros, nunca concatenados na query.
+// Apenas os registros do tenant são consultados.
+func buildFilterClause(tenantID string, filter *Filter) (string, []any) {
+	conditions := []string{"tenant_id = ?"}
+	args := []any{tenantID}
+
+	if filter.Entidade != "" {
+		conditions = append(conditions, "entidade = ?")
+		args = append(args, filter.Entidade)
+	}
+	if filter.EntidadeID != 0 {
+		conditions = append(conditions, "entidade_id = ?")
+		args = append(args, filter.EntidadeID)
+	}
+	if filter.Ator != "" {
+		conditions = append(conditions, "ator = ?")
+		args = append(args, filter.Ator)
+	}
+	if filter.Acao != "" {
+		conditions = append(conditions, "acao = ?")
+		args = append(args, filter.Acao)
+	}
+	if filter.De != nil {
+		conditions = append(conditions, "data_inclusao >= ?")
+		args = append(args, *filter.De)
+	}
+	if filter.Ate != nil {
+		conditions = append(conditions, "data_inclusao <= ?")
+		args = append(args, *filter.Ate)
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// Função responsável por buscar uma página dos registros de auditoria que satisfazem os filtros,
+// do mais recente para o mais antigo, junto com o total de registros.
+func (r AuditRepository) GetEntries(ctx context.Context, filter *Filter) ([]*Entry, int64, error) {
+	entries := make([]*Entry, 0)
+
+	tenantID, err := tenant.Require(ctx)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	where, args := buildFilterClause(tenantID, filter)
+
+	var total int64
+	err = r.db.QueryRowContext(ctx, countEntriesQuery+where, args...).Scan(&total)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	offset := (filter.Pagina - 1) * filter.TamanhoPagina
+	query := getEntriesQuery + where + " ORDER BY id DESC LIMIT ? OFFSET ?"
+
+	rows, err := r.db.QueryContext(ctx, query, append(args, filter.TamanhoPagina, offset)...)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Fechando a conexão com o cursor em caso de erro
+	defer rows.Close()
+
+	for rows.Next() {
+		entry, err := scanEntry(rows)
+
+		if err != nil {
+			return nil, 0, err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return entries, total, nil
+}
@@ -0,0 +1,90 @@
+package audit
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/samluiz/delivery-service/internal/tenant"
+	"github.com/stretchr/testify/assert"
+)
+
+// Testes das consultas na tabela de auditoria
+
+func TestRecord(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAuditRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO auditoria (entidade, entidade_id, acao, ator, request_id, ip, alteracoes, tenant_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`)).
+		WithArgs("delivery", 1, "update", "user-1", "req-1", "192.0.2.1", []byte(`[{"campo":"peso","antes":10,"depois":12.5}]`), "acme").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	tx, err := db.Begin()
+	assert.NoError(t, err)
+
+	err = repo.Record(context.Background(), tx, &Entry{
+		Entidade:   EntityDelivery,
+		EntidadeID: 1,
+		Acao:       ActionUpdate,
+		Ator:       "user-1",
+		RequestID:  "req-1",
+		IP:         "192.0.2.1",
+		Alteracoes: []Change{{Campo: "peso", Antes: 10, Depois: 12.5}},
+		TenantID:   "acme",
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Commit())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRecord_TenantRequired(t *testing.T) {
+	db, _, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAuditRepository(db)
+
+	// O registro é recusado antes de qualquer consulta
+	err = repo.Record(context.Background(), nil, &Entry{Entidade: EntityDelivery, EntidadeID: 1, Acao: ActionUpdate})
+	assert.ErrorIs(t, err, tenant.ErrTenantRequired)
+}
+
+func TestGetEntries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAuditRepository(db)
+
+	from := time.Now().Add(-time.Hour)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM auditoria WHERE tenant_id = ? AND entidade = ? AND entidade_id = ? AND acao = ? AND data_inclusao >= ?`)).
+		WithArgs("acme", "delivery", 1, "update", from).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(25))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, entidade, entidade_id, acao, ator, request_id, ip, alteracoes, data_inclusao FROM auditoria WHERE tenant_id = ? AND entidade = ? AND entidade_id = ? AND acao = ? AND data_inclusao >= ? ORDER BY id DESC LIMIT ? OFFSET ?`)).
+		WithArgs("acme", "delivery", 1, "update", from, 10, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "entidade", "entidade_id", "acao", "ator", "request_id", "ip", "alteracoes", "data_inclusao"}).
+			AddRow(15, "delivery", 1, "update", "user-1", "req-1", "192.0.2.1", []byte(`[{"campo":"cidade","antes":"A","depois":"B"}]`), time.Now()))
+
+	entries, total, err := repo.GetEntries(tenant.WithTenant(context.Background(), "acme"), &Filter{
+		Entidade:      EntityDelivery,
+		EntidadeID:    1,
+		Acao:          ActionUpdate,
+		De:            &from,
+		Pagina:        2,
+		TamanhoPagina: 10,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(25), total)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, []Change{{Campo: "cidade", Antes: "A", Depois: "B"}}, entries[0].Alteracoes)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
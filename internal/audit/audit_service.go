@@ -0,0 +1,53 @@
+package audit
+
+import "context"
+
+const (
+	// Tamanho de página padrão da consulta da auditoria
+	DefaultPageSize = 20
+
+	// Tamanho de página máximo da consulta da auditoria
+	MaxPageSize = 100
+)
+
+type AuditService struct {
+	repository IAuditRepository
+}
+
+type IAuditService interface {
+	GetEntries(ctx context.Context, filter *Filter) (*EntriesPageResponse, error)
+}
+
+func NewAuditService(repository IAuditRepository) IAuditService {
+	return &AuditService{repository: repository}
+}
+
+// Função responsável por consultar uma página dos registros de auditoria.
+// Página e tamanho de página fora dos limites são substituídos pelos valores padrão.
+func (s AuditService) GetEntries(ctx context.Context, filter *Filter) (*EntriesPageResponse, error) {
+	if filter == nil {
+		filter = &Filter{}
+	}
+	if filter.Pagina < 1 {
+		filter.Pagina = 1
+	}
+	if filter.TamanhoPagina < 1 || filter.TamanhoPagina > MaxPageSize {
+		filter.TamanhoPagina = DefaultPageSize
+	}
+
+	entries, total, err := s.repository.GetEntries(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &EntriesPageResponse{
+		Itens:         make([]*EntryResponse, len(entries)),
+		Pagina:        filter.Pagina,
+		TamanhoPagina: filter.TamanhoPagina,
+		Total:         total,
+	}
+	for i, entry := range entries {
+		response.Itens[i] = entry.ToEntryResponse()
+	}
+	return response, nil
+}
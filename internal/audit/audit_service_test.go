@@ -0,0 +1,59 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+// Mocks de funções do repositório que o service chama
+
+func (m *MockAuditRepository) Record(ctx context.Context, tx *sql.Tx, entry *Entry) error {
+	args := m.Called(ctx, tx, entry)
+	return args.Error(0)
+}
+
+func (m *MockAuditRepository) GetEntries(ctx context.Context, filter *Filter) ([]*Entry, int64, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]*Entry), args.Get(1).(int64), args.Error(2)
+}
+
+// Testes das funções do service de auditoria
+
+func TestGetEntriesService(t *testing.T) {
+	mockRepo := new(MockAuditRepository)
+	service := NewAuditService(mockRepo)
+
+	mockRepo.On("GetEntries", mock.Anything, &Filter{Entidade: EntityDelivery, Pagina: 1, TamanhoPagina: 50}).
+		Return([]*Entry{{ID: 1, Entidade: EntityDelivery}}, int64(1), nil)
+
+	response, err := service.GetEntries(context.Background(), &Filter{Entidade: EntityDelivery, TamanhoPagina: 50})
+
+	assert.NoError(t, err)
+	assert.Len(t, response.Itens, 1)
+	assert.Equal(t, 1, response.Pagina)
+	assert.Equal(t, int64(1), response.Total)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetEntriesService_PageSizeLimits(t *testing.T) {
+	mockRepo := new(MockAuditRepository)
+	service := NewAuditService(mockRepo)
+
+	mockRepo.On("GetEntries", mock.Anything, &Filter{Pagina: 1, TamanhoPagina: DefaultPageSize}).
+		Return([]*Entry{}, int64(0), nil)
+
+	response, err := service.GetEntries(context.Background(), &Filter{Pagina: -1, TamanhoPagina: MaxPageSize + 1})
+
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultPageSize, response.TamanhoPagina)
+	assert.Empty(t, response.Itens)
+	mockRepo.AssertExpectations(t)
+}
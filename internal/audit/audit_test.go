@@ -0,0 +1,88 @@
+package audit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/samluiz/delivery-service/internal/auth"
+	"github.com/samluiz/delivery-service/internal/requestmeta"
+	"github.com/samluiz/delivery-service/internal/tenant"
+	"github.com/stretchr/testify/assert"
+)
+
+type model struct {
+	ID            int        `db:"id"`
+	Cidade        string     `db:"cidade"`
+	Peso          float64    `db:"peso"`
+	DataAlteracao time.Time  `db:"data_alteracao"`
+	DataExclusao  *time.Time `db:"data_exclusao"`
+	interno       string
+}
+
+// Testes do cálculo das alterações campo a campo
+
+func TestDiff(t *testing.T) {
+	now := time.Now()
+
+	before := &model{ID: 1, Cidade: "Cidade A", Peso: 10, DataAlteracao: now}
+	after := &model{ID: 1, Cidade: "Cidade B", Peso: 10, DataAlteracao: now.Add(time.Second), DataExclusao: &now}
+
+	changes := Diff(before, after, "data_alteracao")
+
+	assert.Equal(t, []Change{
+		{Campo: "cidade", Antes: "Cidade A", Depois: "Cidade B"},
+		{Campo: "data_exclusao", Antes: nil, Depois: now},
+	}, changes)
+}
+
+func TestDiff_CreateAndDelete(t *testing.T) {
+	var empty *model
+	delivery := &model{ID: 1, Cidade: "Cidade A"}
+
+	// Na criação todos os campos preenchidos aparecem como alterados
+	created := Diff(empty, delivery)
+	assert.Contains(t, created, Change{Campo: "cidade", Antes: nil, Depois: "Cidade A"})
+	assert.NotContains(t, created, Change{Campo: "data_exclusao", Antes: nil, Depois: nil})
+
+	// Na exclusão definitiva todos os valores passam a ser nulos
+	purged := Diff(delivery, nil)
+	assert.Contains(t, purged, Change{Campo: "cidade", Antes: "Cidade A", Depois: nil})
+
+	assert.Nil(t, Diff(nil, nil))
+}
+
+func TestDiff_SameInstantInDifferentLocations(t *testing.T) {
+	now := time.Now()
+
+	before := &model{DataAlteracao: now}
+	after := &model{DataAlteracao: now.UTC()}
+
+	assert.Empty(t, Diff(before, after))
+}
+
+// Testes da montagem dos registros a partir do contexto
+
+func TestNewEntry(t *testing.T) {
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Type: auth.PrincipalAPIKey, Subject: "apikey:1"})
+	ctx = requestmeta.WithMetadata(ctx, requestmeta.Metadata{RequestID: "req-1", ClientIP: "192.0.2.1"})
+	ctx = tenant.WithTenant(ctx, "acme")
+
+	entry := NewEntry(ctx, EntityDelivery, 10, ActionUpdate, nil)
+
+	assert.Equal(t, "delivery", entry.Entidade)
+	assert.Equal(t, 10, entry.EntidadeID)
+	assert.Equal(t, ActionUpdate, entry.Acao)
+	assert.Equal(t, "apikey:1", entry.Ator)
+	assert.Equal(t, "req-1", entry.RequestID)
+	assert.Equal(t, "192.0.2.1", entry.IP)
+	assert.Equal(t, "acme", entry.TenantID)
+}
+
+func TestNewEntry_System(t *testing.T) {
+	entry := NewEntry(context.Background(), EntityDelivery, 10, ActionPurge, nil)
+
+	assert.Equal(t, SystemActor, entry.Ator)
+	assert.Empty(t, entry.RequestID)
+	assert.Empty(t, entry.TenantID)
+}
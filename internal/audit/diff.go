@@ -0,0 +1,75 @@
+package audit
+
+import (
+	"reflect"
+	"slices"
+	"time"
+)
+
+// Função responsável por calcular as alterações campo a campo entre duas versões de um model.
+// Os campos são identificados pela tag "db" e before ou after podem ser nulos (criação e exclusão).
+// Os campos ignorados (ex: datas mantidas pelo banco de dados) não são comparados.
+func Diff(before, after any, ignored ...string) []Change {
+	beforeValue, afterValue := indirect(reflect.ValueOf(before)), indirect(reflect.ValueOf(after))
+
+	// O tipo do model é obtido de qualquer uma das versões não nulas
+	var modelType reflect.Type
+	switch {
+	case beforeValue.IsValid():
+		modelType = beforeValue.Type()
+	case afterValue.IsValid():
+		modelType = afterValue.Type()
+	default:
+		return nil
+	}
+
+	changes := make([]Change, 0)
+
+	for i := 0; i < modelType.NumField(); i++ {
+		column := modelType.Field(i).Tag.Get("db")
+		if column == "" || slices.Contains(ignored, column) {
+			continue
+		}
+
+		previous, current := fieldValue(beforeValue, i), fieldValue(afterValue, i)
+		if !equal(previous, current) {
+			changes = append(changes, Change{Campo: column, Antes: previous, Depois: current})
+		}
+	}
+
+	return changes
+}
+
+// Função responsável por desreferenciar ponteiros, retornando um valor inválido para ponteiros nulos.
+func indirect(value reflect.Value) reflect.Value {
+	for value.IsValid() && value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return reflect.Value{}
+		}
+		value = value.Elem()
+	}
+	return value
+}
+
+// Função responsável por buscar o valor de um campo, retornando nil para models ou ponteiros nulos.
+func fieldValue(model reflect.Value, index int) any {
+	if !model.IsValid() {
+		return nil
+	}
+
+	value := indirect(model.Field(index))
+	if !value.IsValid() {
+		return nil
+	}
+	return value.Interface()
+}
+
+// Função responsável por comparar dois valores de campo.
+// Datas são comparadas pelo instante, ignorando fuso horário e leitura monotônica.
+func equal(a, b any) bool {
+	if aTime, ok := a.(time.Time); ok {
+		bTime, ok := b.(time.Time)
+		return ok && aTime.Equal(bTime)
+	}
+	return reflect.DeepEqual(a, b)
+}
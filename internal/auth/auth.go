@@ -0,0 +1,103 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/samluiz/delivery-service/internal/apikey"
+)
+
+var (
+	ErrUnauthenticated = errors.New("missing credentials")
+	ErrForbidden       = errors.New("access denied")
+)
+
+// Papel de um usuário autenticado via JWT.
+type Role string
+
+const (
+	RoleAdmin      Role = "admin"
+	RoleDispatcher Role = "dispatcher"
+	RoleDriver     Role = "driver"
+	RoleCustomer   Role = "customer"
+)
+
+// Tipo de credencial utilizada na autenticação.
+type PrincipalType string
+
+const (
+	PrincipalAPIKey PrincipalType = "api_key"
+	PrincipalUser   PrincipalType = "user"
+)
+
+// Struct que representa quem está realizando a requisição.
+// O Tenant é a transportadora à qual a credencial pertence.
+type Principal struct {
+	Type    PrincipalType
+	Subject string
+	Roles   []Role
+	Tenant  string
+}
+
+// Função responsável por verificar se o principal possui algum dos papéis informados.
+func (p Principal) HasRole(roles ...Role) bool {
+	for _, role := range p.Roles {
+		for _, r := range roles {
+			if role == r {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Função responsável por verificar se o principal só pode acessar as entregas atribuídas a ele.
+// Motoristas sem nenhum papel de gestão enxergam apenas as próprias entregas.
+func (p Principal) IsRestrictedToAssigned() bool {
+	return p.Type == PrincipalUser && p.HasRole(RoleDriver) && !p.HasRole(RoleAdmin, RoleDispatcher)
+}
+
+type contextKey struct{}
+
+// Função responsável por adicionar o principal autenticado ao contexto da requisição.
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, contextKey{}, principal)
+}
+
+// Função responsável por buscar o principal autenticado no contexto da requisição.
+func FromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(contextKey{}).(*Principal)
+	return principal, ok
+}
+
+// Struct que representa a política de acesso de uma operação, a mesma nas APIs HTTP, gRPC e GraphQL.
+// Chaves de API precisam do escopo informado e usuários (JWT) de algum dos papéis.
+type Policy struct {
+	Scope apikey.Scope
+	Roles []Role
+}
+
+// Função responsável por verificar se o principal autenticado no contexto satisfaz a política.
+// Retorna ErrUnauthenticated quando não há principal e ErrForbidden quando ele não possui o escopo ou papel.
+func Authorize(ctx context.Context, policy Policy) error {
+	principal, ok := FromContext(ctx)
+	if !ok {
+		return ErrUnauthenticated
+	}
+
+	if principal.Type == PrincipalAPIKey {
+		// Verificando se a chave possui o escopo exigido pela operação
+		if key, ok := apikey.FromContext(ctx); !ok || !key.HasScope(policy.Scope) {
+			return fmt.Errorf("%w: api key requires scope %q", ErrForbidden, policy.Scope)
+		}
+		return nil
+	}
+
+	// Verificando se o usuário possui algum dos papéis exigidos pela operação
+	if !principal.HasRole(policy.Roles...) {
+		return fmt.Errorf("%w: user requires one of the roles %v", ErrForbidden, policy.Roles)
+	}
+
+	return nil
+}
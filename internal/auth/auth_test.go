@@ -0,0 +1,77 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/samluiz/delivery-service/internal/apikey"
+	"github.com/stretchr/testify/assert"
+)
+
+// Testes dos papéis e do contexto do principal
+
+func TestHasRole(t *testing.T) {
+	principal := Principal{Type: PrincipalUser, Roles: []Role{RoleDispatcher}}
+
+	assert.True(t, principal.HasRole(RoleDispatcher))
+	assert.True(t, principal.HasRole(RoleAdmin, RoleDispatcher))
+	assert.False(t, principal.HasRole(RoleAdmin))
+}
+
+func TestIsRestrictedToAssigned(t *testing.T) {
+	driver := Principal{Type: PrincipalUser, Roles: []Role{RoleDriver}}
+	driverDispatcher := Principal{Type: PrincipalUser, Roles: []Role{RoleDriver, RoleDispatcher}}
+	apiKey := Principal{Type: PrincipalAPIKey}
+
+	assert.True(t, driver.IsRestrictedToAssigned())
+	assert.False(t, driverDispatcher.IsRestrictedToAssigned())
+	assert.False(t, apiKey.IsRestrictedToAssigned())
+}
+
+func TestPrincipalContext(t *testing.T) {
+	_, ok := FromContext(context.Background())
+	assert.False(t, ok)
+
+	principal := &Principal{Subject: "user-1"}
+	ctx := WithPrincipal(context.Background(), principal)
+
+	found, ok := FromContext(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, principal, found)
+}
+
+func TestAuthorize(t *testing.T) {
+	policy := Policy{Scope: apikey.ScopeWrite, Roles: []Role{RoleAdmin, RoleDispatcher}}
+
+	withAPIKey := func(scopes ...apikey.Scope) context.Context {
+		ctx := apikey.WithAPIKey(context.Background(), &apikey.APIKey{ID: 1, Escopos: scopes})
+		return WithPrincipal(ctx, &Principal{Type: PrincipalAPIKey, Subject: "apikey:1"})
+	}
+	withUser := func(roles ...Role) context.Context {
+		return WithPrincipal(context.Background(), &Principal{Type: PrincipalUser, Subject: "user-1", Roles: roles})
+	}
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		expectedErr error
+	}{
+		{name: "api key with scope", ctx: withAPIKey(apikey.ScopeRead, apikey.ScopeWrite)},
+		{name: "api key without scope", ctx: withAPIKey(apikey.ScopeRead), expectedErr: ErrForbidden},
+		{name: "user with role", ctx: withUser(RoleDispatcher)},
+		{name: "user without role", ctx: withUser(RoleDriver), expectedErr: ErrForbidden},
+		{name: "without principal", ctx: context.Background(), expectedErr: ErrUnauthenticated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Authorize(tt.ctx, policy)
+
+			if tt.expectedErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+}
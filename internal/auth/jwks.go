@@ -0,0 +1,92 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+// Struct que representa uma chave no formato JWK (RFC 7517).
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	K   string `json:"k"`
+}
+
+// Struct que representa o conteúdo de um arquivo JWKS.
+type jwks struct {
+	Keys []jwk `json:"keys"`
+}
+
+// Chaves carregadas de um JWKS, indexadas pelo "kid".
+type keySet struct {
+	hmacKeys map[string][]byte
+	rsaKeys  map[string]*rsa.PublicKey
+}
+
+// Função responsável por carregar as chaves de um arquivo JWKS local.
+// São suportadas chaves RSA ("kty": "RSA") e simétricas ("kty": "oct").
+func loadJWKS(path string) (*keySet, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var document jwks
+	if err := json.Unmarshal(content, &document); err != nil {
+		return nil, fmt.Errorf("JWKS inválido: %w", err)
+	}
+
+	set := &keySet{
+		hmacKeys: make(map[string][]byte),
+		rsaKeys:  make(map[string]*rsa.PublicKey),
+	}
+
+	for _, key := range document.Keys {
+		// Chaves de criptografia não são utilizadas para validar assinaturas
+		if key.Use != "" && key.Use != "sig" {
+			continue
+		}
+
+		switch key.Kty {
+		case "RSA":
+			publicKey, err := parseRSAKey(key)
+			if err != nil {
+				return nil, fmt.Errorf("chave RSA %q inválida: %w", key.Kid, err)
+			}
+			set.rsaKeys[key.Kid] = publicKey
+		case "oct":
+			secret, err := base64.RawURLEncoding.DecodeString(key.K)
+			if err != nil {
+				return nil, fmt.Errorf("chave simétrica %q inválida: %w", key.Kid, err)
+			}
+			set.hmacKeys[key.Kid] = secret
+		}
+	}
+
+	return set, nil
+}
+
+// Função responsável por converter o módulo e o expoente (base64url) em uma chave pública RSA.
+func parseRSAKey(key jwk) (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(key.N)
+	if err != nil {
+		return nil, err
+	}
+
+	e, err := base64.RawURLEncoding.DecodeString(key.E)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(new(big.Int).SetBytes(e).Int64()),
+	}, nil
+}
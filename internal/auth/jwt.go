@@ -0,0 +1,223 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/samluiz/delivery-service/internal/tenant"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrUnknownKey   = errors.New("unknown signing key")
+)
+
+// Struct que representa as configurações de validação dos JWTs.
+type JWTConfig struct {
+	HS256Secret        string          // Segredo compartilhado para tokens HS256
+	RS256PublicKeyFile string          // Arquivo PEM com a chave pública para tokens RS256
+	JWKSFile           string          // Arquivo JWKS local com chaves RSA e/ou simétricas
+	Issuer             string          // Emissor esperado (claim "iss"), opcional
+	Audience           string          // Audiência esperada (claim "aud"), opcional
+	RolesClaim         string          // Claim que contém os papéis do usuário
+	RoleMapping        map[string]Role // Mapeamento dos valores da claim para os papéis da aplicação
+	TenantClaim        string          // Claim que contém o tenant do usuário, ausente no tenant padrão
+}
+
+// Função responsável por verificar se alguma chave de validação foi configurada.
+func (c JWTConfig) Enabled() bool {
+	return c.HS256Secret != "" || c.RS256PublicKeyFile != "" || c.JWKSFile != ""
+}
+
+type JWTValidator struct {
+	hmacKeys    map[string][]byte
+	rsaKeys     map[string]*rsa.PublicKey
+	parser      *jwt.Parser
+	rolesClaim  string
+	roleMapping map[string]Role
+	tenantClaim string
+}
+
+type IJWTValidator interface {
+	Validate(token string) (*Principal, error)
+}
+
+// Função responsável por instanciar o validador de JWTs a partir das chaves configuradas.
+func NewJWTValidator(config JWTConfig) (IJWTValidator, error) {
+	v := &JWTValidator{
+		hmacKeys:    make(map[string][]byte),
+		rsaKeys:     make(map[string]*rsa.PublicKey),
+		rolesClaim:  config.RolesClaim,
+		roleMapping: config.RoleMapping,
+		tenantClaim: config.TenantClaim,
+	}
+
+	if v.rolesClaim == "" {
+		v.rolesClaim = "roles"
+	}
+	if v.tenantClaim == "" {
+		v.tenantClaim = "tenant_id"
+	}
+
+	// Chaves sem "kid" são registradas com o identificador vazio
+	if config.HS256Secret != "" {
+		v.hmacKeys[""] = []byte(config.HS256Secret)
+	}
+
+	if config.RS256PublicKeyFile != "" {
+		pem, err := os.ReadFile(config.RS256PublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+
+		key, err := jwt.ParseRSAPublicKeyFromPEM(pem)
+		if err != nil {
+			return nil, err
+		}
+		v.rsaKeys[""] = key
+	}
+
+	if config.JWKSFile != "" {
+		set, err := loadJWKS(config.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+
+		for kid, key := range set.hmacKeys {
+			v.hmacKeys[kid] = key
+		}
+		for kid, key := range set.rsaKeys {
+			v.rsaKeys[kid] = key
+		}
+	}
+
+	if len(v.hmacKeys) == 0 && len(v.rsaKeys) == 0 {
+		return nil, errors.New("nenhuma chave de validação de JWT configurada")
+	}
+
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg(), jwt.SigningMethodRS256.Alg()}),
+		jwt.WithExpirationRequired(),
+	}
+	if config.Issuer != "" {
+		options = append(options, jwt.WithIssuer(config.Issuer))
+	}
+	if config.Audience != "" {
+		options = append(options, jwt.WithAudience(config.Audience))
+	}
+	v.parser = jwt.NewParser(options...)
+
+	return v, nil
+}
+
+// Função responsável por validar a assinatura e as claims do token, retornando o principal.
+func (v JWTValidator) Validate(token string) (*Principal, error) {
+	claims := jwt.MapClaims{}
+
+	if _, err := v.parser.ParseWithClaims(token, claims, v.keyFunc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return nil, fmt.Errorf("%w: missing subject", ErrInvalidToken)
+	}
+
+	tenantID, err := v.tenant(claims[v.tenantClaim])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	return &Principal{
+		Type:    PrincipalUser,
+		Subject: subject,
+		Roles:   v.mapRoles(claims[v.rolesClaim]),
+		Tenant:  tenantID,
+	}, nil
+}
+
+// Função responsável por converter a claim do tenant. Tokens sem a claim pertencem ao tenant padrão.
+func (v JWTValidator) tenant(claim interface{}) (string, error) {
+	if claim == nil {
+		return tenant.Default, nil
+	}
+
+	id, ok := claim.(string)
+	if !ok {
+		return "", fmt.Errorf("%w: claim %q must be a string", tenant.ErrInvalidTenant, v.tenantClaim)
+	}
+
+	if err := tenant.Validate(id); err != nil {
+		return "", fmt.Errorf("%w: %q", err, id)
+	}
+
+	return id, nil
+}
+
+// Função responsável por escolher a chave de validação pelo algoritmo e pelo "kid" do token.
+func (v JWTValidator) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	switch token.Method.Alg() {
+	case jwt.SigningMethodHS256.Alg():
+		if key, ok := v.hmacKeys[kid]; ok {
+			return key, nil
+		}
+	case jwt.SigningMethodRS256.Alg():
+		if key, ok := v.rsaKeys[kid]; ok {
+			return key, nil
+		}
+	}
+
+	return nil, ErrUnknownKey
+}
+
+// Função responsável por converter a claim de papéis (lista ou string separada por espaços/vírgulas)
+// nos papéis da aplicação. Valores desconhecidos são ignorados.
+func (v JWTValidator) mapRoles(claim interface{}) []Role {
+	values := make([]string, 0)
+
+	switch c := claim.(type) {
+	case string:
+		values = strings.FieldsFunc(c, func(r rune) bool { return r == ' ' || r == ',' })
+	case []interface{}:
+		for _, value := range c {
+			if s, ok := value.(string); ok {
+				values = append(values, s)
+			}
+		}
+	}
+
+	roles := make([]Role, 0)
+	for _, value := range values {
+		if role, ok := v.roleMapping[value]; ok {
+			roles = append(roles, role)
+			continue
+		}
+
+		switch role := Role(value); role {
+		case RoleAdmin, RoleDispatcher, RoleDriver, RoleCustomer:
+			roles = append(roles, role)
+		}
+	}
+
+	return roles
+}
+
+// Função responsável por interpretar o mapeamento de papéis no formato "valor:papel,valor:papel".
+func ParseRoleMapping(value string) map[string]Role {
+	mapping := make(map[string]Role)
+
+	for _, pair := range strings.Split(value, ",") {
+		claim, role, found := strings.Cut(strings.TrimSpace(pair), ":")
+		if found && claim != "" && role != "" {
+			mapping[claim] = Role(role)
+		}
+	}
+
+	return mapping
+}
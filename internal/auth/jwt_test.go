@@ -0,0 +1,219 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+// Funções auxiliares para emissão de tokens nos testes
+
+func signHS256(t *testing.T, secret string, kid string, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString([]byte(secret))
+	assert.NoError(t, err)
+	return signed
+}
+
+func signRS256(t *testing.T, key *rsa.PrivateKey, kid string, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	assert.NoError(t, err)
+	return signed
+}
+
+func validClaims(roles interface{}) jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub":   "user-1",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"roles": roles,
+	}
+}
+
+// Testes da validação de JWTs
+
+func TestNewJWTValidator_NoKeys(t *testing.T) {
+	validator, err := NewJWTValidator(JWTConfig{})
+
+	assert.Nil(t, validator)
+	assert.Error(t, err)
+	assert.False(t, JWTConfig{}.Enabled())
+}
+
+func TestValidate_HS256(t *testing.T) {
+	validator, err := NewJWTValidator(JWTConfig{HS256Secret: "secret"})
+	assert.NoError(t, err)
+
+	principal, err := validator.Validate(signHS256(t, "secret", "", validClaims([]string{"driver", "unknown"})))
+
+	assert.NoError(t, err)
+	assert.Equal(t, PrincipalUser, principal.Type)
+	assert.Equal(t, "user-1", principal.Subject)
+	assert.Equal(t, []Role{RoleDriver}, principal.Roles)
+	assert.Equal(t, "default", principal.Tenant)
+}
+
+func TestValidate_TenantClaim(t *testing.T) {
+	validator, err := NewJWTValidator(JWTConfig{HS256Secret: "secret", TenantClaim: "org"})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		claim    interface{}
+		expected string
+		err      bool
+	}{
+		{"Sem claim", nil, "default", false},
+		{"Tenant informado", "acme", "acme", false},
+		{"Formato inválido", "Acme Ltda", "", true},
+		{"Tipo inválido", 10, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := validClaims("admin")
+			if tt.claim != nil {
+				claims["org"] = tt.claim
+			}
+
+			principal, err := validator.Validate(signHS256(t, "secret", "", claims))
+			if tt.err {
+				assert.ErrorIs(t, err, ErrInvalidToken)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, principal.Tenant)
+		})
+	}
+}
+
+func TestValidate_InvalidTokens(t *testing.T) {
+	validator, err := NewJWTValidator(JWTConfig{HS256Secret: "secret", Issuer: "https://auth.example.com"})
+	assert.NoError(t, err)
+
+	expired := validClaims("admin")
+	expired["iss"] = "https://auth.example.com"
+	expired["exp"] = time.Now().Add(-time.Hour).Unix()
+
+	wrongIssuer := validClaims("admin")
+	wrongIssuer["iss"] = "https://other.example.com"
+
+	withoutExpiration := jwt.MapClaims{"sub": "user-1", "iss": "https://auth.example.com"}
+
+	withoutSubject := validClaims("admin")
+	withoutSubject["iss"] = "https://auth.example.com"
+	delete(withoutSubject, "sub")
+
+	wrongSecret := validClaims("admin")
+	wrongSecret["iss"] = "https://auth.example.com"
+
+	tokens := map[string]string{
+		"expired":            signHS256(t, "secret", "", expired),
+		"wrong issuer":       signHS256(t, "secret", "", wrongIssuer),
+		"without expiration": signHS256(t, "secret", "", withoutExpiration),
+		"without subject":    signHS256(t, "secret", "", withoutSubject),
+		"wrong secret":       signHS256(t, "other", "", wrongSecret),
+		"malformed":          "not-a-jwt",
+	}
+
+	for name, token := range tokens {
+		t.Run(name, func(t *testing.T) {
+			principal, err := validator.Validate(token)
+			assert.Nil(t, principal)
+			assert.ErrorIs(t, err, ErrInvalidToken)
+		})
+	}
+}
+
+func TestValidate_RS256PublicKeyFile(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	der, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	assert.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "public.pem")
+	assert.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600))
+
+	validator, err := NewJWTValidator(JWTConfig{RS256PublicKeyFile: path})
+	assert.NoError(t, err)
+
+	principal, err := validator.Validate(signRS256(t, privateKey, "", validClaims("admin dispatcher")))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Role{RoleAdmin, RoleDispatcher}, principal.Roles)
+
+	// Tokens HS256 não são aceitos quando apenas a chave RSA foi configurada
+	_, err = validator.Validate(signHS256(t, "secret", "", validClaims("admin")))
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestValidate_JWKSFile(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	document := map[string]interface{}{
+		"keys": []map[string]string{
+			{
+				"kty": "RSA",
+				"kid": "rsa-1",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(privateKey.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(privateKey.E)).Bytes()),
+			},
+			{
+				"kty": "oct",
+				"kid": "hmac-1",
+				"k":   base64.RawURLEncoding.EncodeToString([]byte("jwks-secret")),
+			},
+		},
+	}
+	content, _ := json.Marshal(document)
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	assert.NoError(t, os.WriteFile(path, content, 0o600))
+
+	validator, err := NewJWTValidator(JWTConfig{
+		JWKSFile:    path,
+		RolesClaim:  "groups",
+		RoleMapping: ParseRoleMapping("ops-admins:admin, couriers:driver"),
+	})
+	assert.NoError(t, err)
+
+	claims := jwt.MapClaims{"sub": "user-1", "exp": time.Now().Add(time.Hour).Unix(), "groups": []string{"couriers"}}
+
+	principal, err := validator.Validate(signRS256(t, privateKey, "rsa-1", claims))
+	assert.NoError(t, err)
+	assert.Equal(t, []Role{RoleDriver}, principal.Roles)
+
+	principal, err = validator.Validate(signHS256(t, "jwks-secret", "hmac-1", claims))
+	assert.NoError(t, err)
+	assert.Equal(t, []Role{RoleDriver}, principal.Roles)
+
+	// Tokens com "kid" desconhecido são rejeitados
+	_, err = validator.Validate(signRS256(t, privateKey, "rsa-2", claims))
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestParseRoleMapping(t *testing.T) {
+	mapping := ParseRoleMapping("ops-admins:admin,couriers:driver,invalid,:customer")
+
+	assert.Equal(t, map[string]Role{"ops-admins": RoleAdmin, "couriers": RoleDriver}, mapping)
+}
@@ -0,0 +1,293 @@
+// Pacote com o limitador adaptativo de concorrência: limita as requisições em execução simultânea, enfileira o
+// excedente até um limite e ajusta a concorrência pela latência (aumento aditivo, redução multiplicativa).
+package concurrency
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+var (
+	ErrQueueFull    = errors.New("request queue is full")
+	ErrQueueTimeout = errors.New("request waited too long in the queue")
+	ErrShed         = errors.New("request was shed in favor of a higher priority request")
+	ErrInvalidClass = errors.New("invalid request class")
+)
+
+// Classe da requisição, utilizada para priorizar a fila.
+type Class string
+
+const (
+	ClassRead  Class = "read"  // Consultas
+	ClassWrite Class = "write" // Criação, atualização e exclusão
+)
+
+const (
+	// Fator de redução do limite quando a latência ultrapassa a tolerância.
+	backoffRatio = 0.9
+
+	// Janela após a qual a latência mínima é recalculada, para acompanhar mudanças no banco.
+	baselineWindow = time.Minute
+)
+
+// Configuração do limitador.
+type Options struct {
+	InitialLimit int           // Concorrência inicial
+	MinLimit     int           // Concorrência mínima
+	MaxLimit     int           // Concorrência máxima
+	MaxQueue     int           // Quantidade máxima de requisições aguardando; zero recusa o excedente imediatamente
+	MaxWait      time.Duration // Tempo máximo de espera na fila
+	Tolerance    float64       // Razão entre a latência e a latência mínima a partir da qual o limite é reduzido
+
+	// Classe atendida antes na fila; quando a fila está cheia, ela também substitui a requisição mais recente da
+	// outra classe. Vazia mantém a fila em ordem de chegada.
+	Priority Class
+}
+
+// Valores padrão da configuração.
+var DefaultOptions = Options{
+	InitialLimit: 10,
+	MinLimit:     2,
+	MaxLimit:     50,
+	MaxQueue:     100,
+	MaxWait:      2 * time.Second,
+	Tolerance:    2,
+	Priority:     ClassRead,
+}
+
+// Requisição aguardando na fila.
+type waiter struct {
+	ready   chan struct{}
+	err     error // Motivo da recusa, quando a requisição é removida da fila por outra
+	granted bool
+	queue   *list.List
+	element *list.Element
+}
+
+// Limitador adaptativo de concorrência.
+type Limiter struct {
+	mu       sync.Mutex
+	options  Options
+	limit    int
+	inFlight int
+	priority *list.List // Fila da classe prioritária
+	regular  *list.List // Fila das demais classes
+
+	baseline     time.Duration // Latência mínima da janela anterior
+	windowMin    time.Duration // Latência mínima da janela atual
+	windowStart  time.Time
+	lastDecrease time.Time
+
+	now func() time.Time
+}
+
+// Função responsável por interpretar a classe prioritária ("read", "write", "none" ou vazio).
+func ParseClass(value string) (Class, error) {
+	switch Class(value) {
+	case ClassRead, ClassWrite:
+		return Class(value), nil
+	case "", "none":
+		return "", nil
+	}
+	return "", fmt.Errorf("%w %q: must be read, write or none", ErrInvalidClass, value)
+}
+
+// Função responsável por instanciar o limitador, completando a configuração com os valores padrão.
+func NewLimiter(options Options) *Limiter {
+	if options.MinLimit <= 0 {
+		options.MinLimit = 1
+	}
+	if options.MaxLimit < options.MinLimit {
+		options.MaxLimit = options.MinLimit
+	}
+	if options.InitialLimit <= 0 {
+		options.InitialLimit = DefaultOptions.InitialLimit
+	}
+	options.InitialLimit = min(max(options.InitialLimit, options.MinLimit), options.MaxLimit)
+	if options.MaxQueue < 0 {
+		options.MaxQueue = 0
+	}
+	if options.MaxWait <= 0 {
+		options.MaxWait = DefaultOptions.MaxWait
+	}
+	if options.Tolerance <= 1 {
+		options.Tolerance = DefaultOptions.Tolerance
+	}
+
+	return &Limiter{
+		options:  options,
+		limit:    options.InitialLimit,
+		priority: list.New(),
+		regular:  list.New(),
+		now:      time.Now,
+	}
+}
+
+// Função responsável por aguardar uma vaga para executar a requisição.
+// Retorna a função que libera a vaga, que deve ser chamada ao final da requisição, ou o motivo da recusa.
+func (l *Limiter) Acquire(ctx context.Context, class Class) (func(), error) {
+	l.mu.Lock()
+
+	if l.inFlight < l.limit && l.priority.Len() == 0 && l.regular.Len() == 0 {
+		l.inFlight++
+		l.mu.Unlock()
+		return l.releaseFunc(), nil
+	}
+
+	prioritized := l.options.Priority != "" && class == l.options.Priority
+
+	if l.queueDepth() >= l.options.MaxQueue {
+		// A requisição prioritária substitui a requisição mais recente da outra classe
+		if !prioritized || l.regular.Len() == 0 {
+			l.mu.Unlock()
+			return nil, ErrQueueFull
+		}
+		l.shed(l.regular.Back().Value.(*waiter))
+	}
+
+	queue := l.regular
+	if prioritized {
+		queue = l.priority
+	}
+	w := &waiter{ready: make(chan struct{}), queue: queue}
+	w.element = queue.PushBack(w)
+	l.mu.Unlock()
+
+	timer := time.NewTimer(l.options.MaxWait)
+	defer timer.Stop()
+
+	var err error
+	select {
+	case <-w.ready:
+		if w.err != nil {
+			return nil, w.err
+		}
+		return l.releaseFunc(), nil
+	case <-timer.C:
+		err = ErrQueueTimeout
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// A vaga pode ter sido concedida ao mesmo tempo que o fim da espera
+	if w.granted {
+		return l.releaseFunc(), nil
+	}
+	if w.err != nil {
+		return nil, w.err
+	}
+	w.queue.Remove(w.element)
+	return nil, err
+}
+
+// Função responsável por remover a requisição da fila, recusando-a.
+func (l *Limiter) shed(w *waiter) {
+	w.queue.Remove(w.element)
+	w.err = ErrShed
+	close(w.ready)
+}
+
+// Função responsável por criar a função que libera a vaga, registrando a latência da requisição.
+func (l *Limiter) releaseFunc() func() {
+	start := l.now()
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			l.release(l.now().Sub(start))
+		})
+	}
+}
+
+// Função responsável por liberar a vaga, ajustar o limite pela latência e atender a fila.
+func (l *Limiter) release(latency time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	saturated := l.inFlight >= l.limit
+	l.inFlight--
+	l.adapt(latency, saturated)
+
+	for l.inFlight < l.limit {
+		queue := l.priority
+		if queue.Len() == 0 {
+			queue = l.regular
+		}
+		if queue.Len() == 0 {
+			break
+		}
+
+		w := queue.Remove(queue.Front()).(*waiter)
+		w.granted = true
+		l.inFlight++
+		close(w.ready)
+	}
+}
+
+// Função responsável por ajustar o limite: reduz quando a latência ultrapassa a tolerância sobre a latência mínima,
+// no máximo uma vez por latência mínima, e aumenta em um quando o limite estava todo em uso.
+func (l *Limiter) adapt(latency time.Duration, saturated bool) {
+	now := l.now()
+
+	if l.windowStart.IsZero() || now.Sub(l.windowStart) >= baselineWindow {
+		if l.windowMin > 0 {
+			l.baseline = l.windowMin
+		}
+		l.windowMin = 0
+		l.windowStart = now
+	}
+	if l.windowMin == 0 || latency < l.windowMin {
+		l.windowMin = latency
+	}
+	if l.baseline == 0 || latency < l.baseline {
+		l.baseline = latency
+	}
+
+	if float64(latency) > float64(l.baseline)*l.options.Tolerance {
+		if now.Sub(l.lastDecrease) >= l.baseline {
+			l.limit = max(l.options.MinLimit, int(math.Floor(float64(l.limit)*backoffRatio)))
+			l.lastDecrease = now
+		}
+		return
+	}
+
+	if saturated {
+		l.limit = min(l.options.MaxLimit, l.limit+1)
+	}
+}
+
+func (l *Limiter) queueDepth() int {
+	return l.priority.Len() + l.regular.Len()
+}
+
+// Função responsável por retornar a quantidade de requisições aguardando na fila.
+func (l *Limiter) QueueDepth() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.queueDepth()
+}
+
+// Função responsável por retornar o limite de concorrência atual.
+func (l *Limiter) Limit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.limit
+}
+
+// Função responsável por retornar a quantidade de requisições em execução.
+func (l *Limiter) InFlight() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.inFlight
+}
@@ -0,0 +1,247 @@
+package concurrency
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// Função responsável por aguardar a fila atingir a profundidade informada.
+func waitQueue(t *testing.T, limiter *Limiter, depth int) {
+	t.Helper()
+	assert.Eventually(t, func() bool { return limiter.QueueDepth() == depth }, time.Second, time.Millisecond)
+}
+
+// Função responsável por enfileirar uma requisição em segundo plano, enviando o resultado no canal.
+func acquireAsync(limiter *Limiter, class Class) chan error {
+	result := make(chan error, 1)
+	go func() {
+		release, err := limiter.Acquire(context.Background(), class)
+		if err == nil {
+			defer release()
+		}
+		result <- err
+	}()
+	return result
+}
+
+func TestParseClass(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected Class
+		err      bool
+	}{
+		{"read", ClassRead, false},
+		{"write", ClassWrite, false},
+		{"none", "", false},
+		{"", "", false},
+		{"reads", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			class, err := ParseClass(tt.value)
+			if tt.err {
+				assert.ErrorIs(t, err, ErrInvalidClass)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, class)
+		})
+	}
+}
+
+func TestNewLimiter_Defaults(t *testing.T) {
+	limiter := NewLimiter(Options{InitialLimit: 100, MinLimit: 2, MaxLimit: 20})
+
+	assert.Equal(t, 20, limiter.Limit())
+	assert.Equal(t, DefaultOptions.MaxWait, limiter.options.MaxWait)
+	assert.Equal(t, DefaultOptions.Tolerance, limiter.options.Tolerance)
+}
+
+func TestLimiter_QueueFull(t *testing.T) {
+	limiter := NewLimiter(Options{InitialLimit: 1, MinLimit: 1, MaxLimit: 1, MaxQueue: 1, MaxWait: time.Second})
+
+	release, err := limiter.Acquire(context.Background(), ClassRead)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, limiter.InFlight())
+
+	queued := acquireAsync(limiter, ClassRead)
+	waitQueue(t, limiter, 1)
+
+	// A fila está cheia
+	_, err = limiter.Acquire(context.Background(), ClassRead)
+	assert.ErrorIs(t, err, ErrQueueFull)
+
+	// A vaga liberada é entregue à requisição da fila
+	release()
+	assert.NoError(t, <-queued)
+	assert.Equal(t, 0, limiter.QueueDepth())
+}
+
+func TestLimiter_QueueTimeout(t *testing.T) {
+	limiter := NewLimiter(Options{InitialLimit: 1, MinLimit: 1, MaxLimit: 1, MaxQueue: 10, MaxWait: 20 * time.Millisecond})
+
+	release, err := limiter.Acquire(context.Background(), ClassRead)
+	assert.NoError(t, err)
+	defer release()
+
+	_, err = limiter.Acquire(context.Background(), ClassRead)
+	assert.ErrorIs(t, err, ErrQueueTimeout)
+	assert.Equal(t, 0, limiter.QueueDepth())
+}
+
+func TestLimiter_ContextCanceled(t *testing.T) {
+	limiter := NewLimiter(Options{InitialLimit: 1, MinLimit: 1, MaxLimit: 1, MaxQueue: 10, MaxWait: time.Second})
+
+	release, err := limiter.Acquire(context.Background(), ClassRead)
+	assert.NoError(t, err)
+	defer release()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = limiter.Acquire(ctx, ClassRead)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 0, limiter.QueueDepth())
+}
+
+func TestLimiter_Priority(t *testing.T) {
+	tests := []struct {
+		name     string
+		priority Class
+		expected []Class
+	}{
+		{"Leituras primeiro", ClassRead, []Class{ClassRead, ClassRead, ClassWrite}},
+		{"Escritas primeiro", ClassWrite, []Class{ClassWrite, ClassRead, ClassRead}},
+		{"Ordem de chegada", "", []Class{ClassWrite, ClassRead, ClassRead}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := NewLimiter(Options{InitialLimit: 1, MinLimit: 1, MaxLimit: 1, MaxQueue: 10, MaxWait: time.Second, Priority: tt.priority})
+
+			release, err := limiter.Acquire(context.Background(), ClassRead)
+			assert.NoError(t, err)
+
+			order := make(chan Class, 3)
+			for i, class := range []Class{ClassWrite, ClassRead, ClassRead} {
+				go func(class Class) {
+					release, err := limiter.Acquire(context.Background(), class)
+					if assert.NoError(t, err) {
+						order <- class
+						release()
+					}
+				}(class)
+				waitQueue(t, limiter, i+1)
+			}
+
+			release()
+
+			served := make([]Class, 0, 3)
+			for range tt.expected {
+				served = append(served, <-order)
+			}
+			assert.Equal(t, tt.expected, served)
+		})
+	}
+}
+
+func TestLimiter_ShedsLowerPriority(t *testing.T) {
+	limiter := NewLimiter(Options{InitialLimit: 1, MinLimit: 1, MaxLimit: 1, MaxQueue: 1, MaxWait: time.Second, Priority: ClassRead})
+
+	release, err := limiter.Acquire(context.Background(), ClassWrite)
+	assert.NoError(t, err)
+
+	write := acquireAsync(limiter, ClassWrite)
+	waitQueue(t, limiter, 1)
+
+	// A leitura ocupa o lugar da escrita na fila cheia
+	read := acquireAsync(limiter, ClassRead)
+	assert.ErrorIs(t, <-write, ErrShed)
+	waitQueue(t, limiter, 1)
+
+	// Uma escrita não substitui a leitura
+	_, err = limiter.Acquire(context.Background(), ClassWrite)
+	assert.ErrorIs(t, err, ErrQueueFull)
+
+	release()
+	assert.NoError(t, <-read)
+}
+
+func TestLimiter_Adapt(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	limiter := NewLimiter(Options{InitialLimit: 10, MinLimit: 2, MaxLimit: 12, Tolerance: 2})
+	limiter.now = func() time.Time { return now }
+
+	// Simulando requisições com a latência informada, com o limite todo em uso
+	run := func(latency time.Duration) {
+		limiter.mu.Lock()
+		limiter.inFlight = limiter.limit
+		limiter.mu.Unlock()
+
+		now = now.Add(latency)
+		limiter.release(latency)
+	}
+
+	// Latência estável com o limite em uso aumenta o limite até o máximo
+	for i := 0; i < 5; i++ {
+		run(10 * time.Millisecond)
+	}
+	assert.Equal(t, 12, limiter.Limit())
+
+	// Latência acima da tolerância reduz o limite
+	run(50 * time.Millisecond)
+	assert.Equal(t, 10, limiter.Limit())
+
+	// Até o mínimo
+	for i := 0; i < 20; i++ {
+		run(50 * time.Millisecond)
+	}
+	assert.Equal(t, 2, limiter.Limit())
+
+	// Sem uso total do limite, a latência estável não aumenta o limite
+	limiter.mu.Lock()
+	limiter.inFlight = 1
+	limiter.mu.Unlock()
+	limiter.release(10 * time.Millisecond)
+	assert.Equal(t, 2, limiter.Limit())
+}
+
+func TestLimiter_RegisterMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	limiter := NewLimiter(Options{InitialLimit: 1, MinLimit: 1, MaxLimit: 1, MaxQueue: 10, MaxWait: time.Second})
+	assert.NoError(t, limiter.RegisterMetrics(provider.Meter("test")))
+
+	release, err := limiter.Acquire(context.Background(), ClassRead)
+	assert.NoError(t, err)
+	queued := acquireAsync(limiter, ClassRead)
+	waitQueue(t, limiter, 1)
+
+	var data metricdata.ResourceMetrics
+	assert.NoError(t, reader.Collect(context.Background(), &data))
+
+	values := map[string]int64{}
+	for _, scope := range data.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			gauge := m.Data.(metricdata.Gauge[int64])
+			values[m.Name] = gauge.DataPoints[0].Value
+			assert.Equal(t, attribute.NewSet(), gauge.DataPoints[0].Attributes)
+		}
+	}
+	assert.Equal(t, map[string]int64{
+		"http.server.queue.depth":           1,
+		"http.server.concurrency.limit":     1,
+		"http.server.concurrency.in_flight": 1,
+	}, values)
+
+	release()
+	assert.NoError(t, <-queued)
+}
@@ -0,0 +1,47 @@
+package concurrency
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Função responsável por registrar as métricas do limitador: a profundidade da fila, o limite de concorrência
+// e as requisições em execução, observados a cada coleta.
+func (l *Limiter) RegisterMetrics(meter metric.Meter) error {
+	queueDepth, err := meter.Int64ObservableGauge("http.server.queue.depth",
+		metric.WithDescription("Requisições aguardando uma vaga no limitador de concorrência"),
+		metric.WithUnit("{request}"),
+	)
+	if err != nil {
+		return err
+	}
+
+	limit, err := meter.Int64ObservableGauge("http.server.concurrency.limit",
+		metric.WithDescription("Limite de concorrência atual do limitador adaptativo"),
+		metric.WithUnit("{request}"),
+	)
+	if err != nil {
+		return err
+	}
+
+	inFlight, err := meter.Int64ObservableGauge("http.server.concurrency.in_flight",
+		metric.WithDescription("Requisições em execução no limitador de concorrência"),
+		metric.WithUnit("{request}"),
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		o.ObserveInt64(queueDepth, int64(l.queueDepth()))
+		o.ObserveInt64(limit, int64(l.limit))
+		o.ObserveInt64(inFlight, int64(l.inFlight))
+		return nil
+	}, queueDepth, limit, inFlight)
+
+	return err
+}
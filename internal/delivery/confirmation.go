@@ -0,0 +1,94 @@
+package delivery
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/samluiz/delivery-service/internal/tenant"
+)
+
+// Configuração da exclusão em massa de entregas.
+type BulkDeleteConfig struct {
+	Enabled  bool          // Habilita o endpoint de exclusão em massa
+	Secret   []byte        // Segredo utilizado para assinar os tokens de confirmação
+	TokenTTL time.Duration // Validade dos tokens de confirmação
+
+	// Habilita ou desabilita a exclusão em massa por tenant, sobrescrevendo o Enabled
+	Tenants map[string]bool
+}
+
+// Função responsável por verificar se a exclusão em massa está habilitada para o tenant do contexto.
+func (c BulkDeleteConfig) enabled(ctx context.Context) bool {
+	if id, ok := tenant.FromContext(ctx); ok {
+		if enabled, found := c.Tenants[id]; found {
+			return enabled
+		}
+	}
+	return c.Enabled
+}
+
+// Conteúdo assinado do token de confirmação.
+// O token fica vinculado ao tenant, aos filtros e à quantidade de entregas informada no dry-run.
+type confirmationPayload struct {
+	Tenant     string `json:"t"`
+	FilterHash string `json:"f"`
+	Count      int64  `json:"n"`
+	ExpiresAt  int64  `json:"exp"`
+}
+
+// Função responsável por gerar o hash canônico dos filtros da exclusão.
+func hashFilter(filter *DeliveryFilter) string {
+	canonical, _ := json.Marshal(filter)
+	sum := sha256.Sum256(canonical)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// Função responsável por assinar um token de confirmação para o tenant, os filtros e a quantidade informados.
+func signConfirmationToken(secret []byte, tenantID string, filter *DeliveryFilter, count int64, expiresAt time.Time) string {
+	payload, _ := json.Marshal(confirmationPayload{
+		Tenant:     tenantID,
+		FilterHash: hashFilter(filter),
+		Count:      count,
+		ExpiresAt:  expiresAt.Unix(),
+	})
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + sign(secret, encoded)
+}
+
+// Função responsável por validar o token de confirmação, retornando a quantidade confirmada no dry-run.
+func verifyConfirmationToken(secret []byte, token string, tenantID string, filter *DeliveryFilter, now time.Time) (int64, error) {
+	encoded, signature, found := strings.Cut(token, ".")
+	if !found || !hmac.Equal([]byte(signature), []byte(sign(secret, encoded))) {
+		return 0, ErrInvalidConfirmationToken
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return 0, ErrInvalidConfirmationToken
+	}
+
+	var payload confirmationPayload
+	if err := json.Unmarshal(decoded, &payload); err != nil {
+		return 0, ErrInvalidConfirmationToken
+	}
+
+	// O token só confirma a exclusão do mesmo tenant, com os mesmos filtros do dry-run e dentro da validade
+	if payload.Tenant != tenantID || payload.FilterHash != hashFilter(filter) || now.Unix() > payload.ExpiresAt {
+		return 0, ErrInvalidConfirmationToken
+	}
+
+	return payload.Count, nil
+}
+
+// Função responsável por calcular a assinatura HMAC-SHA256 do conteúdo do token.
+func sign(secret []byte, value string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(value))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -3,72 +3,127 @@ package delivery
 import "time"
 
 var (
-	TABLE_NAME = "entregas"
+	TABLE_NAME          = "entregas"
+	VERSIONS_TABLE_NAME = "entregas_versoes"
 )
 
 type Delivery struct {
-	ID            int       `db:"id"`
-	Cliente       string    `db:"cliente"`
-	Peso          float64   `db:"peso"`
-	Endereco      string    `db:"endereco"`
-	Logradouro    string    `db:"logradouro"`
-	Numero        string    `db:"numero"`
-	Bairro        string    `db:"bairro"`
-	Complemento   string    `db:"complemento"`
-	Cidade        string    `db:"cidade"`
-	Estado        string    `db:"estado"`
-	Pais          string    `db:"pais"`
-	Latitude      float64   `db:"latitude"`
-	Longitude     float64   `db:"longitude"`
-	DataInclusao  time.Time `db:"data_inclusao"`
-	DataAlteracao time.Time `db:"data_alteracao"`
+	ID            int        `db:"id"`
+	Cliente       string     `db:"cliente"`
+	Peso          float64    `db:"peso"`
+	Endereco      string     `db:"endereco"`
+	Logradouro    string     `db:"logradouro"`
+	Numero        string     `db:"numero"`
+	Bairro        string     `db:"bairro"`
+	Complemento   string     `db:"complemento"`
+	Cidade        string     `db:"cidade"`
+	Estado        string     `db:"estado"`
+	Pais          string     `db:"pais"`
+	Latitude      float64    `db:"latitude"`
+	Longitude     float64    `db:"longitude"`
+	DataInclusao  time.Time  `db:"data_inclusao"`
+	DataAlteracao time.Time  `db:"data_alteracao"`
+	Motorista     string     `db:"motorista"`
+	DataExclusao  *time.Time `db:"data_exclusao"`
+	TenantID      string     `db:"tenant_id"`
 }
 
 type CreateDeliveryRequest struct {
-	Cliente     string  `json:"cliente" validate:"required"`
-	Peso        float64 `json:"peso" validate:"required"`
-	Endereco    string  `json:"endereco" validate:"required"`
-	Logradouro  string  `json:"logradouro" validate:"required"`
-	Numero      string  `json:"numero" validate:"required"`
-	Bairro      string  `json:"bairro" validate:"required"`
-	Complemento string  `json:"complemento" validate:"required"`
-	Cidade      string  `json:"cidade" validate:"required"`
-	Estado      string  `json:"estado" validate:"required"`
-	Pais        string  `json:"pais" validate:"required"`
-	Latitude    float64 `json:"latitude" validate:"required"`
-	Longitude   float64 `json:"longitude" validate:"required"`
+	Cliente     string   `json:"cliente" validate:"required,max=255"`
+	Peso        float64  `json:"peso" validate:"required,peso"`
+	Endereco    string   `json:"endereco" validate:"required,max=255"`
+	Logradouro  string   `json:"logradouro" validate:"required,max=255"`
+	Numero      string   `json:"numero" validate:"required,max=50"`
+	Bairro      string   `json:"bairro" validate:"required,max=255"`
+	Complemento string   `json:"complemento" validate:"required,max=255"`
+	Cidade      string   `json:"cidade" validate:"required,max=255"`
+	Estado      string   `json:"estado" validate:"required,max=100"`
+	Pais        string   `json:"pais" validate:"required,max=100,pais"`
+	Latitude    *float64 `json:"latitude" validate:"required,latitude"`
+	Longitude   *float64 `json:"longitude" validate:"required,longitude"`
+	Motorista   string   `json:"motorista" validate:"max=255"`
 }
 
 type UpdateDeliveryRequest struct {
-	Peso        float64 `json:"peso" validate:"required"`
-	Endereco    string  `json:"endereco" validate:"required"`
-	Logradouro  string  `json:"logradouro" validate:"required"`
-	Numero      string  `json:"numero" validate:"required"`
-	Bairro      string  `json:"bairro" validate:"required"`
-	Complemento string  `json:"complemento" validate:"required"`
-	Cidade      string  `json:"cidade" validate:"required"`
-	Estado      string  `json:"estado" validate:"required"`
-	Pais        string  `json:"pais" validate:"required"`
-	Latitude    float64 `json:"latitude" validate:"required"`
-	Longitude   float64 `json:"longitude" validate:"required"`
+	Peso        float64  `json:"peso" validate:"required,peso"`
+	Endereco    string   `json:"endereco" validate:"required,max=255"`
+	Logradouro  string   `json:"logradouro" validate:"required,max=255"`
+	Numero      string   `json:"numero" validate:"required,max=50"`
+	Bairro      string   `json:"bairro" validate:"required,max=255"`
+	Complemento string   `json:"complemento" validate:"required,max=255"`
+	Cidade      string   `json:"cidade" validate:"required,max=255"`
+	Estado      string   `json:"estado" validate:"required,max=100"`
+	Pais        string   `json:"pais" validate:"required,max=100,pais"`
+	Latitude    *float64 `json:"latitude" validate:"required,latitude"`
+	Longitude   *float64 `json:"longitude" validate:"required,longitude"`
+	Motorista   string   `json:"motorista" validate:"max=255"`
+}
+
+// Filtros da listagem de entregas. Campos vazios não são aplicados.
+// Excluidas seleciona as entregas da lixeira em vez das ativas.
+// AposID e Limite paginam a listagem por cursor: como as entregas são ordenadas da mais recente para a
+// mais antiga, a próxima página começa após o ID da última entrega da página anterior.
+// Deslocamento pula as primeiras entregas da listagem e só é aplicado junto com o Limite.
+type DeliveryFilter struct {
+	Cidade       string
+	Motorista    string
+	Excluidas    bool
+	AposID       int
+	Limite       int
+	Deslocamento int
+	Ordenacao    DeliverySort
+}
+
+// Campo pelo qual a listagem de entregas pode ser ordenada.
+type SortField string
+
+const (
+	SortByID            SortField = "id"
+	SortByCliente       SortField = "cliente"
+	SortByPeso          SortField = "peso"
+	SortByCidade        SortField = "cidade"
+	SortByDataInclusao  SortField = "data_inclusao"
+	SortByDataAlteracao SortField = "data_alteracao"
+)
+
+// Ordenação da listagem de entregas. Sem campo, as entregas são listadas da mais recente para a mais antiga.
+// Entregas com o mesmo valor no campo são desempatadas pelo ID, na mesma direção.
+type DeliverySort struct {
+	Campo     SortField
+	Crescente bool
+}
+
+// Resultado do dry-run da exclusão em massa de entregas.
+// O token deve ser enviado na exclusão para confirmá-la.
+type DeleteDeliveriesPreview struct {
+	Quantidade       int64     `json:"quantidade"`
+	TokenConfirmacao string    `json:"token_confirmacao"`
+	ExpiraEm         time.Time `json:"expira_em"`
+}
+
+// Resultado da exclusão em massa de entregas.
+type DeleteDeliveriesResult struct {
+	Quantidade int64 `json:"quantidade"`
 }
 
 type DeliveryResponse struct {
-	ID            int       `json:"id"`
-	Cliente       string    `json:"cliente"`
-	Peso          float64   `json:"peso"`
-	Endereco      string    `json:"endereco"`
-	Logradouro    string    `json:"logradouro"`
-	Numero        string    `json:"numero"`
-	Bairro        string    `json:"bairro"`
-	Complemento   string    `json:"complemento"`
-	Cidade        string    `json:"cidade"`
-	Estado        string    `json:"estado"`
-	Pais          string    `json:"pais"`
-	Latitude      float64   `json:"latitude"`
-	Longitude     float64   `json:"longitude"`
-	DataInclusao  time.Time `json:"data_inclusao"`
-	DataAlteracao time.Time `json:"data_alteracao"`
+	ID            int        `json:"id"`
+	Cliente       string     `json:"cliente"`
+	Peso          float64    `json:"peso"`
+	Endereco      string     `json:"endereco"`
+	Logradouro    string     `json:"logradouro"`
+	Numero        string     `json:"numero"`
+	Bairro        string     `json:"bairro"`
+	Complemento   string     `json:"complemento"`
+	Cidade        string     `json:"cidade"`
+	Estado        string     `json:"estado"`
+	Pais          string     `json:"pais"`
+	Latitude      float64    `json:"latitude"`
+	Longitude     float64    `json:"longitude"`
+	DataInclusao  time.Time  `json:"data_inclusao"`
+	DataAlteracao time.Time  `json:"data_alteracao"`
+	Motorista     string     `json:"motorista"`
+	DataExclusao  *time.Time `json:"data_exclusao,omitempty"`
 }
 
 func (r DeliveryResponse) ToDelivery() *Delivery {
@@ -88,6 +143,8 @@ func (r DeliveryResponse) ToDelivery() *Delivery {
 		Longitude:     r.Longitude,
 		DataInclusao:  r.DataInclusao,
 		DataAlteracao: r.DataAlteracao,
+		Motorista:     r.Motorista,
+		DataExclusao:  r.DataExclusao,
 	}
 }
 
@@ -108,6 +165,8 @@ func (r Delivery) ToDeliveryResponse() *DeliveryResponse {
 		Longitude:     r.Longitude,
 		DataInclusao:  r.DataInclusao,
 		DataAlteracao: r.DataAlteracao,
+		Motorista:     r.Motorista,
+		DataExclusao:  r.DataExclusao,
 	}
 }
 
@@ -124,7 +183,9 @@ var (
 			estado,
 			pais,
 			latitude,
-			longitude
+			longitude,
+			motorista,
+			tenant_id
 		) VALUES (
 			?,
 			?,
@@ -137,12 +198,13 @@ var (
 			?,
 			?,
 			?,
+			?,
+			?,
 			?
 		)`
 
 	updateDeliveryQuery = `UPDATE entregas
 			SET
-				cliente = ?,
 				peso = ?,
 				endereco = ?,
 				logradouro = ?,
@@ -153,16 +215,26 @@ var (
 				estado = ?,
 				pais = ?,
 				latitude = ?,
-				longitude = ?
-			WHERE id = ?`
+				longitude = ?,
+				motorista = ?
+			WHERE id = ? AND tenant_id = ? AND data_exclusao IS NULL`
+
+	getDeliveryQuery = `SELECT * FROM entregas WHERE id = ? AND tenant_id = ? AND data_exclusao IS NULL`
+
+	lockDeliveryQuery = `SELECT * FROM entregas WHERE id = ? AND tenant_id = ? FOR UPDATE`
+
+	getDeliveriesQuery = `SELECT * FROM entregas`
+
+	deleteDeliveryQuery = `UPDATE entregas SET data_exclusao = CURRENT_TIMESTAMP WHERE id = ? AND tenant_id = ? AND data_exclusao IS NULL`
 
-	getDeliveryQuery = `SELECT * FROM entregas WHERE id = ?`
+	restoreDeliveryQuery = `UPDATE entregas SET data_exclusao = NULL WHERE id = ? AND tenant_id = ? AND data_exclusao IS NOT NULL`
 
-	getDeliveriesQuery = `SELECT * FROM entregas ORDER BY id DESC`
+	countDeliveriesQuery = `SELECT COUNT(*) FROM entregas`
 
-	getDeliveriesByCityQuery = `SELECT * FROM entregas WHERE cidade = ? ORDER BY id DESC`
+	deleteDeliveriesQuery = `UPDATE entregas SET data_exclusao = ?`
 
-	deleteDeliveryQuery = `DELETE FROM entregas WHERE id = ?`
+	// A expurgação da lixeira é executada pelo job para todos os tenants
+	lockPurgeableDeliveriesQuery = `SELECT * FROM entregas WHERE data_exclusao IS NOT NULL AND data_exclusao < ? FOR UPDATE`
 
-	deleteAllDeliveriesQuery = `DELETE FROM entregas`
+	purgeDeliveriesQuery = `DELETE FROM entregas WHERE data_exclusao IS NOT NULL AND data_exclusao < ?`
 )
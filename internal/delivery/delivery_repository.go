@@ -3,30 +3,53 @@ package delivery
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/samluiz/delivery-service/internal/audit"
+	"github.com/samluiz/delivery-service/internal/tenant"
 )
 
 type DeliveryRepository struct {
-	db *sql.DB
+	db    *sql.DB
+	audit audit.IAuditRepository
 }
 
+// Todas as consultas são filtradas pelo tenant do contexto (tenant.WithTenant); sem tenant, as operações
+// retornam tenant.ErrTenantRequired. A expurgação da lixeira é a única operação executada para todos os tenants.
 type IDeliveryRepository interface {
-	CreateDelivery(request *CreateDeliveryRequest) (*DeliveryResponse, error)
-	UpdateDelivery(request *UpdateDeliveryRequest, id int) (*DeliveryResponse, error)
-	GetDelivery(id int) (*DeliveryResponse, error)
-	GetDeliveries() ([]*DeliveryResponse, error)
-	GetDeliveriesByCity(city string) ([]*DeliveryResponse, error)
-	DeleteDelivery(id int) error
-	DeleteAllDeliveries() error
+	CreateDelivery(ctx context.Context, request *CreateDeliveryRequest) (*DeliveryResponse, error)
+	UpdateDelivery(ctx context.Context, request *UpdateDeliveryRequest, id int) (*DeliveryResponse, error)
+	GetDelivery(ctx context.Context, id int) (*DeliveryResponse, error)
+	GetDeliveries(ctx context.Context, filter *DeliveryFilter) ([]*DeliveryResponse, error)
+	GetDeliveriesByIDs(ctx context.Context, ids []int) ([]*DeliveryResponse, error)
+	DeleteDelivery(ctx context.Context, id int) (*DeliveryResponse, error)
+	RestoreDelivery(ctx context.Context, id int) (*DeliveryResponse, error)
+	PurgeDeletedDeliveries(ctx context.Context, before time.Time) (int64, error)
+	CountDeliveries(ctx context.Context, filter *DeliveryFilter) (int64, error)
+	DeleteDeliveries(ctx context.Context, filter *DeliveryFilter, expected int64) (int64, error)
+	GetDeliveryVersions(ctx context.Context, id int) ([]*DeliveryVersionResponse, error)
+	GetDeliveryVersionAsOf(ctx context.Context, id int, asOf time.Time) (*DeliveryVersionResponse, error)
+	RevertDelivery(ctx context.Context, id int, version int) (*DeliveryResponse, error)
 }
 
 func NewDeliveryRepository(db *sql.DB) IDeliveryRepository {
-	return &DeliveryRepository{db: db}
+	return &DeliveryRepository{db: db, audit: audit.NewAuditRepository(db)}
 }
 
+// Campos que não são registrados na auditoria por serem mantidos pelo banco de dados.
+var auditIgnoredFields = []string{"data_alteracao"}
+
 // Função responsável por inserir uma nova entrega no banco de dados.
-func (r DeliveryRepository) CreateDelivery(request *CreateDeliveryRequest) (*DeliveryResponse, error) {
-	// Criando contexto e transação para possibilitar rollback em caso de erro
-	ctx := context.Background()
+func (r DeliveryRepository) CreateDelivery(ctx context.Context, request *CreateDeliveryRequest) (*DeliveryResponse, error) {
+	tenantID, err := tenant.Require(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Criando transação para possibilitar rollback em caso de erro
 	tx, err := r.db.BeginTx(ctx, nil)
 
 	if err != nil {
@@ -34,7 +57,8 @@ func (r DeliveryRepository) CreateDelivery(request *CreateDeliveryRequest) (*Del
 	}
 
 	// Executando a query usando o contexto e transação
-	res, err := tx.ExecContext(ctx, insertDeliveryQuery,
+	queryCtx, span := startQuerySpan(ctx, "insertDelivery")
+	res, err := tx.ExecContext(queryCtx, insertDeliveryQuery,
 		&request.Cliente,
 		&request.Peso,
 		&request.Endereco,
@@ -45,94 +69,98 @@ func (r DeliveryRepository) CreateDelivery(request *CreateDeliveryRequest) (*Del
 		&request.Cidade,
 		&request.Estado,
 		&request.Pais,
-		&request.Latitude,
-		&request.Longitude,
+		request.Latitude,
+		request.Longitude,
+		&request.Motorista,
+		tenantID,
 	)
+	endSpan(span, err)
 
 	if err != nil {
+		tx.Rollback()
 		return nil, err
 	}
 
-	// Commitando a transação
-	err = tx.Commit()
+	id, err := res.LastInsertId()
 
 	if err != nil {
+		tx.Rollback()
 		return nil, err
 	}
 
-	id, err := res.LastInsertId()
+	// Buscando a entrega recém-criada na mesma transação para registrar a auditoria
+	created, err := r.lockDelivery(ctx, tx, tenantID, int(id))
 
 	if err != nil {
+		tx.Rollback()
 		return nil, err
 	}
 
-	// Buscando o delivery recém-criado
-	delivery, err := r.GetDelivery(int(id))
+	err = r.record(ctx, tx, audit.ActionCreate, nil, created)
 
 	if err != nil {
+		tx.Rollback()
 		return nil, err
 	}
 
-	return delivery, nil
-}
-
-// Função responsável por atualizar uma entrega pelo seu ID.
-func (r DeliveryRepository) UpdateDelivery(request *UpdateDeliveryRequest, id int) (*DeliveryResponse, error) {
-	// Criando contexto e transação para possibilitar rollback em caso de erro
-	ctx := context.Background()
-	tx, err := r.db.BeginTx(ctx, nil)
+	// Commitando a transação
+	err = tx.Commit()
 
 	if err != nil {
 		return nil, err
 	}
 
-	// Executando a query usando o contexto e transação
-	_, err = tx.ExecContext(ctx, updateDeliveryQuery,
-		&request.Peso,
-		&request.Endereco,
-		&request.Logradouro,
-		&request.Numero,
-		&request.Bairro,
-		&request.Complemento,
-		&request.Cidade,
-		&request.Estado,
-		&request.Pais,
-		&request.Latitude,
-		&request.Longitude,
-		id,
-	)
+	return created.ToDeliveryResponse(), nil
+}
 
-	if err != nil {
-		// Verificando se o erro aconteceu por não encontrar a entrega para atualização
-		if err == sql.ErrNoRows {
-			return nil, ErrDeliveryNotFound
+// Função responsável por atualizar uma entrega pelo seu ID.
+func (r DeliveryRepository) UpdateDelivery(ctx context.Context, request *UpdateDeliveryRequest, id int) (*DeliveryResponse, error) {
+	updated, err := r.mutate(ctx, id, audit.ActionUpdate, func(tx *sql.Tx, before *Delivery) error {
+		// Entregas na lixeira não podem ser alteradas
+		if before.DataExclusao != nil {
+			return ErrDeliveryNotFound
 		}
-		return nil, err
-	}
 
-	// Commitando a transação
-	err = tx.Commit()
-
-	if err != nil {
-		return nil, err
-	}
+		// Executando a query usando o contexto e transação
+		queryCtx, span := startQuerySpan(ctx, "updateDelivery")
+		_, err := tx.ExecContext(queryCtx, updateDeliveryQuery,
+			&request.Peso,
+			&request.Endereco,
+			&request.Logradouro,
+			&request.Numero,
+			&request.Bairro,
+			&request.Complemento,
+			&request.Cidade,
+			&request.Estado,
+			&request.Pais,
+			request.Latitude,
+			request.Longitude,
+			&request.Motorista,
+			id,
+			before.TenantID,
+		)
+		endSpan(span, err)
 
-	// Buscando o delivery recém-atualizado
-	delivery, err := r.GetDelivery(int(id))
+		return err
+	})
 
 	if err != nil {
 		return nil, err
 	}
 
-	return delivery, nil
+	return updated.ToDeliveryResponse(), nil
 }
 
-// Função responsável por buscar uma entrega pelo seu ID.
-func (r DeliveryRepository) GetDelivery(id int) (*DeliveryResponse, error) {
+// Interface comum entre *sql.Row e *sql.Rows para reaproveitar o scan.
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// Função responsável por escanear uma linha da tabela de entregas para o model.
+func scanDelivery(row scanner) (*Delivery, error) {
 	var delivery Delivery
 
-	// Executando a query de consulta sem necessidade de transação
-	err := r.db.QueryRow(getDeliveryQuery, id).Scan(
+	err := row.Scan(
 		&delivery.ID,
 		&delivery.Cliente,
 		&delivery.Peso,
@@ -148,8 +176,178 @@ func (r DeliveryRepository) GetDelivery(id int) (*DeliveryResponse, error) {
 		&delivery.Longitude,
 		&delivery.DataInclusao,
 		&delivery.DataAlteracao,
+		&delivery.Motorista,
+		&delivery.DataExclusao,
+		&delivery.TenantID,
 	)
 
+	if err != nil {
+		return nil, err
+	}
+
+	return &delivery, nil
+}
+
+// Função responsável por escanear todas as linhas de uma consulta na tabela de entregas.
+func scanDeliveries(rows *sql.Rows) ([]*Delivery, error) {
+	// Fechando a conexão com o cursor em caso de erro
+	defer rows.Close()
+
+	deliveries := make([]*Delivery, 0)
+
+	// Iterando sobre os resultados da consulta
+	for rows.Next() {
+		// Escaneando os resultados da consulta para o model
+		delivery, err := scanDelivery(rows)
+
+		if err != nil {
+			return nil, err
+		}
+
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+// Função responsável por buscar e bloquear uma entrega (incluindo as da lixeira) do tenant dentro da transação.
+func (r DeliveryRepository) lockDelivery(ctx context.Context, tx *sql.Tx, tenantID string, id int) (*Delivery, error) {
+	queryCtx, span := startQuerySpan(ctx, "lockDelivery")
+	delivery, err := scanDelivery(tx.QueryRowContext(queryCtx, lockDeliveryQuery, id, tenantID))
+	endSpan(span, err)
+
+	if err != nil {
+		// Verificando se o erro aconteceu por não encontrar a entrega
+		if err == sql.ErrNoRows {
+			return nil, ErrDeliveryNotFound
+		}
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+// Função responsável por registrar a auditoria e a nova versão de uma entrega dentro da transação.
+// O registro pertence ao tenant da entrega, inclusive nas expurgações executadas para todos os tenants.
+func (r DeliveryRepository) record(ctx context.Context, tx *sql.Tx, action audit.Action, before, after *Delivery) error {
+	current := after
+	if current == nil {
+		current = before
+	}
+
+	changes := audit.Diff(before, after, auditIgnoredFields...)
+
+	entry := audit.NewEntry(ctx, audit.EntityDelivery, current.ID, action, changes)
+	entry.TenantID = current.TenantID
+
+	err := r.audit.Record(ctx, tx, entry)
+
+	if err != nil {
+		return err
+	}
+
+	// Entregas expurgadas não geram versão, as anteriores são removidas junto com a entrega
+	if after == nil {
+		return nil
+	}
+
+	return r.snapshot(ctx, tx, action, after)
+}
+
+// Função responsável por gravar uma cópia completa da entrega como sua próxima versão.
+// A entrega está bloqueada na transação, garantindo a sequência dos números de versão.
+func (r DeliveryRepository) snapshot(ctx context.Context, tx *sql.Tx, action audit.Action, delivery *Delivery) error {
+	data, err := json.Marshal(delivery.ToDeliveryResponse())
+
+	if err != nil {
+		return err
+	}
+
+	var version int
+
+	queryCtx, span := startTableQuerySpan(ctx, VERSIONS_TABLE_NAME, "nextDeliveryVersion")
+	err = tx.QueryRowContext(queryCtx, nextVersionQuery, delivery.ID).Scan(&version)
+	endSpan(span, err)
+
+	if err != nil {
+		return err
+	}
+
+	queryCtx, span = startTableQuerySpan(ctx, VERSIONS_TABLE_NAME, "insertDeliveryVersion")
+	_, err = tx.ExecContext(queryCtx, insertVersionQuery, delivery.ID, version, action, data, delivery.TenantID)
+	endSpan(span, err)
+
+	return err
+}
+
+// Função responsável por executar uma alteração em uma entrega existente.
+// A entrega é bloqueada antes da alteração e lida novamente depois dela, e ambas as versões
+// são registradas na auditoria na mesma transação. Qualquer erro desfaz a operação inteira.
+func (r DeliveryRepository) mutate(ctx context.Context, id int, action audit.Action, exec func(tx *sql.Tx, before *Delivery) error) (*Delivery, error) {
+	tenantID, err := tenant.Require(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Criando transação para possibilitar rollback em caso de erro
+	tx, err := r.db.BeginTx(ctx, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	before, err := r.lockDelivery(ctx, tx, tenantID, id)
+
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	err = exec(tx, before)
+
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	after, err := r.lockDelivery(ctx, tx, tenantID, id)
+
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	err = r.record(ctx, tx, action, before, after)
+
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Commitando a transação
+	err = tx.Commit()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return after, nil
+}
+
+// Função responsável por buscar uma entrega pelo seu ID.
+func (r DeliveryRepository) GetDelivery(ctx context.Context, id int) (*DeliveryResponse, error) {
+	tenantID, err := tenant.Require(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Executando a query de consulta sem necessidade de transação
+	queryCtx, span := startQuerySpan(ctx, "getDelivery")
+	delivery, err := scanDelivery(r.db.QueryRowContext(queryCtx, getDeliveryQuery, id, tenantID))
+	endSpan(span, err)
+
 	if err != nil {
 		// Verificando se o erro aconteceu por não encontrar a entrega
 		if err == sql.ErrNoRows {
@@ -164,156 +362,540 @@ func (r DeliveryRepository) GetDelivery(id int) (*DeliveryResponse, error) {
 	return response, nil
 }
 
-// Função responsável por buscar todas as entregas.
-func (r DeliveryRepository) GetDeliveries() ([]*DeliveryResponse, error) {
-	var deliveries []*DeliveryResponse = make([]*DeliveryResponse, 0)
+// Função responsável por montar a cláusula WHERE a partir dos filtros informados.
+// Os valores são sempre passados como parâmetros, nunca concatenados na query.
+// Entregas excluídas só são retornadas quando o filtro seleciona a lixeira, e sempre apenas as do tenant.
+func buildFilterClause(tenantID string, filter *DeliveryFilter) (string, []any) {
+	conditions := []string{"tenant_id = ?", "data_exclusao IS NULL"}
+	args := []any{tenantID}
 
-	// Executando a query de consulta sem necessidade de transação
-	rows, err := r.db.Query(getDeliveriesQuery)
+	if filter != nil {
+		if filter.Excluidas {
+			conditions[1] = "data_exclusao IS NOT NULL"
+		}
+		if filter.Cidade != "" {
+			conditions = append(conditions, "cidade = ?")
+			args = append(args, filter.Cidade)
+		}
+		if filter.Motorista != "" {
+			conditions = append(conditions, "motorista = ?")
+			args = append(args, filter.Motorista)
+		}
+		if filter.AposID > 0 {
+			conditions = append(conditions, "id < ?")
+			args = append(args, filter.AposID)
+		}
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// Colunas aceitas na ordenação da listagem. Campos fora da lista mantêm a ordenação padrão.
+var sortColumns = map[SortField]string{
+	SortByID:            "id",
+	SortByCliente:       "cliente",
+	SortByPeso:          "peso",
+	SortByCidade:        "cidade",
+	SortByDataInclusao:  "data_inclusao",
+	SortByDataAlteracao: "data_alteracao",
+}
+
+// Função responsável por montar a cláusula ORDER BY a partir da ordenação do filtro.
+// A coluna vem sempre da lista de colunas aceitas, nunca do valor recebido.
+func buildOrderClause(filter *DeliveryFilter) string {
+	if filter == nil {
+		return " ORDER BY id DESC"
+	}
+
+	column, ok := sortColumns[filter.Ordenacao.Campo]
+	if !ok {
+		return " ORDER BY id DESC"
+	}
+
+	direction := "DESC"
+	if filter.Ordenacao.Crescente {
+		direction = "ASC"
+	}
+
+	if column == "id" {
+		return " ORDER BY id " + direction
+	}
+
+	return " ORDER BY " + column + " " + direction + ", id " + direction
+}
+
+// Função responsável por buscar as entregas que satisfazem os filtros informados.
+func (r DeliveryRepository) GetDeliveries(ctx context.Context, filter *DeliveryFilter) ([]*DeliveryResponse, error) {
+	tenantID, err := tenant.Require(ctx)
 
 	if err != nil {
 		return nil, err
 	}
 
-	// Fechando a conexão com o cursor em caso de erro
-	defer rows.Close()
-
-	// Iterando sobre os resultados da consulta
-	for rows.Next() {
-		var delivery Delivery
+	where, args := buildFilterClause(tenantID, filter)
+	query := getDeliveriesQuery + where + buildOrderClause(filter)
 
-		// Escaneando os resultados da consulta para o model
-		err := rows.Scan(
-			&delivery.ID,
-			&delivery.Cliente,
-			&delivery.Peso,
-			&delivery.Endereco,
-			&delivery.Logradouro,
-			&delivery.Numero,
-			&delivery.Bairro,
-			&delivery.Complemento,
-			&delivery.Cidade,
-			&delivery.Estado,
-			&delivery.Pais,
-			&delivery.Latitude,
-			&delivery.Longitude,
-			&delivery.DataInclusao,
-			&delivery.DataAlteracao,
-		)
+	if filter != nil && filter.Limite > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limite)
 
-		if err != nil {
-			return nil, err
+		if filter.Deslocamento > 0 {
+			query += " OFFSET ?"
+			args = append(args, filter.Deslocamento)
 		}
+	}
+
+	// Executando a query de consulta sem necessidade de transação
+	queryCtx, span := startQuerySpan(ctx, "getDeliveries")
+	rows, err := r.db.QueryContext(queryCtx, query, args...)
+	endSpan(span, err)
 
-		// Convertendo o model para o response e adicionando ao array
-		deliveries = append(deliveries, delivery.ToDeliveryResponse())
+	if err != nil {
+		return nil, err
 	}
 
-	return deliveries, nil
+	deliveries, err := scanDeliveries(rows)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Convertendo os models para o response
+	response := make([]*DeliveryResponse, len(deliveries))
+	for i, delivery := range deliveries {
+		response[i] = delivery.ToDeliveryResponse()
+	}
+
+	return response, nil
 }
 
-// Função responsável por buscar todas as entregas filtrando por cidade.
-func (r DeliveryRepository) GetDeliveriesByCity(city string) ([]*DeliveryResponse, error) {
-	var deliveries []*DeliveryResponse = make([]*DeliveryResponse, 0)
+// Função responsável por buscar as entregas ativas com os IDs informados em uma única consulta.
+// IDs inexistentes ou de entregas excluídas são ignorados e o resultado não segue a ordem dos IDs.
+func (r DeliveryRepository) GetDeliveriesByIDs(ctx context.Context, ids []int) ([]*DeliveryResponse, error) {
+	tenantID, err := tenant.Require(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ids) == 0 {
+		return []*DeliveryResponse{}, nil
+	}
+
+	placeholders := make([]string, len(ids))
+	args := []any{tenantID}
+	for i, id := range ids {
+		placeholders[i] = "?"
+		args = append(args, id)
+	}
+
+	query := getDeliveriesQuery + " WHERE tenant_id = ? AND data_exclusao IS NULL AND id IN (" + strings.Join(placeholders, ", ") + ")"
 
 	// Executando a query de consulta sem necessidade de transação
-	rows, err := r.db.Query(getDeliveriesByCityQuery, city)
+	queryCtx, span := startQuerySpan(ctx, "getDeliveriesByIDs")
+	rows, err := r.db.QueryContext(queryCtx, query, args...)
+	endSpan(span, err)
 
 	if err != nil {
 		return nil, err
 	}
 
-	// Fechando a conexão com o cursor em caso de erro
-	defer rows.Close()
+	deliveries, err := scanDeliveries(rows)
 
-	// Iterando sobre os resultados da consulta
-	for rows.Next() {
-		var delivery Delivery
+	if err != nil {
+		return nil, err
+	}
 
-		// Escaneando os resultados da consulta para o model
-		err := rows.Scan(
-			&delivery.ID,
-			&delivery.Cliente,
-			&delivery.Peso,
-			&delivery.Endereco,
-			&delivery.Logradouro,
-			&delivery.Numero,
-			&delivery.Bairro,
-			&delivery.Complemento,
-			&delivery.Cidade,
-			&delivery.Estado,
-			&delivery.Pais,
-			&delivery.Latitude,
-			&delivery.Longitude,
-			&delivery.DataInclusao,
-			&delivery.DataAlteracao,
-		)
+	// Convertendo os models para o response
+	response := make([]*DeliveryResponse, len(deliveries))
+	for i, delivery := range deliveries {
+		response[i] = delivery.ToDeliveryResponse()
+	}
 
-		if err != nil {
-			return nil, err
+	return response, nil
+}
+
+// Função responsável por excluir logicamente uma entrega pelo seu ID, retornando a entrega excluída.
+// A entrega é movida para a lixeira até ser restaurada ou expurgada.
+func (r DeliveryRepository) DeleteDelivery(ctx context.Context, id int) (*DeliveryResponse, error) {
+	deleted, err := r.mutate(ctx, id, audit.ActionDelete, func(tx *sql.Tx, before *Delivery) error {
+		// Entregas já excluídas são tratadas como inexistentes
+		if before.DataExclusao != nil {
+			return ErrDeliveryNotFound
+		}
+
+		// Executando a query usando o contexto e transação
+		queryCtx, span := startQuerySpan(ctx, "deleteDelivery")
+		_, err := tx.ExecContext(queryCtx, deleteDeliveryQuery, id, before.TenantID)
+		endSpan(span, err)
+
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return deleted.ToDeliveryResponse(), nil
+}
+
+// Função responsável por restaurar uma entrega da lixeira pelo seu ID.
+func (r DeliveryRepository) RestoreDelivery(ctx context.Context, id int) (*DeliveryResponse, error) {
+	restored, err := r.mutate(ctx, id, audit.ActionRestore, func(tx *sql.Tx, before *Delivery) error {
+		// Apenas entregas da lixeira podem ser restauradas
+		if before.DataExclusao == nil {
+			return ErrDeliveryNotFound
 		}
 
-		// Convertendo o model para o response e adicionando ao array
-		deliveries = append(deliveries, delivery.ToDeliveryResponse())
+		// Executando a query usando o contexto e transação
+		queryCtx, span := startQuerySpan(ctx, "restoreDelivery")
+		_, err := tx.ExecContext(queryCtx, restoreDeliveryQuery, id, before.TenantID)
+		endSpan(span, err)
+
+		return err
+	})
+
+	if err != nil {
+		return nil, err
 	}
 
-	return deliveries, nil
+	return restored.ToDeliveryResponse(), nil
 }
 
-// Função responsável por excluir uma entrega pelo seu ID.
-func (r DeliveryRepository) DeleteDelivery(id int) error {
-	// Criando contexto e transação para possibilitar rollback em caso de erro
-	ctx := context.Background()
+// Função responsável por excluir definitivamente as entregas que estão na lixeira desde antes da data informada.
+// Executada pelo job de expurgação para todos os tenants, cada registro de auditoria fica com o tenant da entrega.
+func (r DeliveryRepository) PurgeDeletedDeliveries(ctx context.Context, before time.Time) (int64, error) {
+	// Criando transação para possibilitar rollback em caso de erro
 	tx, err := r.db.BeginTx(ctx, nil)
 
 	if err != nil {
-		return err
+		return 0, err
+	}
+
+	// Bloqueando as entregas que serão expurgadas para registrar a auditoria
+	queryCtx, span := startQuerySpan(ctx, "lockPurgeableDeliveries")
+	rows, err := tx.QueryContext(queryCtx, lockPurgeableDeliveriesQuery, before)
+	endSpan(span, err)
+
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	purged, err := scanDeliveries(rows)
+
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	// Nada a expurgar
+	if len(purged) == 0 {
+		return 0, tx.Rollback()
 	}
 
 	// Executando a query usando o contexto e transação
-	_, err = tx.ExecContext(ctx, deleteDeliveryQuery, id)
+	queryCtx, span = startQuerySpan(ctx, "purgeDeliveries")
+	res, err := tx.ExecContext(queryCtx, purgeDeliveriesQuery, before)
+	endSpan(span, err)
 
 	if err != nil {
-		// Verificando se o erro aconteceu por não encontrar a entrega para exclusão
-		if err == sql.ErrNoRows {
-			return ErrDeliveryNotFound
+		tx.Rollback()
+		return 0, err
+	}
+
+	affected, err := res.RowsAffected()
+
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	for _, delivery := range purged {
+		if err := r.record(ctx, tx, audit.ActionPurge, delivery, nil); err != nil {
+			tx.Rollback()
+			return 0, err
 		}
-		return err
 	}
 
 	// Commitando a transação
 	err = tx.Commit()
 
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	return nil
+	return affected, nil
 }
 
-// Função responsável por excluir todas as entregas.
-func (r DeliveryRepository) DeleteAllDeliveries() error {
-	// Criando contexto e transação para possibilitar rollback em caso de erro
-	ctx := context.Background()
+// Função responsável por contar as entregas que satisfazem os filtros informados.
+func (r DeliveryRepository) CountDeliveries(ctx context.Context, filter *DeliveryFilter) (int64, error) {
+	var count int64
+
+	tenantID, err := tenant.Require(ctx)
+
+	if err != nil {
+		return 0, err
+	}
+
+	where, args := buildFilterClause(tenantID, filter)
+
+	// Executando a query de consulta sem necessidade de transação
+	queryCtx, span := startQuerySpan(ctx, "countDeliveries")
+	err = r.db.QueryRowContext(queryCtx, countDeliveriesQuery+where, args...).Scan(&count)
+	endSpan(span, err)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// Função responsável por excluir logicamente as entregas que satisfazem os filtros informados.
+// A exclusão é desfeita caso a quantidade de entregas removidas seja diferente da esperada.
+func (r DeliveryRepository) DeleteDeliveries(ctx context.Context, filter *DeliveryFilter, expected int64) (int64, error) {
+	tenantID, err := tenant.Require(ctx)
+
+	if err != nil {
+		return 0, err
+	}
+
+	// Criando transação para possibilitar rollback em caso de erro
 	tx, err := r.db.BeginTx(ctx, nil)
 
 	if err != nil {
-		return err
+		return 0, err
 	}
 
+	where, args := buildFilterClause(tenantID, filter)
+
+	// Bloqueando as entregas que serão excluídas para registrar a auditoria
+	queryCtx, span := startQuerySpan(ctx, "lockDeliveries")
+	rows, err := tx.QueryContext(queryCtx, getDeliveriesQuery+where+" FOR UPDATE", args...)
+	endSpan(span, err)
+
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	deleted, err := scanDeliveries(rows)
+
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	// Desfazendo a exclusão caso as entregas tenham mudado desde o dry-run
+	if int64(len(deleted)) != expected {
+		tx.Rollback()
+		return 0, ErrConfirmationMismatch
+	}
+
+	// A data de exclusão é definida aqui para ser registrada igual na entrega e na auditoria
+	deletedAt := time.Now().Truncate(time.Second)
+
 	// Executando a query usando o contexto e transação
-	_, err = tx.ExecContext(ctx, deleteAllDeliveriesQuery)
+	queryCtx, span = startQuerySpan(ctx, "deleteDeliveries")
+	res, err := tx.ExecContext(queryCtx, deleteDeliveriesQuery+where, append([]any{deletedAt}, args...)...)
+	endSpan(span, err)
 
 	if err != nil {
-		return err
+		tx.Rollback()
+		return 0, err
+	}
+
+	affected, err := res.RowsAffected()
+
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	if affected != expected {
+		tx.Rollback()
+		return 0, ErrConfirmationMismatch
+	}
+
+	for _, before := range deleted {
+		after := *before
+		after.DataExclusao = &deletedAt
+
+		if err := r.record(ctx, tx, audit.ActionDelete, before, &after); err != nil {
+			tx.Rollback()
+			return 0, err
+		}
 	}
 
 	// Commitando a transação
 	err = tx.Commit()
 
 	if err != nil {
+		return 0, err
+	}
+
+	return affected, nil
+}
+
+// Interface comum entre *sql.DB e *sql.Tx para consultas dentro ou fora de transações.
+type querier interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// Função responsável por escanear uma linha da tabela de versões para o model.
+func scanVersion(row scanner) (*DeliveryVersion, error) {
+	var version DeliveryVersion
+
+	err := row.Scan(
+		&version.EntregaID,
+		&version.Versao,
+		&version.Acao,
+		&version.Dados,
+		&version.DataVersao,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &version, nil
+}
+
+// Função responsável por buscar uma versão de uma entrega pelo seu número.
+func (r DeliveryRepository) getVersion(ctx context.Context, q querier, tenantID string, id int, version int) (*DeliveryVersionResponse, error) {
+	queryCtx, span := startTableQuerySpan(ctx, VERSIONS_TABLE_NAME, "getDeliveryVersion")
+	found, err := scanVersion(q.QueryRowContext(queryCtx, getVersionQuery, id, tenantID, version))
+	endSpan(span, err)
+
+	if err != nil {
+		// Verificando se o erro aconteceu por não encontrar a versão
+		if err == sql.ErrNoRows {
+			return nil, ErrVersionNotFound
+		}
+		return nil, err
+	}
+
+	return found.ToDeliveryVersionResponse()
+}
+
+// Função responsável por buscar as versões de uma entrega, da mais recente para a mais antiga.
+func (r DeliveryRepository) GetDeliveryVersions(ctx context.Context, id int) ([]*DeliveryVersionResponse, error) {
+	tenantID, err := tenant.Require(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Executando a query de consulta sem necessidade de transação
+	queryCtx, span := startTableQuerySpan(ctx, VERSIONS_TABLE_NAME, "getDeliveryVersions")
+	rows, err := r.db.QueryContext(queryCtx, getVersionsQuery, id, tenantID)
+	endSpan(span, err)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Fechando a conexão com o cursor em caso de erro
+	defer rows.Close()
+
+	versions := make([]*DeliveryVersionResponse, 0)
+
+	for rows.Next() {
+		version, err := scanVersion(rows)
+
+		if err != nil {
+			return nil, err
+		}
+
+		response, err := version.ToDeliveryVersionResponse()
+
+		if err != nil {
+			return nil, err
+		}
+
+		versions = append(versions, response)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Entregas sem versões não existem (ou já foram expurgadas)
+	if len(versions) == 0 {
+		return nil, ErrDeliveryNotFound
+	}
+
+	return versions, nil
+}
+
+// Função responsável por buscar a versão vigente de uma entrega no instante informado.
+func (r DeliveryRepository) GetDeliveryVersionAsOf(ctx context.Context, id int, asOf time.Time) (*DeliveryVersionResponse, error) {
+	tenantID, err := tenant.Require(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Executando a query de consulta sem necessidade de transação
+	queryCtx, span := startTableQuerySpan(ctx, VERSIONS_TABLE_NAME, "getDeliveryVersionAsOf")
+	version, err := scanVersion(r.db.QueryRowContext(queryCtx, getVersionAsOfQuery, id, tenantID, asOf))
+	endSpan(span, err)
+
+	if err != nil {
+		// Verificando se o erro aconteceu por a entrega não existir no instante informado
+		if err == sql.ErrNoRows {
+			return nil, ErrDeliveryNotFound
+		}
+		return nil, err
+	}
+
+	return version.ToDeliveryVersionResponse()
+}
+
+// Função responsável por reverter uma entrega para uma versão anterior.
+// Os dados da versão são gravados como uma nova escrita, gerando uma nova versão.
+func (r DeliveryRepository) RevertDelivery(ctx context.Context, id int, version int) (*DeliveryResponse, error) {
+	reverted, err := r.mutate(ctx, id, audit.ActionRevert, func(tx *sql.Tx, before *Delivery) error {
+		// Entregas na lixeira precisam ser restauradas antes da reversão
+		if before.DataExclusao != nil {
+			return ErrDeliveryNotFound
+		}
+
+		target, err := r.getVersion(ctx, tx, before.TenantID, id, version)
+
+		if err != nil {
+			return err
+		}
+
+		snapshot := target.Entrega
+
+		// Executando a query usando o contexto e transação
+		queryCtx, span := startQuerySpan(ctx, "revertDelivery")
+		_, err = tx.ExecContext(queryCtx, updateDeliveryQuery,
+			snapshot.Peso,
+			snapshot.Endereco,
+			snapshot.Logradouro,
+			snapshot.Numero,
+			snapshot.Bairro,
+			snapshot.Complemento,
+			snapshot.Cidade,
+			snapshot.Estado,
+			snapshot.Pais,
+			snapshot.Latitude,
+			snapshot.Longitude,
+			snapshot.Motorista,
+			id,
+			before.TenantID,
+		)
+		endSpan(span, err)
+
 		return err
+	})
+
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	return reverted.ToDeliveryResponse(), nil
 }
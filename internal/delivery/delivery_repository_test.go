@@ -9,6 +9,9 @@ import (
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/samluiz/delivery-service/internal/auth"
+	"github.com/samluiz/delivery-service/internal/requestmeta"
+	"github.com/samluiz/delivery-service/internal/tenant"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -34,6 +37,47 @@ func (m *MockDB) ExecContext(ctx context.Context, query string, args ...interfac
 	return call.Get(0).(sql.Result), call.Error(1)
 }
 
+// Colunas da tabela de entregas retornadas pelo SELECT *
+var deliveryColumns = []string{"id", "cliente", "peso", "endereco", "logradouro", "numero", "bairro", "complemento", "cidade", "estado", "pais", "latitude", "longitude", "data_inclusao", "data_alteracao", "motorista", "data_exclusao", "tenant_id"}
+
+// Tenant das operações dos testes
+const testTenant = "acme"
+
+// Função auxiliar que retorna o contexto com o tenant dos testes
+func tenantContext() context.Context {
+	return tenant.WithTenant(context.Background(), testTenant)
+}
+
+// Função auxiliar que retorna o ponteiro de uma coordenada dos requests
+func coordinate(value float64) *float64 {
+	return &value
+}
+
+// Função auxiliar que espera o bloqueio de uma entrega dentro da transação
+func expectLockDelivery(mock sqlmock.Sqlmock, id int, dataExclusao any) {
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM entregas WHERE id = ? AND tenant_id = ? FOR UPDATE`)).
+		WithArgs(id, testTenant).
+		WillReturnRows(sqlmock.NewRows(deliveryColumns).
+			AddRow(id, "Cliente A", 10.5, "Endereço 123", "Rua 1", "123", "Bairro A", "Casa", "Cidade A", "Estado A", "País A", 40.7128, -74.0060, time.Now(), time.Now(), "", dataExclusao, testTenant))
+}
+
+// Função auxiliar que espera o registro da auditoria de uma entrega dentro da transação
+func expectAuditRecord(mock sqlmock.Sqlmock, id int, action string) {
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO auditoria`)).
+		WithArgs("delivery", id, action, "system", "", "", sqlmock.AnyArg(), testTenant).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+}
+
+// Função auxiliar que espera a gravação da próxima versão de uma entrega dentro da transação
+func expectVersionRecord(mock sqlmock.Sqlmock, id int, action string, version int) {
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COALESCE(MAX(versao), 0) + 1 FROM entregas_versoes WHERE entrega_id = ?`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"versao"}).AddRow(version))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO entregas_versoes`)).
+		WithArgs(id, version, action, sqlmock.AnyArg(), testTenant).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+}
+
 // Testes das consultas na tabela de entregas
 
 func TestCreateDeliveryRepository(t *testing.T) {
@@ -54,24 +98,23 @@ func TestCreateDeliveryRepository(t *testing.T) {
 		Cidade:      "Cidade A",
 		Estado:      "Estado A",
 		Pais:        "País A",
-		Latitude:    40.7128,
-		Longitude:   -74.0060,
+		Latitude:    coordinate(40.7128),
+		Longitude:   coordinate(-74.0060),
 	}
 
 	mock.ExpectBegin()
 	mock.ExpectExec(`INSERT INTO entregas`).
-		WithArgs(request.Cliente, request.Peso, request.Endereco, request.Logradouro, request.Numero, request.Bairro, request.Complemento, request.Cidade, request.Estado, request.Pais, request.Latitude, request.Longitude).
+		WithArgs(request.Cliente, request.Peso, request.Endereco, request.Logradouro, request.Numero, request.Bairro, request.Complemento, request.Cidade, request.Estado, request.Pais, request.Latitude, request.Longitude, request.Motorista, testTenant).
 		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectLockDelivery(mock, 1, nil)
+	expectAuditRecord(mock, 1, "create")
+	expectVersionRecord(mock, 1, "create", 1)
 	mock.ExpectCommit()
 
-	mock.ExpectQuery(`SELECT \* FROM entregas WHERE id = \?`).
-		WithArgs(1).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "cliente", "peso", "endereco", "logradouro", "numero", "bairro", "complemento", "cidade", "estado", "pais", "latitude", "longitude", "data_inclusao", "data_alteracao"}).
-			AddRow(1, "Cliente A", 10.5, "Endereço 123", "Rua 1", "123", "Bairro A", "Casa", "Cidade A", "Estado A", "País A", 40.7128, -74.0060, time.Now(), time.Now()))
-
-	delivery, err := repo.CreateDelivery(request)
+	delivery, err := repo.CreateDelivery(tenantContext(), request)
 	assert.NoError(t, err)
 	assert.Equal(t, "Cliente A", delivery.Cliente)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 func TestUpdateDeliveryRepository(t *testing.T) {
@@ -91,24 +134,99 @@ func TestUpdateDeliveryRepository(t *testing.T) {
 		Cidade:      "Nova Cidade",
 		Estado:      "Novo Estado",
 		Pais:        "Novo País",
-		Latitude:    51.5074,
-		Longitude:   -0.1278,
+		Latitude:    coordinate(51.5074),
+		Longitude:   coordinate(-0.1278),
 	}
 
 	mock.ExpectBegin()
+	expectLockDelivery(mock, 1, nil)
 	mock.ExpectExec(`UPDATE entregas`).
-		WithArgs(request.Peso, request.Endereco, request.Logradouro, request.Numero, request.Bairro, request.Complemento, request.Cidade, request.Estado, request.Pais, request.Latitude, request.Longitude, 1).
+		WithArgs(request.Peso, request.Endereco, request.Logradouro, request.Numero, request.Bairro, request.Complemento, request.Cidade, request.Estado, request.Pais, request.Latitude, request.Longitude, request.Motorista, 1, testTenant).
 		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM entregas WHERE id = ? AND tenant_id = ? FOR UPDATE`)).
+		WithArgs(1, testTenant).
+		WillReturnRows(sqlmock.NewRows(deliveryColumns).
+			AddRow(1, "Cliente A", 12.5, "456 Novo Endereço", "Nova Rua", "456", "Novo Bairro", "Apartamento", "Nova Cidade", "Novo Estado", "Novo País", 51.5074, -0.1278, time.Now(), time.Now(), "", nil, testTenant))
+	expectAuditRecord(mock, 1, "update")
+	expectVersionRecord(mock, 1, "update", 2)
 	mock.ExpectCommit()
 
-	mock.ExpectQuery(`SELECT \* FROM entregas WHERE id = \?`).
-		WithArgs(1).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "cliente", "peso", "endereco", "logradouro", "numero", "bairro", "complemento", "cidade", "estado", "pais", "latitude", "longitude", "data_inclusao", "data_alteracao"}).
-			AddRow(1, "Cliente A", 12.5, "456 Novo Endereço", "Nova Rua", "456", "Novo Bairro", "Apartamento", "Nova Cidade", "Novo Estado", "Novo País", 51.5074, -0.1278, time.Now(), time.Now()))
-
-	delivery, err := repo.UpdateDelivery(request, 1)
+	delivery, err := repo.UpdateDelivery(tenantContext(), request, 1)
 	assert.NoError(t, err)
 	assert.Equal(t, "Nova Cidade", delivery.Cidade)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateDelivery_AuditEntry(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewDeliveryRepository(db)
+
+	ctx := auth.WithPrincipal(tenantContext(), &auth.Principal{Type: auth.PrincipalUser, Subject: "user-1"})
+	ctx = requestmeta.WithMetadata(ctx, requestmeta.Metadata{RequestID: "req-1", ClientIP: "203.0.113.7"})
+
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM entregas WHERE id = ? AND tenant_id = ? FOR UPDATE`)).
+		WithArgs(1, testTenant).
+		WillReturnRows(sqlmock.NewRows(deliveryColumns).
+			AddRow(1, "Cliente A", 10.5, "Endereço 123", "Rua 1", "123", "Bairro A", "Casa", "Cidade A", "Estado A", "País A", 40.7128, -74.0060, now, now, "", nil, testTenant))
+	mock.ExpectExec(`UPDATE entregas`).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM entregas WHERE id = ? AND tenant_id = ? FOR UPDATE`)).
+		WithArgs(1, testTenant).
+		WillReturnRows(sqlmock.NewRows(deliveryColumns).
+			AddRow(1, "Cliente A", 10.5, "Endereço 123", "Rua 1", "123", "Bairro A", "Casa", "Nova Cidade", "Estado A", "País A", 40.7128, -74.0060, now, time.Now(), "", nil, testTenant))
+
+	// Apenas o campo alterado é registrado, com o ator e os metadados da requisição
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO auditoria`)).
+		WithArgs("delivery", 1, "update", "user-1", "req-1", "203.0.113.7", []byte(`[{"campo":"cidade","antes":"Cidade A","depois":"Nova Cidade"}]`), testTenant).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectVersionRecord(mock, 1, "update", 2)
+	mock.ExpectCommit()
+
+	_, err = repo.UpdateDelivery(ctx, &UpdateDeliveryRequest{Cidade: "Nova Cidade"}, 1)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateDelivery_AuditError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewDeliveryRepository(db)
+
+	// A falha ao gravar a auditoria desfaz a atualização
+	mock.ExpectBegin()
+	expectLockDelivery(mock, 1, nil)
+	mock.ExpectExec(`UPDATE entregas`).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectLockDelivery(mock, 1, nil)
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO auditoria`)).WillReturnError(errors.New("audit error"))
+	mock.ExpectRollback()
+
+	_, err = repo.UpdateDelivery(tenantContext(), &UpdateDeliveryRequest{}, 1)
+	assert.EqualError(t, err, "audit error")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateDelivery_Deleted(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewDeliveryRepository(db)
+
+	// Entregas da lixeira não podem ser alteradas
+	mock.ExpectBegin()
+	expectLockDelivery(mock, 1, time.Now())
+	mock.ExpectRollback()
+
+	_, err = repo.UpdateDelivery(tenantContext(), &UpdateDeliveryRequest{}, 1)
+	assert.ErrorIs(t, err, ErrDeliveryNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 func TestGetDeliveryRepository(t *testing.T) {
@@ -118,16 +236,46 @@ func TestGetDeliveryRepository(t *testing.T) {
 
 	repo := NewDeliveryRepository(db)
 
-	mock.ExpectQuery(`SELECT \* FROM entregas WHERE id = \?`).
-		WithArgs(1).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "cliente", "peso", "endereco", "logradouro", "numero", "bairro", "complemento", "cidade", "estado", "pais", "latitude", "longitude", "data_inclusao", "data_alteracao"}).
-			AddRow(1, "Cliente A", 10.5, "Endereço 123", "Rua 1", "123", "Bairro A", "Casa", "Cidade A", "Estado A", "País A", 40.7128, -74.0060, time.Now(), time.Now()))
+	mock.ExpectQuery(`SELECT \* FROM entregas WHERE id = \? AND tenant_id = \?`).
+		WithArgs(1, testTenant).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "cliente", "peso", "endereco", "logradouro", "numero", "bairro", "complemento", "cidade", "estado", "pais", "latitude", "longitude", "data_inclusao", "data_alteracao", "motorista", "data_exclusao", "tenant_id"}).
+			AddRow(1, "Cliente A", 10.5, "Endereço 123", "Rua 1", "123", "Bairro A", "Casa", "Cidade A", "Estado A", "País A", 40.7128, -74.0060, time.Now(), time.Now(), "", nil, testTenant))
 
-	delivery, err := repo.GetDelivery(1)
+	delivery, err := repo.GetDelivery(tenantContext(), 1)
 	assert.NoError(t, err)
 	assert.Equal(t, "Cliente A", delivery.Cliente)
 }
 
+func TestDeliveryRepository_TenantRequired(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewDeliveryRepository(db)
+	ctx := context.Background()
+
+	// Sem tenant no contexto nenhuma consulta é executada
+	_, err = repo.CreateDelivery(ctx, &CreateDeliveryRequest{})
+	assert.ErrorIs(t, err, tenant.ErrTenantRequired)
+
+	_, err = repo.GetDelivery(ctx, 1)
+	assert.ErrorIs(t, err, tenant.ErrTenantRequired)
+
+	_, err = repo.GetDeliveries(ctx, nil)
+	assert.ErrorIs(t, err, tenant.ErrTenantRequired)
+
+	_, err = repo.UpdateDelivery(ctx, &UpdateDeliveryRequest{}, 1)
+	assert.ErrorIs(t, err, tenant.ErrTenantRequired)
+
+	_, err = repo.CountDeliveries(ctx, nil)
+	assert.ErrorIs(t, err, tenant.ErrTenantRequired)
+
+	_, err = repo.GetDeliveryVersions(ctx, 1)
+	assert.ErrorIs(t, err, tenant.ErrTenantRequired)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestGetDeliveriesRepository(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
@@ -135,12 +283,13 @@ func TestGetDeliveriesRepository(t *testing.T) {
 
 	repo := NewDeliveryRepository(db)
 
-	mock.ExpectQuery(`SELECT \* FROM entregas ORDER BY id DESC`).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "cliente", "peso", "endereco", "logradouro", "numero", "bairro", "complemento", "cidade", "estado", "pais", "latitude", "longitude", "data_inclusao", "data_alteracao"}).
-			AddRow(1, "Cliente A", 10.5, "Endereço 123", "Rua 1", "123", "Bairro A", "Casa", "Cidade A", "Estado A", "País A", 40.7128, -74.0060, time.Now(), time.Now()).
-			AddRow(2, "Cliente B", 20.0, "Endereço 456", "Rua 2", "456", "Bairro B", "Apartamento", "Cidade B", "Estado B", "País B", 51.5074, -0.1278, time.Now(), time.Now()))
+	mock.ExpectQuery(`SELECT \* FROM entregas WHERE tenant_id = \? AND data_exclusao IS NULL ORDER BY id DESC`).
+		WithArgs(testTenant).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "cliente", "peso", "endereco", "logradouro", "numero", "bairro", "complemento", "cidade", "estado", "pais", "latitude", "longitude", "data_inclusao", "data_alteracao", "motorista", "data_exclusao", "tenant_id"}).
+			AddRow(1, "Cliente A", 10.5, "Endereço 123", "Rua 1", "123", "Bairro A", "Casa", "Cidade A", "Estado A", "País A", 40.7128, -74.0060, time.Now(), time.Now(), "", nil, testTenant).
+			AddRow(2, "Cliente B", 20.0, "Endereço 456", "Rua 2", "456", "Bairro B", "Apartamento", "Cidade B", "Estado B", "País B", 51.5074, -0.1278, time.Now(), time.Now(), "", nil, testTenant))
 
-	deliveries, err := repo.GetDeliveries()
+	deliveries, err := repo.GetDeliveries(tenantContext(), nil)
 	assert.NoError(t, err)
 	assert.Len(t, deliveries, 2)
 }
@@ -152,14 +301,106 @@ func TestGetDeliveriesByCity(t *testing.T) {
 
 	repo := NewDeliveryRepository(db)
 
-	mock.ExpectQuery(`SELECT \* FROM entregas WHERE cidade = \? ORDER BY id DESC`).
-		WithArgs("São Paulo").
-		WillReturnRows(sqlmock.NewRows([]string{"id", "cliente", "peso", "endereco", "logradouro", "numero", "bairro", "complemento", "cidade", "estado", "pais", "latitude", "longitude", "data_inclusao", "data_alteracao"}).
-			AddRow(2, "Cliente B", 20.0, "Endereço 456", "Rua 2", "456", "Bairro B", "Apartamento", "São Paulo", "Estado B", "País B", 51.5074, -0.1278, time.Now(), time.Now()))
+	mock.ExpectQuery(`SELECT \* FROM entregas WHERE tenant_id = \? AND data_exclusao IS NULL AND cidade = \? ORDER BY id DESC`).
+		WithArgs(testTenant, "São Paulo").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "cliente", "peso", "endereco", "logradouro", "numero", "bairro", "complemento", "cidade", "estado", "pais", "latitude", "longitude", "data_inclusao", "data_alteracao", "motorista", "data_exclusao", "tenant_id"}).
+			AddRow(2, "Cliente B", 20.0, "Endereço 456", "Rua 2", "456", "Bairro B", "Apartamento", "São Paulo", "Estado B", "País B", 51.5074, -0.1278, time.Now(), time.Now(), "", nil, testTenant))
+
+	deliveries, err := repo.GetDeliveries(tenantContext(), &DeliveryFilter{Cidade: "São Paulo"})
+	assert.NoError(t, err)
+	assert.Len(t, deliveries, 1)
+}
+
+func TestGetDeliveriesByCityAndDriver(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewDeliveryRepository(db)
+
+	mock.ExpectQuery(`SELECT \* FROM entregas WHERE tenant_id = \? AND data_exclusao IS NULL AND cidade = \? AND motorista = \? ORDER BY id DESC`).
+		WithArgs(testTenant, "São Paulo", "driver-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "cliente", "peso", "endereco", "logradouro", "numero", "bairro", "complemento", "cidade", "estado", "pais", "latitude", "longitude", "data_inclusao", "data_alteracao", "motorista", "data_exclusao", "tenant_id"}).
+			AddRow(2, "Cliente B", 20.0, "Endereço 456", "Rua 2", "456", "Bairro B", "Apartamento", "São Paulo", "Estado B", "País B", 51.5074, -0.1278, time.Now(), time.Now(), "driver-1", nil, testTenant))
+
+	deliveries, err := repo.GetDeliveries(tenantContext(), &DeliveryFilter{Cidade: "São Paulo", Motorista: "driver-1"})
 
-	deliveries, err := repo.GetDeliveriesByCity("São Paulo")
 	assert.NoError(t, err)
 	assert.Len(t, deliveries, 1)
+	assert.Equal(t, "driver-1", deliveries[0].Motorista)
+}
+
+func TestGetDeliveriesPaginated(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewDeliveryRepository(db)
+
+	mock.ExpectQuery(`SELECT \* FROM entregas WHERE tenant_id = \? AND data_exclusao IS NULL AND cidade = \? AND id < \? ORDER BY id DESC LIMIT \?`).
+		WithArgs(testTenant, "São Paulo", 10, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "cliente", "peso", "endereco", "logradouro", "numero", "bairro", "complemento", "cidade", "estado", "pais", "latitude", "longitude", "data_inclusao", "data_alteracao", "motorista", "data_exclusao", "tenant_id"}).
+			AddRow(9, "Cliente B", 20.0, "Endereço 456", "Rua 2", "456", "Bairro B", "Apartamento", "São Paulo", "Estado B", "País B", 51.5074, -0.1278, time.Now(), time.Now(), "", nil, testTenant).
+			AddRow(7, "Cliente C", 30.0, "Endereço 789", "Rua 3", "789", "Bairro C", "Casa", "São Paulo", "Estado C", "País C", 51.5074, -0.1278, time.Now(), time.Now(), "", nil, testTenant))
+
+	deliveries, err := repo.GetDeliveries(tenantContext(), &DeliveryFilter{Cidade: "São Paulo", AposID: 10, Limite: 2})
+
+	assert.NoError(t, err)
+	assert.Len(t, deliveries, 2)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDeliveriesSorted(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewDeliveryRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM entregas WHERE tenant_id = ? AND data_exclusao IS NULL ORDER BY peso ASC, id ASC LIMIT ? OFFSET ?`)).
+		WithArgs(testTenant, 10, 20).
+		WillReturnRows(sqlmock.NewRows(deliveryColumns))
+
+	_, err = repo.GetDeliveries(tenantContext(), &DeliveryFilter{
+		Ordenacao:    DeliverySort{Campo: SortByPeso, Crescente: true},
+		Limite:       10,
+		Deslocamento: 20,
+	})
+	assert.NoError(t, err)
+
+	// Campos fora da lista de colunas mantêm a ordenação padrão
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM entregas WHERE tenant_id = ? AND data_exclusao IS NULL ORDER BY id DESC`)).
+		WillReturnRows(sqlmock.NewRows(deliveryColumns))
+
+	_, err = repo.GetDeliveries(tenantContext(), &DeliveryFilter{Ordenacao: DeliverySort{Campo: "peso; DROP TABLE entregas"}})
+	assert.NoError(t, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDeliveriesByIDs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewDeliveryRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM entregas WHERE tenant_id = ? AND data_exclusao IS NULL AND id IN (?, ?, ?)`)).
+		WithArgs(testTenant, 1, 2, 3).
+		WillReturnRows(sqlmock.NewRows(deliveryColumns).
+			AddRow(1, "Cliente A", 10.0, "Endereço 123", "Rua 1", "123", "Bairro A", "Casa", "Cidade A", "Estado A", "País A", 40.7128, -74.0060, time.Now(), time.Now(), "", nil, testTenant).
+			AddRow(3, "Cliente C", 30.0, "Endereço 789", "Rua 3", "789", "Bairro C", "Casa", "Cidade C", "Estado C", "País C", 51.5074, -0.1278, time.Now(), time.Now(), "", nil, testTenant))
+
+	deliveries, err := repo.GetDeliveriesByIDs(tenantContext(), []int{1, 2, 3})
+
+	assert.NoError(t, err)
+	assert.Len(t, deliveries, 2)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// Sem IDs, o banco não é consultado
+	deliveries, err = repo.GetDeliveriesByIDs(tenantContext(), nil)
+	assert.NoError(t, err)
+	assert.Empty(t, deliveries)
 }
 
 func TestDeleteDeliveryRepository(t *testing.T) {
@@ -170,29 +411,333 @@ func TestDeleteDeliveryRepository(t *testing.T) {
 	repo := NewDeliveryRepository(db)
 
 	mock.ExpectBegin()
-	mock.ExpectExec(`DELETE FROM entregas WHERE id = \?`).
-		WithArgs(1).
+	expectLockDelivery(mock, 1, nil)
+	mock.ExpectExec(`UPDATE entregas SET data_exclusao = CURRENT_TIMESTAMP WHERE id = \? AND tenant_id = \? AND data_exclusao IS NULL`).
+		WithArgs(1, testTenant).
 		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectLockDelivery(mock, 1, time.Now())
+	expectAuditRecord(mock, 1, "delete")
+	expectVersionRecord(mock, 1, "delete", 2)
 	mock.ExpectCommit()
 
-	err = repo.DeleteDelivery(1)
+	deleted, err := repo.DeleteDelivery(tenantContext(), 1)
 	assert.NoError(t, err)
+	assert.Equal(t, 1, deleted.ID)
+	assert.NotNil(t, deleted.DataExclusao)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestDeleteAllDeliveriesRepository(t *testing.T) {
+func TestDeleteDelivery_NotFound(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
 
 	repo := NewDeliveryRepository(db)
 
+	// Entrega inexistente
 	mock.ExpectBegin()
-	mock.ExpectExec(`DELETE FROM entregas`).
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM entregas WHERE id = ? AND tenant_id = ? FOR UPDATE`)).
+		WithArgs(1, testTenant).
+		WillReturnRows(sqlmock.NewRows(deliveryColumns))
+	mock.ExpectRollback()
+
+	_, err = repo.DeleteDelivery(tenantContext(), 1)
+	assert.ErrorIs(t, err, ErrDeliveryNotFound)
+
+	// Entrega já excluída
+	mock.ExpectBegin()
+	expectLockDelivery(mock, 1, time.Now())
+	mock.ExpectRollback()
+
+	_, err = repo.DeleteDelivery(tenantContext(), 1)
+	assert.ErrorIs(t, err, ErrDeliveryNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDeletedDeliveriesRepository(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewDeliveryRepository(db)
+
+	deletedAt := time.Now()
+	mock.ExpectQuery(`SELECT \* FROM entregas WHERE tenant_id = \? AND data_exclusao IS NOT NULL ORDER BY id DESC`).
+		WithArgs(testTenant).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "cliente", "peso", "endereco", "logradouro", "numero", "bairro", "complemento", "cidade", "estado", "pais", "latitude", "longitude", "data_inclusao", "data_alteracao", "motorista", "data_exclusao", "tenant_id"}).
+			AddRow(1, "Cliente A", 10.5, "Endereço 123", "Rua 1", "123", "Bairro A", "Casa", "Cidade A", "Estado A", "País A", 40.7128, -74.0060, time.Now(), time.Now(), "", deletedAt, testTenant))
+
+	deliveries, err := repo.GetDeliveries(tenantContext(), &DeliveryFilter{Excluidas: true})
+	assert.NoError(t, err)
+	assert.Len(t, deliveries, 1)
+	assert.Equal(t, deletedAt, *deliveries[0].DataExclusao)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRestoreDeliveryRepository(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewDeliveryRepository(db)
+
+	mock.ExpectBegin()
+	expectLockDelivery(mock, 1, time.Now())
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE entregas SET data_exclusao = NULL WHERE id = ? AND tenant_id = ? AND data_exclusao IS NOT NULL`)).
+		WithArgs(1, testTenant).
 		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectLockDelivery(mock, 1, nil)
+	expectAuditRecord(mock, 1, "restore")
+	expectVersionRecord(mock, 1, "restore", 2)
+	mock.ExpectCommit()
+
+	delivery, err := repo.RestoreDelivery(tenantContext(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, delivery.ID)
+	assert.Nil(t, delivery.DataExclusao)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRestoreDelivery_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewDeliveryRepository(db)
+
+	// Entrega fora da lixeira
+	mock.ExpectBegin()
+	expectLockDelivery(mock, 1, nil)
+	mock.ExpectRollback()
+
+	_, err = repo.RestoreDelivery(tenantContext(), 1)
+	assert.ErrorIs(t, err, ErrDeliveryNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPurgeDeletedDeliveriesRepository(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewDeliveryRepository(db)
+
+	before := time.Now().Add(-24 * time.Hour)
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM entregas WHERE data_exclusao IS NOT NULL AND data_exclusao < ? FOR UPDATE`)).
+		WithArgs(before).
+		WillReturnRows(sqlmock.NewRows(deliveryColumns).
+			AddRow(1, "Cliente A", 10.5, "Endereço 123", "Rua 1", "123", "Bairro A", "Casa", "Cidade A", "Estado A", "País A", 40.7128, -74.0060, time.Now(), time.Now(), "", before, testTenant).
+			AddRow(2, "Cliente B", 20.0, "Endereço 456", "Rua 2", "456", "Bairro B", "Apartamento", "Cidade B", "Estado B", "País B", 51.5074, -0.1278, time.Now(), time.Now(), "", before, testTenant))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM entregas WHERE data_exclusao IS NOT NULL AND data_exclusao < ?`)).
+		WithArgs(before).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	expectAuditRecord(mock, 1, "purge")
+	expectAuditRecord(mock, 2, "purge")
+	mock.ExpectCommit()
+
+	purged, err := repo.PurgeDeletedDeliveries(context.Background(), before)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), purged)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPurgeDeletedDeliveries_NothingToPurge(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewDeliveryRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM entregas WHERE data_exclusao IS NOT NULL`)).
+		WillReturnRows(sqlmock.NewRows(deliveryColumns))
+	mock.ExpectRollback()
+
+	purged, err := repo.PurgeDeletedDeliveries(context.Background(), time.Now())
+	assert.NoError(t, err)
+	assert.Zero(t, purged)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCountDeliveriesRepository(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewDeliveryRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM entregas WHERE tenant_id = ? AND data_exclusao IS NULL AND cidade = ?`)).
+		WithArgs(testTenant, "Cidade A").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	count, err := repo.CountDeliveries(tenantContext(), &DeliveryFilter{Cidade: "Cidade A"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteDeliveriesRepository(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewDeliveryRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM entregas WHERE tenant_id = ? AND data_exclusao IS NULL AND cidade = ? AND motorista = ? FOR UPDATE`)).
+		WithArgs(testTenant, "Cidade A", "motorista-1").
+		WillReturnRows(sqlmock.NewRows(deliveryColumns).
+			AddRow(1, "Cliente A", 10.5, "Endereço 123", "Rua 1", "123", "Bairro A", "Casa", "Cidade A", "Estado A", "País A", 40.7128, -74.0060, time.Now(), time.Now(), "motorista-1", nil, testTenant).
+			AddRow(2, "Cliente B", 20.0, "Endereço 456", "Rua 2", "456", "Bairro B", "Apartamento", "Cidade A", "Estado B", "País B", 51.5074, -0.1278, time.Now(), time.Now(), "motorista-1", nil, testTenant))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE entregas SET data_exclusao = ? WHERE tenant_id = ? AND data_exclusao IS NULL AND cidade = ? AND motorista = ?`)).
+		WithArgs(sqlmock.AnyArg(), testTenant, "Cidade A", "motorista-1").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	expectAuditRecord(mock, 1, "delete")
+	expectVersionRecord(mock, 1, "delete", 2)
+	expectAuditRecord(mock, 2, "delete")
+	expectVersionRecord(mock, 2, "delete", 2)
 	mock.ExpectCommit()
 
-	err = repo.DeleteAllDeliveries()
+	deleted, err := repo.DeleteDeliveries(tenantContext(), &DeliveryFilter{Cidade: "Cidade A", Motorista: "motorista-1"}, 2)
 	assert.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteDeliveries_CountMismatch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewDeliveryRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM entregas WHERE tenant_id = ? AND data_exclusao IS NULL FOR UPDATE`)).
+		WillReturnRows(sqlmock.NewRows(deliveryColumns).
+			AddRow(1, "Cliente A", 10.5, "Endereço 123", "Rua 1", "123", "Bairro A", "Casa", "Cidade A", "Estado A", "País A", 40.7128, -74.0060, time.Now(), time.Now(), "", nil, testTenant))
+	mock.ExpectRollback()
+
+	deleted, err := repo.DeleteDeliveries(tenantContext(), &DeliveryFilter{}, 4)
+	assert.ErrorIs(t, err, ErrConfirmationMismatch)
+	assert.Zero(t, deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+var versionColumns = []string{"entrega_id", "versao", "acao", "dados", "data_versao"}
+
+func TestGetDeliveryVersionsRepository(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewDeliveryRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT entrega_id, versao, acao, dados, data_versao FROM entregas_versoes WHERE entrega_id = ? AND tenant_id = ? ORDER BY versao DESC`)).
+		WithArgs(1, testTenant).
+		WillReturnRows(sqlmock.NewRows(versionColumns).
+			AddRow(1, 2, "update", []byte(`{"id":1,"cidade":"Nova Cidade"}`), time.Now()).
+			AddRow(1, 1, "create", []byte(`{"id":1,"cidade":"Cidade A"}`), time.Now()))
+
+	versions, err := repo.GetDeliveryVersions(tenantContext(), 1)
+	assert.NoError(t, err)
+	assert.Len(t, versions, 2)
+	assert.Equal(t, 2, versions[0].Versao)
+	assert.Equal(t, "Nova Cidade", versions[0].Entrega.Cidade)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDeliveryVersions_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewDeliveryRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM entregas_versoes WHERE entrega_id = ? AND tenant_id = ?`)).
+		WithArgs(1, testTenant).
+		WillReturnRows(sqlmock.NewRows(versionColumns))
+
+	versions, err := repo.GetDeliveryVersions(tenantContext(), 1)
+	assert.ErrorIs(t, err, ErrDeliveryNotFound)
+	assert.Nil(t, versions)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDeliveryVersionAsOfRepository(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewDeliveryRepository(db)
+
+	asOf := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM entregas_versoes WHERE entrega_id = ? AND tenant_id = ? AND data_versao <= ? ORDER BY versao DESC LIMIT 1`)).
+		WithArgs(1, testTenant, asOf).
+		WillReturnRows(sqlmock.NewRows(versionColumns).
+			AddRow(1, 1, "create", []byte(`{"id":1,"cidade":"Cidade A"}`), asOf.Add(-time.Hour)))
+
+	version, err := repo.GetDeliveryVersionAsOf(tenantContext(), 1, asOf)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, version.Versao)
+	assert.Equal(t, "Cidade A", version.Entrega.Cidade)
+
+	// Antes da primeira versão a entrega não existia
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM entregas_versoes WHERE entrega_id = ? AND tenant_id = ? AND data_versao <= ?`)).
+		WithArgs(1, testTenant, asOf).
+		WillReturnRows(sqlmock.NewRows(versionColumns))
+
+	version, err = repo.GetDeliveryVersionAsOf(tenantContext(), 1, asOf)
+	assert.ErrorIs(t, err, ErrDeliveryNotFound)
+	assert.Nil(t, version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRevertDeliveryRepository(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewDeliveryRepository(db)
+
+	mock.ExpectBegin()
+	expectLockDelivery(mock, 1, nil)
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM entregas_versoes WHERE entrega_id = ? AND tenant_id = ? AND versao = ?`)).
+		WithArgs(1, testTenant, 1).
+		WillReturnRows(sqlmock.NewRows(versionColumns).
+			AddRow(1, 1, "create", []byte(`{"id":1,"peso":5,"cidade":"Cidade Antiga","motorista":"motorista-1"}`), time.Now()))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE entregas`)).
+		WithArgs(5.0, "", "", "", "", "", "Cidade Antiga", "", "", 0.0, 0.0, "motorista-1", 1, testTenant).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectLockDelivery(mock, 1, nil)
+	expectAuditRecord(mock, 1, "revert")
+	expectVersionRecord(mock, 1, "revert", 3)
+	mock.ExpectCommit()
+
+	_, err = repo.RevertDelivery(tenantContext(), 1, 1)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRevertDelivery_VersionNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewDeliveryRepository(db)
+
+	mock.ExpectBegin()
+	expectLockDelivery(mock, 1, nil)
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM entregas_versoes WHERE entrega_id = ? AND tenant_id = ? AND versao = ?`)).
+		WithArgs(1, testTenant, 9).
+		WillReturnRows(sqlmock.NewRows(versionColumns))
+	mock.ExpectRollback()
+
+	response, err := repo.RevertDelivery(tenantContext(), 1, 9)
+	assert.ErrorIs(t, err, ErrVersionNotFound)
+	assert.Nil(t, response)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 // Testes de erros nas consultas
@@ -207,7 +752,7 @@ func TestCreateDelivery_Error(t *testing.T) {
 	repo := NewDeliveryRepository(db)
 
 	request := &CreateDeliveryRequest{}
-	delivery, err := repo.CreateDelivery(request)
+	delivery, err := repo.CreateDelivery(tenantContext(), request)
 
 	assert.Nil(t, delivery)
 	assert.Error(t, err)
@@ -234,7 +779,9 @@ func TestCreateDelivery_ExecError(t *testing.T) {
 			estado,
 			pais,
 			latitude,
-			longitude
+			longitude,
+			motorista,
+			tenant_id
 		) VALUES (
 			?,
 			?,
@@ -247,13 +794,16 @@ func TestCreateDelivery_ExecError(t *testing.T) {
 			?,
 			?,
 			?,
+			?,
+			?,
 			?
 		)`)).WillReturnError(errors.New("exec error"))
+	mock.ExpectRollback()
 
 	repo := NewDeliveryRepository(db)
 
 	request := &CreateDeliveryRequest{}
-	delivery, err := repo.CreateDelivery(request)
+	delivery, err := repo.CreateDelivery(tenantContext(), request)
 
 	assert.Nil(t, delivery)
 	assert.Error(t, err)
@@ -280,7 +830,9 @@ func TestCreateDelivery_CommitError(t *testing.T) {
 			estado,
 			pais,
 			latitude,
-			longitude
+			longitude,
+			motorista,
+			tenant_id
 		) VALUES (
 			?,
 			?,
@@ -293,14 +845,19 @@ func TestCreateDelivery_CommitError(t *testing.T) {
 			?,
 			?,
 			?,
+			?,
+			?,
 			?
 		)`)).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectLockDelivery(mock, 1, nil)
+	expectAuditRecord(mock, 1, "create")
+	expectVersionRecord(mock, 1, "create", 1)
 	mock.ExpectCommit().WillReturnError(errors.New("commit error"))
 
 	repo := NewDeliveryRepository(db)
 
 	request := &CreateDeliveryRequest{}
-	delivery, err := repo.CreateDelivery(request)
+	delivery, err := repo.CreateDelivery(tenantContext(), request)
 
 	assert.Nil(t, delivery)
 	assert.Error(t, err)
@@ -319,7 +876,7 @@ func TestUpdateDelivery_Error(t *testing.T) {
 	repo := NewDeliveryRepository(db)
 
 	request := &UpdateDeliveryRequest{}
-	delivery, err := repo.UpdateDelivery(request, 1)
+	delivery, err := repo.UpdateDelivery(tenantContext(), request, 1)
 
 	assert.Nil(t, delivery)
 	assert.Error(t, err)
@@ -334,12 +891,14 @@ func TestUpdateDelivery_ExecError(t *testing.T) {
 	defer db.Close()
 
 	mock.ExpectBegin()
+	expectLockDelivery(mock, 1, nil)
 	mock.ExpectExec(`UPDATE entregas`).WillReturnError(errors.New("exec error"))
+	mock.ExpectRollback()
 
 	repo := NewDeliveryRepository(db)
 
 	request := &UpdateDeliveryRequest{}
-	delivery, err := repo.UpdateDelivery(request, 1)
+	delivery, err := repo.UpdateDelivery(tenantContext(), request, 1)
 
 	assert.Nil(t, delivery)
 	assert.Error(t, err)
@@ -354,8 +913,8 @@ func TestUpdateDelivery_CommitError(t *testing.T) {
 	defer db.Close()
 
 	mock.ExpectBegin()
+	expectLockDelivery(mock, 1, nil)
 	mock.ExpectExec(regexp.QuoteMeta(`UPDATE entregas SET 
-			cliente = ?, 
 			peso = ?, 
 			endereco = ?, 
 			logradouro = ?, 
@@ -366,14 +925,18 @@ func TestUpdateDelivery_CommitError(t *testing.T) {
 			estado = ?, 
 			pais = ?, 
 			latitude = ?, 
-			longitude = ? 
-		WHERE id = ?`)).WillReturnResult(sqlmock.NewResult(1, 1))
+			longitude = ?, 
+			motorista = ? 
+		WHERE id = ? AND tenant_id = ? AND data_exclusao IS NULL`)).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectLockDelivery(mock, 1, nil)
+	expectAuditRecord(mock, 1, "update")
+	expectVersionRecord(mock, 1, "update", 2)
 	mock.ExpectCommit().WillReturnError(errors.New("commit error"))
 
 	repo := NewDeliveryRepository(db)
 
 	request := &UpdateDeliveryRequest{}
-	delivery, err := repo.UpdateDelivery(request, 1)
+	delivery, err := repo.UpdateDelivery(tenantContext(), request, 1)
 
 	assert.Nil(t, delivery)
 	assert.Error(t, err)
@@ -391,7 +954,7 @@ func TestDeleteDelivery_Error(t *testing.T) {
 
 	repo := NewDeliveryRepository(db)
 
-	err = repo.DeleteDelivery(1)
+	_, err = repo.DeleteDelivery(tenantContext(), 1)
 
 	assert.Error(t, err)
 	assert.Equal(t, "transaction error", err.Error())
@@ -405,11 +968,13 @@ func TestDeleteDelivery_ExecError(t *testing.T) {
 	defer db.Close()
 
 	mock.ExpectBegin()
-	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM entregas WHERE id = ?`)).WillReturnError(errors.New("exec error"))
+	expectLockDelivery(mock, 1, nil)
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE entregas SET data_exclusao = CURRENT_TIMESTAMP WHERE id = ?`)).WillReturnError(errors.New("exec error"))
+	mock.ExpectRollback()
 
 	repo := NewDeliveryRepository(db)
 
-	err = repo.DeleteDelivery(1)
+	_, err = repo.DeleteDelivery(tenantContext(), 1)
 
 	assert.Error(t, err)
 	assert.Equal(t, "exec error", err.Error())
@@ -423,12 +988,16 @@ func TestDeleteDelivery_CommitError(t *testing.T) {
 	defer db.Close()
 
 	mock.ExpectBegin()
-	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM entregas WHERE id = ?`)).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectLockDelivery(mock, 1, nil)
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE entregas SET data_exclusao = CURRENT_TIMESTAMP WHERE id = ?`)).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectLockDelivery(mock, 1, time.Now())
+	expectAuditRecord(mock, 1, "delete")
+	expectVersionRecord(mock, 1, "delete", 2)
 	mock.ExpectCommit().WillReturnError(errors.New("commit error"))
 
 	repo := NewDeliveryRepository(db)
 
-	err = repo.DeleteDelivery(1)
+	_, err = repo.DeleteDelivery(tenantContext(), 1)
 
 	assert.Error(t, err)
 	assert.Equal(t, "commit error", err.Error())
@@ -445,7 +1014,7 @@ func TestGetDelivery_Error(t *testing.T) {
 
 	repo := NewDeliveryRepository(db)
 
-	delivery, err := repo.GetDelivery(1)
+	delivery, err := repo.GetDelivery(tenantContext(), 1)
 
 	assert.Nil(t, delivery)
 	assert.Error(t, err)
@@ -463,7 +1032,7 @@ func TestGetDeliveries_Error(t *testing.T) {
 
 	repo := NewDeliveryRepository(db)
 
-	deliveries, err := repo.GetDeliveries()
+	deliveries, err := repo.GetDeliveries(tenantContext(), nil)
 
 	assert.Nil(t, deliveries)
 	assert.Error(t, err)
@@ -472,7 +1041,7 @@ func TestGetDeliveries_Error(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestDeleteAllDeliveries_Error(t *testing.T) {
+func TestDeleteDeliveries_Error(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
@@ -481,7 +1050,7 @@ func TestDeleteAllDeliveries_Error(t *testing.T) {
 
 	repo := NewDeliveryRepository(db)
 
-	err = repo.DeleteAllDeliveries()
+	_, err = repo.DeleteDeliveries(tenantContext(), nil, 1)
 
 	assert.Error(t, err)
 	assert.Equal(t, "transaction error", err.Error())
@@ -489,17 +1058,21 @@ func TestDeleteAllDeliveries_Error(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestDeleteAllDeliveries_ExecError(t *testing.T) {
+func TestDeleteDeliveries_ExecError(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
 
 	mock.ExpectBegin()
-	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM entregas`)).WillReturnError(errors.New("exec error"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM entregas WHERE tenant_id = ? AND data_exclusao IS NULL FOR UPDATE`)).
+		WillReturnRows(sqlmock.NewRows(deliveryColumns).
+			AddRow(1, "Cliente A", 10.5, "Endereço 123", "Rua 1", "123", "Bairro A", "Casa", "Cidade A", "Estado A", "País A", 40.7128, -74.0060, time.Now(), time.Now(), "", nil, testTenant))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE entregas SET data_exclusao = ?`)).WillReturnError(errors.New("exec error"))
+	mock.ExpectRollback()
 
 	repo := NewDeliveryRepository(db)
 
-	err = repo.DeleteAllDeliveries()
+	_, err = repo.DeleteDeliveries(tenantContext(), nil, 1)
 
 	assert.Error(t, err)
 	assert.Equal(t, "exec error", err.Error())
@@ -507,18 +1080,23 @@ func TestDeleteAllDeliveries_ExecError(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestDeleteAllDeliveries_CommitError(t *testing.T) {
+func TestDeleteDeliveries_CommitError(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
 
 	mock.ExpectBegin()
-	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM entregas`)).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM entregas WHERE tenant_id = ? AND data_exclusao IS NULL FOR UPDATE`)).
+		WillReturnRows(sqlmock.NewRows(deliveryColumns).
+			AddRow(1, "Cliente A", 10.5, "Endereço 123", "Rua 1", "123", "Bairro A", "Casa", "Cidade A", "Estado A", "País A", 40.7128, -74.0060, time.Now(), time.Now(), "", nil, testTenant))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE entregas SET data_exclusao = ?`)).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectAuditRecord(mock, 1, "delete")
+	expectVersionRecord(mock, 1, "delete", 2)
 	mock.ExpectCommit().WillReturnError(errors.New("commit error"))
 
 	repo := NewDeliveryRepository(db)
 
-	err = repo.DeleteAllDeliveries()
+	_, err = repo.DeleteDeliveries(tenantContext(), nil, 1)
 
 	assert.Error(t, err)
 	assert.Equal(t, "commit error", err.Error())
@@ -1,45 +1,247 @@
 package delivery
 
+import (
+	"context"
+	"time"
+
+	"github.com/samluiz/delivery-service/internal/auth"
+	"github.com/samluiz/delivery-service/internal/tenant"
+)
+
 type DeliveryService struct {
 	repository IDeliveryRepository
+	bulkDelete BulkDeleteConfig
 }
 
 type IDeliveryService interface {
-	CreateDelivery(request *CreateDeliveryRequest) (*DeliveryResponse, error)
-	GetDelivery(id int) (*DeliveryResponse, error)
-	GetDeliveries(city string) ([]*DeliveryResponse, error)
-	UpdateDelivery(request *UpdateDeliveryRequest, id int) (*DeliveryResponse, error)
-	DeleteDelivery(id int) error
-	DeleteAllDeliveries() error
+	CreateDelivery(ctx context.Context, request *CreateDeliveryRequest) (*DeliveryResponse, error)
+	GetDelivery(ctx context.Context, id int) (*DeliveryResponse, error)
+	GetDeliveries(ctx context.Context, filter *DeliveryFilter) ([]*DeliveryResponse, error)
+	GetDeliveriesByIDs(ctx context.Context, ids []int) ([]*DeliveryResponse, error)
+	UpdateDelivery(ctx context.Context, request *UpdateDeliveryRequest, id int) (*DeliveryResponse, error)
+	DeleteDelivery(ctx context.Context, id int) (*DeliveryResponse, error)
+	GetDeletedDeliveries(ctx context.Context, filter *DeliveryFilter) ([]*DeliveryResponse, error)
+	RestoreDelivery(ctx context.Context, id int) (*DeliveryResponse, error)
+	PurgeDeletedDeliveries(ctx context.Context, retention time.Duration) (int64, error)
+	PreviewDeleteDeliveries(ctx context.Context, filter *DeliveryFilter) (*DeleteDeliveriesPreview, error)
+	DeleteDeliveries(ctx context.Context, filter *DeliveryFilter, token string) (*DeleteDeliveriesResult, error)
+	GetDeliveryAsOf(ctx context.Context, id int, asOf time.Time) (*DeliveryResponse, error)
+	GetDeliveryVersions(ctx context.Context, id int) ([]*DeliveryVersionResponse, error)
+	RevertDelivery(ctx context.Context, id int, version int) (*DeliveryResponse, error)
+}
+
+func NewDeliveryService(repository IDeliveryRepository, bulkDelete BulkDeleteConfig) IDeliveryService {
+	return &DeliveryService{repository: repository, bulkDelete: bulkDelete}
+}
+
+func (s DeliveryService) CreateDelivery(ctx context.Context, request *CreateDeliveryRequest) (*DeliveryResponse, error) {
+	ctx, span := tracer.Start(ctx, "DeliveryService.CreateDelivery")
+	response, err := s.repository.CreateDelivery(ctx, request)
+	endSpan(span, err)
+	return response, err
+}
+
+func (s DeliveryService) GetDelivery(ctx context.Context, id int) (*DeliveryResponse, error) {
+	ctx, span := tracer.Start(ctx, "DeliveryService.GetDelivery")
+	response, err := s.repository.GetDelivery(ctx, id)
+
+	if err == nil && !isVisible(ctx, response) {
+		response, err = nil, ErrDeliveryNotFound
+	}
+
+	endSpan(span, err)
+	return response, err
+}
+
+func (s DeliveryService) GetDeliveries(ctx context.Context, filter *DeliveryFilter) ([]*DeliveryResponse, error) {
+	ctx, span := tracer.Start(ctx, "DeliveryService.GetDeliveries")
+
+	if filter == nil {
+		filter = &DeliveryFilter{}
+	}
+
+	// Motoristas só enxergam as entregas atribuídas a eles, independente do filtro informado
+	if principal, ok := auth.FromContext(ctx); ok && principal.IsRestrictedToAssigned() {
+		filter.Motorista = principal.Subject
+	}
+
+	response, err := s.repository.GetDeliveries(ctx, filter)
+	endSpan(span, err)
+	return response, err
 }
 
-func NewDeliveryService(repository IDeliveryRepository) IDeliveryService {
-	return &DeliveryService{repository: repository}
+// Função responsável por buscar várias entregas pelos IDs em uma única consulta.
+// Entregas inexistentes ou não visíveis para o usuário autenticado são omitidas do resultado.
+func (s DeliveryService) GetDeliveriesByIDs(ctx context.Context, ids []int) ([]*DeliveryResponse, error) {
+	ctx, span := tracer.Start(ctx, "DeliveryService.GetDeliveriesByIDs")
+	deliveries, err := s.repository.GetDeliveriesByIDs(ctx, ids)
+	endSpan(span, err)
+
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]*DeliveryResponse, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		if isVisible(ctx, delivery) {
+			response = append(response, delivery)
+		}
+	}
+
+	return response, nil
 }
 
-func (s DeliveryService) CreateDelivery(request *CreateDeliveryRequest) (*DeliveryResponse, error) {
-	return s.repository.CreateDelivery(request)
+func (s DeliveryService) UpdateDelivery(ctx context.Context, request *UpdateDeliveryRequest, id int) (*DeliveryResponse, error) {
+	ctx, span := tracer.Start(ctx, "DeliveryService.UpdateDelivery")
+	response, err := s.repository.UpdateDelivery(ctx, request, id)
+	endSpan(span, err)
+	return response, err
 }
 
-func (s DeliveryService) GetDelivery(id int) (*DeliveryResponse, error) {
-	return s.repository.GetDelivery(id)
+func (s DeliveryService) DeleteDelivery(ctx context.Context, id int) (*DeliveryResponse, error) {
+	ctx, span := tracer.Start(ctx, "DeliveryService.DeleteDelivery")
+	response, err := s.repository.DeleteDelivery(ctx, id)
+	endSpan(span, err)
+	return response, err
 }
 
-func (s DeliveryService) GetDeliveries(city string) ([]*DeliveryResponse, error) {
-	if city == "" {
-		return s.repository.GetDeliveries()
+// Função responsável por listar as entregas da lixeira que satisfazem os filtros informados.
+func (s DeliveryService) GetDeletedDeliveries(ctx context.Context, filter *DeliveryFilter) ([]*DeliveryResponse, error) {
+	ctx, span := tracer.Start(ctx, "DeliveryService.GetDeletedDeliveries")
+
+	if filter == nil {
+		filter = &DeliveryFilter{}
 	}
-	return s.repository.GetDeliveriesByCity(city)
+	filter.Excluidas = true
+
+	response, err := s.repository.GetDeliveries(ctx, filter)
+	endSpan(span, err)
+	return response, err
+}
+
+func (s DeliveryService) RestoreDelivery(ctx context.Context, id int) (*DeliveryResponse, error) {
+	ctx, span := tracer.Start(ctx, "DeliveryService.RestoreDelivery")
+	response, err := s.repository.RestoreDelivery(ctx, id)
+	endSpan(span, err)
+	return response, err
+}
+
+// Função responsável por excluir definitivamente as entregas que estão na lixeira há mais tempo que a retenção.
+func (s DeliveryService) PurgeDeletedDeliveries(ctx context.Context, retention time.Duration) (int64, error) {
+	ctx, span := tracer.Start(ctx, "DeliveryService.PurgeDeletedDeliveries")
+	purged, err := s.repository.PurgeDeletedDeliveries(ctx, time.Now().Add(-retention))
+	endSpan(span, err)
+	return purged, err
+}
+
+// Função responsável pelo dry-run da exclusão em massa.
+// Retorna a quantidade de entregas que seriam excluídas e o token que confirma a exclusão.
+func (s DeliveryService) PreviewDeleteDeliveries(ctx context.Context, filter *DeliveryFilter) (*DeleteDeliveriesPreview, error) {
+	ctx, span := tracer.Start(ctx, "DeliveryService.PreviewDeleteDeliveries")
+
+	if !s.bulkDelete.enabled(ctx) {
+		endSpan(span, ErrBulkDeleteDisabled)
+		return nil, ErrBulkDeleteDisabled
+	}
+
+	if filter == nil {
+		filter = &DeliveryFilter{}
+	}
+
+	count, err := s.repository.CountDeliveries(ctx, filter)
+	endSpan(span, err)
+
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(s.bulkDelete.TokenTTL)
+	tenantID, _ := tenant.FromContext(ctx)
+
+	return &DeleteDeliveriesPreview{
+		Quantidade:       count,
+		TokenConfirmacao: signConfirmationToken(s.bulkDelete.Secret, tenantID, filter, count, expiresAt),
+		ExpiraEm:         expiresAt,
+	}, nil
+}
+
+// Função responsável pela exclusão em massa das entregas que satisfazem os filtros.
+// Exige o token obtido no dry-run com os mesmos filtros.
+func (s DeliveryService) DeleteDeliveries(ctx context.Context, filter *DeliveryFilter, token string) (*DeleteDeliveriesResult, error) {
+	ctx, span := tracer.Start(ctx, "DeliveryService.DeleteDeliveries")
+
+	response, err := s.deleteDeliveries(ctx, filter, token)
+	endSpan(span, err)
+	return response, err
+}
+
+func (s DeliveryService) deleteDeliveries(ctx context.Context, filter *DeliveryFilter, token string) (*DeleteDeliveriesResult, error) {
+	if !s.bulkDelete.enabled(ctx) {
+		return nil, ErrBulkDeleteDisabled
+	}
+
+	if token == "" {
+		return nil, ErrMissingConfirmationToken
+	}
+
+	if filter == nil {
+		filter = &DeliveryFilter{}
+	}
+
+	tenantID, _ := tenant.FromContext(ctx)
+
+	expected, err := verifyConfirmationToken(s.bulkDelete.Secret, token, tenantID, filter, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	deleted, err := s.repository.DeleteDeliveries(ctx, filter, expected)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DeleteDeliveriesResult{Quantidade: deleted}, nil
+}
+
+// Função responsável por buscar a entrega como ela estava no instante informado.
+func (s DeliveryService) GetDeliveryAsOf(ctx context.Context, id int, asOf time.Time) (*DeliveryResponse, error) {
+	ctx, span := tracer.Start(ctx, "DeliveryService.GetDeliveryAsOf")
+
+	var response *DeliveryResponse
+	version, err := s.repository.GetDeliveryVersionAsOf(ctx, id, asOf)
+
+	if err == nil {
+		response = version.Entrega
+
+		// Entregas que estavam na lixeira no instante informado são tratadas como inexistentes
+		if response.DataExclusao != nil || !isVisible(ctx, response) {
+			response, err = nil, ErrDeliveryNotFound
+		}
+	}
+
+	endSpan(span, err)
+	return response, err
 }
 
-func (s DeliveryService) UpdateDelivery(request *UpdateDeliveryRequest, id int) (*DeliveryResponse, error) {
-	return s.repository.UpdateDelivery(request, id)
+// Função responsável por listar o histórico de versões de uma entrega.
+func (s DeliveryService) GetDeliveryVersions(ctx context.Context, id int) ([]*DeliveryVersionResponse, error) {
+	ctx, span := tracer.Start(ctx, "DeliveryService.GetDeliveryVersions")
+	response, err := s.repository.GetDeliveryVersions(ctx, id)
+	endSpan(span, err)
+	return response, err
 }
 
-func (s DeliveryService) DeleteDelivery(id int) error {
-	return s.repository.DeleteDelivery(id)
+// Função responsável por reverter uma entrega para uma versão anterior, gravando-a como uma nova versão.
+func (s DeliveryService) RevertDelivery(ctx context.Context, id int, version int) (*DeliveryResponse, error) {
+	ctx, span := tracer.Start(ctx, "DeliveryService.RevertDelivery")
+	response, err := s.repository.RevertDelivery(ctx, id, version)
+	endSpan(span, err)
+	return response, err
 }
 
-func (s DeliveryService) DeleteAllDeliveries() error {
-	return s.repository.DeleteAllDeliveries()
+// Função responsável por verificar se a entrega é visível para o usuário autenticado.
+// Motoristas só enxergam as entregas atribuídas a eles, as demais são tratadas como inexistentes.
+func isVisible(ctx context.Context, response *DeliveryResponse) bool {
+	principal, ok := auth.FromContext(ctx)
+	return !ok || !principal.IsRestrictedToAssigned() || response.Motorista == principal.Subject
 }
@@ -1,8 +1,12 @@
 package delivery
 
 import (
+	"context"
 	"testing"
+	"time"
 
+	"github.com/samluiz/delivery-service/internal/auth"
+	"github.com/samluiz/delivery-service/internal/tenant"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -13,53 +17,100 @@ type MockDeliveryRepository struct {
 
 // Mocks de funções do repositório que o service chama
 
-func (m *MockDeliveryRepository) CreateDelivery(request *CreateDeliveryRequest) (*DeliveryResponse, error) {
-	args := m.Called(request)
+func (m *MockDeliveryRepository) CreateDelivery(ctx context.Context, request *CreateDeliveryRequest) (*DeliveryResponse, error) {
+	args := m.Called(ctx, request)
 	return args.Get(0).(*DeliveryResponse), args.Error(1)
 }
 
-func (m *MockDeliveryRepository) GetDelivery(id int) (*DeliveryResponse, error) {
-	args := m.Called(id)
+func (m *MockDeliveryRepository) GetDelivery(ctx context.Context, id int) (*DeliveryResponse, error) {
+	args := m.Called(ctx, id)
 	return args.Get(0).(*DeliveryResponse), args.Error(1)
 }
 
-func (m *MockDeliveryRepository) GetDeliveries() ([]*DeliveryResponse, error) {
-	args := m.Called()
+func (m *MockDeliveryRepository) GetDeliveries(ctx context.Context, filter *DeliveryFilter) ([]*DeliveryResponse, error) {
+	args := m.Called(ctx, filter)
 	return args.Get(0).([]*DeliveryResponse), args.Error(1)
 }
 
-func (m *MockDeliveryRepository) GetDeliveriesByCity(city string) ([]*DeliveryResponse, error) {
-	args := m.Called(city)
+func (m *MockDeliveryRepository) GetDeliveriesByIDs(ctx context.Context, ids []int) ([]*DeliveryResponse, error) {
+	args := m.Called(ctx, ids)
 	return args.Get(0).([]*DeliveryResponse), args.Error(1)
 }
 
-func (m *MockDeliveryRepository) UpdateDelivery(request *UpdateDeliveryRequest, id int) (*DeliveryResponse, error) {
-	args := m.Called(request, id)
+func (m *MockDeliveryRepository) UpdateDelivery(ctx context.Context, request *UpdateDeliveryRequest, id int) (*DeliveryResponse, error) {
+	args := m.Called(ctx, request, id)
 	return args.Get(0).(*DeliveryResponse), args.Error(1)
 }
 
-func (m *MockDeliveryRepository) DeleteDelivery(id int) error {
-	args := m.Called(id)
-	return args.Error(0)
+func (m *MockDeliveryRepository) DeleteDelivery(ctx context.Context, id int) (*DeliveryResponse, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*DeliveryResponse), args.Error(1)
+}
+
+func (m *MockDeliveryRepository) RestoreDelivery(ctx context.Context, id int) (*DeliveryResponse, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*DeliveryResponse), args.Error(1)
+}
+
+func (m *MockDeliveryRepository) PurgeDeletedDeliveries(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockDeliveryRepository) CountDeliveries(ctx context.Context, filter *DeliveryFilter) (int64, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockDeliveryRepository) DeleteDeliveries(ctx context.Context, filter *DeliveryFilter, expected int64) (int64, error) {
+	args := m.Called(ctx, filter, expected)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockDeliveryRepository) GetDeliveryVersions(ctx context.Context, id int) ([]*DeliveryVersionResponse, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*DeliveryVersionResponse), args.Error(1)
 }
 
-func (m *MockDeliveryRepository) DeleteAllDeliveries() error {
-	args := m.Called()
-	return args.Error(0)
+func (m *MockDeliveryRepository) GetDeliveryVersionAsOf(ctx context.Context, id int, asOf time.Time) (*DeliveryVersionResponse, error) {
+	args := m.Called(ctx, id, asOf)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*DeliveryVersionResponse), args.Error(1)
 }
 
+func (m *MockDeliveryRepository) RevertDelivery(ctx context.Context, id int, version int) (*DeliveryResponse, error) {
+	args := m.Called(ctx, id, version)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*DeliveryResponse), args.Error(1)
+}
+
+var bulkDeleteConfig = BulkDeleteConfig{Enabled: true, Secret: []byte("secret"), TokenTTL: time.Minute}
+
 // Testes das funções do service que chamam o repositório
 
 func TestCreateDelivery(t *testing.T) {
 	mockRepo := new(MockDeliveryRepository)
-	service := NewDeliveryService(mockRepo)
+	service := NewDeliveryService(mockRepo, BulkDeleteConfig{})
 
 	request := &CreateDeliveryRequest{}
 	expectedResponse := &DeliveryResponse{}
 
-	mockRepo.On("CreateDelivery", request).Return(expectedResponse, nil)
+	mockRepo.On("CreateDelivery", mock.Anything, request).Return(expectedResponse, nil)
 
-	response, err := service.CreateDelivery(request)
+	response, err := service.CreateDelivery(context.Background(), request)
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedResponse, response)
@@ -68,14 +119,14 @@ func TestCreateDelivery(t *testing.T) {
 
 func TestGetDelivery(t *testing.T) {
 	mockRepo := new(MockDeliveryRepository)
-	service := NewDeliveryService(mockRepo)
+	service := NewDeliveryService(mockRepo, BulkDeleteConfig{})
 
 	id := 1
 	expectedResponse := &DeliveryResponse{}
 
-	mockRepo.On("GetDelivery", id).Return(expectedResponse, nil)
+	mockRepo.On("GetDelivery", mock.Anything, id).Return(expectedResponse, nil)
 
-	response, err := service.GetDelivery(id)
+	response, err := service.GetDelivery(context.Background(), id)
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedResponse, response)
@@ -84,38 +135,104 @@ func TestGetDelivery(t *testing.T) {
 
 func TestGetDeliveries(t *testing.T) {
 	mockRepo := new(MockDeliveryRepository)
-	service := NewDeliveryService(mockRepo)
+	service := NewDeliveryService(mockRepo, BulkDeleteConfig{})
 
 	expectedResponse := []*DeliveryResponse{}
-	mockRepo.On("GetDeliveries").Return(expectedResponse, nil)
+	mockRepo.On("GetDeliveries", mock.Anything, &DeliveryFilter{}).Return(expectedResponse, nil)
 
-	response, err := service.GetDeliveries("")
+	response, err := service.GetDeliveries(context.Background(), nil)
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedResponse, response)
 	mockRepo.AssertExpectations(t)
 
-	city := "City1"
-	mockRepo.On("GetDeliveriesByCity", city).Return(expectedResponse, nil)
+	filter := &DeliveryFilter{Cidade: "City1"}
+	mockRepo.On("GetDeliveries", mock.Anything, filter).Return(expectedResponse, nil)
 
-	response, err = service.GetDeliveries(city)
+	response, err = service.GetDeliveries(context.Background(), filter)
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedResponse, response)
 	mockRepo.AssertExpectations(t)
 }
 
+func TestGetDeliveries_DriverSeesOnlyAssigned(t *testing.T) {
+	mockRepo := new(MockDeliveryRepository)
+	service := NewDeliveryService(mockRepo, BulkDeleteConfig{})
+
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{
+		Type:    auth.PrincipalUser,
+		Subject: "driver-1",
+		Roles:   []auth.Role{auth.RoleDriver},
+	})
+
+	expectedResponse := []*DeliveryResponse{{ID: 1, Motorista: "driver-1"}}
+	mockRepo.On("GetDeliveries", mock.Anything, &DeliveryFilter{Cidade: "City1", Motorista: "driver-1"}).Return(expectedResponse, nil)
+
+	// O filtro de motorista informado pelo usuário é sobrescrito
+	response, err := service.GetDeliveries(ctx, &DeliveryFilter{Cidade: "City1", Motorista: "driver-2"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedResponse, response)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetDelivery_DriverNotAssigned(t *testing.T) {
+	mockRepo := new(MockDeliveryRepository)
+	service := NewDeliveryService(mockRepo, BulkDeleteConfig{})
+
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{
+		Type:    auth.PrincipalUser,
+		Subject: "driver-1",
+		Roles:   []auth.Role{auth.RoleDriver},
+	})
+
+	mockRepo.On("GetDelivery", mock.Anything, 1).Return(&DeliveryResponse{ID: 1, Motorista: "driver-1"}, nil)
+	mockRepo.On("GetDelivery", mock.Anything, 2).Return(&DeliveryResponse{ID: 2, Motorista: "driver-2"}, nil)
+
+	response, err := service.GetDelivery(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, response.ID)
+
+	response, err = service.GetDelivery(ctx, 2)
+	assert.Nil(t, response)
+	assert.ErrorIs(t, err, ErrDeliveryNotFound)
+}
+
+func TestGetDeliveriesByIDs_DriverSeesOnlyAssigned(t *testing.T) {
+	mockRepo := new(MockDeliveryRepository)
+	service := NewDeliveryService(mockRepo, BulkDeleteConfig{})
+
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{
+		Type:    auth.PrincipalUser,
+		Subject: "driver-1",
+		Roles:   []auth.Role{auth.RoleDriver},
+	})
+
+	mockRepo.On("GetDeliveriesByIDs", mock.Anything, []int{1, 2}).Return([]*DeliveryResponse{
+		{ID: 1, Motorista: "driver-1"},
+		{ID: 2, Motorista: "driver-2"},
+	}, nil)
+
+	// A entrega de outro motorista é omitida
+	response, err := service.GetDeliveriesByIDs(ctx, []int{1, 2})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []*DeliveryResponse{{ID: 1, Motorista: "driver-1"}}, response)
+	mockRepo.AssertExpectations(t)
+}
+
 func TestUpdateDelivery(t *testing.T) {
 	mockRepo := new(MockDeliveryRepository)
-	service := NewDeliveryService(mockRepo)
+	service := NewDeliveryService(mockRepo, BulkDeleteConfig{})
 
 	request := &UpdateDeliveryRequest{}
 	id := 1
 	expectedResponse := &DeliveryResponse{}
 
-	mockRepo.On("UpdateDelivery", request, id).Return(expectedResponse, nil)
+	mockRepo.On("UpdateDelivery", mock.Anything, request, id).Return(expectedResponse, nil)
 
-	response, err := service.UpdateDelivery(request, id)
+	response, err := service.UpdateDelivery(context.Background(), request, id)
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedResponse, response)
@@ -124,26 +241,283 @@ func TestUpdateDelivery(t *testing.T) {
 
 func TestDeleteDelivery(t *testing.T) {
 	mockRepo := new(MockDeliveryRepository)
-	service := NewDeliveryService(mockRepo)
+	service := NewDeliveryService(mockRepo, BulkDeleteConfig{})
 
 	id := 1
 
-	mockRepo.On("DeleteDelivery", id).Return(nil)
+	mockRepo.On("DeleteDelivery", mock.Anything, id).Return(&DeliveryResponse{ID: id}, nil)
+
+	response, err := service.DeleteDelivery(context.Background(), id)
+
+	assert.NoError(t, err)
+	assert.Equal(t, id, response.ID)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetDeletedDeliveries(t *testing.T) {
+	mockRepo := new(MockDeliveryRepository)
+	service := NewDeliveryService(mockRepo, BulkDeleteConfig{})
+
+	expectedResponse := []*DeliveryResponse{{ID: 1}}
+	mockRepo.On("GetDeliveries", mock.Anything, &DeliveryFilter{Cidade: "Cidade A", Excluidas: true}).Return(expectedResponse, nil)
+
+	response, err := service.GetDeletedDeliveries(context.Background(), &DeliveryFilter{Cidade: "Cidade A"})
 
-	err := service.DeleteDelivery(id)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedResponse, response)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRestoreDelivery(t *testing.T) {
+	mockRepo := new(MockDeliveryRepository)
+	service := NewDeliveryService(mockRepo, BulkDeleteConfig{})
+
+	expectedResponse := &DeliveryResponse{ID: 1}
+	mockRepo.On("RestoreDelivery", mock.Anything, 1).Return(expectedResponse, nil)
+
+	response, err := service.RestoreDelivery(context.Background(), 1)
 
 	assert.NoError(t, err)
+	assert.Equal(t, expectedResponse, response)
 	mockRepo.AssertExpectations(t)
 }
 
-func TestDeleteAllDeliveries(t *testing.T) {
+func TestGetDeliveryAsOf(t *testing.T) {
 	mockRepo := new(MockDeliveryRepository)
-	service := NewDeliveryService(mockRepo)
+	service := NewDeliveryService(mockRepo, BulkDeleteConfig{})
 
-	mockRepo.On("DeleteAllDeliveries").Return(nil)
+	asOf := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	deletedAt := asOf.Add(-time.Hour)
 
-	err := service.DeleteAllDeliveries()
+	mockRepo.On("GetDeliveryVersionAsOf", mock.Anything, 1, asOf).Return(&DeliveryVersionResponse{Versao: 2, Entrega: &DeliveryResponse{ID: 1, Cidade: "Cidade A"}}, nil)
+	mockRepo.On("GetDeliveryVersionAsOf", mock.Anything, 2, asOf).Return(&DeliveryVersionResponse{Versao: 3, Entrega: &DeliveryResponse{ID: 2, DataExclusao: &deletedAt}}, nil)
+	mockRepo.On("GetDeliveryVersionAsOf", mock.Anything, 3, asOf).Return(nil, ErrDeliveryNotFound)
 
+	response, err := service.GetDeliveryAsOf(context.Background(), 1, asOf)
 	assert.NoError(t, err)
+	assert.Equal(t, "Cidade A", response.Cidade)
+
+	// Entrega que estava na lixeira no instante informado
+	response, err = service.GetDeliveryAsOf(context.Background(), 2, asOf)
+	assert.Nil(t, response)
+	assert.ErrorIs(t, err, ErrDeliveryNotFound)
+
+	// Entrega que ainda não existia no instante informado
+	response, err = service.GetDeliveryAsOf(context.Background(), 3, asOf)
+	assert.Nil(t, response)
+	assert.ErrorIs(t, err, ErrDeliveryNotFound)
 	mockRepo.AssertExpectations(t)
 }
+
+func TestGetDeliveryAsOf_DriverNotAssigned(t *testing.T) {
+	mockRepo := new(MockDeliveryRepository)
+	service := NewDeliveryService(mockRepo, BulkDeleteConfig{})
+
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{
+		Type:    auth.PrincipalUser,
+		Subject: "driver-1",
+		Roles:   []auth.Role{auth.RoleDriver},
+	})
+
+	asOf := time.Now()
+	mockRepo.On("GetDeliveryVersionAsOf", mock.Anything, 1, asOf).Return(&DeliveryVersionResponse{Entrega: &DeliveryResponse{ID: 1, Motorista: "driver-2"}}, nil)
+
+	response, err := service.GetDeliveryAsOf(ctx, 1, asOf)
+	assert.Nil(t, response)
+	assert.ErrorIs(t, err, ErrDeliveryNotFound)
+}
+
+func TestGetDeliveryVersions(t *testing.T) {
+	mockRepo := new(MockDeliveryRepository)
+	service := NewDeliveryService(mockRepo, BulkDeleteConfig{})
+
+	expectedResponse := []*DeliveryVersionResponse{{Versao: 2}, {Versao: 1}}
+	mockRepo.On("GetDeliveryVersions", mock.Anything, 1).Return(expectedResponse, nil)
+
+	response, err := service.GetDeliveryVersions(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedResponse, response)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRevertDelivery(t *testing.T) {
+	mockRepo := new(MockDeliveryRepository)
+	service := NewDeliveryService(mockRepo, BulkDeleteConfig{})
+
+	expectedResponse := &DeliveryResponse{ID: 1}
+	mockRepo.On("RevertDelivery", mock.Anything, 1, 2).Return(expectedResponse, nil)
+
+	response, err := service.RevertDelivery(context.Background(), 1, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedResponse, response)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPurgeDeletedDeliveries(t *testing.T) {
+	mockRepo := new(MockDeliveryRepository)
+	service := NewDeliveryService(mockRepo, BulkDeleteConfig{})
+
+	// Apenas as entregas excluídas antes do período de retenção são expurgadas
+	mockRepo.On("PurgeDeletedDeliveries", mock.Anything, mock.MatchedBy(func(before time.Time) bool {
+		return time.Since(before) >= 24*time.Hour && time.Since(before) < 25*time.Hour
+	})).Return(int64(2), nil)
+
+	purged, err := service.PurgeDeletedDeliveries(context.Background(), 24*time.Hour)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), purged)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRunPurgeJob(t *testing.T) {
+	mockRepo := new(MockDeliveryRepository)
+	service := NewDeliveryService(mockRepo, BulkDeleteConfig{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// O job executa imediatamente e é encerrado com o cancelamento do contexto
+	mockRepo.On("PurgeDeletedDeliveries", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { cancel() }).
+		Return(int64(1), nil)
+
+	done := make(chan struct{})
+	go func() {
+		RunPurgeJob(ctx, service, time.Hour, time.Hour)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("o job de expurgo não foi encerrado")
+	}
+	mockRepo.AssertNumberOfCalls(t, "PurgeDeletedDeliveries", 1)
+}
+
+func TestValidatePurgeSchedule(t *testing.T) {
+	tests := []struct {
+		name      string
+		retention time.Duration
+		interval  time.Duration
+		valid     bool
+	}{
+		{"Retenção e intervalo positivos", 30 * 24 * time.Hour, time.Hour, true},
+		{"Retenção zero", 0, time.Hour, false},
+		{"Retenção negativa", -time.Hour, time.Hour, false},
+		{"Intervalo zero", time.Hour, 0, false},
+		{"Intervalo negativo", time.Hour, -time.Minute, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePurgeSchedule(tt.retention, tt.interval)
+
+			if tt.valid {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, ErrInvalidPurgeSchedule)
+		})
+	}
+}
+
+func TestRunPurgeJob_InvalidSchedule(t *testing.T) {
+	mockRepo := new(MockDeliveryRepository)
+	service := NewDeliveryService(mockRepo, BulkDeleteConfig{})
+
+	// O job é encerrado sem expurgar e sem criar o ticker, que não aceita intervalos zero ou negativos
+	done := make(chan struct{})
+	go func() {
+		RunPurgeJob(context.Background(), service, 0, 0)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("o job de expurgo não foi encerrado")
+	}
+	mockRepo.AssertNotCalled(t, "PurgeDeletedDeliveries", mock.Anything, mock.Anything)
+}
+
+func TestDeleteDeliveries(t *testing.T) {
+	mockRepo := new(MockDeliveryRepository)
+	service := NewDeliveryService(mockRepo, bulkDeleteConfig)
+
+	filter := &DeliveryFilter{Cidade: "Cidade A"}
+	mockRepo.On("CountDeliveries", mock.Anything, filter).Return(int64(3), nil)
+	mockRepo.On("DeleteDeliveries", mock.Anything, filter, int64(3)).Return(int64(3), nil)
+
+	preview, err := service.PreviewDeleteDeliveries(context.Background(), filter)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), preview.Quantidade)
+	assert.NotEmpty(t, preview.TokenConfirmacao)
+
+	result, err := service.DeleteDeliveries(context.Background(), filter, preview.TokenConfirmacao)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), result.Quantidade)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestDeleteDeliveries_Disabled(t *testing.T) {
+	mockRepo := new(MockDeliveryRepository)
+	service := NewDeliveryService(mockRepo, BulkDeleteConfig{})
+
+	_, err := service.PreviewDeleteDeliveries(context.Background(), nil)
+	assert.ErrorIs(t, err, ErrBulkDeleteDisabled)
+
+	_, err = service.DeleteDeliveries(context.Background(), nil, "token")
+	assert.ErrorIs(t, err, ErrBulkDeleteDisabled)
+	mockRepo.AssertNotCalled(t, "DeleteDeliveries", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDeleteDeliveries_TenantOverride(t *testing.T) {
+	mockRepo := new(MockDeliveryRepository)
+	service := NewDeliveryService(mockRepo, BulkDeleteConfig{Secret: []byte("secret"), TokenTTL: time.Minute, Tenants: map[string]bool{testTenant: true}})
+
+	mockRepo.On("CountDeliveries", mock.Anything, mock.Anything).Return(int64(1), nil)
+
+	// Habilitada apenas para o tenant que sobrescreve a configuração global
+	_, err := service.PreviewDeleteDeliveries(tenantContext(), nil)
+	assert.NoError(t, err)
+
+	_, err = service.PreviewDeleteDeliveries(tenant.WithTenant(context.Background(), "globex"), nil)
+	assert.ErrorIs(t, err, ErrBulkDeleteDisabled)
+}
+
+func TestDeleteDeliveries_InvalidToken(t *testing.T) {
+	mockRepo := new(MockDeliveryRepository)
+	service := NewDeliveryService(mockRepo, bulkDeleteConfig)
+
+	mockRepo.On("CountDeliveries", mock.Anything, mock.Anything).Return(int64(3), nil)
+
+	preview, err := service.PreviewDeleteDeliveries(tenantContext(), &DeliveryFilter{Cidade: "Cidade A"})
+	assert.NoError(t, err)
+
+	// Sem token
+	_, err = service.DeleteDeliveries(tenantContext(), &DeliveryFilter{Cidade: "Cidade A"}, "")
+	assert.ErrorIs(t, err, ErrMissingConfirmationToken)
+
+	// Token obtido com outros filtros
+	_, err = service.DeleteDeliveries(tenantContext(), &DeliveryFilter{Cidade: "Cidade B"}, preview.TokenConfirmacao)
+	assert.ErrorIs(t, err, ErrInvalidConfirmationToken)
+
+	// Token adulterado
+	_, err = service.DeleteDeliveries(tenantContext(), &DeliveryFilter{Cidade: "Cidade A"}, preview.TokenConfirmacao+"x")
+	assert.ErrorIs(t, err, ErrInvalidConfirmationToken)
+
+	// Token obtido por outro tenant
+	_, err = service.DeleteDeliveries(tenant.WithTenant(context.Background(), "globex"), &DeliveryFilter{Cidade: "Cidade A"}, preview.TokenConfirmacao)
+	assert.ErrorIs(t, err, ErrInvalidConfirmationToken)
+
+	// Token expirado
+	expired := NewDeliveryService(mockRepo, BulkDeleteConfig{Enabled: true, Secret: bulkDeleteConfig.Secret, TokenTTL: -time.Minute})
+	preview, err = expired.PreviewDeleteDeliveries(context.Background(), &DeliveryFilter{})
+	assert.NoError(t, err)
+	_, err = expired.DeleteDeliveries(context.Background(), &DeliveryFilter{}, preview.TokenConfirmacao)
+	assert.ErrorIs(t, err, ErrInvalidConfirmationToken)
+
+	mockRepo.AssertNotCalled(t, "DeleteDeliveries", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -10,6 +10,7 @@ import (
 // Testes de campos do request
 
 func TestCreateDeliveryRequest(t *testing.T) {
+	latitude, longitude := 40.7128, -74.0060
 	request := &CreateDeliveryRequest{
 		Cliente:     "Cliente 1",
 		Peso:        10.5,
@@ -21,8 +22,8 @@ func TestCreateDeliveryRequest(t *testing.T) {
 		Cidade:      "Cidade 4",
 		Estado:      "Estado 5",
 		Pais:        "País 6",
-		Latitude:    40.7128,
-		Longitude:   -74.0060,
+		Latitude:    &latitude,
+		Longitude:   &longitude,
 	}
 
 	assert.Equal(t, request.Cliente, "Cliente 1")
@@ -35,11 +36,12 @@ func TestCreateDeliveryRequest(t *testing.T) {
 	assert.Equal(t, request.Cidade, "Cidade 4")
 	assert.Equal(t, request.Estado, "Estado 5")
 	assert.Equal(t, request.Pais, "País 6")
-	assert.Equal(t, request.Latitude, 40.7128)
-	assert.Equal(t, request.Longitude, -74.0060)
+	assert.Equal(t, *request.Latitude, 40.7128)
+	assert.Equal(t, *request.Longitude, -74.0060)
 }
 
 func TestUpdateDeliveryRequest(t *testing.T) {
+	latitude, longitude := 51.5074, -0.1278
 	request := &UpdateDeliveryRequest{
 		Peso:        12.5,
 		Endereco:    "456 Novo Endereço",
@@ -50,8 +52,8 @@ func TestUpdateDeliveryRequest(t *testing.T) {
 		Cidade:      "Nova Cidade",
 		Estado:      "Novo Estado",
 		Pais:        "Novo País",
-		Latitude:    51.5074,
-		Longitude:   -0.1278,
+		Latitude:    &latitude,
+		Longitude:   &longitude,
 	}
 
 	assert.Equal(t, request.Peso, 12.5)
@@ -63,8 +65,8 @@ func TestUpdateDeliveryRequest(t *testing.T) {
 	assert.Equal(t, request.Cidade, "Nova Cidade")
 	assert.Equal(t, request.Estado, "Novo Estado")
 	assert.Equal(t, request.Pais, "Novo País")
-	assert.Equal(t, request.Latitude, 51.5074)
-	assert.Equal(t, request.Longitude, -0.1278)
+	assert.Equal(t, *request.Latitude, 51.5074)
+	assert.Equal(t, *request.Longitude, -0.1278)
 }
 
 // Testes de conversão de structs (model -> response)
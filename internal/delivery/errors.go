@@ -3,5 +3,11 @@ package delivery
 import "errors"
 
 var (
-	ErrDeliveryNotFound = errors.New("delivery not found")
+	ErrDeliveryNotFound         = errors.New("delivery not found")
+	ErrVersionNotFound          = errors.New("delivery version not found")
+	ErrBulkDeleteDisabled       = errors.New("bulk delete is disabled")
+	ErrMissingConfirmationToken = errors.New("confirmation token is required, request a dry run first")
+	ErrInvalidConfirmationToken = errors.New("confirmation token is invalid, expired or does not match the filters")
+	ErrConfirmationMismatch     = errors.New("the number of matching deliveries changed since the dry run")
+	ErrInvalidPurgeSchedule     = errors.New("trash retention and purge interval must be positive")
 )
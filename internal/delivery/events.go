@@ -0,0 +1,195 @@
+package delivery
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/samluiz/delivery-service/internal/audit"
+	"github.com/samluiz/delivery-service/internal/auth"
+	"github.com/samluiz/delivery-service/internal/tenant"
+)
+
+var ErrSubscriptionLagging = errors.New("subscriber is not consuming the delivery events fast enough")
+
+// Tamanho padrão do buffer de eventos de cada inscrição.
+const DefaultEventBuffer = 64
+
+// Mudança de uma entrega, publicada após a escrita ser confirmada no banco de dados.
+type Event struct {
+	Tenant     string
+	Acao       audit.Action
+	Entrega    *DeliveryResponse
+	DataEvento time.Time
+}
+
+// Função responsável por indicar se a entrega está na lixeira após a mudança.
+func (e Event) Excluida() bool {
+	return e.Entrega.DataExclusao != nil
+}
+
+// Filtros da inscrição nos eventos. Campos vazios não são aplicados, exceto o Tenant,
+// definido pelo Subscribe a partir do contexto.
+type EventFilter struct {
+	Tenant    string
+	ID        int
+	Cidade    string
+	Motorista string
+}
+
+// Função responsável por verificar se o evento satisfaz os filtros.
+// Eventos de outros tenants nunca são entregues.
+func (f EventFilter) matches(event Event) bool {
+	return event.Tenant == f.Tenant &&
+		(f.ID == 0 || event.Entrega.ID == f.ID) &&
+		(f.Cidade == "" || event.Entrega.Cidade == f.Cidade) &&
+		(f.Motorista == "" || event.Entrega.Motorista == f.Motorista)
+}
+
+// Struct responsável por distribuir os eventos das entregas para os inscritos (ex: streams gRPC).
+// A distribuição é feita em memória e alcança apenas os inscritos desta instância.
+type EventBroker struct {
+	mu            sync.Mutex
+	subscriptions map[*Subscription]struct{}
+	buffer        int
+}
+
+// Inscrição nos eventos das entregas.
+type Subscription struct {
+	events chan Event
+	filter EventFilter
+	err    error
+}
+
+// Função responsável por instanciar o broker com o tamanho do buffer de cada inscrição.
+func NewEventBroker(buffer int) *EventBroker {
+	if buffer <= 0 {
+		buffer = DefaultEventBuffer
+	}
+	return &EventBroker{subscriptions: make(map[*Subscription]struct{}), buffer: buffer}
+}
+
+// Função responsável por inscrever o chamador nos eventos que satisfazem os filtros, até o contexto ser cancelado.
+// Apenas os eventos do tenant do contexto são recebidos, e motoristas só recebem os eventos das entregas
+// atribuídas a eles, independente do filtro informado.
+func (b *EventBroker) Subscribe(ctx context.Context, filter EventFilter) *Subscription {
+	filter.Tenant, _ = tenant.FromContext(ctx)
+
+	if principal, ok := auth.FromContext(ctx); ok && principal.IsRestrictedToAssigned() {
+		filter.Motorista = principal.Subject
+	}
+
+	subscription := &Subscription{events: make(chan Event, b.buffer), filter: filter}
+
+	b.mu.Lock()
+	b.subscriptions[subscription] = struct{}{}
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		b.close(subscription, ctx.Err())
+	}()
+
+	return subscription
+}
+
+// Função responsável por publicar o evento para as inscrições cujos filtros ele satisfaz.
+// A publicação não bloqueia: inscrições com o buffer cheio são encerradas com ErrSubscriptionLagging.
+func (b *EventBroker) Publish(event Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for subscription := range b.subscriptions {
+		if !subscription.filter.matches(event) {
+			continue
+		}
+
+		select {
+		case subscription.events <- event:
+		default:
+			b.closeLocked(subscription, ErrSubscriptionLagging)
+		}
+	}
+}
+
+// Função responsável por encerrar a inscrição, registrando o motivo.
+func (b *EventBroker) close(subscription *Subscription, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closeLocked(subscription, err)
+}
+
+func (b *EventBroker) closeLocked(subscription *Subscription, err error) {
+	if _, ok := b.subscriptions[subscription]; !ok {
+		return
+	}
+
+	delete(b.subscriptions, subscription)
+	subscription.err = err
+	close(subscription.events)
+}
+
+// Função responsável por retornar o canal dos eventos, fechado quando a inscrição é encerrada.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Função responsável por retornar o motivo do encerramento da inscrição.
+// Deve ser chamada após o fechamento do canal de eventos.
+func (s *Subscription) Err() error {
+	return s.err
+}
+
+// Struct responsável por publicar os eventos das escritas realizadas pelo service.
+type eventPublishingService struct {
+	IDeliveryService
+	broker *EventBroker
+}
+
+// Função responsável por decorar o service para publicar um evento a cada escrita individual confirmada.
+// Exclusões em massa e expurgos não geram eventos.
+func WithEvents(service IDeliveryService, broker *EventBroker) IDeliveryService {
+	return &eventPublishingService{IDeliveryService: service, broker: broker}
+}
+
+func (s eventPublishingService) CreateDelivery(ctx context.Context, request *CreateDeliveryRequest) (*DeliveryResponse, error) {
+	response, err := s.IDeliveryService.CreateDelivery(ctx, request)
+	s.publish(ctx, audit.ActionCreate, response, err)
+	return response, err
+}
+
+func (s eventPublishingService) UpdateDelivery(ctx context.Context, request *UpdateDeliveryRequest, id int) (*DeliveryResponse, error) {
+	response, err := s.IDeliveryService.UpdateDelivery(ctx, request, id)
+	s.publish(ctx, audit.ActionUpdate, response, err)
+	return response, err
+}
+
+func (s eventPublishingService) DeleteDelivery(ctx context.Context, id int) (*DeliveryResponse, error) {
+	response, err := s.IDeliveryService.DeleteDelivery(ctx, id)
+	s.publish(ctx, audit.ActionDelete, response, err)
+	return response, err
+}
+
+func (s eventPublishingService) RestoreDelivery(ctx context.Context, id int) (*DeliveryResponse, error) {
+	response, err := s.IDeliveryService.RestoreDelivery(ctx, id)
+	s.publish(ctx, audit.ActionRestore, response, err)
+	return response, err
+}
+
+func (s eventPublishingService) RevertDelivery(ctx context.Context, id int, version int) (*DeliveryResponse, error) {
+	response, err := s.IDeliveryService.RevertDelivery(ctx, id, version)
+	s.publish(ctx, audit.ActionRevert, response, err)
+	return response, err
+}
+
+// Função responsável por publicar o evento da escrita, apenas quando ela foi bem sucedida.
+// O evento pertence ao tenant do contexto em que a escrita foi realizada.
+func (s eventPublishingService) publish(ctx context.Context, action audit.Action, response *DeliveryResponse, err error) {
+	if err != nil || response == nil {
+		return
+	}
+
+	id, _ := tenant.FromContext(ctx)
+	s.broker.Publish(Event{Tenant: id, Acao: action, Entrega: response, DataEvento: time.Now()})
+}
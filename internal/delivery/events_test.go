@@ -0,0 +1,193 @@
+package delivery
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/samluiz/delivery-service/internal/audit"
+	"github.com/samluiz/delivery-service/internal/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Testes da distribuição dos eventos das entregas
+
+func newEvent(id int, cidade string, motorista string) Event {
+	return Event{
+		Acao:       audit.ActionUpdate,
+		Entrega:    &DeliveryResponse{ID: id, Cidade: cidade, Motorista: motorista},
+		DataEvento: time.Now(),
+	}
+}
+
+// Função responsável por receber os eventos já publicados, sem aguardar novos.
+func receivedIDs(subscription *Subscription) []int {
+	ids := make([]int, 0)
+	for {
+		select {
+		case event, ok := <-subscription.Events():
+			if !ok {
+				return ids
+			}
+			ids = append(ids, event.Entrega.ID)
+		default:
+			return ids
+		}
+	}
+}
+
+func TestEventBroker_Filters(t *testing.T) {
+	broker := NewEventBroker(10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	all := broker.Subscribe(ctx, EventFilter{})
+	byID := broker.Subscribe(ctx, EventFilter{ID: 2})
+	byCity := broker.Subscribe(ctx, EventFilter{Cidade: "Recife"})
+	byCityAndDriver := broker.Subscribe(ctx, EventFilter{Cidade: "Recife", Motorista: "driver-1"})
+
+	broker.Publish(newEvent(1, "Recife", "driver-1"))
+	broker.Publish(newEvent(2, "Recife", ""))
+	broker.Publish(newEvent(3, "Olinda", "driver-1"))
+
+	assert.Equal(t, []int{1, 2, 3}, receivedIDs(all))
+	assert.Equal(t, []int{2}, receivedIDs(byID))
+	assert.Equal(t, []int{1, 2}, receivedIDs(byCity))
+	assert.Equal(t, []int{1}, receivedIDs(byCityAndDriver))
+}
+
+func TestEventBroker_DriverOnlyReceivesAssignedDeliveries(t *testing.T) {
+	broker := NewEventBroker(10)
+	ctx, cancel := context.WithCancel(auth.WithPrincipal(context.Background(), &auth.Principal{
+		Type:    auth.PrincipalUser,
+		Subject: "driver-1",
+		Roles:   []auth.Role{auth.RoleDriver},
+	}))
+	defer cancel()
+
+	// O filtro de motorista informado é substituído pelo do próprio usuário
+	subscription := broker.Subscribe(ctx, EventFilter{Motorista: "driver-2"})
+
+	broker.Publish(newEvent(1, "Recife", "driver-1"))
+	broker.Publish(newEvent(2, "Recife", "driver-2"))
+
+	assert.Equal(t, []int{1}, receivedIDs(subscription))
+}
+
+func TestEventBroker_OnlyReceivesTenantEvents(t *testing.T) {
+	broker := NewEventBroker(10)
+	ctx, cancel := context.WithCancel(tenantContext())
+	defer cancel()
+
+	// O tenant informado no filtro é substituído pelo do contexto
+	subscription := broker.Subscribe(ctx, EventFilter{Tenant: "globex"})
+
+	acme := newEvent(1, "Recife", "")
+	acme.Tenant = testTenant
+	globex := newEvent(2, "Recife", "")
+	globex.Tenant = "globex"
+
+	broker.Publish(acme)
+	broker.Publish(globex)
+	broker.Publish(newEvent(3, "Recife", ""))
+
+	assert.Equal(t, []int{1}, receivedIDs(subscription))
+}
+
+func TestEventBroker_ClosesOnCancel(t *testing.T) {
+	broker := NewEventBroker(10)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	subscription := broker.Subscribe(ctx, EventFilter{})
+	cancel()
+
+	select {
+	case _, ok := <-subscription.Events():
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("subscription was not closed")
+	}
+	assert.ErrorIs(t, subscription.Err(), context.Canceled)
+
+	// Publicar após o encerramento não envia para a inscrição
+	broker.Publish(newEvent(1, "Recife", ""))
+}
+
+func TestEventBroker_ClosesLaggingSubscription(t *testing.T) {
+	broker := NewEventBroker(1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	lagging := broker.Subscribe(ctx, EventFilter{})
+	other := broker.Subscribe(ctx, EventFilter{ID: 1})
+
+	broker.Publish(newEvent(1, "Recife", ""))
+	broker.Publish(newEvent(2, "Recife", ""))
+
+	assert.Equal(t, []int{1}, receivedIDs(lagging))
+	_, ok := <-lagging.Events()
+	assert.False(t, ok)
+	assert.ErrorIs(t, lagging.Err(), ErrSubscriptionLagging)
+
+	// As demais inscrições não são afetadas
+	assert.Equal(t, []int{1}, receivedIDs(other))
+}
+
+// Service utilizado pelos testes do decorator, com apenas os métodos necessários.
+type stubDeliveryService struct {
+	IDeliveryService
+	err error
+}
+
+func (s stubDeliveryService) CreateDelivery(ctx context.Context, request *CreateDeliveryRequest) (*DeliveryResponse, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &DeliveryResponse{ID: 1, Cliente: request.Cliente}, nil
+}
+
+// Data da exclusão registrada pelo repositório, que deve ser a publicada no evento
+var stubDeletedAt = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func (s stubDeliveryService) DeleteDelivery(ctx context.Context, id int) (*DeliveryResponse, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &DeliveryResponse{ID: id, DataExclusao: &stubDeletedAt}, nil
+}
+
+func TestWithEvents(t *testing.T) {
+	broker := NewEventBroker(10)
+	ctx, cancel := context.WithCancel(tenantContext())
+	defer cancel()
+
+	subscription := broker.Subscribe(ctx, EventFilter{})
+	service := WithEvents(stubDeliveryService{}, broker)
+
+	_, err := service.CreateDelivery(ctx, &CreateDeliveryRequest{Cliente: "Cliente A"})
+	require.NoError(t, err)
+	_, err = service.DeleteDelivery(ctx, 1)
+	require.NoError(t, err)
+
+	created := <-subscription.Events()
+	assert.Equal(t, testTenant, created.Tenant)
+	assert.Equal(t, audit.ActionCreate, created.Acao)
+	assert.Equal(t, "Cliente A", created.Entrega.Cliente)
+	assert.False(t, created.Excluida())
+
+	deleted := <-subscription.Events()
+	assert.Equal(t, audit.ActionDelete, deleted.Acao)
+	assert.Equal(t, 1, deleted.Entrega.ID)
+	assert.True(t, deleted.Excluida())
+	assert.Equal(t, stubDeletedAt, *deleted.Entrega.DataExclusao)
+
+	// Escritas com erro não geram eventos
+	failing := WithEvents(stubDeliveryService{err: ErrDeliveryNotFound}, broker)
+
+	_, err = failing.CreateDelivery(ctx, &CreateDeliveryRequest{})
+	assert.ErrorIs(t, err, ErrDeliveryNotFound)
+	_, err = failing.DeleteDelivery(ctx, 1)
+	assert.ErrorIs(t, err, ErrDeliveryNotFound)
+	assert.Empty(t, receivedIDs(subscription))
+}
@@ -0,0 +1,46 @@
+package delivery
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Função responsável por validar a retenção e o intervalo do expurgo.
+// Uma retenção zero ou negativa expurgaria todas as entregas da lixeira, desfazendo a exclusão lógica.
+func ValidatePurgeSchedule(retention time.Duration, interval time.Duration) error {
+	if retention <= 0 || interval <= 0 {
+		return fmt.Errorf("%w: retention %s, interval %s", ErrInvalidPurgeSchedule, retention, interval)
+	}
+	return nil
+}
+
+// Função responsável por expurgar periodicamente as entregas que estão na lixeira há mais tempo que a retenção.
+// A primeira execução acontece imediatamente e o job é encerrado quando o contexto é cancelado.
+// Com a retenção ou o intervalo inválidos o job não é executado.
+func RunPurgeJob(ctx context.Context, service IDeliveryService, retention time.Duration, interval time.Duration) {
+	if err := ValidatePurgeSchedule(retention, interval); err != nil {
+		log.Printf("Expurgo das entregas excluídas desabilitado: %v", err)
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := service.PurgeDeletedDeliveries(ctx, retention)
+
+		if err != nil {
+			log.Printf("Erro ao expurgar as entregas excluídas: %v", err)
+		} else if purged > 0 {
+			log.Printf("%d entregas excluídas expurgadas", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
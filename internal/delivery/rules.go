@@ -0,0 +1,103 @@
+package delivery
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Tags das regras de validação do domínio de entregas.
+const (
+	tagPeso = "peso" // Peso positivo e até o máximo configurado
+	tagUF   = "uf"   // Sigla de UF brasileira quando o país é o Brasil (regra entre campos)
+	tagPais = "pais" // Código de país ISO 3166-1 alfa-2 ou alfa-3, ou o nome Brasil do contrato original
+)
+
+// Configuração das regras de validação do domínio.
+type ValidationConfig struct {
+	MaxPeso float64 // Peso máximo aceito em uma entrega
+}
+
+var DefaultValidationConfig = ValidationConfig{MaxPeso: 1000}
+
+// Siglas das unidades federativas do Brasil.
+var ufs = map[string]bool{
+	"AC": true, "AL": true, "AP": true, "AM": true, "BA": true, "CE": true, "DF": true,
+	"ES": true, "GO": true, "MA": true, "MT": true, "MS": true, "MG": true, "PA": true,
+	"PB": true, "PR": true, "PE": true, "PI": true, "RJ": true, "RN": true, "RS": true,
+	"RO": true, "RR": true, "SC": true, "SP": true, "SE": true, "TO": true,
+}
+
+// Função responsável por registrar as regras de domínio das entregas no validator.
+func (c ValidationConfig) RegisterValidationRules(validate *validator.Validate) error {
+	if err := validate.RegisterValidation(tagPais, func(fl validator.FieldLevel) bool {
+		country := normalizeCode(fl.Field().String())
+		return isBrazil(country) || validate.Var(country, "iso3166_1_alpha2|iso3166_1_alpha3") == nil
+	}); err != nil {
+		return err
+	}
+
+	if err := validate.RegisterValidation(tagPeso, func(fl validator.FieldLevel) bool {
+		peso := fl.Field().Float()
+		return peso > 0 && peso <= c.MaxPeso
+	}); err != nil {
+		return err
+	}
+
+	// Regras que dependem de mais de um campo são validadas no nível do struct
+	validate.RegisterStructValidation(deliveryAddressRule, CreateDeliveryRequest{}, UpdateDeliveryRequest{}, AddressV2{})
+
+	return nil
+}
+
+// Função responsável por retornar o parâmetro das regras cujo limite vem da configuração, e não da tag.
+func (c ValidationConfig) ValidationParam(tag string) (string, bool) {
+	if tag == tagPeso {
+		return strconv.FormatFloat(c.MaxPeso, 'f', -1, 64), true
+	}
+	return "", false
+}
+
+// Função responsável por validar o estado do endereço da entrega de acordo com o país.
+// Para o Brasil o estado deve ser a sigla de uma UF, os demais países aceitam qualquer valor.
+func deliveryAddressRule(sl validator.StructLevel) {
+	var estado, pais string
+
+	// Nome do campo do estado no JSON e no struct de cada versão da API
+	field, structField := "estado", "Estado"
+
+	switch request := sl.Current().Interface().(type) {
+	case CreateDeliveryRequest:
+		estado, pais = request.Estado, request.Pais
+	case UpdateDeliveryRequest:
+		estado, pais = request.Estado, request.Pais
+	case AddressV2:
+		estado, pais = request.State, request.Country
+		field, structField = "state", "State"
+	}
+
+	// Estado vazio já é reportado pela regra required
+	if estado == "" || !isBrazil(pais) {
+		return
+	}
+
+	if !ufs[normalizeCode(estado)] {
+		sl.ReportError(estado, field, structField, tagUF, "")
+	}
+}
+
+// Função responsável por verificar se o país é o Brasil: os códigos ISO 3166-1 ou o nome, enviado pelos clientes
+// e registros anteriores às regras de domínio, sem diferenciar maiúsculas.
+func isBrazil(country string) bool {
+	switch normalizeCode(country) {
+	case "BR", "BRA", "BRASIL":
+		return true
+	}
+	return false
+}
+
+// Função responsável por normalizar os códigos de país e UF, ignorando espaços e maiúsculas (ex: " br " em BR).
+func normalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
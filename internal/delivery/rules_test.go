@@ -0,0 +1,156 @@
+package delivery
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+)
+
+// Struct utilizado para testar as regras de domínio, com as mesmas tags das requisições de entregas
+type DeliveryRulesStruct struct {
+	Peso      float64  `validate:"required,peso"`
+	Numero    string   `validate:"required,max=50"`
+	Estado    string   `validate:"required,max=100"`
+	Pais      string   `validate:"required,max=100,pais"`
+	Latitude  *float64 `validate:"required,latitude"`
+	Longitude *float64 `validate:"required,longitude"`
+}
+
+// Função auxiliar que cria um validator com as regras de domínio registradas
+func newRulesValidator(t *testing.T, config ValidationConfig) *validator.Validate {
+	validate := validator.New()
+	assert.NoError(t, config.RegisterValidationRules(validate))
+	return validate
+}
+
+// Função auxiliar que retorna as falhas de validação de um struct
+func validationErrors(t *testing.T, validate *validator.Validate, data any) validator.ValidationErrors {
+	err := validate.Struct(data)
+	if err == nil {
+		return nil
+	}
+
+	errs, ok := err.(validator.ValidationErrors)
+	assert.True(t, ok)
+	return errs
+}
+
+// Testes das regras de validação do domínio de entregas
+
+func TestValidationRules(t *testing.T) {
+	valid := func() DeliveryRulesStruct {
+		return DeliveryRulesStruct{Peso: 10, Numero: "123", Estado: "PE", Pais: "BR", Latitude: coordinate(-8.05), Longitude: coordinate(-34.9)}
+	}
+
+	tests := []struct {
+		name          string
+		modify        func(d *DeliveryRulesStruct)
+		expectedField string
+		expectedTag   string
+	}{
+		{name: "valid", modify: func(d *DeliveryRulesStruct) {}},
+		{name: "zero coordinates", modify: func(d *DeliveryRulesStruct) { d.Latitude, d.Longitude = coordinate(0), coordinate(0) }},
+		{name: "coordinate limits", modify: func(d *DeliveryRulesStruct) { d.Latitude, d.Longitude = coordinate(-90), coordinate(180) }},
+		{name: "latitude out of range", modify: func(d *DeliveryRulesStruct) { d.Latitude = coordinate(90.5) }, expectedField: "Latitude", expectedTag: "latitude"},
+		{name: "longitude out of range", modify: func(d *DeliveryRulesStruct) { d.Longitude = coordinate(-500) }, expectedField: "Longitude", expectedTag: "longitude"},
+		{name: "missing latitude", modify: func(d *DeliveryRulesStruct) { d.Latitude = nil }, expectedField: "Latitude", expectedTag: "required"},
+		{name: "missing longitude", modify: func(d *DeliveryRulesStruct) { d.Longitude = nil }, expectedField: "Longitude", expectedTag: "required"},
+		{name: "negative peso", modify: func(d *DeliveryRulesStruct) { d.Peso = -1 }, expectedField: "Peso", expectedTag: "peso"},
+		{name: "peso over maximum", modify: func(d *DeliveryRulesStruct) { d.Peso = 50.5 }, expectedField: "Peso", expectedTag: "peso"},
+		{name: "Brasil as country name", modify: func(d *DeliveryRulesStruct) { d.Pais = "Brasil" }},
+		{name: "Brasil in lowercase", modify: func(d *DeliveryRulesStruct) { d.Pais = "brasil" }},
+		{name: "alpha-2 in lowercase", modify: func(d *DeliveryRulesStruct) { d.Pais = "br" }},
+		{name: "alpha-3 in lowercase", modify: func(d *DeliveryRulesStruct) { d.Pais = "bra" }},
+		{name: "other alpha-2 in lowercase", modify: func(d *DeliveryRulesStruct) { d.Pais, d.Estado = "ar", "Buenos Aires" }},
+		{name: "country with spaces", modify: func(d *DeliveryRulesStruct) { d.Pais = " BR " }},
+		{name: "unknown code in lowercase", modify: func(d *DeliveryRulesStruct) { d.Pais = "xx" }, expectedField: "Pais", expectedTag: "pais"},
+		{name: "other country name", modify: func(d *DeliveryRulesStruct) { d.Pais = "Argentina" }, expectedField: "Pais", expectedTag: "pais"},
+		{name: "numero too long", modify: func(d *DeliveryRulesStruct) { d.Numero = strings.Repeat("1", 51) }, expectedField: "Numero", expectedTag: "max"},
+	}
+
+	validate := newRulesValidator(t, ValidationConfig{MaxPeso: 50})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := valid()
+			tt.modify(&data)
+
+			errs := validationErrors(t, validate, data)
+
+			if tt.expectedField == "" {
+				assert.Empty(t, errs)
+				return
+			}
+
+			assert.Len(t, errs, 1)
+			assert.Equal(t, tt.expectedField, errs[0].Field())
+			assert.Equal(t, tt.expectedTag, errs[0].Tag())
+		})
+	}
+}
+
+func TestValidationParam(t *testing.T) {
+	config := ValidationConfig{MaxPeso: 50}
+
+	param, ok := config.ValidationParam("peso")
+	assert.True(t, ok)
+	assert.Equal(t, "50", param)
+
+	_, ok = config.ValidationParam("max")
+	assert.False(t, ok)
+}
+
+func TestDeliveryAddressRule(t *testing.T) {
+	tests := []struct {
+		name        string
+		estado      string
+		pais        string
+		expectError bool
+	}{
+		{name: "UF in Brazil", estado: "PE", pais: "BR"},
+		{name: "invalid UF in Brazil", estado: "Pernambuco", pais: "BR", expectError: true},
+		{name: "alpha-3 Brazil", estado: "XX", pais: "BRA", expectError: true},
+		{name: "UF with Brasil name", estado: "PE", pais: "Brasil"},
+		{name: "invalid UF with Brasil name", estado: "Estado A", pais: "BRASIL", expectError: true},
+		{name: "state outside Brazil", estado: "California", pais: "US"},
+		{name: "lowercase UF with lowercase alpha-2", estado: "pe", pais: "br"},
+		{name: "UF with spaces with lowercase alpha-3", estado: " sp ", pais: "bra"},
+		{name: "invalid UF with lowercase alpha-3", estado: "xx", pais: "bra", expectError: true},
+	}
+
+	validate := newRulesValidator(t, DefaultValidationConfig)
+	latitude, longitude := coordinate(-8.05), coordinate(-34.9)
+	coordinates := &CoordinatesV2{Latitude: latitude, Longitude: longitude}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address := AddressV2{Line: "Rua A", Street: "Rua A", Number: "1", Neighborhood: "Bairro A", Complement: "Casa", City: "Cidade A", State: tt.estado, Country: tt.pais}
+
+			// Requests de cada versão da API e o caminho do campo do estado
+			requests := []struct {
+				body      any
+				namespace string
+			}{
+				{&CreateDeliveryRequest{Cliente: "Cliente A", Peso: 10, Endereco: "Rua A", Logradouro: "Rua A", Numero: "1", Bairro: "Bairro A", Complemento: "Casa", Cidade: "Cidade A", Estado: tt.estado, Pais: tt.pais, Latitude: latitude, Longitude: longitude}, "CreateDeliveryRequest.Estado"},
+				{&UpdateDeliveryRequest{Peso: 10, Endereco: "Rua A", Logradouro: "Rua A", Numero: "1", Bairro: "Bairro A", Complemento: "Casa", Cidade: "Cidade A", Estado: tt.estado, Pais: tt.pais, Latitude: latitude, Longitude: longitude}, "UpdateDeliveryRequest.Estado"},
+				{&CreateDeliveryRequestV2{Customer: "Cliente A", Weight: 10, Address: address, Coordinates: coordinates}, "CreateDeliveryRequestV2.Address.State"},
+				{&UpdateDeliveryRequestV2{Weight: 10, Address: address, Coordinates: coordinates}, "UpdateDeliveryRequestV2.Address.State"},
+			}
+
+			for _, request := range requests {
+				errs := validationErrors(t, validate, request.body)
+
+				if !tt.expectError {
+					assert.Empty(t, errs)
+					continue
+				}
+
+				assert.Len(t, errs, 1)
+				assert.Equal(t, request.namespace, errs[0].StructNamespace())
+				assert.Equal(t, "uf", errs[0].Tag())
+			}
+		})
+	}
+}
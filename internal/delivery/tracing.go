@@ -0,0 +1,40 @@
+package delivery
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracer utilizado pelo service e pelo repositório de entregas.
+var tracer = otel.Tracer("github.com/samluiz/delivery-service/internal/delivery")
+
+// Função responsável por iniciar o span de uma instrução SQL na tabela de entregas.
+// Apenas o nome da instrução é registrado, nunca os valores dos parâmetros.
+func startQuerySpan(ctx context.Context, statement string) (context.Context, trace.Span) {
+	return startTableQuerySpan(ctx, TABLE_NAME, statement)
+}
+
+// Função responsável por iniciar o span de uma instrução SQL na tabela informada.
+func startTableQuerySpan(ctx context.Context, table string, statement string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "sql."+statement,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "mysql"),
+			attribute.String("db.sql.table", table),
+			attribute.String("db.statement.name", statement),
+		),
+	)
+}
+
+// Função responsável por registrar o erro (se houver) e finalizar o span.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,51 @@
+package delivery
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// Testes dos spans criados pelo service e pelo repositório
+
+func TestTracingSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(noop.NewTracerProvider())
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	service := NewDeliveryService(NewDeliveryRepository(db), BulkDeleteConfig{})
+
+	mock.ExpectQuery(`SELECT \* FROM entregas WHERE id = \? AND tenant_id = \?`).
+		WithArgs(1, testTenant).
+		WillReturnRows(sqlmock.NewRows(deliveryColumns).
+			AddRow(1, "Cliente A", 10.5, "Endereço 123", "Rua 1", "123", "Bairro A", "Casa", "Cidade A", "Estado A", "País A", 40.7128, -74.0060, time.Now(), time.Now(), "", nil, testTenant))
+
+	_, err = service.GetDelivery(tenantContext(), 1)
+	assert.NoError(t, err)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+
+	// O span da instrução SQL é filho do span do service
+	assert.Equal(t, "sql.getDelivery", spans[0].Name())
+	assert.Equal(t, "DeliveryService.GetDelivery", spans[1].Name())
+	assert.Equal(t, spans[1].SpanContext().SpanID(), spans[0].Parent().SpanID())
+
+	// Apenas o nome da instrução é registrado, nunca os valores
+	for _, attr := range spans[0].Attributes() {
+		assert.NotContains(t, attr.Value.Emit(), "Cliente A")
+		if attr.Key == "db.statement.name" {
+			assert.Equal(t, "getDelivery", attr.Value.AsString())
+		}
+	}
+}
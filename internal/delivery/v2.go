@@ -0,0 +1,171 @@
+package delivery
+
+import (
+	"time"
+
+	"github.com/samluiz/delivery-service/internal/audit"
+)
+
+// Contrato da v2 da API de entregas: campos em inglês, com endereço e coordenadas em objetos aninhados.
+// Os tipos da v2 são convertidos para os da v1, utilizados pelo service, e vice-versa.
+
+// Endereço da entrega na v2.
+type AddressV2 struct {
+	Line         string `json:"line" validate:"required,max=255"`
+	Street       string `json:"street" validate:"required,max=255"`
+	Number       string `json:"number" validate:"required,max=50"`
+	Complement   string `json:"complement" validate:"required,max=255"`
+	Neighborhood string `json:"neighborhood" validate:"required,max=255"`
+	City         string `json:"city" validate:"required,max=255"`
+	State        string `json:"state" validate:"required,max=100"`
+	Country      string `json:"country" validate:"required,max=100,pais"`
+}
+
+// Coordenadas da entrega na v2.
+// Os campos são ponteiros para que 0 (linha do equador ou meridiano de Greenwich) seja aceito como valor informado.
+type CoordinatesV2 struct {
+	Latitude  *float64 `json:"latitude" validate:"required,latitude"`
+	Longitude *float64 `json:"longitude" validate:"required,longitude"`
+}
+
+type CreateDeliveryRequestV2 struct {
+	Customer    string         `json:"customer" validate:"required,max=255"`
+	Weight      float64        `json:"weight" validate:"required,peso"`
+	Address     AddressV2      `json:"address" validate:"required"`
+	Coordinates *CoordinatesV2 `json:"coordinates" validate:"required"`
+	Driver      string         `json:"driver" validate:"max=255"`
+}
+
+type UpdateDeliveryRequestV2 struct {
+	Weight      float64        `json:"weight" validate:"required,peso"`
+	Address     AddressV2      `json:"address" validate:"required"`
+	Coordinates *CoordinatesV2 `json:"coordinates" validate:"required"`
+	Driver      string         `json:"driver" validate:"max=255"`
+}
+
+type DeliveryResponseV2 struct {
+	ID          int           `json:"id"`
+	Customer    string        `json:"customer"`
+	Weight      float64       `json:"weight"`
+	Address     AddressV2     `json:"address"`
+	Coordinates CoordinatesV2 `json:"coordinates"`
+	Driver      string        `json:"driver"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+	DeletedAt   *time.Time    `json:"deleted_at,omitempty"`
+}
+
+// Versão do histórico de uma entrega na v2.
+type DeliveryVersionResponseV2 struct {
+	Version     int                 `json:"version"`
+	Action      audit.Action        `json:"action"`
+	VersionedAt time.Time           `json:"versioned_at"`
+	Delivery    *DeliveryResponseV2 `json:"delivery"`
+}
+
+// Resultado do dry-run da exclusão em massa na v2.
+type DeleteDeliveriesPreviewV2 struct {
+	Count             int64     `json:"count"`
+	ConfirmationToken string    `json:"confirmation_token"`
+	ExpiresAt         time.Time `json:"expires_at"`
+}
+
+// Resultado da exclusão em massa na v2.
+type DeleteDeliveriesResultV2 struct {
+	Count int64 `json:"count"`
+}
+
+func (r CreateDeliveryRequestV2) ToCreateDeliveryRequest() *CreateDeliveryRequest {
+	latitude, longitude := r.Coordinates.values()
+
+	return &CreateDeliveryRequest{
+		Cliente:     r.Customer,
+		Peso:        r.Weight,
+		Endereco:    r.Address.Line,
+		Logradouro:  r.Address.Street,
+		Numero:      r.Address.Number,
+		Bairro:      r.Address.Neighborhood,
+		Complemento: r.Address.Complement,
+		Cidade:      r.Address.City,
+		Estado:      r.Address.State,
+		Pais:        r.Address.Country,
+		Latitude:    latitude,
+		Longitude:   longitude,
+		Motorista:   r.Driver,
+	}
+}
+
+func (r UpdateDeliveryRequestV2) ToUpdateDeliveryRequest() *UpdateDeliveryRequest {
+	latitude, longitude := r.Coordinates.values()
+
+	return &UpdateDeliveryRequest{
+		Peso:        r.Weight,
+		Endereco:    r.Address.Line,
+		Logradouro:  r.Address.Street,
+		Numero:      r.Address.Number,
+		Bairro:      r.Address.Neighborhood,
+		Complemento: r.Address.Complement,
+		Cidade:      r.Address.City,
+		Estado:      r.Address.State,
+		Pais:        r.Address.Country,
+		Latitude:    latitude,
+		Longitude:   longitude,
+		Motorista:   r.Driver,
+	}
+}
+
+// Função responsável por retornar a latitude e a longitude, ou nil quando as coordenadas não foram informadas
+// (requisições sem coordenadas são recusadas pela validação).
+func (c *CoordinatesV2) values() (*float64, *float64) {
+	if c == nil {
+		return nil, nil
+	}
+	return c.Latitude, c.Longitude
+}
+
+func (r DeliveryResponse) ToDeliveryResponseV2() *DeliveryResponseV2 {
+	latitude, longitude := r.Latitude, r.Longitude
+
+	return &DeliveryResponseV2{
+		ID:       r.ID,
+		Customer: r.Cliente,
+		Weight:   r.Peso,
+		Address: AddressV2{
+			Line:         r.Endereco,
+			Street:       r.Logradouro,
+			Number:       r.Numero,
+			Complement:   r.Complemento,
+			Neighborhood: r.Bairro,
+			City:         r.Cidade,
+			State:        r.Estado,
+			Country:      r.Pais,
+		},
+		Coordinates: CoordinatesV2{Latitude: &latitude, Longitude: &longitude},
+		Driver:      r.Motorista,
+		CreatedAt:   r.DataInclusao,
+		UpdatedAt:   r.DataAlteracao,
+		DeletedAt:   r.DataExclusao,
+	}
+}
+
+func (r DeliveryVersionResponse) ToDeliveryVersionResponseV2() *DeliveryVersionResponseV2 {
+	response := &DeliveryVersionResponseV2{
+		Version:     r.Versao,
+		Action:      r.Acao,
+		VersionedAt: r.DataVersao,
+	}
+
+	if r.Entrega != nil {
+		response.Delivery = r.Entrega.ToDeliveryResponseV2()
+	}
+
+	return response
+}
+
+func (r DeleteDeliveriesPreview) ToDeleteDeliveriesPreviewV2() *DeleteDeliveriesPreviewV2 {
+	return &DeleteDeliveriesPreviewV2{Count: r.Quantidade, ConfirmationToken: r.TokenConfirmacao, ExpiresAt: r.ExpiraEm}
+}
+
+func (r DeleteDeliveriesResult) ToDeleteDeliveriesResultV2() *DeleteDeliveriesResultV2 {
+	return &DeleteDeliveriesResultV2{Count: r.Quantidade}
+}
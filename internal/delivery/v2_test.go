@@ -0,0 +1,144 @@
+package delivery
+
+import (
+	"testing"
+	"time"
+
+	"github.com/samluiz/delivery-service/internal/audit"
+	"github.com/stretchr/testify/assert"
+)
+
+// Testes da conversão entre os contratos da v2 e da v1
+
+func TestCreateDeliveryRequestV2_ToCreateDeliveryRequest(t *testing.T) {
+	latitude, longitude := 40.7128, -74.0060
+	request := CreateDeliveryRequestV2{
+		Customer: "Cliente 1",
+		Weight:   10.5,
+		Address: AddressV2{
+			Line:         "Endereço 123",
+			Street:       "Rua 1",
+			Number:       "123",
+			Complement:   "Apartamento 3",
+			Neighborhood: "Bairro 2",
+			City:         "Cidade 4",
+			State:        "PE",
+			Country:      "BR",
+		},
+		Coordinates: &CoordinatesV2{Latitude: &latitude, Longitude: &longitude},
+		Driver:      "Motorista 1",
+	}
+
+	expected := &CreateDeliveryRequest{
+		Cliente:     "Cliente 1",
+		Peso:        10.5,
+		Endereco:    "Endereço 123",
+		Logradouro:  "Rua 1",
+		Numero:      "123",
+		Bairro:      "Bairro 2",
+		Complemento: "Apartamento 3",
+		Cidade:      "Cidade 4",
+		Estado:      "PE",
+		Pais:        "BR",
+		Latitude:    &latitude,
+		Longitude:   &longitude,
+		Motorista:   "Motorista 1",
+	}
+
+	assert.Equal(t, expected, request.ToCreateDeliveryRequest())
+}
+
+func TestUpdateDeliveryRequestV2_ToUpdateDeliveryRequest(t *testing.T) {
+	// A latitude 0 (linha do equador) é mantida como valor informado
+	latitude, longitude := 0.0, -34.9
+	request := UpdateDeliveryRequestV2{
+		Weight:      2,
+		Address:     AddressV2{City: "Recife", State: "PE", Country: "BR"},
+		Coordinates: &CoordinatesV2{Latitude: &latitude, Longitude: &longitude},
+	}
+
+	converted := request.ToUpdateDeliveryRequest()
+
+	assert.Equal(t, 2.0, converted.Peso)
+	assert.Equal(t, "Recife", converted.Cidade)
+	assert.Equal(t, "PE", converted.Estado)
+	assert.Equal(t, "BR", converted.Pais)
+	assert.Equal(t, 0.0, *converted.Latitude)
+	assert.Equal(t, -34.9, *converted.Longitude)
+
+	// Sem coordenadas, o request da v1 também fica sem coordenadas e é recusado pela validação
+	converted = UpdateDeliveryRequestV2{Weight: 2}.ToUpdateDeliveryRequest()
+	assert.Nil(t, converted.Latitude)
+	assert.Nil(t, converted.Longitude)
+}
+
+func TestDeliveryResponse_ToDeliveryResponseV2(t *testing.T) {
+	now := time.Now()
+	response := DeliveryResponse{
+		ID:            1,
+		Cliente:       "Cliente 1",
+		Peso:          10.5,
+		Endereco:      "Endereço 123",
+		Logradouro:    "Rua 1",
+		Numero:        "123",
+		Bairro:        "Bairro 2",
+		Complemento:   "Apartamento 3",
+		Cidade:        "Cidade 4",
+		Estado:        "PE",
+		Pais:          "BR",
+		Latitude:      0,
+		Longitude:     -74.0060,
+		Motorista:     "Motorista 1",
+		DataInclusao:  now,
+		DataAlteracao: now,
+		DataExclusao:  &now,
+	}
+
+	converted := response.ToDeliveryResponseV2()
+
+	assert.Equal(t, 1, converted.ID)
+	assert.Equal(t, "Cliente 1", converted.Customer)
+	assert.Equal(t, 10.5, converted.Weight)
+	assert.Equal(t, AddressV2{
+		Line:         "Endereço 123",
+		Street:       "Rua 1",
+		Number:       "123",
+		Complement:   "Apartamento 3",
+		Neighborhood: "Bairro 2",
+		City:         "Cidade 4",
+		State:        "PE",
+		Country:      "BR",
+	}, converted.Address)
+	assert.Equal(t, 0.0, *converted.Coordinates.Latitude)
+	assert.Equal(t, -74.0060, *converted.Coordinates.Longitude)
+	assert.Equal(t, "Motorista 1", converted.Driver)
+	assert.Equal(t, now, converted.CreatedAt)
+	assert.Equal(t, now, converted.UpdatedAt)
+	assert.Equal(t, &now, converted.DeletedAt)
+}
+
+func TestDeliveryVersionResponse_ToDeliveryVersionResponseV2(t *testing.T) {
+	now := time.Now()
+	version := DeliveryVersionResponse{Versao: 2, Acao: audit.ActionUpdate, DataVersao: now, Entrega: &DeliveryResponse{ID: 1}}
+
+	converted := version.ToDeliveryVersionResponseV2()
+
+	assert.Equal(t, 2, converted.Version)
+	assert.Equal(t, audit.ActionUpdate, converted.Action)
+	assert.Equal(t, now, converted.VersionedAt)
+	assert.Equal(t, 1, converted.Delivery.ID)
+
+	// Versões sem dados da entrega continuam sem dados na v2
+	version.Entrega = nil
+	assert.Nil(t, version.ToDeliveryVersionResponseV2().Delivery)
+}
+
+func TestDeleteDeliveries_ToV2(t *testing.T) {
+	now := time.Now()
+
+	preview := DeleteDeliveriesPreview{Quantidade: 3, TokenConfirmacao: "token", ExpiraEm: now}
+	assert.Equal(t, &DeleteDeliveriesPreviewV2{Count: 3, ConfirmationToken: "token", ExpiresAt: now}, preview.ToDeleteDeliveriesPreviewV2())
+
+	result := DeleteDeliveriesResult{Quantidade: 3}
+	assert.Equal(t, &DeleteDeliveriesResultV2{Count: 3}, result.ToDeleteDeliveriesResultV2())
+}
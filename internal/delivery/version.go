@@ -0,0 +1,52 @@
+package delivery
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/samluiz/delivery-service/internal/audit"
+)
+
+// Versão de uma entrega: cópia completa da entrega após cada escrita.
+type DeliveryVersion struct {
+	EntregaID  int          `db:"entrega_id"`
+	Versao     int          `db:"versao"`
+	Acao       audit.Action `db:"acao"`
+	Dados      []byte       `db:"dados"`
+	DataVersao time.Time    `db:"data_versao"`
+}
+
+type DeliveryVersionResponse struct {
+	Versao     int               `json:"versao"`
+	Acao       audit.Action      `json:"acao"`
+	DataVersao time.Time         `json:"data_versao"`
+	Entrega    *DeliveryResponse `json:"entrega"`
+}
+
+// Função responsável por converter a versão para o response, desserializando a cópia da entrega.
+func (v DeliveryVersion) ToDeliveryVersionResponse() (*DeliveryVersionResponse, error) {
+	var delivery DeliveryResponse
+
+	if err := json.Unmarshal(v.Dados, &delivery); err != nil {
+		return nil, err
+	}
+
+	return &DeliveryVersionResponse{
+		Versao:     v.Versao,
+		Acao:       v.Acao,
+		DataVersao: v.DataVersao,
+		Entrega:    &delivery,
+	}, nil
+}
+
+var (
+	nextVersionQuery = `SELECT COALESCE(MAX(versao), 0) + 1 FROM entregas_versoes WHERE entrega_id = ?`
+
+	insertVersionQuery = `INSERT INTO entregas_versoes (entrega_id, versao, acao, dados, tenant_id) VALUES (?, ?, ?, ?, ?)`
+
+	getVersionsQuery = `SELECT entrega_id, versao, acao, dados, data_versao FROM entregas_versoes WHERE entrega_id = ? AND tenant_id = ? ORDER BY versao DESC`
+
+	getVersionQuery = `SELECT entrega_id, versao, acao, dados, data_versao FROM entregas_versoes WHERE entrega_id = ? AND tenant_id = ? AND versao = ?`
+
+	getVersionAsOfQuery = `SELECT entrega_id, versao, acao, dados, data_versao FROM entregas_versoes WHERE entrega_id = ? AND tenant_id = ? AND data_versao <= ? ORDER BY versao DESC LIMIT 1`
+)
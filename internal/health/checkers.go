@@ -0,0 +1,78 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// Verificação de disponibilidade do banco de dados.
+type DatabaseChecker struct {
+	db *sql.DB
+}
+
+func NewDatabaseChecker(db *sql.DB) Checker {
+	return &DatabaseChecker{db: db}
+}
+
+func (c DatabaseChecker) Name() string {
+	return "database"
+}
+
+// Função responsável por verificar se o banco de dados responde dentro do timeout.
+func (c DatabaseChecker) Check(ctx context.Context) error {
+	return c.db.PingContext(ctx)
+}
+
+// Verificação de que as tabelas esperadas pela aplicação foram criadas.
+type MigrationsChecker struct {
+	db     *sql.DB
+	tables []string
+}
+
+func NewMigrationsChecker(db *sql.DB, tables []string) Checker {
+	return &MigrationsChecker{db: db, tables: tables}
+}
+
+func (c MigrationsChecker) Name() string {
+	return "migrations"
+}
+
+// Função responsável por verificar se todas as tabelas esperadas existem no schema atual.
+func (c MigrationsChecker) Check(ctx context.Context) error {
+	rows, err := c.db.QueryContext(ctx, `SELECT table_name FROM information_schema.tables WHERE table_schema = DATABASE()`)
+	if err != nil {
+		return err
+	}
+
+	// Fechando a conexão com o cursor em caso de erro
+	defer rows.Close()
+
+	existing := make(map[string]bool)
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			return err
+		}
+		existing[strings.ToLower(table)] = true
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	// Listando as tabelas que ainda não foram criadas
+	missing := make([]string, 0)
+	for _, table := range c.tables {
+		if !existing[strings.ToLower(table)] {
+			missing = append(missing, table)
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("tabelas não encontradas: %s", strings.Join(missing, ", "))
+	}
+
+	return nil
+}
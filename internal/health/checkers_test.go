@@ -0,0 +1,59 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// Testes das verificações do banco de dados
+
+func TestDatabaseChecker(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	assert.NoError(t, err)
+	defer db.Close()
+
+	checker := NewDatabaseChecker(db)
+
+	mock.ExpectPing()
+	assert.NoError(t, checker.Check(context.Background()))
+
+	mock.ExpectPing().WillReturnError(errors.New("connection refused"))
+	assert.Error(t, checker.Check(context.Background()))
+
+	assert.Equal(t, "database", checker.Name())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrationsChecker(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	checker := NewMigrationsChecker(db, []string{"entregas"})
+
+	mock.ExpectQuery(`SELECT table_name FROM information_schema.tables`).
+		WillReturnRows(sqlmock.NewRows([]string{"table_name"}).AddRow("entregas"))
+
+	assert.NoError(t, checker.Check(context.Background()))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrationsChecker_MissingTable(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	checker := NewMigrationsChecker(db, []string{"entregas"})
+
+	mock.ExpectQuery(`SELECT table_name FROM information_schema.tables`).
+		WillReturnRows(sqlmock.NewRows([]string{"table_name"}))
+
+	err = checker.Check(context.Background())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "entregas")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
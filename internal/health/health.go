@@ -0,0 +1,108 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Status de uma dependência ou do serviço como um todo.
+type Status string
+
+const (
+	StatusUp   Status = "up"
+	StatusDown Status = "down"
+)
+
+// Interface que representa a verificação de uma dependência do serviço.
+// Novas dependências (cache, filas, etc) devem apenas implementar essa interface.
+type Checker interface {
+	Name() string
+	Check(ctx context.Context) error
+}
+
+// Struct que representa o resultado da verificação de uma dependência.
+type CheckResult struct {
+	Name      string  `json:"name"`
+	Status    Status  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Struct que representa o relatório de saúde do serviço.
+type Report struct {
+	Status Status        `json:"status"`
+	Checks []CheckResult `json:"checks,omitempty"`
+}
+
+type HealthService struct {
+	checkers []Checker
+	timeout  time.Duration
+}
+
+type IHealthService interface {
+	Liveness() *Report
+	Readiness(ctx context.Context) *Report
+}
+
+// Função responsável por instanciar o service de saúde.
+// O timeout é aplicado individualmente a cada verificação.
+func NewHealthService(timeout time.Duration, checkers ...Checker) IHealthService {
+	return &HealthService{checkers: checkers, timeout: timeout}
+}
+
+// Função responsável por informar se o processo está vivo.
+// Não verifica dependências, pois uma falha externa não deve reiniciar o processo.
+func (s HealthService) Liveness() *Report {
+	return &Report{Status: StatusUp}
+}
+
+// Função responsável por verificar se o serviço está pronto para receber requisições.
+// As verificações são executadas em paralelo, cada uma com seu próprio timeout.
+func (s HealthService) Readiness(ctx context.Context) *Report {
+	report := &Report{
+		Status: StatusUp,
+		Checks: make([]CheckResult, len(s.checkers)),
+	}
+
+	var wg sync.WaitGroup
+	for i, checker := range s.checkers {
+		wg.Add(1)
+		go func(i int, checker Checker) {
+			defer wg.Done()
+			report.Checks[i] = s.check(ctx, checker)
+		}(i, checker)
+	}
+	wg.Wait()
+
+	// O serviço só está pronto se todas as dependências estiverem disponíveis
+	for _, result := range report.Checks {
+		if result.Status != StatusUp {
+			report.Status = StatusDown
+		}
+	}
+
+	return report
+}
+
+// Função responsável por executar uma verificação e medir sua latência.
+func (s HealthService) check(ctx context.Context, checker Checker) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := checker.Check(ctx)
+
+	result := CheckResult{
+		Name:      checker.Name(),
+		Status:    StatusUp,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+
+	return result
+}
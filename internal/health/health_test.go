@@ -0,0 +1,80 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Checker fake utilizado para simular dependências
+
+type fakeChecker struct {
+	name  string
+	err   error
+	delay time.Duration
+}
+
+func (c fakeChecker) Name() string {
+	return c.name
+}
+
+func (c fakeChecker) Check(ctx context.Context) error {
+	select {
+	case <-time.After(c.delay):
+		return c.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Testes do service de saúde
+
+func TestLiveness(t *testing.T) {
+	service := NewHealthService(time.Second, fakeChecker{name: "database", err: errors.New("down")})
+
+	report := service.Liveness()
+
+	assert.Equal(t, StatusUp, report.Status)
+	assert.Empty(t, report.Checks)
+}
+
+func TestReadiness_AllUp(t *testing.T) {
+	service := NewHealthService(time.Second,
+		fakeChecker{name: "database"},
+		fakeChecker{name: "migrations"},
+	)
+
+	report := service.Readiness(context.Background())
+
+	assert.Equal(t, StatusUp, report.Status)
+	assert.Len(t, report.Checks, 2)
+	assert.Equal(t, "database", report.Checks[0].Name)
+	assert.Equal(t, StatusUp, report.Checks[0].Status)
+	assert.Equal(t, "migrations", report.Checks[1].Name)
+}
+
+func TestReadiness_DependencyDown(t *testing.T) {
+	service := NewHealthService(time.Second,
+		fakeChecker{name: "database", err: errors.New("connection refused")},
+		fakeChecker{name: "migrations"},
+	)
+
+	report := service.Readiness(context.Background())
+
+	assert.Equal(t, StatusDown, report.Status)
+	assert.Equal(t, StatusDown, report.Checks[0].Status)
+	assert.Equal(t, "connection refused", report.Checks[0].Error)
+	assert.Equal(t, StatusUp, report.Checks[1].Status)
+}
+
+func TestReadiness_Timeout(t *testing.T) {
+	service := NewHealthService(10*time.Millisecond, fakeChecker{name: "database", delay: time.Second})
+
+	report := service.Readiness(context.Background())
+
+	assert.Equal(t, StatusDown, report.Status)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks[0].Error)
+}
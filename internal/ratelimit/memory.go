@@ -0,0 +1,68 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Intervalo entre as limpezas dos baldes que já estão cheios.
+const sweepInterval = time.Minute
+
+// Armazenamento dos baldes em memória, para implantações com uma única instância.
+// Baldes cheios são equivalentes a baldes novos e são removidos periodicamente, para que a memória
+// não cresça com clientes que deixaram de fazer requisições.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*memoryBucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type memoryBucket struct {
+	bucket
+	limit Limit
+}
+
+// Função responsável por instanciar o armazenamento em memória.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]*memoryBucket), now: time.Now}
+}
+
+// Função responsável por consumir um token do balde do cliente, criando-o cheio caso não exista.
+func (s *MemoryStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+
+	if now.Sub(s.lastSweep) >= sweepInterval {
+		s.sweep(now)
+	}
+
+	b, ok := s.buckets[key]
+	if !ok || b.limit != limit {
+		b = &memoryBucket{bucket: bucket{tokens: float64(limit.Requests), updated: now}, limit: limit}
+		s.buckets[key] = b
+	}
+
+	return b.take(limit, now), nil
+}
+
+// Função responsável por remover os baldes que já estão cheios.
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, b := range s.buckets {
+		if b.full(b.limit, now) {
+			delete(s.buckets, key)
+		}
+	}
+	s.lastSweep = now
+}
+
+// Função responsável por retornar a quantidade de baldes armazenados.
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.buckets)
+}
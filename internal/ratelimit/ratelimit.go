@@ -0,0 +1,163 @@
+// Pacote com o rate limiting por token bucket: cada cliente possui um balde com capacidade para Requests requisições,
+// reabastecido continuamente à taxa de Requests por Period.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidLimit = errors.New("invalid rate limit")
+
+// Struct que representa um limite de requisições por período (ex: 100 por minuto).
+// O limite com Requests igual a zero é considerado desabilitado.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// Função responsável por verificar se o limite está habilitado.
+func (l Limit) Enabled() bool {
+	return l.Requests > 0 && l.Period > 0
+}
+
+// Função responsável por retornar a taxa de reabastecimento do balde, em tokens por segundo.
+func (l Limit) rate() float64 {
+	return float64(l.Requests) / l.Period.Seconds()
+}
+
+// Função responsável por formatar o limite no formato aceito pelo ParseLimit (ex: 100/1m0s).
+func (l Limit) String() string {
+	return fmt.Sprintf("%d/%s", l.Requests, l.Period)
+}
+
+// Resultado da tentativa de consumir um token do balde.
+type Result struct {
+	Limit      Limit
+	Allowed    bool
+	Remaining  int           // Tokens restantes após a requisição
+	Reset      time.Duration // Tempo até o balde estar cheio novamente
+	RetryAfter time.Duration // Tempo até o próximo token, quando a requisição é recusada
+}
+
+// Interface responsável por armazenar os baldes dos clientes.
+// O Take precisa ser atômico por chave, para que instâncias concorrentes não consumam o mesmo token.
+// A implementação em memória atende uma única instância; implantações com várias instâncias devem utilizar um
+// armazenamento compartilhado (ex: Redis, com o cálculo em um script Lua).
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// Função responsável por interpretar um limite no formato "<requisições>/<período>".
+// O período pode ser uma unidade (s, m ou h) ou uma duração do Go (ex: 100/m, 10/s, 50/30s).
+func ParseLimit(value string) (Limit, error) {
+	requests, period, found := strings.Cut(strings.TrimSpace(value), "/")
+	if !found {
+		return Limit{}, fmt.Errorf("%w %q: must be <requests>/<period>", ErrInvalidLimit, value)
+	}
+
+	count, err := strconv.Atoi(strings.TrimSpace(requests))
+	if err != nil || count < 0 {
+		return Limit{}, fmt.Errorf("%w %q: requests must be a non-negative integer", ErrInvalidLimit, value)
+	}
+
+	duration, err := parsePeriod(strings.TrimSpace(period))
+	if err != nil || duration <= 0 {
+		return Limit{}, fmt.Errorf("%w %q: period must be s, m, h or a positive duration", ErrInvalidLimit, value)
+	}
+
+	return Limit{Requests: count, Period: duration}, nil
+}
+
+// Função responsável por interpretar o limite em arquivos de configuração (ex: "100/m" no JSON dos tenants).
+func (l *Limit) UnmarshalText(text []byte) error {
+	limit, err := ParseLimit(string(text))
+	if err != nil {
+		return err
+	}
+	*l = limit
+	return nil
+}
+
+func parsePeriod(period string) (time.Duration, error) {
+	switch period {
+	case "s":
+		return time.Second, nil
+	case "m":
+		return time.Minute, nil
+	case "h":
+		return time.Hour, nil
+	}
+	return time.ParseDuration(period)
+}
+
+// Função responsável por interpretar uma lista de limites no formato "chave=limite,chave=limite"
+// (ex: "POST /v2/deliveries=10/s,GET /v2/deliveries=100/m").
+func ParseLimits(value string) (map[string]Limit, error) {
+	limits := make(map[string]Limit)
+
+	for _, pair := range strings.Split(value, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+
+		key, raw, found := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			return nil, fmt.Errorf("%w %q: must be <key>=<requests>/<period>", ErrInvalidLimit, pair)
+		}
+
+		limit, err := ParseLimit(raw)
+		if err != nil {
+			return nil, err
+		}
+		limits[key] = limit
+	}
+
+	return limits, nil
+}
+
+// Estado do balde de um cliente.
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// Função responsável por reabastecer o balde até o instante informado e tentar consumir um token.
+func (b *bucket) take(limit Limit, now time.Time) Result {
+	capacity := float64(limit.Requests)
+	rate := limit.rate()
+
+	if elapsed := now.Sub(b.updated).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(capacity, b.tokens+elapsed*rate)
+	}
+	b.updated = now
+
+	result := Result{Limit: limit}
+
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = seconds((1 - b.tokens) / rate)
+	}
+
+	result.Remaining = int(math.Floor(b.tokens))
+	result.Reset = seconds((capacity - b.tokens) / rate)
+
+	return result
+}
+
+// Função responsável por verificar se o balde já estaria cheio no instante informado.
+func (b *bucket) full(limit Limit, now time.Time) bool {
+	return b.tokens+now.Sub(b.updated).Seconds()*limit.rate() >= float64(limit.Requests)
+}
+
+func seconds(value float64) time.Duration {
+	return time.Duration(math.Ceil(value * float64(time.Second)))
+}
@@ -0,0 +1,124 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected Limit
+		err      bool
+	}{
+		{"por segundo", "10/s", Limit{Requests: 10, Period: time.Second}, false},
+		{"por minuto", "100/m", Limit{Requests: 100, Period: time.Minute}, false},
+		{"por hora", " 1000 / h ", Limit{Requests: 1000, Period: time.Hour}, false},
+		{"duração do Go", "50/30s", Limit{Requests: 50, Period: 30 * time.Second}, false},
+		{"desabilitado", "0/m", Limit{Requests: 0, Period: time.Minute}, false},
+		{"sem período", "100", Limit{}, true},
+		{"requisições inválidas", "abc/m", Limit{}, true},
+		{"requisições negativas", "-1/m", Limit{}, true},
+		{"período inválido", "10/dia", Limit{}, true},
+		{"período zero", "10/0s", Limit{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limit, err := ParseLimit(tt.value)
+
+			if tt.err {
+				assert.ErrorIs(t, err, ErrInvalidLimit)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, limit)
+		})
+	}
+}
+
+func TestParseLimits(t *testing.T) {
+	limits, err := ParseLimits("POST /v2/deliveries=10/s, read=1200/m,")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]Limit{
+		"POST /v2/deliveries": {Requests: 10, Period: time.Second},
+		"read":                {Requests: 1200, Period: time.Minute},
+	}, limits)
+
+	limits, err = ParseLimits("")
+	assert.NoError(t, err)
+	assert.Empty(t, limits)
+
+	_, err = ParseLimits("read")
+	assert.ErrorIs(t, err, ErrInvalidLimit)
+
+	_, err = ParseLimits("=10/s")
+	assert.ErrorIs(t, err, ErrInvalidLimit)
+
+	_, err = ParseLimits("read=10")
+	assert.ErrorIs(t, err, ErrInvalidLimit)
+}
+
+func TestMemoryStore_Take(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+
+	limit := Limit{Requests: 3, Period: 3 * time.Second}
+	ctx := context.Background()
+
+	// O balde começa cheio
+	for remaining := 2; remaining >= 0; remaining-- {
+		result, err := store.Take(ctx, "apikey:1", limit)
+		assert.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, remaining, result.Remaining)
+	}
+
+	result, _ := store.Take(ctx, "apikey:1", limit)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+	assert.Equal(t, time.Second, result.RetryAfter)
+	assert.Equal(t, 3*time.Second, result.Reset)
+
+	// Os baldes são separados por chave
+	result, _ = store.Take(ctx, "apikey:2", limit)
+	assert.True(t, result.Allowed)
+
+	// Um token é reabastecido a cada segundo
+	now = now.Add(time.Second)
+	result, _ = store.Take(ctx, "apikey:1", limit)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+
+	result, _ = store.Take(ctx, "apikey:1", limit)
+	assert.False(t, result.Allowed)
+
+	// O balde não passa da capacidade
+	now = now.Add(time.Hour)
+	result, _ = store.Take(ctx, "apikey:1", limit)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 2, result.Remaining)
+}
+
+func TestMemoryStore_Sweep(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+
+	limit := Limit{Requests: 10, Period: time.Minute}
+	ctx := context.Background()
+
+	store.Take(ctx, "ip:10.0.0.1", limit)
+	store.Take(ctx, "ip:10.0.0.2", limit)
+	assert.Equal(t, 2, store.Len())
+
+	// Após o intervalo os baldes cheios são removidos, e apenas o balde consumido permanece
+	now = now.Add(sweepInterval)
+	store.Take(ctx, "ip:10.0.0.3", limit)
+	assert.Equal(t, 1, store.Len())
+}
@@ -0,0 +1,22 @@
+package requestmeta
+
+import "context"
+
+// Struct que representa os metadados da requisição que originou uma operação.
+type Metadata struct {
+	RequestID string // Identificador da requisição (header X-Request-ID)
+	ClientIP  string // Endereço IP do cliente
+}
+
+type contextKey struct{}
+
+// Função responsável por adicionar os metadados da requisição ao contexto.
+func WithMetadata(ctx context.Context, metadata Metadata) context.Context {
+	return context.WithValue(ctx, contextKey{}, metadata)
+}
+
+// Função responsável por buscar os metadados da requisição no contexto.
+func FromContext(ctx context.Context) (Metadata, bool) {
+	metadata, ok := ctx.Value(contextKey{}).(Metadata)
+	return metadata, ok
+}
@@ -0,0 +1,68 @@
+package tenant
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/samluiz/delivery-service/internal/ratelimit"
+)
+
+// Configuração de um tenant. Os campos não informados seguem a configuração global do serviço.
+type Config struct {
+	Nome            string          `json:"nome"`              // Nome de exibição da transportadora
+	RateLimit       ratelimit.Limit `json:"rate_limit"`        // Limite de requisições por cliente do tenant (ex: "100/m")
+	ExclusaoEmMassa *bool           `json:"exclusao_em_massa"` // Habilita ou desabilita a exclusão em massa para o tenant
+}
+
+// Configurações dos tenants, indexadas pelo identificador.
+type Registry map[string]Config
+
+// Função responsável por carregar as configurações dos tenants de um arquivo JSON no formato
+// {"<tenant>": {"nome": "...", "rate_limit": "100/m", "exclusao_em_massa": true}}.
+// Sem arquivo informado, o registro é vazio e todos os tenants seguem a configuração global.
+func LoadRegistry(path string) (Registry, error) {
+	registry := Registry{}
+	if path == "" {
+		return registry, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(content, &registry); err != nil {
+		return nil, err
+	}
+
+	for id := range registry {
+		if err := Validate(id); err != nil {
+			return nil, fmt.Errorf("%w: %q", err, id)
+		}
+	}
+
+	return registry, nil
+}
+
+// Função responsável por retornar os limites de requisições configurados, indexados pelo tenant.
+func (r Registry) RateLimits() map[string]ratelimit.Limit {
+	limits := map[string]ratelimit.Limit{}
+	for id, config := range r {
+		if config.RateLimit.Enabled() {
+			limits[id] = config.RateLimit
+		}
+	}
+	return limits
+}
+
+// Função responsável por retornar a exclusão em massa dos tenants que sobrescrevem a configuração global.
+func (r Registry) BulkDelete() map[string]bool {
+	enabled := map[string]bool{}
+	for id, config := range r {
+		if config.ExclusaoEmMassa != nil {
+			enabled[id] = *config.ExclusaoEmMassa
+		}
+	}
+	return enabled
+}
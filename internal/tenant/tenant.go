@@ -0,0 +1,53 @@
+// Pacote com a identificação do tenant (transportadora) das requisições.
+// Cada registro do banco de dados pertence a um tenant, e os repositórios filtram as consultas pelo tenant
+// presente no contexto, para que um tenant nunca leia ou altere os dados de outro.
+package tenant
+
+import (
+	"context"
+	"errors"
+	"regexp"
+)
+
+// Tenant dos registros criados antes do multi-tenant e das credenciais sem tenant informado.
+const Default = "default"
+
+var (
+	ErrTenantRequired = errors.New("tenant is required")
+	ErrInvalidTenant  = errors.New("invalid tenant")
+)
+
+// Formato aceito nos identificadores de tenant, compatível com a coluna tenant_id.
+var idPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+type contextKey struct{}
+
+// Função responsável por validar o identificador do tenant.
+// São aceitos até 64 caracteres minúsculos, dígitos, "-" e "_", iniciando por letra ou dígito.
+func Validate(id string) error {
+	if !idPattern.MatchString(id) {
+		return ErrInvalidTenant
+	}
+	return nil
+}
+
+// Função responsável por adicionar o tenant ao contexto da requisição.
+func WithTenant(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// Função responsável por buscar o tenant no contexto da requisição.
+func FromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(contextKey{}).(string)
+	return id, ok && id != ""
+}
+
+// Função responsável por exigir o tenant no contexto.
+// Utilizada pelos repositórios, que recusam a consulta (ErrTenantRequired) em vez de consultar todos os tenants.
+func Require(ctx context.Context) (string, error) {
+	id, ok := FromContext(ctx)
+	if !ok {
+		return "", ErrTenantRequired
+	}
+	return id, nil
+}
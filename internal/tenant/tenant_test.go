@@ -0,0 +1,110 @@
+package tenant
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samluiz/delivery-service/internal/ratelimit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		err  bool
+	}{
+		{"padrão", Default, false},
+		{"com hífen e sublinhado", "acme-log_2", false},
+		{"apenas dígitos", "42", false},
+		{"vazio", "", true},
+		{"letras maiúsculas", "Acme", true},
+		{"iniciando por hífen", "-acme", true},
+		{"com espaço", "acme log", true},
+		{"maior que 64 caracteres", strings.Repeat("a", 65), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.id)
+
+			if tt.err {
+				assert.ErrorIs(t, err, ErrInvalidTenant)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	_, ok := FromContext(context.Background())
+	assert.False(t, ok)
+
+	// O tenant vazio equivale à ausência de tenant
+	_, ok = FromContext(WithTenant(context.Background(), ""))
+	assert.False(t, ok)
+
+	id, ok := FromContext(WithTenant(context.Background(), "acme"))
+	assert.True(t, ok)
+	assert.Equal(t, "acme", id)
+}
+
+func TestRequire(t *testing.T) {
+	_, err := Require(context.Background())
+	assert.ErrorIs(t, err, ErrTenantRequired)
+
+	id, err := Require(WithTenant(context.Background(), "acme"))
+	assert.NoError(t, err)
+	assert.Equal(t, "acme", id)
+}
+
+func TestLoadRegistry(t *testing.T) {
+	registry, err := LoadRegistry("")
+	assert.NoError(t, err)
+	assert.Empty(t, registry)
+
+	path := filepath.Join(t.TempDir(), "tenants.json")
+	content := `{
+		"acme": {"nome": "Acme Transportes", "rate_limit": "100/m", "exclusao_em_massa": true},
+		"globex": {"nome": "Globex Logística", "exclusao_em_massa": false},
+		"initech": {"nome": "Initech"}
+	}`
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	registry, err = LoadRegistry(path)
+	assert.NoError(t, err)
+	assert.Len(t, registry, 3)
+	assert.Equal(t, "Acme Transportes", registry["acme"].Nome)
+
+	assert.Equal(t, map[string]ratelimit.Limit{"acme": {Requests: 100, Period: time.Minute}}, registry.RateLimits())
+	assert.Equal(t, map[string]bool{"acme": true, "globex": false}, registry.BulkDelete())
+}
+
+func TestLoadRegistry_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		err     error
+	}{
+		{"tenant inválido", `{"Acme": {"nome": "Acme"}}`, ErrInvalidTenant},
+		{"limite inválido", `{"acme": {"rate_limit": "100"}}`, ratelimit.ErrInvalidLimit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "tenants.json")
+			assert.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+
+			_, err := LoadRegistry(path)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+
+	_, err := LoadRegistry(filepath.Join(t.TempDir(), "missing.json"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -1,36 +1,366 @@
 package main
 
 import (
+	"context"
+	"crypto/rand"
+	"errors"
 	"log"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"github.com/samluiz/delivery-service/api/docs"
+	graphqlserver "github.com/samluiz/delivery-service/api/graphql/server"
+	grpcserver "github.com/samluiz/delivery-service/api/grpc/server"
 	"github.com/samluiz/delivery-service/api/http/handlers"
+	"github.com/samluiz/delivery-service/api/http/middleware"
+	"github.com/samluiz/delivery-service/api/http/routes"
+	"github.com/samluiz/delivery-service/api/http/utils"
 	"github.com/samluiz/delivery-service/config/db"
+	"github.com/samluiz/delivery-service/config/env"
 	"github.com/samluiz/delivery-service/config/server"
+	"github.com/samluiz/delivery-service/config/telemetry"
+	"github.com/samluiz/delivery-service/internal/apikey"
+	"github.com/samluiz/delivery-service/internal/audit"
+	"github.com/samluiz/delivery-service/internal/auth"
+	"github.com/samluiz/delivery-service/internal/concurrency"
 	"github.com/samluiz/delivery-service/internal/delivery"
+	"github.com/samluiz/delivery-service/internal/health"
+	"github.com/samluiz/delivery-service/internal/ratelimit"
+	"github.com/samluiz/delivery-service/internal/tenant"
+	"go.opentelemetry.io/otel"
+	"google.golang.org/grpc"
 )
 
 func main() {
-	db := db.OpenMySQLConnection()
+	// Comandos administrativos (ex: ./main apikey create -nome admin -escopos admin)
+	if len(os.Args) > 1 && os.Args[1] == "apikey" {
+		if err := runAPIKeyCommand(os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
 
-	srv := server.NewServer(db)
+	// Tempo para concluir as requisições em andamento e enviar a telemetria pendente no encerramento
+	shutdownTimeout := env.GetDuration("SHUTDOWN_TIMEOUT", 10*time.Second)
 
-	deliveryRepository := delivery.NewDeliveryRepository(db)
-	deliveryService := delivery.NewDeliveryService(deliveryRepository)
-	deliveryHandler := handlers.NewDeliveryHandler(deliveryService)
+	// Configurando o tracing (OpenTelemetry)
+	shutdownTracing, err := telemetry.InitTracing(context.Background())
+	if err != nil {
+		log.Fatalf("Erro ao configurar o tracing: %v", err)
+	}
+	defer shutdownTelemetry("o tracing", shutdownTracing, shutdownTimeout)
 
-	srv.Router.HandleFunc("POST /deliveries", deliveryHandler.HandleCreateDelivery)
-	srv.Router.HandleFunc("GET /deliveries", deliveryHandler.HandleGetDeliveries)
-	srv.Router.HandleFunc("GET /deliveries/{id}", deliveryHandler.HandleGetDelivery)
-	srv.Router.HandleFunc("PUT /deliveries/{id}", deliveryHandler.HandleUpdateDelivery)
-	srv.Router.HandleFunc("DELETE /deliveries/{id}", deliveryHandler.HandleDeleteDelivery)
-	srv.Router.HandleFunc("DELETE /deliveries", deliveryHandler.HandleDeleteAllDeliveries)
+	// Configurando as métricas (OpenTelemetry)
+	shutdownMetrics, err := telemetry.InitMetrics(context.Background())
+	if err != nil {
+		log.Fatalf("Erro ao configurar as métricas: %v", err)
+	}
+	defer shutdownTelemetry("as métricas", shutdownMetrics, shutdownTimeout)
 
-	srv.Router.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte("OK"))
+	// Encerrando o serviço ao receber SIGINT ou SIGTERM. O main retorna após o encerramento dos servidores,
+	// para que os defers enviem os spans e métricas pendentes.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Validator das requisições com as regras de domínio, compartilhado pelas APIs HTTP, gRPC e GraphQL
+	validator, err := utils.NewXValidator(delivery.ValidationConfig{
+		MaxPeso: env.GetFloat("DELIVERY_MAX_WEIGHT", delivery.DefaultValidationConfig.MaxPeso),
+	})
+	if err != nil {
+		log.Fatalf("Erro ao configurar o validator: %v", err)
+	}
+	utils.ConfigureMaxBodyBytes(int64(env.GetInt("MAX_REQUEST_BODY_BYTES", int(utils.DefaultMaxBodyBytes))))
+
+	conn := db.OpenMySQLConnection()
+	defer conn.Close()
+
+	srv := server.NewServer(conn)
+
+	apiKeyRepository := apikey.NewAPIKeyRepository(conn)
+	apiKeyService := apikey.NewAPIKeyService(apiKeyRepository)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyService, validator)
+
+	// Eventos das escritas nas entregas, acompanhados pela API gRPC
+	deliveryEvents := delivery.NewEventBroker(env.GetInt("DELIVERY_EVENTS_BUFFER", delivery.DefaultEventBuffer))
+
+	// Configurações por tenant (transportadora), que sobrescrevem a configuração global do serviço
+	tenants, err := tenant.LoadRegistry(env.GetString("TENANTS_FILE", ""))
+	if err != nil {
+		log.Fatalf("Erro ao carregar a configuração dos tenants: %v", err)
+	}
+
+	deliveryRepository := delivery.NewDeliveryRepository(conn)
+	deliveryService := delivery.WithEvents(delivery.NewDeliveryService(deliveryRepository, delivery.BulkDeleteConfig{
+		Enabled:  env.GetBool("BULK_DELETE_ENABLED", false),
+		Secret:   bulkDeleteSecret(),
+		TokenTTL: env.GetDuration("BULK_DELETE_TOKEN_TTL", 5*time.Minute),
+		Tenants:  tenants.BulkDelete(),
+	}), deliveryEvents)
+	deliveryHandler := handlers.NewDeliveryHandler(deliveryService, validator)
+
+	// Expurgo das entregas que estão na lixeira há mais tempo que a retenção
+	trashRetention := env.GetDuration("DELIVERY_TRASH_RETENTION", 30*24*time.Hour)
+	purgeInterval := env.GetDuration("DELIVERY_PURGE_INTERVAL", time.Hour)
+	if err := delivery.ValidatePurgeSchedule(trashRetention, purgeInterval); err != nil {
+		log.Fatalf("Erro ao configurar o expurgo da lixeira: %v", err)
+	}
+	go delivery.RunPurgeJob(ctx, deliveryService, trashRetention, purgeInterval)
+
+	// Validador de JWTs, habilitado apenas quando alguma chave for configurada
+	var jwtValidator auth.IJWTValidator
+	jwtConfig := auth.JWTConfig{
+		HS256Secret:        env.GetString("JWT_HS256_SECRET", ""),
+		RS256PublicKeyFile: env.GetString("JWT_RS256_PUBLIC_KEY_FILE", ""),
+		JWKSFile:           env.GetString("JWT_JWKS_FILE", ""),
+		Issuer:             env.GetString("JWT_ISSUER", ""),
+		Audience:           env.GetString("JWT_AUDIENCE", ""),
+		RolesClaim:         env.GetString("JWT_ROLES_CLAIM", "roles"),
+		RoleMapping:        auth.ParseRoleMapping(env.GetString("JWT_ROLE_MAPPING", "")),
+		TenantClaim:        env.GetString("JWT_TENANT_CLAIM", "tenant_id"),
+	}
+	if jwtConfig.Enabled() {
+		jwtValidator, err = auth.NewJWTValidator(jwtConfig)
+		if err != nil {
+			log.Fatalf("Erro ao configurar a validação de JWTs: %v", err)
+		}
+	}
+
+	authenticator := middleware.NewAuthenticator(apiKeyService, jwtValidator)
+
+	// Políticas de acesso: escopo exigido das chaves de API e papéis permitidos para usuários
+	readPolicy := auth.Policy{
+		Scope: apikey.ScopeRead,
+		Roles: []auth.Role{auth.RoleAdmin, auth.RoleDispatcher, auth.RoleDriver},
+	}
+	createPolicy := auth.Policy{
+		Scope: apikey.ScopeWrite,
+		Roles: []auth.Role{auth.RoleAdmin, auth.RoleDispatcher, auth.RoleCustomer},
+	}
+	writePolicy := auth.Policy{
+		Scope: apikey.ScopeWrite,
+		Roles: []auth.Role{auth.RoleAdmin, auth.RoleDispatcher},
+	}
+	adminPolicy := auth.Policy{
+		Scope: apikey.ScopeAdmin,
+		Roles: []auth.Role{auth.RoleAdmin},
+	}
+	read := authenticator.Require(readPolicy)
+	create := authenticator.Require(createPolicy)
+	write := authenticator.Require(writePolicy)
+	admin := authenticator.Require(adminPolicy)
+
+	auditService := audit.NewAuditService(audit.NewAuditRepository(conn))
+	auditHandler := handlers.NewAuditHandler(auditService)
+
+	healthService := health.NewHealthService(
+		env.GetDuration("HEALTH_CHECK_TIMEOUT", 2*time.Second),
+		health.NewDatabaseChecker(conn),
+		health.NewMigrationsChecker(conn, db.Tables),
+	)
+	healthHandler := handlers.NewHealthHandler(healthService)
+
+	contract, err := middleware.NewOpenAPIValidator(docs.OpenAPI, validator, middleware.OpenAPIOptions{
+		ValidateResponses: env.GetBool("OPENAPI_VALIDATE_RESPONSES", false),
+	})
+	if err != nil {
+		log.Fatalf("Erro ao carregar o documento OpenAPI: %v", err)
+	}
+
+	// Limite de requisições por cliente, para que uma integração não esgote o pool de conexões do banco
+	rateLimiter := middleware.NewRateLimiter(ratelimit.NewMemoryStore(), rateLimitOptions(tenants))
+
+	// Limite de concorrência, para que as rajadas sejam descartadas em vez de aguardarem o pool de conexões do banco
+	priority, err := concurrency.ParseClass(env.GetString("CONCURRENCY_PRIORITY", string(concurrency.DefaultOptions.Priority)))
+	if err != nil {
+		log.Fatalf("Erro ao ler a prioridade do limite de concorrência: %v", err)
+	}
+	concurrencyLimiter := concurrency.NewLimiter(concurrency.Options{
+		InitialLimit: env.GetInt("CONCURRENCY_INITIAL_LIMIT", concurrency.DefaultOptions.InitialLimit),
+		MinLimit:     env.GetInt("CONCURRENCY_MIN_LIMIT", concurrency.DefaultOptions.MinLimit),
+		MaxLimit:     env.GetInt("CONCURRENCY_MAX_LIMIT", concurrency.DefaultOptions.MaxLimit),
+		MaxQueue:     env.GetInt("CONCURRENCY_MAX_QUEUE", concurrency.DefaultOptions.MaxQueue),
+		MaxWait:      env.GetDuration("CONCURRENCY_MAX_WAIT", concurrency.DefaultOptions.MaxWait),
+		Tolerance:    env.GetFloat("CONCURRENCY_LATENCY_TOLERANCE", concurrency.DefaultOptions.Tolerance),
+		Priority:     priority,
 	})
+	if err := concurrencyLimiter.RegisterMetrics(otel.Meter("delivery-service")); err != nil {
+		log.Fatalf("Erro ao registrar as métricas do limite de concorrência: %v", err)
+	}
+	loadShedder := middleware.NewLoadShedder(concurrencyLimiter, env.GetDuration("CONCURRENCY_RETRY_AFTER", middleware.DefaultShedRetryAfter))
+
+	// API GraphQL, com as mesmas permissões da API REST verificadas em cada operação
+	graphqlServer, err := graphqlserver.NewServer(deliveryService, validator, graphqlserver.Policies{
+		Read:   readPolicy,
+		Create: createPolicy,
+		Write:  writePolicy,
+	}, graphqlserver.Limits{
+		MaxDepth:      env.GetInt("GRAPHQL_MAX_DEPTH", 10),
+		MaxComplexity: env.GetInt("GRAPHQL_MAX_COMPLEXITY", 5000),
+	})
+	if err != nil {
+		log.Fatalf("Erro ao montar o schema GraphQL: %v", err)
+	}
+	graphqlHandler := handlers.NewGraphQLHandler(graphqlServer, validator)
+
+	// As queries GraphQL são da classe read no limite de concorrência, como as consultas da API REST
+	loadShedder.Classify("POST /graphql", graphqlHandler.RequestClass)
+
+	routes.Register(srv.Router, routes.Routes(routes.Handlers{
+		Delivery:   deliveryHandler,
+		DeliveryV2: handlers.NewDeliveryHandlerV2(deliveryService, validator),
+		Audit:      auditHandler,
+		APIKey:     apiKeyHandler,
+		Health:     healthHandler,
+		Docs:       handlers.NewDocsHandler(),
+		GraphQL:    graphqlHandler,
+	}, routes.Policies{
+		Read:          read,
+		Create:        create,
+		Write:         write,
+		Admin:         admin,
+		Authenticated: authenticator.RequireAuthentication(),
+		Contract:      contract.Validate,
+		// A v1 (campos em português) está congelada e será removida após o sunset
+		Deprecated: middleware.Deprecated(middleware.Deprecation{
+			DeprecatedAt: env.GetTime("API_V1_DEPRECATED_AT", time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)),
+			Sunset:       env.GetTime("API_V1_SUNSET", time.Date(2027, 4, 19, 0, 0, 0, 0, time.UTC)),
+			Successor:    "/v2/deliveries",
+		}),
+		Negotiate:                middleware.Negotiate,
+		Decompress:               middleware.Decompress,
+		RateLimit:                rateLimiter.Limit,
+		RateLimitUnauthenticated: rateLimiter.LimitUnauthenticated,
+		Concurrency:              loadShedder.Limit,
+	}))
+
+	// API gRPC para os serviços internos, em uma porta separada
+	grpcServer := grpcserver.NewServer(authenticator, grpcserver.Policies{
+		Read:   readPolicy,
+		Create: createPolicy,
+		Write:  writePolicy,
+	}, grpcserver.Limits{
+		RateLimit:   rateLimiter,
+		Concurrency: loadShedder,
+	}, grpcserver.NewDeliveryServer(deliveryService, deliveryEvents, validator))
+
+	grpcPort := env.GetString("GRPC_PORT", "9090")
+	grpcListener, err := net.Listen("tcp", ":"+grpcPort)
+	if err != nil {
+		log.Fatalf("Erro ao abrir a porta do servidor gRPC: %v", err)
+	}
+
+	httpServer := &http.Server{Addr: ":8080", Handler: srv}
+
+	// Erros dos servidores, que encerram o serviço
+	serveErrors := make(chan error, 2)
+
+	go func() {
+		log.Printf("Servidor gRPC iniciando na porta %s...", grpcPort)
+		serveErrors <- grpcServer.Serve(grpcListener)
+	}()
+
+	go func() {
+		log.Println("Server iniciando na porta 8080...")
+		if err := httpServer.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			serveErrors <- err
+		}
+	}()
+
+	select {
+	case <-ctx.Done():
+		log.Println("Encerrando o serviço...")
+	case err := <-serveErrors:
+		log.Printf("Erro no servidor, encerrando o serviço: %v", err)
+	}
+
+	shutdownServers(httpServer, grpcServer, shutdownTimeout)
+}
+
+// Função responsável por encerrar os servidores, aguardando as requisições em andamento até o timeout.
+// As chamadas gRPC ainda abertas após o timeout (ex: streams do WatchDeliveries) são interrompidas.
+func shutdownServers(httpServer *http.Server, grpcServer *grpc.Server, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := httpServer.Shutdown(ctx); err != nil {
+		log.Printf("Erro ao encerrar o servidor HTTP: %v", err)
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		grpcServer.Stop()
+	}
+}
+
+// Função responsável por enviar a telemetria pendente no encerramento, com o timeout do encerramento.
+func shutdownTelemetry(name string, shutdown telemetry.ShutdownFunc, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := shutdown(ctx); err != nil {
+		log.Printf("Erro ao encerrar %s: %v", name, err)
+	}
+}
+
+// Função responsável por ler os limites de requisições: RATE_LIMIT_DEFAULT (ex: 600/m), RATE_LIMIT_ROUTES
+// (ex: "POST /v2/deliveries=10/s"), RATE_LIMIT_SCOPES (ex: "read=1200/m,admin=0/m") e RATE_LIMIT_UNAUTHENTICATED,
+// aplicado por IP antes da autenticação, além dos limites dos tenants. O limite 0 desabilita o rate limiting.
+func rateLimitOptions(tenants tenant.Registry) middleware.RateLimitOptions {
+	defaultLimit, err := ratelimit.ParseLimit(env.GetString("RATE_LIMIT_DEFAULT", "600/m"))
+	if err != nil {
+		log.Fatalf("Erro ao ler o limite de requisições padrão: %v", err)
+	}
+
+	routeLimits, err := ratelimit.ParseLimits(env.GetString("RATE_LIMIT_ROUTES", ""))
+	if err != nil {
+		log.Fatalf("Erro ao ler os limites de requisições por rota: %v", err)
+	}
+
+	scopeLimits, err := ratelimit.ParseLimits(env.GetString("RATE_LIMIT_SCOPES", ""))
+	if err != nil {
+		log.Fatalf("Erro ao ler os limites de requisições por escopo: %v", err)
+	}
+
+	scopes := make(map[apikey.Scope]ratelimit.Limit, len(scopeLimits))
+	for scope, limit := range scopeLimits {
+		scopes[apikey.Scope(scope)] = limit
+	}
+
+	unauthenticatedLimit, err := ratelimit.ParseLimit(env.GetString("RATE_LIMIT_UNAUTHENTICATED", "1200/m"))
+	if err != nil {
+		log.Fatalf("Erro ao ler o limite de requisições antes da autenticação: %v", err)
+	}
+
+	return middleware.RateLimitOptions{
+		Default:         defaultLimit,
+		Routes:          routeLimits,
+		Scopes:          scopes,
+		Tenants:         tenants.RateLimits(),
+		Unauthenticated: unauthenticatedLimit,
+	}
+}
+
+// Função responsável por obter o segredo dos tokens de confirmação da exclusão em massa.
+// Sem BULK_DELETE_SECRET um segredo aleatório é gerado, válido apenas para esta instância.
+func bulkDeleteSecret() []byte {
+	if secret := env.GetString("BULK_DELETE_SECRET", ""); secret != "" {
+		return []byte(secret)
+	}
 
-	log.Println("Server iniciando na porta 8080...")
-	log.Fatal(http.ListenAndServe(":8080", srv))
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		log.Fatalf("Erro ao gerar o segredo da exclusão em massa: %v", err)
+	}
+	return secret
 }